@@ -0,0 +1,315 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// TransitionKind is the kind of animation used by a Transition.
+type TransitionKind int
+
+// These are the kinds of transition.  A slide moves the old content out
+// of the region, in the direction named, as the new content moves in
+// behind it.  A wipe leaves both in place, and moves the edge between
+// them in the direction named.  A fade blends the colors of the old
+// content into its background, changes the background to that of the new
+// content, and then brings up the colors of the new content.  Fades need
+// colors with RGB values; others (such as ColorDefault) simply change at
+// the halfway point.
+const (
+	TransitionSlideLeft TransitionKind = iota
+	TransitionSlideRight
+	TransitionSlideUp
+	TransitionSlideDown
+	TransitionWipeLeft
+	TransitionWipeRight
+	TransitionWipeUp
+	TransitionWipeDown
+	TransitionFade
+)
+
+// Transition describes an animated change to the contents of a region.
+type Transition struct {
+	Kind     TransitionKind
+	X, Y     int
+	Width    int
+	Height   int
+	Duration time.Duration
+
+	// Ease maps the fraction of the duration that has elapsed to the
+	// fraction of the animation to show, both from 0 to 1.  If it is nil,
+	// the animation proceeds at an even pace.
+	Ease func(float64) float64
+}
+
+// EaseInOut is an easing function for a Transition that starts and ends
+// slowly.
+func EaseInOut(f float64) float64 {
+	return f * f * (3 - 2*f)
+}
+
+// EventTransition is posted by an Animator when a transition ends.
+type EventTransition struct {
+	t       time.Time
+	id      interface{}
+	stopped bool
+}
+
+// When returns the time when the transition ended.
+func (ev *EventTransition) When() time.Time {
+	return ev.t
+}
+
+// ID returns the identifier given to Animator.Start for the transition.
+func (ev *EventTransition) ID() interface{} {
+	return ev.id
+}
+
+// Stopped returns true if the transition was ended early, by Stop or by
+// starting another with the same identifier.
+func (ev *EventTransition) Stopped() bool {
+	return ev.stopped
+}
+
+// animCell is the content of a cell, as captured from the screen.
+type animCell struct {
+	mainc rune
+	combc []rune
+	style Style
+	width int
+}
+
+type animation struct {
+	tr       Transition
+	from, to []animCell
+	start    time.Time
+	stop     func() bool
+}
+
+// Animator animates changes to regions of a Screen, so that menus, panels
+// and the like can slide, wipe or fade into place.  The application draws
+// the new content of a region as usual, but within the function given to
+// Start; the Animator then draws the frames from the old content to the
+// new, calling Show for each, and posts an EventTransition when done.
+// Several regions may be animated at once, each with its own identifier.
+// The application should not draw into a region while it is animated.
+type Animator struct {
+	// FrameInterval is the time between frames; the default is 1/30s.
+	FrameInterval time.Duration
+
+	s       Screen
+	clock   Clock
+	running map[interface{}]*animation
+	sync.Mutex
+}
+
+// NewAnimator creates an Animator for the screen.
+func NewAnimator(s Screen) *Animator {
+	return &Animator{
+		FrameInterval: time.Second / 30,
+		s:             s,
+		clock:         SystemClock,
+		running:       make(map[interface{}]*animation),
+	}
+}
+
+// SetClock sets the clock that drives the animations.  The default is
+// SystemClock.
+func (a *Animator) SetClock(c Clock) {
+	a.Lock()
+	a.clock = c
+	a.Unlock()
+}
+
+// capture returns the contents of the region of a transition.
+func (a *Animator) capture(tr Transition) []animCell {
+	cells := make([]animCell, 0, tr.Width*tr.Height)
+	for row := 0; row < tr.Height; row++ {
+		for col := 0; col < tr.Width; col++ {
+			var c animCell
+			c.mainc, c.combc, c.style, c.width = a.s.GetContent(tr.X+col, tr.Y+row)
+			cells = append(cells, c)
+		}
+	}
+	return cells
+}
+
+// Start animates the region of the transition from its current contents
+// to what draw draws into it.  The first frame is drawn and shown at
+// once.  Any transition already running with the same id is ended first.
+func (a *Animator) Start(id interface{}, tr Transition, draw func()) {
+	a.Stop(id)
+	if tr.Width <= 0 || tr.Height <= 0 {
+		draw()
+		return
+	}
+	anim := &animation{tr: tr, from: a.capture(tr)}
+	draw()
+	anim.to = a.capture(tr)
+
+	a.Lock()
+	anim.start = a.clock.Now()
+	a.running[id] = anim
+	a.frame(anim, 0)
+	a.s.Show()
+	anim.stop = a.clock.AfterFunc(a.FrameInterval, func() { a.tick(id, anim) })
+	a.Unlock()
+}
+
+// Stop ends the transition with the given id at once, drawing its final
+// frame.  It does nothing if there is no such transition.
+func (a *Animator) Stop(id interface{}) {
+	a.Lock()
+	anim, ok := a.running[id]
+	if !ok {
+		a.Unlock()
+		return
+	}
+	anim.stop()
+	delete(a.running, id)
+	a.frame(anim, 1)
+	ev := &EventTransition{t: a.clock.Now(), id: id, stopped: true}
+	a.Unlock()
+	a.s.Show()
+	_ = a.s.PostEvent(ev)
+}
+
+// Running returns true if the transition with the given id has not ended.
+func (a *Animator) Running(id interface{}) bool {
+	a.Lock()
+	defer a.Unlock()
+	_, ok := a.running[id]
+	return ok
+}
+
+// tick is called by the timer for each frame after the first.
+func (a *Animator) tick(id interface{}, anim *animation) {
+	a.Lock()
+	if a.running[id] != anim {
+		a.Unlock()
+		return
+	}
+	f := 1.0
+	if anim.tr.Duration > 0 {
+		f = float64(a.clock.Now().Sub(anim.start)) / float64(anim.tr.Duration)
+	}
+	var ev Event
+	if f >= 1 {
+		delete(a.running, id)
+		a.frame(anim, 1)
+		ev = &EventTransition{t: a.clock.Now(), id: id}
+	} else {
+		if anim.tr.Ease != nil {
+			f = anim.tr.Ease(f)
+		}
+		a.frame(anim, f)
+		anim.stop = a.clock.AfterFunc(a.FrameInterval, func() { a.tick(id, anim) })
+	}
+	a.Unlock()
+	a.s.Show()
+	if ev != nil {
+		_ = a.s.PostEvent(ev)
+	}
+}
+
+// frame draws the animation f of the way through.
+func (a *Animator) frame(anim *animation, f float64) {
+	tr := anim.tr
+	w, h := tr.Width, tr.Height
+	f = math.Max(0, math.Min(1, f))
+	n := func(length int) int {
+		return int(math.Round(f * float64(length)))
+	}
+	for row := 0; row < h; row++ {
+		for col := 0; col < w; col++ {
+			var c animCell
+			switch tr.Kind {
+			case TransitionSlideLeft:
+				c = animPick(anim, col+n(w), row, w, 0)
+			case TransitionSlideRight:
+				c = animPick(anim, col-n(w), row, w, 0)
+			case TransitionSlideUp:
+				c = animPick(anim, col, row+n(h), 0, h)
+			case TransitionSlideDown:
+				c = animPick(anim, col, row-n(h), 0, h)
+			case TransitionWipeLeft:
+				c = animChoose(anim, col, row, col >= w-n(w))
+			case TransitionWipeRight:
+				c = animChoose(anim, col, row, col < n(w))
+			case TransitionWipeUp:
+				c = animChoose(anim, col, row, row >= h-n(h))
+			case TransitionWipeDown:
+				c = animChoose(anim, col, row, row < n(h))
+			default:
+				c = animFade(anim.from[row*w+col], anim.to[row*w+col], f)
+			}
+			if c.width > 1 && col+1 >= w {
+				c = animCell{mainc: ' ', style: c.style, width: 1}
+			}
+			a.s.SetContent(tr.X+col, tr.Y+row, c.mainc, c.combc, c.style)
+			if c.width > 1 {
+				col++
+			}
+		}
+	}
+}
+
+// animPick returns the cell at col, row of the old content, where the new
+// content follows it (or precedes it, for negative positions) at a
+// distance of dx or dy.
+func animPick(anim *animation, col, row, dx, dy int) animCell {
+	w, h := anim.tr.Width, anim.tr.Height
+	switch {
+	case col >= w || row >= h:
+		return anim.to[(row-dy)*w+col-dx]
+	case col < 0 || row < 0:
+		return anim.to[(row+dy)*w+col+dx]
+	}
+	return anim.from[row*w+col]
+}
+
+// animChoose returns the cell at col, row of the new content, or the old.
+func animChoose(anim *animation, col, row int, new bool) animCell {
+	if new {
+		return anim.to[row*anim.tr.Width+col]
+	}
+	return anim.from[row*anim.tr.Width+col]
+}
+
+// animFade returns a cell f of the way through a fade from one cell to
+// another.
+func animFade(from, to animCell, f float64) animCell {
+	blend := func(a, b Color, f float64) Color {
+		if !a.Valid() || !b.Valid() {
+			if f < 0.5 {
+				return a
+			}
+			return b
+		}
+		return BlendColor(a, b, f)
+	}
+	ffg, fbg, _ := from.style.Decompose()
+	tfg, tbg, _ := to.style.Decompose()
+	bg := blend(fbg, tbg, f)
+	if f < 0.5 {
+		from.style = from.style.Foreground(blend(ffg, bg, f*2)).Background(bg)
+		return from
+	}
+	to.style = to.style.Foreground(blend(bg, tfg, f*2-1)).Background(bg)
+	return to
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnimator(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(6, 2)
+	text := func(x, y int, str string) {
+		for i, r := range str {
+			s.SetContent(x+i, y, r, nil, StyleDefault)
+		}
+	}
+
+	clock := NewFakeClock(time.Unix(1000, 0))
+	a := NewAnimator(s)
+	a.SetClock(clock)
+	a.FrameInterval = 100 * time.Millisecond
+	text(0, 0, "abcdef")
+	tr := Transition{Kind: TransitionSlideLeft, X: 1, Width: 4, Height: 1, Duration: 400 * time.Millisecond}
+	a.Start("menu", tr, func() { text(1, 0, "wxyz") })
+	if got := simRow(s, 0, 0, 6); got != "abcdef" {
+		t.Errorf("wrong first frame: %q", got)
+	}
+	clock.Advance(100 * time.Millisecond)
+	if got := simRow(s, 0, 0, 6); got != "acdewf" {
+		t.Errorf("wrong second frame: %q", got)
+	}
+	clock.Advance(200 * time.Millisecond)
+	if got := simRow(s, 0, 0, 6); got != "aewxyf" || !a.Running("menu") {
+		t.Errorf("wrong fourth frame: %q", got)
+	}
+	clock.Advance(100 * time.Millisecond)
+	if got := simRow(s, 0, 0, 6); got != "awxyzf" || a.Running("menu") {
+		t.Errorf("wrong last frame: %q", got)
+	}
+	if ev, ok := s.PollEvent().(*EventTransition); !ok || ev.ID() != "menu" || ev.Stopped() {
+		t.Errorf("wrong event: %v", ev)
+	}
+
+	tr = Transition{Kind: TransitionWipeDown, Width: 6, Height: 2, Duration: time.Second}
+	a.Start(1, tr, func() { text(0, 0, "ghijkl"); text(0, 1, "mnopqr") })
+	clock.Advance(500 * time.Millisecond)
+	if got := simRow(s, 0, 0, 6) + "|" + simRow(s, 0, 1, 6); got != "ghijkl|      " {
+		t.Errorf("wrong wipe: %q", got)
+	}
+	a.Stop(1)
+	if got := simRow(s, 0, 1, 6); got != "mnopqr" {
+		t.Errorf("wrong wipe after stop: %q", got)
+	}
+	if ev, ok := s.PollEvent().(*EventTransition); !ok || ev.ID() != 1 || !ev.Stopped() {
+		t.Errorf("wrong event: %v", ev)
+	}
+
+	from := StyleDefault.Foreground(NewRGBColor(200, 0, 0)).Background(NewRGBColor(0, 0, 0))
+	to := StyleDefault.Foreground(NewRGBColor(0, 200, 0)).Background(NewRGBColor(100, 100, 100))
+	mid := animFade(animCell{mainc: 'a', style: from}, animCell{mainc: 'b', style: to}, 0.25)
+	if mid.mainc != 'a' || mid.style != from.Foreground(NewRGBColor(112, 13, 13)).Background(NewRGBColor(25, 25, 25)) {
+		t.Errorf("wrong fade: %q %v", mid.mainc, mid.style)
+	}
+}
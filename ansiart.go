@@ -0,0 +1,328 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	runewidth "github.com/mattn/go-runewidth"
+)
+
+// CellSetter is anything that cells can be drawn into.  Both Screen and
+// CellBuffer are CellSetters.
+type CellSetter interface {
+	SetContent(x int, y int, mainc rune, combc []rune, style Style)
+}
+
+// ImportANSI draws text containing ANSI (ECMA-48) escape sequences into
+// the region of dst with its top left corner at x, y, and the given
+// width and height.  This is useful for showing ANSI art, logos, and the
+// captured output of commands.  Colors and attributes (SGR), cursor
+// movement, erasing, and tabs are understood; other control sequences are
+// ignored.  Each newline starts a new row.  Anything outside the region
+// is clipped.  The text must be UTF-8; files using a DOS code page (as
+// most .ans files do) should be decoded first, for example with the
+// encodings registered by the encoding package.  A SAUCE record (or
+// anything else after a ^Z) is ignored.
+//
+// The return value is the number of rows that were used.
+func ImportANSI(dst CellSetter, x, y, width, height int, text string) int {
+	p := &ansiParser{
+		put: func(col, row int, mainc rune, combc []rune, style Style) {
+			if col >= 0 && col < width && row >= 0 && row < height {
+				dst.SetContent(x+col, y+row, mainc, combc, style)
+			}
+		},
+		width:  width,
+		height: height,
+	}
+	p.parse(text)
+	rows := p.rows
+	if rows > height {
+		rows = height
+	}
+	return rows
+}
+
+// ansiParser interprets text with escape sequences, calling put for
+// each cell that is drawn.  Coordinates are relative to the origin of
+// the text.  If width is non-zero, the cursor stops at the last column.
+// If height is non-zero, cursor movement stops just below the last row,
+// where nothing more is drawn.
+type ansiParser struct {
+	put    func(x, y int, mainc rune, combc []rune, style Style)
+	width  int
+	height int
+	style  Style
+	x, y   int
+	sx     int // saved cursor position
+	sy     int
+	lastx  int // last cell written, for combining characters
+	lasty  int
+	lastr  rune
+	lastc  []rune
+	lasts  Style
+	rows   int
+}
+
+func (p *ansiParser) parse(text string) {
+	p.lastx = -1
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch c {
+		case '\x1a': // DOS end of file; SAUCE metadata follows
+			return
+		case '\x1b':
+			i += p.escape(text[i:])
+			continue
+		case '\n':
+			p.x = 0
+			p.y++
+		case '\r':
+			p.x = 0
+		case '\t':
+			p.x = (p.x/defaultTabWidth + 1) * defaultTabWidth
+			p.clampX()
+		case '\b':
+			if p.x > 0 {
+				p.x--
+			}
+		default:
+			if c < ' ' || c == 0x7f {
+				break
+			}
+			r, n := utf8.DecodeRuneInString(text[i:])
+			p.rune(r)
+			i += n
+			continue
+		}
+		i++
+	}
+}
+
+func (p *ansiParser) clampX() {
+	if p.width > 0 && p.x >= p.width {
+		p.x = p.width - 1
+	}
+	if p.x < 0 {
+		p.x = 0
+	}
+}
+
+func (p *ansiParser) clampY() {
+	if p.height > 0 && p.y > p.height {
+		p.y = p.height
+	}
+	if p.y < 0 {
+		p.y = 0
+	}
+}
+
+func (p *ansiParser) rune(r rune) {
+	w := runewidth.RuneWidth(r)
+	if w == 0 {
+		// combining character; attach to the last cell drawn
+		if p.lastx >= 0 {
+			p.lastc = append(p.lastc, r)
+			p.put(p.lastx, p.lasty, p.lastr, p.lastc, p.lasts)
+		}
+		return
+	}
+	if p.width > 0 && p.x+w > p.width {
+		// wrap to the next line, as a terminal would
+		p.x = 0
+		p.y++
+	}
+	p.put(p.x, p.y, r, nil, p.style)
+	p.lastx, p.lasty, p.lastr, p.lastc, p.lasts = p.x, p.y, r, nil, p.style
+	if p.y >= p.rows {
+		p.rows = p.y + 1
+	}
+	p.x += w
+}
+
+// escape handles an escape sequence at the start of s, and returns its
+// length.
+func (p *ansiParser) escape(s string) int {
+	kind, payload, n, ok := scanSequence([]byte(s))
+	if !ok || n == 0 {
+		// malformed or truncated; skip the ESC alone
+		return 1
+	}
+	if kind == SequenceCSI {
+		p.csi(payload)
+	}
+	return n
+}
+
+// maxANSIArg limits the parameters of cursor movement, which are larger
+// than any real screen, so that nonsense cannot overflow.
+const maxANSIArg = 1 << 16
+
+func (p *ansiParser) csi(payload string) {
+	if payload == "" {
+		return
+	}
+	final := payload[len(payload)-1]
+	body := payload[:len(payload)-1]
+	if body != "" && (body[0] < '0' || body[0] > ';') {
+		// private sequences, such as ?25h, are of no interest
+		return
+	}
+	var args []int
+	for _, f := range strings.Split(body, ";") {
+		v, _ := strconv.Atoi(f)
+		args = append(args, v)
+	}
+	arg := func(i, def int) int {
+		if i < len(args) && args[i] != 0 {
+			if args[i] > maxANSIArg {
+				return maxANSIArg
+			}
+			return args[i]
+		}
+		return def
+	}
+	switch final {
+	case 'm':
+		p.style = sgrStyle(p.style, args)
+	case 'A':
+		p.y -= arg(0, 1)
+	case 'B':
+		p.y += arg(0, 1)
+	case 'C':
+		p.x += arg(0, 1)
+		p.clampX()
+	case 'D':
+		p.x -= arg(0, 1)
+		p.clampX()
+	case 'G':
+		p.x = arg(0, 1) - 1
+		p.clampX()
+	case 'd':
+		p.y = arg(0, 1) - 1
+	case 'H', 'f':
+		p.y = arg(0, 1) - 1
+		p.x = arg(1, 1) - 1
+		p.clampX()
+	case 's':
+		p.sx, p.sy = p.x, p.y
+	case 'u':
+		p.x, p.y = p.sx, p.sy
+	case 'K':
+		p.eraseLine(arg(0, 0))
+	case 'J':
+		if arg(0, 0) == 2 {
+			for y := 0; y < p.rows && (p.height == 0 || y < p.height); y++ {
+				p.eraseRow(y, 0, p.width)
+			}
+		}
+	}
+	p.clampY()
+}
+
+func (p *ansiParser) eraseRow(y, from, to int) {
+	for x := from; x < to; x++ {
+		p.put(x, y, ' ', nil, p.style)
+	}
+}
+
+func (p *ansiParser) eraseLine(mode int) {
+	if p.width == 0 {
+		return
+	}
+	switch mode {
+	case 0:
+		p.eraseRow(p.y, p.x, p.width)
+	case 1:
+		p.eraseRow(p.y, 0, p.x+1)
+	case 2:
+		p.eraseRow(p.y, 0, p.width)
+	}
+}
+
+// sgrStyle applies the SGR (select graphic rendition) parameters to a
+// style.
+func sgrStyle(st Style, args []int) Style {
+	if len(args) == 0 {
+		return StyleDefault
+	}
+	color := func(i int) (Color, int) {
+		// extended colors: 5;n or 2;r;g;b
+		if i+1 < len(args) && args[i] == 5 {
+			return PaletteColor(args[i+1]), 2
+		}
+		if i+3 < len(args) && args[i] == 2 {
+			return NewRGBColor(int32(args[i+1]), int32(args[i+2]), int32(args[i+3])), 4
+		}
+		return ColorDefault, len(args) - i
+	}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == 0:
+			st = StyleDefault
+		case a == 1:
+			st = st.Bold(true)
+		case a == 2:
+			st = st.Dim(true)
+		case a == 3:
+			st = st.Italic(true)
+		case a == 4:
+			st = st.Underline(true)
+		case a == 5 || a == 6:
+			st = st.Blink(true)
+		case a == 7:
+			st = st.Reverse(true)
+		case a == 9:
+			st = st.StrikeThrough(true)
+		case a == 22:
+			st = st.Bold(false).Dim(false)
+		case a == 23:
+			st = st.Italic(false)
+		case a == 24:
+			st = st.Underline(false)
+		case a == 25:
+			st = st.Blink(false)
+		case a == 27:
+			st = st.Reverse(false)
+		case a == 29:
+			st = st.StrikeThrough(false)
+		case a >= 30 && a <= 37:
+			st = st.Foreground(PaletteColor(a - 30))
+		case a == 38:
+			c, n := color(i + 1)
+			st = st.Foreground(c)
+			i += n
+		case a == 39:
+			st = st.Foreground(ColorDefault)
+		case a >= 40 && a <= 47:
+			st = st.Background(PaletteColor(a - 40))
+		case a == 48:
+			c, n := color(i + 1)
+			st = st.Background(c)
+			i += n
+		case a == 49:
+			st = st.Background(ColorDefault)
+		case a >= 90 && a <= 97:
+			st = st.Foreground(PaletteColor(a - 90 + 8))
+		case a >= 100 && a <= 107:
+			st = st.Background(PaletteColor(a - 100 + 8))
+		}
+	}
+	return st
+}
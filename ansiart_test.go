@@ -0,0 +1,63 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestImportANSI(t *testing.T) {
+	var cb CellBuffer
+	cb.Resize(10, 5)
+
+	art := "\x1b[1;31mab\x1b[0m\r\nc\x1b[2Cd\x1b[3;1He\u0301\x1b[38;5;20mtoolong\n\n\nclipped\x1a\x1b[0mSAUCE"
+	rows := ImportANSI(&cb, 1, 1, 4, 3, art)
+	if rows != 3 {
+		t.Errorf("wrong number of rows: %d", rows)
+	}
+	check := func(x, y int, r rune, st Style) {
+		t.Helper()
+		mainc, _, style, _ := cb.GetContent(x, y)
+		if mainc != r || style != st {
+			t.Errorf("%d,%d: got %q %v, want %q %v", x, y, mainc, style, r, st)
+		}
+	}
+	red := StyleDefault.Bold(true).Foreground(ColorMaroon)
+	check(1, 1, 'a', red)
+	check(2, 1, 'b', red)
+	check(1, 2, 'c', StyleDefault)
+	check(4, 2, 'd', StyleDefault)
+	check(1, 3, 'e', StyleDefault)
+	check(2, 3, 't', StyleDefault.Foreground(PaletteColor(20)))
+	check(5, 3, ' ', StyleDefault) // clipped
+	if _, combc, _, _ := cb.GetContent(1, 3); len(combc) != 1 || combc[0] != '\u0301' {
+		t.Errorf("combining character lost: %q", combc)
+	}
+}
+
+func TestImportANSIHuge(t *testing.T) {
+	var cb CellBuffer
+	cb.Resize(4, 3)
+
+	// Movement far outside the region must not be followed by erasing
+	// (or anything else) for every row or column moved over.
+	art := "\x1b[999999999dx\x1b[2J\x1b[9223372036854775807C\x1b[9223372036854775807B\x1b[1;1Hy"
+	if rows := ImportANSI(&cb, 0, 0, 4, 3, art); rows != 3 {
+		t.Errorf("wrong number of rows: %d", rows)
+	}
+	if mainc, _, _, _ := cb.GetContent(0, 0); mainc != 'y' {
+		t.Errorf("wrong content: %q", mainc)
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BannerFont is a font of large characters, each made of several cells,
+// for drawing banners with DrawBanner.  BannerBlock and BannerHalf are
+// built in, and FIGlet fonts can be loaded with ParseFIGlet.
+type BannerFont struct {
+	height int
+	glyphs map[rune][][]rune
+}
+
+// ErrInvalidFIGlet is returned by ParseFIGlet for data that is not a
+// FIGlet font.
+var ErrInvalidFIGlet = errors.New("invalid FIGlet font")
+
+var (
+	// BannerBlock draws printable ASCII nine cells high, with a full
+	// block for each pixel of the font used by ToImage.
+	BannerBlock = newImageBanner(false)
+
+	// BannerHalf draws printable ASCII five cells high, with half
+	// blocks for the pixels of the font used by ToImage, so that they
+	// are about square.
+	BannerHalf = newImageBanner(true)
+)
+
+// newImageBanner makes a BannerFont from imageFontData, with a blank
+// column after each glyph to separate them.
+func newImageBanner(half bool) *BannerFont {
+	f := &BannerFont{height: 9, glyphs: make(map[rune][][]rune)}
+	if half {
+		f.height = 5
+	}
+	for i, s := range imageFontData {
+		pixels := strings.Fields(s)
+		set := func(row, col int) bool {
+			return row < len(pixels) && pixels[row][col] == '#'
+		}
+		rows := make([][]rune, f.height)
+		for row := range rows {
+			for col := 0; col < 5; col++ {
+				r := ' '
+				if !half {
+					if set(row, col) {
+						r = '█'
+					}
+				} else {
+					switch top, bot := set(row*2, col), set(row*2+1, col); {
+					case top && bot:
+						r = '█'
+					case top:
+						r = '▀'
+					case bot:
+						r = '▄'
+					}
+				}
+				rows[row] = append(rows[row], r)
+			}
+			rows[row] = append(rows[row], ' ')
+		}
+		f.glyphs[rune(' '+i)] = rows
+	}
+	return f
+}
+
+// ParseFIGlet reads a FIGlet font (a .flf file), or a TOIlet font (a .tlf
+// file, which may use any characters in UTF-8).  Characters are placed
+// next to each other at their full width; the kerning and smushing rules
+// of the font are not used.
+func ParseFIGlet(r io.Reader) (*BannerFont, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	line := func() (string, bool) {
+		if !sc.Scan() {
+			return "", false
+		}
+		return strings.TrimRight(sc.Text(), "\r"), true
+	}
+
+	header, _ := line()
+	if !strings.HasPrefix(header, "flf2a") && !strings.HasPrefix(header, "tlf2a") {
+		return nil, ErrInvalidFIGlet
+	}
+	fields := strings.Fields(header[5:])
+	if len(fields) < 6 {
+		return nil, ErrInvalidFIGlet
+	}
+	hardblank := []rune(fields[0])[0]
+	height, err := strconv.Atoi(fields[1])
+	if err != nil || height < 1 {
+		return nil, ErrInvalidFIGlet
+	}
+	comments, err := strconv.Atoi(fields[5])
+	if err != nil || comments < 0 {
+		return nil, ErrInvalidFIGlet
+	}
+	for i := 0; i < comments; i++ {
+		if _, ok := line(); !ok {
+			return nil, ErrInvalidFIGlet
+		}
+	}
+
+	f := &BannerFont{height: height, glyphs: make(map[rune][][]rune)}
+	glyph := func() ([][]rune, bool) {
+		rows := make([][]rune, height)
+		width := 0
+		for i := range rows {
+			s, ok := line()
+			s = strings.TrimRight(s, " \t")
+			if !ok || s == "" {
+				return nil, false
+			}
+			// each line ends with one or more end marks
+			row := []rune(s)
+			end := row[len(row)-1]
+			for len(row) > 0 && row[len(row)-1] == end {
+				row = row[:len(row)-1]
+			}
+			for j, r := range row {
+				if r == hardblank {
+					row[j] = ' '
+				}
+			}
+			rows[i] = row
+			if len(row) > width {
+				width = len(row)
+			}
+		}
+		for i := range rows {
+			for len(rows[i]) < width {
+				rows[i] = append(rows[i], ' ')
+			}
+		}
+		return rows, true
+	}
+
+	// The printable ASCII characters are required, and are followed by
+	// seven German characters; fonts that end without those are accepted.
+	for r := ' '; r <= '~'; r++ {
+		rows, ok := glyph()
+		if !ok {
+			return nil, ErrInvalidFIGlet
+		}
+		f.glyphs[r] = rows
+	}
+	for _, r := range "ÄÖÜäöüß" {
+		rows, ok := glyph()
+		if !ok {
+			return f, nil
+		}
+		f.glyphs[r] = rows
+	}
+
+	// The rest are each introduced by a line with the code of the
+	// character, in decimal, octal or hexadecimal.
+	for {
+		s, ok := line()
+		if !ok {
+			return f, nil
+		}
+		fields := strings.Fields(s)
+		if len(fields) == 0 {
+			continue
+		}
+		code, err := strconv.ParseInt(fields[0], 0, 32)
+		if err != nil {
+			return f, nil
+		}
+		rows, ok := glyph()
+		if !ok {
+			return f, nil
+		}
+		if code >= 0 {
+			f.glyphs[rune(code)] = rows
+		}
+	}
+}
+
+// Height returns the height of the characters of the font, in cells.
+func (f *BannerFont) Height() int {
+	return f.height
+}
+
+// glyph returns the glyph for r.  Characters not in the font are drawn
+// as their best fit in ASCII if possible, and otherwise as the glyph
+// for character zero, if the font has one, as FIGlet does.
+func (f *BannerFont) glyph(r rune) [][]rune {
+	if g, ok := f.glyphs[r]; ok {
+		return g
+	}
+	if s, ok := bestFit(r); ok && len(s) == 1 {
+		if g, ok := f.glyphs[rune(s[0])]; ok {
+			return g
+		}
+	}
+	return f.glyphs[0]
+}
+
+// BannerSize returns the width and height, in cells, of text drawn with
+// the font.  Text may have several lines, separated by newlines.
+func BannerSize(text string, font *BannerFont) (int, int) {
+	width := 0
+	lines := strings.Split(text, "\n")
+	for _, l := range lines {
+		w := 0
+		for _, r := range l {
+			if g := font.glyph(r); len(g) > 0 {
+				w += len(g[0])
+			}
+		}
+		if w > width {
+			width = w
+		}
+	}
+	return width, len(lines) * font.height
+}
+
+// DrawBanner draws text in large characters with the font, with its top
+// left corner at x, y, for splash screens, clocks and the like.  Text may
+// have several lines, separated by newlines.  Every cell of each glyph is
+// drawn with style, including the blank ones, so the background of the
+// style fills the banner.  The width and height drawn are returned, as
+// with BannerSize.
+func DrawBanner(dst CellSetter, x, y int, text string, font *BannerFont, style Style) (int, int) {
+	width := 0
+	lines := strings.Split(text, "\n")
+	for n, l := range lines {
+		col := x
+		for _, r := range l {
+			g := font.glyph(r)
+			for row, cells := range g {
+				for i, c := range cells {
+					dst.SetContent(col+i, y+n*font.height+row, c, nil, style)
+				}
+			}
+			if len(g) > 0 {
+				col += len(g[0])
+			}
+		}
+		if col-x > width {
+			width = col - x
+		}
+	}
+	return width, len(lines) * font.height
+}
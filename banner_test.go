@@ -0,0 +1,57 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBanner(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(20, 6)
+	if w, h := DrawBanner(s, 0, 0, "1", BannerHalf, StyleDefault); w != 6 || h != 5 {
+		t.Errorf("wrong size: %d x %d", w, h)
+	}
+	s.Show()
+	want := []string{" ▄█   ", "  █   ", "  █   ", " ▀▀▀  ", "      "}
+	for y, line := range want {
+		if got := simRow(s, 0, y, 6); got != line {
+			t.Errorf("wrong row %d: %q", y, got)
+		}
+	}
+
+	font := "flf2a$ 2 2 4 0 1\ncomment\n" +
+		strings.Repeat(" @\n @@\n", 'A'-' ') +
+		"/\\@\n$$@@\n" +
+		strings.Repeat(" @\n @@\n", '~'-'A'+7) +
+		"0x263A smiley\n:)@\n$ @@\n"
+	f, err := ParseFIGlet(strings.NewReader(font))
+	if err != nil {
+		t.Fatalf("cannot parse font: %v", err)
+	}
+	if w, h := BannerSize("A☺\nA", f); w != 4 || h != 4 {
+		t.Errorf("wrong size: %d x %d", w, h)
+	}
+	DrawBanner(s, 10, 0, "A☺", f, StyleDefault)
+	s.Show()
+	if got := simRow(s, 10, 0, 4) + "|" + simRow(s, 10, 1, 4); got != "/\\:)|    " {
+		t.Errorf("wrong banner: %q", got)
+	}
+	if _, err := ParseFIGlet(strings.NewReader("hello")); err != ErrInvalidFIGlet {
+		t.Errorf("parsed an invalid font: %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"time"
+)
+
+// BellPolicy determines what Beep does.
+type BellPolicy int
+
+const (
+	BellAudible BellPolicy = iota // sound the terminal's bell (the default)
+	BellVisual                    // flash the screen, or part of it
+	BellNone                      // do nothing
+)
+
+// defaultBellDuration is how long the visual bell flashes for.
+const defaultBellDuration = 100 * time.Millisecond
+
+// visualBell flashes the screen, or a region of it, by reversing the
+// video of the cells there while a timer runs.  Terminals have a visual
+// bell of their own (the flash capability) but it cannot be limited to a
+// region, nor its duration changed, and few terminals describe it.
+type visualBell struct {
+	policy     BellPolicy
+	d          time.Duration
+	x, y, w, h int // w or h <= 0 for the whole screen
+	on         bool
+	timer      *time.Timer
+}
+
+func (vb *visualBell) set(d time.Duration, x, y, w, h int) {
+	if d <= 0 {
+		d = defaultBellDuration
+	}
+	vb.d = d
+	vb.x, vb.y, vb.w, vb.h = x, y, w, h
+}
+
+func (vb *visualBell) contains(x, y int) bool {
+	if vb.w <= 0 || vb.h <= 0 {
+		return true
+	}
+	return x >= vb.x && x < vb.x+vb.w && y >= vb.y && y < vb.y+vb.h
+}
+
+// apply returns the style for a cell, reversed while the bell flashes.
+func (vb *visualBell) apply(x, y int, style Style) Style {
+	if !vb.on || !vb.contains(x, y) {
+		return style
+	}
+	return style.Reverse(style.attrs&AttrReverse == 0)
+}
+
+// start turns the flash on, arranging for done to be called when it
+// should be turned off.
+func (vb *visualBell) start(done func()) {
+	vb.on = true
+	if vb.d <= 0 {
+		vb.d = defaultBellDuration
+	}
+	if vb.timer != nil {
+		vb.timer.Stop()
+	}
+	vb.timer = time.AfterFunc(vb.d, done)
+}
+
+// stop turns the flash off.
+func (vb *visualBell) stop() {
+	if vb.timer != nil {
+		vb.timer.Stop()
+		vb.timer = nil
+	}
+	vb.on = false
+}
+
+func (t *tScreen) SetBellPolicy(policy BellPolicy) {
+	t.Lock()
+	t.bell.policy = policy
+	t.Unlock()
+}
+
+func (t *tScreen) SetVisualBell(d time.Duration, x, y, w, h int) {
+	t.Lock()
+	t.bell.set(d, x, y, w, h)
+	t.Unlock()
+}
+
+// Beep rings the bell, as determined by the bell policy.
+func (t *tScreen) Beep() error {
+	t.Lock()
+	defer t.Unlock()
+	switch t.bell.policy {
+	case BellAudible:
+		t.writeString(string(byte(7)))
+	case BellVisual:
+		if t.fini || !t.running {
+			return nil
+		}
+		t.bell.start(t.bellDone)
+		t.drawOnly(t.bell.contains)
+	}
+	return nil
+}
+
+// bellDone ends the flash of the visual bell.
+func (t *tScreen) bellDone() {
+	t.Lock()
+	defer t.Unlock()
+	t.bell.stop()
+	if t.fini || !t.running {
+		return
+	}
+	t.drawOnly(t.bell.contains)
+}
@@ -0,0 +1,204 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bench provides repeatable drawing workloads for measuring the
+// performance of tcell screens.  The workloads can be run against a
+// SimulationScreen, or against a terminfo screen whose output is thrown
+// away, which measures the cost of encoding the output as well.
+// The benchmarks in this package run every workload on both; use
+//
+//	go test -bench . github.com/gdamore/tcell/v2/bench
+//
+// and compare the results between releases (with benchstat, for
+// example) to find performance regressions.
+package bench
+
+import (
+	"io"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// A Workload draws one frame of some kind of update onto a screen, and
+// then shows it.  The frame number lets each frame differ from the last,
+// in a way that is the same every time the workload is run.
+type Workload struct {
+	Name  string
+	Frame func(s tcell.Screen, frame int)
+}
+
+// Workloads are the standard workloads.
+var Workloads = []Workload{
+	{"FullRepaint", FullRepaint},
+	{"Scroll", Scroll},
+	{"SparseUpdate", SparseUpdate},
+	{"ColorChurn", ColorChurn},
+}
+
+// Run draws the given number of frames of the workload.
+func Run(s tcell.Screen, w Workload, frames int) {
+	for i := 0; i < frames; i++ {
+		w.Frame(s, i)
+	}
+}
+
+// FullRepaint changes every cell on every frame.
+func FullRepaint(s tcell.Screen, frame int) {
+	w, h := s.Size()
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r := rune('!' + (x+y+frame)%94)
+			s.SetContent(x, y, r, nil, tcell.StyleDefault)
+		}
+	}
+	s.Show()
+}
+
+// Scroll moves everything up by one row, and draws a new bottom row,
+// as a log viewer would.
+func Scroll(s tcell.Screen, frame int) {
+	w, h := s.Size()
+	s.Scroll(0, 0, w, h, 1)
+	for x := 0; x < w; x++ {
+		r := rune('a' + (x+frame)%26)
+		if (x+frame)%7 == 0 {
+			r = ' '
+		}
+		s.SetContent(x, h-1, r, nil, tcell.StyleDefault)
+	}
+	s.Show()
+}
+
+// SparseUpdate changes a few cells scattered across the screen, as a
+// clock or a progress display would.
+func SparseUpdate(s tcell.Screen, frame int) {
+	w, h := s.Size()
+	if w == 0 || h == 0 {
+		return
+	}
+	seed := uint32(frame)*2654435761 + 1
+	n := w * h / 100
+	if n < 1 {
+		n = 1
+	}
+	for i := 0; i < n; i++ {
+		// a simple linear congruential generator, for repeatability
+		seed = seed*1664525 + 1013904223
+		pos := int(seed>>8) % (w * h)
+		s.SetContent(pos%w, pos/w, rune('0'+i%10), nil, tcell.StyleDefault.Bold(i%2 == 0))
+	}
+	s.Show()
+}
+
+// ColorChurn keeps the text the same, but changes the colors of every
+// cell on every frame, using the full RGB range.
+func ColorChurn(s tcell.Screen, frame int) {
+	w, h := s.Size()
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := int32((x*7 + y*13 + frame*29) & 0xffffff)
+			st := tcell.StyleDefault.
+				Foreground(tcell.NewHexColor(c)).
+				Background(tcell.NewHexColor(c ^ 0xffffff))
+			s.SetContent(x, y, '#', nil, st)
+		}
+	}
+	s.Show()
+}
+
+// NewSimulationScreen returns an initialized SimulationScreen of the
+// given size.
+func NewSimulationScreen(w, h int) (tcell.Screen, error) {
+	s := tcell.NewSimulationScreen("UTF-8")
+	if err := s.Init(); err != nil {
+		return nil, err
+	}
+	s.SetSize(w, h)
+	return s, nil
+}
+
+// NewNullScreen returns an initialized terminfo screen, for the named
+// terminal type, whose output is discarded.  It never receives any input.
+func NewNullScreen(term string, w, h int) (tcell.Screen, error) {
+	ti, err := tcell.LookupTerminfo(term)
+	if err != nil {
+		return nil, err
+	}
+	s, err := tcell.NewTerminfoScreenFromTtyTerminfo(newNullTty(w, h), ti)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.Init(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// nullTty is a Tty that discards output, and whose reads block until
+// it is drained.
+type nullTty struct {
+	w, h  int
+	stopQ chan struct{}
+	sync.Mutex
+}
+
+func newNullTty(w, h int) *nullTty {
+	return &nullTty{w: w, h: h}
+}
+
+func (n *nullTty) Start() error {
+	n.Lock()
+	n.stopQ = make(chan struct{})
+	n.Unlock()
+	return nil
+}
+
+func (n *nullTty) Drain() error {
+	n.Lock()
+	if n.stopQ != nil {
+		close(n.stopQ)
+		n.stopQ = nil
+	}
+	n.Unlock()
+	return nil
+}
+
+func (n *nullTty) Stop() error {
+	return n.Drain()
+}
+
+func (n *nullTty) Read(b []byte) (int, error) {
+	n.Lock()
+	q := n.stopQ
+	n.Unlock()
+	if q != nil {
+		<-q
+	}
+	return 0, io.EOF
+}
+
+func (n *nullTty) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (n *nullTty) Close() error {
+	return nil
+}
+
+func (n *nullTty) NotifyResize(func()) {}
+
+func (n *nullTty) WindowSize() (int, int, error) {
+	return n.w, n.h, nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bench
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+type screenMaker struct {
+	name string
+	make func() (tcell.Screen, error)
+}
+
+var screens = []screenMaker{
+	{"Sim", func() (tcell.Screen, error) { return NewSimulationScreen(80, 25) }},
+	{"Xterm", func() (tcell.Screen, error) { return NewNullScreen("xterm-256color", 80, 25) }},
+}
+
+// TestWorkloads is a quick stress test, making sure that every workload
+// runs to completion on every screen.
+func TestWorkloads(t *testing.T) {
+	for _, sm := range screens {
+		s, err := sm.make()
+		if err != nil {
+			t.Fatalf("%s: %v", sm.name, err)
+		}
+		for _, w := range Workloads {
+			Run(s, w, 50)
+		}
+		s.Fini()
+	}
+}
+
+func BenchmarkWorkloads(b *testing.B) {
+	for _, sm := range screens {
+		for _, w := range Workloads {
+			b.Run(sm.name+"/"+w.Name, func(b *testing.B) {
+				s, err := sm.make()
+				if err != nil {
+					b.Fatalf("%v", err)
+				}
+				defer s.Fini()
+				b.ResetTimer()
+				Run(s, w, b.N)
+			})
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// When the terminal uses a legacy character set, many characters simply
+// cannot be represented.  Rather than showing '?' for these, we try a
+// "best fit" approximation, in the same spirit as the best fit tables that
+// Windows uses for its code pages: accented Latin letters lose their
+// accents, and typographic punctuation becomes its plain ASCII cousin.
+// This is only used when the character set cannot encode the character,
+// and neither the terminal's alternate character set nor a registered
+// rune fallback provides something better.
+
+// bestFitLatin covers U+00C0 through U+017F (Latin-1 Supplement letters
+// and Latin Extended-A), indexed from U+00C0.
+var bestFitLatin = [...]string{
+	// U+00C0
+	"A", "A", "A", "A", "A", "A", "A", "C",
+	"E", "E", "E", "E", "I", "I", "I", "I",
+	"D", "N", "O", "O", "O", "O", "O", "x",
+	"O", "U", "U", "U", "U", "Y", "T", "s",
+	"a", "a", "a", "a", "a", "a", "a", "c",
+	"e", "e", "e", "e", "i", "i", "i", "i",
+	"d", "n", "o", "o", "o", "o", "o", "/",
+	"o", "u", "u", "u", "u", "y", "t", "y",
+	// U+0100
+	"A", "a", "A", "a", "A", "a", "C", "c",
+	"C", "c", "C", "c", "C", "c", "D", "d",
+	"D", "d", "E", "e", "E", "e", "E", "e",
+	"E", "e", "E", "e", "G", "g", "G", "g",
+	"G", "g", "G", "g", "H", "h", "H", "h",
+	"I", "i", "I", "i", "I", "i", "I", "i",
+	"I", "i", "I", "i", "J", "j", "K", "k",
+	"k", "L", "l", "L", "l", "L", "l", "L",
+	"l", "L", "l", "N", "n", "N", "n", "N",
+	"n", "n", "N", "n", "O", "o", "O", "o",
+	"O", "o", "O", "o", "R", "r", "R", "r",
+	"R", "r", "S", "s", "S", "s", "S", "s",
+	"S", "s", "T", "t", "T", "t", "T", "t",
+	"U", "u", "U", "u", "U", "u", "U", "u",
+	"U", "u", "U", "u", "W", "w", "Y", "y",
+	"Y", "Z", "z", "Z", "z", "Z", "z", "s",
+}
+
+// bestFitOther covers punctuation and symbols that have obvious ASCII
+// approximations.  Like the letters, each is a single character, as a
+// replacement must take up as many cells as the rune that it replaces.
+var bestFitOther = map[rune]string{
+	'\u00a0': " ", // no-break space
+	'¦':      "|", // broken bar
+	'©':      "c", // copyright
+	'«':      "<", // left guillemet
+	'\u00ad': "-", // soft hyphen
+	'®':      "R", // registered
+	'´':      "'", // acute accent
+	'µ':      "u", // micro
+	'·':      ".", // middle dot
+	'¸':      ",", // cedilla
+	'»':      ">", // right guillemet
+	'‐':      "-", // hyphen
+	'‑':      "-", // non-breaking hyphen
+	'‒':      "-", // figure dash
+	'–':      "-", // en dash
+	'—':      "-", // em dash
+	'―':      "-", // horizontal bar
+	'‘':      "'",
+	'’':      "'",
+	'‚':      ",",
+	'‛':      "'",
+	'“':      "\"",
+	'”':      "\"",
+	'„':      ",",
+	'‟':      "\"",
+	'•':      "*",  // bullet
+	'…':      ".",  // ellipsis
+	'′':      "'",  // prime
+	'″':      "\"", // double prime
+	'‹':      "<",
+	'›':      ">",
+	'⁄':      "/", // fraction slash
+	'€':      "E",
+	'−':      "-", // minus
+	'∕':      "/", // division slash
+	'∗':      "*", // asterisk operator
+}
+
+// bestFit returns an approximation of r, as a single ASCII character, if
+// there is one.
+func bestFit(r rune) (string, bool) {
+	if r >= 0xc0 && r < 0xc0+rune(len(bestFitLatin)) {
+		return bestFitLatin[r-0xc0], true
+	}
+	if s, ok := bestFitOther[r]; ok {
+		return s, true
+	}
+	// Fullwidth ASCII variants map directly to ASCII.
+	if r >= 0xff01 && r <= 0xff5e {
+		return string(r - 0xff01 + '!'), true
+	}
+	return "", false
+}
@@ -0,0 +1,32 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestBestFitSim(t *testing.T) {
+	s := mkTestScreen(t, "US-ASCII")
+	defer s.Fini()
+
+	s.SetContent(0, 0, 'é', nil, StyleDefault)
+	s.SetContent(1, 0, '…', nil, StyleDefault)
+	s.Show()
+	b, _, _ := s.GetContents()
+	if string(b[0].Bytes) != "e" || string(b[1].Bytes) != "." {
+		t.Errorf("wrong best fit output: %q %q", b[0].Bytes, b[1].Bytes)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"time"
+)
+
+// Blinking text is sent with a single blink attribute for each run of
+// cells with the same style, since the attributes are only sent when the
+// style changes.  Not every terminal blinks, however; some have no blink
+// capability at all, and others (notably many VTE based terminals) accept
+// it and ignore it.  For these we can blink in software instead: a timer
+// alternately blanks and redraws the blinking cells.
+//
+// Software blinking is used when terminfo has no blink capability, and
+// can be forced on or off by setting TCELL_SOFTBLINK to "enable" or
+// "disable".
+
+// softBlinkInterval is the time that blinking text is shown, and the
+// time that it is hidden.
+const softBlinkInterval = 500 * time.Millisecond
+
+// prepareSoftBlink decides whether to blink in software.
+func (t *tScreen) prepareSoftBlink() {
+	t.softBlink = t.ti.Blink == ""
+	switch t.getenv("TCELL_SOFTBLINK") {
+	case "enable":
+		t.softBlink = true
+	case "disable":
+		t.softBlink = false
+	}
+}
+
+// blinkStyle adjusts a cell that is about to be drawn for software
+// blinking.  It returns the style to use, and true if the cell should be
+// drawn blank.  It starts the blink timer if it is not already running.
+func (t *tScreen) blinkStyle(style Style) (Style, bool) {
+	if !t.softBlink || style.attrs&AttrBlink == 0 {
+		return style, false
+	}
+	if t.blinker == nil {
+		t.blinker = time.AfterFunc(softBlinkInterval, t.blinkTick)
+	}
+	return style.Blink(false), t.blinkOff
+}
+
+// blinkTick toggles the blinking cells, and redraws them.  The timer
+// stops once there is nothing left to blink.
+func (t *tScreen) blinkTick() {
+	t.Lock()
+	defer t.Unlock()
+	t.blinker = nil
+	if t.fini || !t.running {
+		t.blinkOff = false
+		return
+	}
+	t.blinkOff = !t.blinkOff
+	if t.drawBlink() == 0 {
+		t.blinkOff = false
+	}
+}
+
+// stopBlink stops the blink timer, leaving blinking text visible.
+func (t *tScreen) stopBlink() {
+	if t.blinker != nil {
+		t.blinker.Stop()
+		t.blinker = nil
+	}
+	t.blinkOff = false
+}
+
+// drawBlink redraws the cells with the blink attribute (see drawOnly),
+// and returns the number of them.  Cells with StyleDefault use the
+// screen's style.
+func (t *tScreen) drawBlink() int {
+	return t.drawOnly(func(x, y int) bool {
+		_, _, style, _ := t.cells.GetContent(x, y)
+		if style == StyleDefault {
+			style = t.style
+		}
+		return style.attrs&AttrBlink != 0
+	})
+}
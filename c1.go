@@ -0,0 +1,93 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strings"
+)
+
+// Genuine DEC terminals (and emulators configured to behave like them)
+// can use 8-bit C1 control characters, such as 0x9B for CSI, in place of
+// the two byte 7-bit escape sequences (ESC [).  This saves bandwidth on
+// slow serial lines.  This is only possible when the character set leaves
+// the range 0x80 - 0x9F free for controls, as the ISO 8859 family does.
+// It cannot be used with UTF-8, or with multibyte encodings such as
+// Shift-JIS, which use those bytes for characters.
+//
+// Terminfo has no capability that says whether a terminal accepts 8-bit
+// controls, and terminals that can use them are usually set not to, so
+// they are only used if TCELL_C1 is set to "enable", in the environment
+// or as a quirk.
+
+const (
+	s7c1t = "\x1b F" // ask the terminal to send 7-bit controls
+	s8c1t = "\x1b G" // ask the terminal to send 8-bit controls
+)
+
+// prepareC1 decides whether to use 8-bit controls.  It must be called
+// after the character set is known.
+func (t *tScreen) prepareC1() {
+	c1 := t.getenv("TCELL_C1") == "enable"
+	// Only the ISO 8859 family (and plain ASCII) leave the C1 range
+	// unused; everything else, including the DOS code pages, puts
+	// characters there.
+	cs := strings.ToUpper(t.charset)
+	if !strings.Contains(cs, "8859") && cs != "US-ASCII" {
+		c1 = false
+	}
+	t.c1 = c1
+}
+
+// toC1 converts 7-bit escape sequences in s to their 8-bit equivalents.
+// Only ESC followed by a byte in the range 0x40 - 0x5F has an 8-bit form;
+// other escapes (such as ESC =, or ESC SP F) are left alone.
+func toC1(s string) string {
+	if strings.IndexByte(s, '\x1b') < 0 {
+		return s
+	}
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' && i+1 < len(s) && s[i+1] >= 0x40 && s[i+1] <= 0x5f {
+			b = append(b, s[i+1]+0x40)
+			i++
+			continue
+		}
+		b = append(b, s[i])
+	}
+	return string(b)
+}
+
+// fromC1 converts 8-bit C1 controls in input to their 7-bit equivalents,
+// so that the rest of the input decoder only needs to deal with one form.
+func fromC1(in []byte) []byte {
+	n := 0
+	for _, c := range in {
+		if c >= 0x80 && c <= 0x9f {
+			n++
+		}
+	}
+	if n == 0 {
+		return in
+	}
+	out := make([]byte, 0, len(in)+n)
+	for _, c := range in {
+		if c >= 0x80 && c <= 0x9f {
+			out = append(out, '\x1b', c-0x40)
+		} else {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,31 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestC1(t *testing.T) {
+	if s := toC1("\x1b[1;2H\x1b=\x1b]0;x\x1b\\"); s != "\x9b1;2H\x1b=\x9d0;x\x9c" {
+		t.Errorf("bad C1 output: %q", s)
+	}
+	if s := toC1(s7c1t); s != s7c1t {
+		t.Errorf("S7C1T should not be converted: %q", s)
+	}
+	if b := fromC1([]byte("a\x9bA\x8fP")); string(b) != "a\x1b[A\x1bOP" {
+		t.Errorf("bad C1 input: %q", b)
+	}
+}
@@ -38,13 +38,20 @@ type CellBuffer struct {
 	w     int
 	h     int
 	cells []cell
+	tabs  []int
+	wraps []bool // rows that continue on the next, for reflow
 }
 
 // SetContent sets the contents (primary rune, combining runes,
-// and style) for a cell at a given location.
+// and style) for a cell at a given location.  A tab character ('\t')
+// is expanded into blanks up to the next tab stop.
 func (cb *CellBuffer) SetContent(x int, y int,
 	mainc rune, combc []rune, style Style) {
 
+	if mainc == '\t' {
+		cb.setTab(x, y, style)
+		return
+	}
 	if x >= 0 && y >= 0 && x < cb.w && y < cb.h {
 		c := &cb.cells[(y*cb.w)+x]
 
@@ -161,6 +168,9 @@ func (cb *CellBuffer) Resize(w, h int) {
 	cb.cells = newc
 	cb.h = h
 	cb.w = w
+	if len(cb.wraps) > h {
+		cb.wraps = cb.wraps[:h]
+	}
 }
 
 // Fill fills the entire cell buffer array with the specified character
@@ -174,4 +184,5 @@ func (cb *CellBuffer) Fill(r rune, style Style) {
 		c.currStyle = style
 		c.width = 1
 	}
+	cb.wraps = nil
 }
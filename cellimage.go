@@ -0,0 +1,109 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"image"
+	"image/color"
+)
+
+// CellGetSetter is anything that cells can be both drawn into and read
+// back from.  Both Screen and CellBuffer are CellGetSetters.
+type CellGetSetter interface {
+	CellSetter
+	GetContent(x, y int) (mainc rune, combc []rune, style Style, width int)
+}
+
+// CellImage is an image (a draw.Image) made of a region of cells, so that
+// the image packages, and anything else that draws images, can draw on a
+// screen.  Each cell holds two pixels, one above the other, drawn as an
+// upper half block with the upper pixel as the foreground color and the
+// lower as the background.  So the image is as wide as the region, and
+// twice as high.
+//
+// Pixels that are fully transparent are the default color; otherwise the
+// alpha is ignored, so images should be composited first if need be.
+// Cells that hold anything other than blocks read as their background.
+type CellImage struct {
+	dst  CellGetSetter
+	x, y int
+	w, h int
+}
+
+// NewCellImage returns a CellImage for the region of dst with its top
+// left corner at x, y, and the given width and height in cells.
+func NewCellImage(dst CellGetSetter, x, y, width, height int) *CellImage {
+	return &CellImage{dst: dst, x: x, y: y, w: width, h: height}
+}
+
+// ColorModel implements image.Image.
+func (ci *CellImage) ColorModel() color.Model {
+	return color.RGBAModel
+}
+
+// Bounds implements image.Image.
+func (ci *CellImage) Bounds() image.Rectangle {
+	return image.Rect(0, 0, ci.w, ci.h*2)
+}
+
+// pixels returns the colors of the two pixels of a cell.
+func (ci *CellImage) pixels(x, y int) (Color, Color) {
+	mainc, _, st, _ := ci.dst.GetContent(ci.x+x, ci.y+y)
+	fg, bg, _ := st.Decompose()
+	switch mainc {
+	case '▀':
+		return fg, bg
+	case '▄':
+		return bg, fg
+	case '█':
+		return fg, fg
+	}
+	return bg, bg
+}
+
+// At implements image.Image.
+func (ci *CellImage) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(ci.Bounds())) {
+		return color.RGBA{}
+	}
+	top, bottom := ci.pixels(x, y/2)
+	c := top
+	if y%2 == 1 {
+		c = bottom
+	}
+	r, g, b := c.RGB()
+	if r < 0 {
+		return color.RGBA{}
+	}
+	return color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: 0xff}
+}
+
+// Set implements draw.Image.
+func (ci *CellImage) Set(x, y int, c color.Color) {
+	if !(image.Point{x, y}.In(ci.Bounds())) {
+		return
+	}
+	tc := ColorDefault
+	if _, _, _, a := c.RGBA(); a != 0 {
+		tc = FromImageColor(c)
+	}
+	top, bottom := ci.pixels(x, y/2)
+	if y%2 == 0 {
+		top = tc
+	} else {
+		bottom = tc
+	}
+	ci.dst.SetContent(ci.x+x, ci.y+y/2, '▀', nil, StyleDefault.Foreground(top).Background(bottom))
+}
@@ -0,0 +1,46 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestCellImage(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(10, 4)
+	img := NewCellImage(s, 2, 1, 4, 2)
+	if b := img.Bounds(); b.Dx() != 4 || b.Dy() != 4 {
+		t.Fatalf("wrong bounds: %v", b)
+	}
+	red := color.RGBA{R: 0xff, A: 0xff}
+	blue := color.RGBA{B: 0xff, A: 0xff}
+	draw.Draw(img, image.Rect(0, 0, 4, 4), image.NewUniform(red), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 1, 2, 2), image.NewUniform(blue), image.Point{}, draw.Src)
+	s.Show()
+
+	cells, w, _ := s.GetContents()
+	c := cells[w+2]
+	if c.Runes[0] != '▀' || c.Style != StyleDefault.Foreground(NewRGBColor(0xff, 0, 0)).Background(NewRGBColor(0, 0, 0xff)) {
+		t.Errorf("wrong cell: %v", c)
+	}
+	if img.At(1, 1) != blue || img.At(1, 2) != red || img.At(9, 9) != (color.RGBA{}) {
+		t.Errorf("wrong pixels")
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// CellChangeHandler is called with the position of a cell that was
+// redrawn, as registered with OnCellChange.
+type CellChangeHandler func(x, y int)
+
+type cellWatcher struct {
+	x, y, w, h int
+	f          CellChangeHandler
+}
+
+type cellChange struct {
+	cw   *cellWatcher
+	x, y int
+}
+
+// cellChanges tracks the functions registered with OnCellChange, and the
+// changes found when drawing, which are reported once the screen is no
+// longer locked.  The screen can be drawn several times before they are
+// reported (by Show or Sync), so each cell is noted only once for each
+// function.
+type cellChanges struct {
+	watchers []*cellWatcher
+	pending  []cellChange
+	noted    map[cellChange]bool
+}
+
+func (cc *cellChanges) add(x, y, w, h int, f CellChangeHandler) *cellWatcher {
+	cw := &cellWatcher{x: x, y: y, w: w, h: h, f: f}
+	cc.watchers = append(cc.watchers, cw)
+	return cw
+}
+
+func (cc *cellChanges) remove(cw *cellWatcher) {
+	for i, other := range cc.watchers {
+		if other == cw {
+			cc.watchers = append(cc.watchers[:i:i], cc.watchers[i+1:]...)
+			return
+		}
+	}
+}
+
+// collect notes the cells that are about to be drawn.  It must be called
+// before they are drawn, while they are still dirty.
+func (cc *cellChanges) collect(cb *CellBuffer) {
+	bw, bh := cb.Size()
+	for _, cw := range cc.watchers {
+		for y := cw.y; y < cw.y+cw.h && y < bh; y++ {
+			for x := cw.x; x < cw.x+cw.w && x < bw; x++ {
+				c := cellChange{cw: cw, x: x, y: y}
+				if x >= 0 && y >= 0 && cb.Dirty(x, y) && !cc.noted[c] {
+					if cc.noted == nil {
+						cc.noted = make(map[cellChange]bool)
+					}
+					cc.noted[c] = true
+					cc.pending = append(cc.pending, c)
+				}
+			}
+		}
+	}
+}
+
+// take returns the changes collected, and forgets them.
+func (cc *cellChanges) take() []cellChange {
+	changes := cc.pending
+	cc.pending = nil
+	cc.noted = nil
+	return changes
+}
+
+// reportChanges calls the functions for the changes; the screen must not
+// be locked, so that they can use it.
+func reportChanges(changes []cellChange) {
+	for _, c := range changes {
+		c.cw.f(c.x, c.y)
+	}
+}
+
+func (t *tScreen) OnCellChange(x, y, width, height int, f CellChangeHandler) func() {
+	t.Lock()
+	cw := t.changes.add(x, y, width, height, f)
+	t.Unlock()
+	return func() {
+		t.Lock()
+		t.changes.remove(cw)
+		t.Unlock()
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCellChange(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(10, 4)
+	s.Show()
+
+	var changed []string
+	cancel := s.OnCellChange(2, 1, 3, 2, func(x, y int) {
+		r, _, _, _ := s.GetContent(x, y)
+		changed = append(changed, fmt.Sprintf("%d,%d:%c", x, y, r))
+	})
+	s.SetContent(0, 1, 'a', nil, StyleDefault)
+	s.SetContent(3, 1, 'b', nil, StyleDefault)
+	s.SetContent(2, 2, 'c', nil, StyleDefault)
+	s.Show()
+	if got := strings.Join(changed, " "); got != "3,1:b 2,2:c" {
+		t.Errorf("wrong changes: %q", got)
+	}
+	changed = nil
+	s.Show()
+	if len(changed) != 0 {
+		t.Errorf("unchanged cells reported: %v", changed)
+	}
+	s.Sync()
+	if len(changed) != 6 {
+		t.Errorf("wrong changes after sync: %v", changed)
+	}
+	changed = nil
+	cancel()
+	s.SetContent(3, 1, 'd', nil, StyleDefault)
+	s.Show()
+	if len(changed) != 0 {
+		t.Errorf("changes reported after cancel: %v", changed)
+	}
+}
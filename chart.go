@@ -0,0 +1,236 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"math"
+	"strconv"
+
+	runewidth "github.com/mattn/go-runewidth"
+)
+
+// These are primitives for drawing charts, such as those of monitoring
+// dashboards, into a region of cells.  They draw with the block elements,
+// in eighths of a cell, and so need a font that has them.  Values are
+// scaled from lo to hi; for those that draw several values, if lo is not
+// less than hi, the range of the values is used instead.  Values that are
+// not numbers (NaN) are left blank.
+
+var (
+	chartUp    = []rune(" ▁▂▃▄▅▆▇█") // from the bottom, in eighths
+	chartRight = []rune(" ▏▎▍▌▋▊▉█") // from the left, in eighths
+)
+
+// chartRange returns lo and hi, or else the range of values.
+func chartRange(values []float64, lo, hi float64) (float64, float64) {
+	if lo < hi {
+		return lo, hi
+	}
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+	}
+	return lo, hi
+}
+
+// chartFrac returns where v is from lo to hi, from 0 to 1.
+func chartFrac(v, lo, hi float64) float64 {
+	if hi <= lo {
+		return 0
+	}
+	return math.Max(0, math.Min(1, (v-lo)/(hi-lo)))
+}
+
+// chartEighths returns the length of a bar for v, in eighths of n cells.
+func chartEighths(v, lo, hi float64, n int) int {
+	return int(math.Round(chartFrac(v, lo, hi) * float64(n*8)))
+}
+
+// DrawSparkline draws values as a sparkline, a row of bars one cell high,
+// starting at x, y.  If there are more values than fit in width, only the
+// last (most recent) are drawn.  The lowest value is still drawn, as the
+// shortest bar, so that it can be seen.
+func DrawSparkline(dst CellSetter, x, y, width int, values []float64, lo, hi float64, style Style) {
+	if width <= 0 {
+		return
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	lo, hi = chartRange(values, lo, hi)
+	for i := 0; i < width; i++ {
+		r := ' '
+		if i < len(values) && !math.IsNaN(values[i]) {
+			n := 8
+			if hi > lo {
+				n = 1 + int(math.Round(chartFrac(values[i], lo, hi)*7))
+			}
+			r = chartUp[n]
+		}
+		dst.SetContent(x+i, y, r, nil, style)
+	}
+}
+
+// DrawHBar draws a horizontal bar for v, from x, y, up to width cells long.
+func DrawHBar(dst CellSetter, x, y, width int, v, lo, hi float64, style Style) {
+	n := 0
+	if !math.IsNaN(v) {
+		n = chartEighths(v, lo, hi, width)
+	}
+	for i := 0; i < width; i++ {
+		part := n - i*8
+		if part > 8 {
+			part = 8
+		} else if part < 0 {
+			part = 0
+		}
+		dst.SetContent(x+i, y, chartRight[part], nil, style)
+	}
+}
+
+// DrawVBar draws a vertical bar for v, rising from the bottom of the
+// column of height cells whose top is at x, y.
+func DrawVBar(dst CellSetter, x, y, height int, v, lo, hi float64, style Style) {
+	n := 0
+	if !math.IsNaN(v) {
+		n = chartEighths(v, lo, hi, height)
+	}
+	for i := 0; i < height; i++ {
+		part := n - i*8
+		if part > 8 {
+			part = 8
+		} else if part < 0 {
+			part = 0
+		}
+		dst.SetContent(x, y+height-1-i, chartUp[part], nil, style)
+	}
+}
+
+// DrawBarChart draws values as a chart of vertical bars, one column each,
+// in the region at x, y of the given width and height.  As with
+// DrawSparkline, only the last values are drawn if they do not all fit.
+// Bars rise from lo, which if the range of the values is used, is zero
+// (or the least value, if that is negative).
+func DrawBarChart(dst CellSetter, x, y, width, height int, values []float64, lo, hi float64, style Style) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if lo >= hi {
+		lo, hi = chartRange(values, lo, hi)
+		lo = math.Min(lo, 0)
+	}
+	for i := 0; i < width; i++ {
+		v := math.NaN()
+		if i < len(values) {
+			v = values[i]
+		}
+		DrawVBar(dst, x+i, y, height, v, lo, hi, style)
+	}
+}
+
+// chartTicks returns n values evenly spaced from lo to hi, with the
+// positions for them in a length of cells.
+func chartTicks(lo, hi float64, n, length int) ([]float64, []int) {
+	if n < 2 {
+		n = 2
+	}
+	if n > length {
+		n = length
+	}
+	if n == 1 {
+		// only room for one, at the start
+		return []float64{lo}, []int{0}
+	}
+	var vals []float64
+	var pos []int
+	for i := 0; i < n; i++ {
+		vals = append(vals, lo+(hi-lo)*float64(i)/float64(n-1))
+		pos = append(pos, int(math.Round(float64(length-1)*float64(i)/float64(n-1))))
+	}
+	return vals, pos
+}
+
+// chartLabel formats a tick value.
+func chartLabel(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// DrawHAxis draws a horizontal axis from lo (at the left) to hi, with
+// ticks at n values evenly spaced.  The axis is drawn in the row y, from
+// x for width cells, and the labels of the ticks in the row below.
+// Labels that would overlap the one before are left out.
+func DrawHAxis(dst CellSetter, x, y, width int, lo, hi float64, n int, style Style) {
+	if width <= 0 {
+		return
+	}
+	for i := 0; i < width; i++ {
+		dst.SetContent(x+i, y, RuneHLine, nil, style)
+		dst.SetContent(x+i, y+1, ' ', nil, style)
+	}
+	vals, pos := chartTicks(lo, hi, n, width)
+	next := 0
+	for i, p := range pos {
+		dst.SetContent(x+p, y, '┬', nil, style)
+		label := chartLabel(vals[i])
+		w := runewidth.StringWidth(label)
+		start := p - w/2
+		if start+w > width {
+			start = width - w
+		}
+		if start < next || start < 0 {
+			continue
+		}
+		chartText(dst, x+start, y+1, label, style)
+		next = start + w + 1
+	}
+}
+
+// DrawVAxis draws a vertical axis from lo (at the bottom) to hi, with
+// ticks at n values evenly spaced.  The axis is drawn in the last column
+// of the region at x, y of the given width and height, and the labels of
+// the ticks to the left of it, aligned to the right.
+func DrawVAxis(dst CellSetter, x, y, width, height int, lo, hi float64, n int, style Style) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	for row := 0; row < height; row++ {
+		for col := 0; col < width-1; col++ {
+			dst.SetContent(x+col, y+row, ' ', nil, style)
+		}
+		dst.SetContent(x+width-1, y+row, RuneVLine, nil, style)
+	}
+	vals, pos := chartTicks(lo, hi, n, height)
+	for i, p := range pos {
+		row := y + height - 1 - p
+		dst.SetContent(x+width-1, row, '┤', nil, style)
+		label := chartLabel(vals[i])
+		if w := runewidth.StringWidth(label); w <= width-1 {
+			chartText(dst, x+width-1-w, row, label, style)
+		}
+	}
+}
+
+func chartText(dst CellSetter, x, y int, text string, style Style) {
+	for _, r := range text {
+		dst.SetContent(x, y, r, nil, style)
+		x += runewidth.RuneWidth(r)
+	}
+}
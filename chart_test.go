@@ -0,0 +1,61 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCharts(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(20, 6)
+	DrawSparkline(s, 0, 0, 4, []float64{9, 0, 1, 4, 8}, 0, 0, StyleDefault)
+	s.Show()
+	if got := simRow(s, 0, 0, 4); got != "▁▂▅█" {
+		t.Errorf("wrong sparkline: %q", got)
+	}
+	DrawHBar(s, 0, 1, 4, 2.5, 0, 8, StyleDefault)
+	s.Show()
+	if got := simRow(s, 0, 1, 4); got != "█▎  " {
+		t.Errorf("wrong bar: %q", got)
+	}
+	DrawBarChart(s, 0, 2, 3, 2, []float64{1, 2, math.NaN()}, 0, 0, StyleDefault)
+	s.Show()
+	if got := simRow(s, 0, 2, 3) + "|" + simRow(s, 0, 3, 3); got != " █ |██ " {
+		t.Errorf("wrong bar chart: %q", got)
+	}
+	DrawHAxis(s, 0, 4, 11, 0, 10, 3, StyleDefault)
+	s.Show()
+	if got := simRow(s, 0, 4, 11) + "|" + simRow(s, 0, 5, 11); got != "┬────┬────┬|0    5   10" {
+		t.Errorf("wrong axis: %q", got)
+	}
+}
+
+func TestChartsSmall(t *testing.T) {
+	var cb CellBuffer
+	cb.Resize(4, 4)
+	// Nothing is drawn where there is no room, and nothing panics.
+	DrawSparkline(&cb, 0, 0, -1, []float64{1, 2}, 0, 0, StyleDefault)
+	DrawBarChart(&cb, 0, 0, -1, 2, []float64{1, 2}, 0, 0, StyleDefault)
+	if vals, pos := chartTicks(0, 10, 3, 1); len(vals) != 1 || vals[0] != 0 || pos[0] != 0 {
+		t.Errorf("wrong ticks for one cell: %v %v", vals, pos)
+	}
+	DrawHAxis(&cb, 0, 0, 1, 0, 10, 3, StyleDefault)
+	if mainc, _, _, _ := cb.GetContent(0, 1); mainc != '0' {
+		t.Errorf("wrong label for one cell: %q", mainc)
+	}
+}
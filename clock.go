@@ -0,0 +1,129 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is a source of time.  The simulation screen uses one to time
+// stamp the events it generates, so that tests which depend on timing
+// (such as detecting double clicks, or key repeat) can be made
+// deterministic by using a FakeClock.  Applications that use the same
+// clock for their own timers can then be tested without real delays.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// AfterFunc calls f in its own goroutine once d has elapsed.  The
+	// returned function cancels the call, and returns false if it was
+	// too late to do so.
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+// SystemClock is the Clock that uses the real time.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// FakeClock is a Clock whose time only changes when it is told to.
+// It is safe for concurrent use.
+type FakeClock struct {
+	now    time.Time
+	timers []*fakeTimer
+	seq    int
+	sync.Mutex
+}
+
+type fakeTimer struct {
+	when time.Time
+	seq  int
+	f    func()
+}
+
+// NewFakeClock returns a FakeClock set to the given time.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns the clock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+// AfterFunc arranges for f to be called when the clock is advanced by at
+// least d.  Unlike time.AfterFunc, f is called synchronously by Advance,
+// which makes the order of events in tests predictable.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) func() bool {
+	c.Lock()
+	defer c.Unlock()
+	c.seq++
+	t := &fakeTimer{when: c.now.Add(d), seq: c.seq, f: f}
+	c.timers = append(c.timers, t)
+	return func() bool {
+		c.Lock()
+		defer c.Unlock()
+		for i, other := range c.timers {
+			if other == t {
+				c.timers = append(c.timers[:i], c.timers[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Advance moves the clock forward by d, calling the functions of any
+// timers that expire, in order.  While each is called, the clock reads
+// the time at which that timer expired.  Timers started by those
+// functions are also called, if they expire within d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.Lock()
+	end := c.now.Add(d)
+	for {
+		sort.Slice(c.timers, func(i, j int) bool {
+			a, b := c.timers[i], c.timers[j]
+			if a.when.Equal(b.when) {
+				return a.seq < b.seq
+			}
+			return a.when.Before(b.when)
+		})
+		if len(c.timers) == 0 || c.timers[0].when.After(end) {
+			break
+		}
+		t := c.timers[0]
+		c.timers = c.timers[1:]
+		if t.when.After(c.now) {
+			c.now = t.when
+		}
+		c.Unlock()
+		t.f()
+		c.Lock()
+	}
+	c.now = end
+	c.Unlock()
+}
@@ -838,6 +838,14 @@ const (
 	// ColorReset is used to indicate that the color should use the
 	// vanilla terminal colors.  (Basically go back to the defaults.)
 	ColorReset = ColorSpecial | iota
+
+	// ColorInherit is used to indicate that the color should be taken
+	// from the screen's default style, as set by Screen.SetStyle.  This
+	// differs from ColorDefault, which (unless the entire style is
+	// StyleDefault) means the terminal's own default color.  For example,
+	// StyleDefault.Foreground(ColorRed).Background(ColorInherit) has a
+	// red foreground on the application's background.
+	ColorInherit
 )
 
 // ColorNames holds the written names of colors. Useful to present a list of
@@ -15,6 +15,7 @@
 package tcell
 
 import (
+	"errors"
 	ic "image/color"
 	"testing"
 )
@@ -127,3 +128,104 @@ func TestFromImageColor(t *testing.T) {
 		t.Errorf("%v is not 0x00FFFF", hex)
 	}
 }
+
+func TestParseColor(t *testing.T) {
+	for _, v := range []struct {
+		text  string
+		color Color
+		str   string
+	}{
+		{"rebeccapurple", ColorRebeccaPurple, "rebeccapurple"},
+		{"Dark Slate Grey", ColorDarkSlateGray, "darkslategray"},
+		{"#1e90ff", NewHexColor(0x1e90ff), "#1e90ff"},
+		{"#F80", NewHexColor(0xff8800), "#ff8800"},
+		{"rgb(30, 144, 255)", NewRGBColor(30, 144, 255), "#1e90ff"},
+		{"rgb(100%,0%,50%)", NewRGBColor(255, 0, 128), "#ff0080"},
+		{"color208", Color208, "color208"},
+		{"default", ColorDefault, "default"},
+		{"reset", ColorReset, "reset"},
+		{"inherit", ColorInherit, "inherit"},
+	} {
+		c, err := ParseColor(v.text)
+		if err != nil {
+			t.Errorf("%q: %v", v.text, err)
+			continue
+		}
+		if c != v.color {
+			t.Errorf("%q: got %x want %x", v.text, uint64(c), uint64(v.color))
+		}
+		if c.String() != v.str {
+			t.Errorf("%q: formatted as %q", v.text, c.String())
+		}
+		if again, _ := ParseColor(c.String()); again != c {
+			t.Errorf("%q: did not round trip", v.text)
+		}
+	}
+	for _, bad := range []string{"", "nosuchcolor", "#12345", "#gggggg", "rgb(1,2)", "rgb(256,0,0)", "color256"} {
+		if _, err := ParseColor(bad); !errors.Is(err, ErrInvalidColor) {
+			t.Errorf("%q: expected ErrInvalidColor, got %v", bad, err)
+		}
+	}
+}
+
+func TestPalette(t *testing.T) {
+	if r := ContrastRatio(ColorBlack, ColorWhite); r != 21 {
+		t.Errorf("wrong contrast of black and white: %v", r)
+	}
+	if r := ContrastRatio(ColorDefault, ColorWhite); r != 0 {
+		t.Errorf("contrast with default color should be unknown: %v", r)
+	}
+
+	issues := CheckStyles([]Style{
+		StyleDefault.Foreground(ColorBlack).Background(ColorWhite),
+		StyleDefault.Foreground(ColorRed).Background(ColorGreen),
+		StyleDefault.Foreground(ColorRed),
+	}, ContrastAA)
+	if len(issues) != len(Visions) {
+		t.Fatalf("wrong issues: %v", issues)
+	}
+	for _, issue := range issues {
+		if issue.Index != 1 || issue.Contrast >= ContrastAA {
+			t.Errorf("wrong issue: %v", issue)
+		}
+	}
+
+	// Red and green are alike only with red-green color blindness.
+	for _, issue := range CheckPalette([]Color{ColorRed, ColorGreen}, 10) {
+		if issue.Vision != Deuteranopia && issue.Vision != Protanopia {
+			t.Errorf("wrong issue: %v", issue)
+		}
+	}
+	if issues := CheckPalette([]Color{ColorRed, ColorGreen}, 10); len(issues) == 0 {
+		t.Errorf("red and green not found alike")
+	}
+
+	p := SafePalette(10)
+	if len(p) != 10 {
+		t.Fatalf("wrong palette size: %d", len(p))
+	}
+	if issues := CheckPalette(p, 5); len(issues) != 0 {
+		t.Errorf("palette colors alike: %v", issues)
+	}
+}
+
+func TestInheritColors(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(3, 1)
+	s.SetStyle(StyleDefault.Foreground(ColorWhite).Background(ColorNavy))
+	s.SetContent(0, 0, 'a', nil, StyleDefault.Foreground(ColorRed).Background(ColorInherit))
+	s.SetContent(1, 0, 'b', nil, StyleDefault.Foreground(ColorRed))
+	s.SetContent(2, 0, 'c', nil, StyleDefault)
+	s.Show()
+	b, _, _ := s.GetContents()
+	for i, want := range []Style{
+		StyleDefault.Foreground(ColorRed).Background(ColorNavy),
+		StyleDefault.Foreground(ColorRed),
+		StyleDefault.Foreground(ColorWhite).Background(ColorNavy),
+	} {
+		if b[i].Style != want {
+			t.Errorf("cell %d: wrong style %v", i, b[i].Style)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// colorNameOf is the reverse of ColorNames.  Where a color has several
+// names (such as "gray" and "grey"), the alphabetically first is used,
+// so that the result does not depend on map ordering.
+var colorNameOf = func() map[Color]string {
+	m := make(map[Color]string, len(ColorNames))
+	for name, c := range ColorNames {
+		if old, ok := m[c]; !ok || name < old {
+			m[c] = name
+		}
+	}
+	return m
+}()
+
+// ParseColor parses a color written in one of the familiar notations:
+//
+//   - a W3C or X11 color name, such as "rebeccapurple" or "Dark Slate Gray"
+//   - a hexadecimal RGB value, "#1e90ff", or the short form "#1e9"
+//   - a CSS functional form, "rgb(30,144,255)", where the components may
+//     also be percentages
+//   - a palette index, "color208"
+//   - "default", "reset" or "inherit"
+//
+// Names are not case sensitive, and spaces within them are ignored.
+// Hexadecimal and functional forms produce RGB colors, whereas names
+// produce the corresponding named palette colors.  ErrInvalidColor is
+// returned if the string is not understood.
+func ParseColor(s string) (Color, error) {
+	str := strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case str == "default":
+		return ColorDefault, nil
+	case str == "reset":
+		return ColorReset, nil
+	case str == "inherit":
+		return ColorInherit, nil
+	case strings.HasPrefix(str, "#"):
+		if c, ok := parseHexColor(str[1:]); ok {
+			return c, nil
+		}
+	case strings.HasPrefix(str, "rgb(") && strings.HasSuffix(str, ")"):
+		if c, ok := parseRGBFunc(str[4 : len(str)-1]); ok {
+			return c, nil
+		}
+	case strings.HasPrefix(str, "color"):
+		if n, e := strconv.Atoi(str[5:]); e == nil && n >= 0 && n < 256 {
+			return PaletteColor(n), nil
+		}
+	default:
+		if c, ok := ColorNames[strings.Replace(str, " ", "", -1)]; ok {
+			return c, nil
+		}
+	}
+	return ColorDefault, fmt.Errorf("%w: %q", ErrInvalidColor, s)
+}
+
+func parseHexColor(s string) (Color, bool) {
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return ColorDefault, false
+	}
+	v, e := strconv.ParseUint(s, 16, 32)
+	if e != nil {
+		return ColorDefault, false
+	}
+	return NewHexColor(int32(v)), true
+}
+
+func parseRGBFunc(s string) (Color, bool) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return ColorDefault, false
+	}
+	var rgb [3]int32
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		pct := strings.HasSuffix(p, "%")
+		v, e := strconv.ParseFloat(strings.TrimSuffix(p, "%"), 64)
+		if pct {
+			v = v * 255 / 100
+		}
+		if e != nil || v < 0 || v > 255 {
+			return ColorDefault, false
+		}
+		rgb[i] = int32(v + 0.5)
+	}
+	return NewRGBColor(rgb[0], rgb[1], rgb[2]), true
+}
+
+// String returns the color in a form understood by ParseColor: a name for
+// named colors, "#rrggbb" for RGB colors, and "colorN" for palette colors
+// that have no name.
+func (c Color) String() string {
+	switch {
+	case c == ColorDefault:
+		return "default"
+	case c == ColorReset:
+		return "reset"
+	case c == ColorInherit:
+		return "inherit"
+	case c.IsRGB():
+		return fmt.Sprintf("#%06x", c.Hex())
+	case c.Valid():
+		if name, ok := colorNameOf[c]; ok {
+			return name
+		}
+		return fmt.Sprintf("color%d", int(c&^ColorValid))
+	}
+	return fmt.Sprintf("Color(%#x)", uint64(c))
+}
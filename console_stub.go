@@ -21,3 +21,7 @@ package tcell
 func NewConsoleScreen() (Screen, error) {
 	return nil, ErrNoScreen
 }
+
+func newConsoleScreen(*screenOptions) (Screen, error) {
+	return nil, ErrNoScreen
+}
@@ -24,7 +24,9 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 	"unicode/utf16"
+	"unicode/utf8"
 	"unsafe"
 )
 
@@ -41,22 +43,53 @@ type cScreen struct {
 	clear      bool
 	fini       bool
 	vten       bool
+	vtin       bool
+	ctrlC      bool
+	noEnv      bool
+	colors     ColorMode
 	truecolor  bool
 	running    bool
 
 	w int
 	h int
 
+	// The console window may show only part of a larger screen buffer, at
+	// vx, vy, if the console will not make the buffer fit the window, in
+	// which case scrolled is true.
+	vx       int
+	vy       int
+	scrolled bool
+
 	oscreen     consoleInfo
 	ocursor     cursorInfo
 	cursorStyle CursorStyle
+	softCursor  softCursor
+	selection   selection
+	highlights  selection
+	rows        rowStyles
+	guides      guides
+	mag         magnifier
+	filter      RenderFilter
+	bell        visualBell
+	progState   ProgressState
+	states      snapshots
 	oimode      uint32
 	oomode      uint32
 	cells       CellBuffer
+	history     scrollback
+	reflow      ResizePolicy
+	wantSize    [2]int
+	changes     cellChanges
 
 	finiOnce sync.Once
 
 	mouseEnabled bool
+	wheelKeys    int
+	keypad       bool
+	surrogate    uint16        // the first half of a surrogate pair
+	vtbuf        []byte        // VT input not yet decoded
+	vtdec        *InputDecoder // for VT input
+	suspends     int
 	wg           sync.WaitGroup
 	stopQ        chan struct{}
 
@@ -116,6 +149,7 @@ var (
 // without this suffix, as the resolution is made via preprocessor.
 var (
 	procReadConsoleInput            = k32.NewProc("ReadConsoleInputW")
+	procGetNumberOfConsoleInput     = k32.NewProc("GetNumberOfConsoleInputEvents")
 	procWaitForMultipleObjects      = k32.NewProc("WaitForMultipleObjects")
 	procCreateEvent                 = k32.NewProc("CreateEventW")
 	procSetEvent                    = k32.NewProc("SetEvent")
@@ -123,6 +157,7 @@ var (
 	procSetConsoleCursorInfo        = k32.NewProc("SetConsoleCursorInfo")
 	procSetConsoleCursorPosition    = k32.NewProc("SetConsoleCursorPosition")
 	procSetConsoleMode              = k32.NewProc("SetConsoleMode")
+	procSetConsoleCtrlHandler       = k32.NewProc("SetConsoleCtrlHandler")
 	procGetConsoleMode              = k32.NewProc("GetConsoleMode")
 	procGetConsoleScreenBufferInfo  = k32.NewProc("GetConsoleScreenBufferInfo")
 	procFillConsoleOutputAttribute  = k32.NewProc("FillConsoleOutputAttribute")
@@ -132,11 +167,24 @@ var (
 	procSetConsoleTextAttribute     = k32.NewProc("SetConsoleTextAttribute")
 	procGetLargestConsoleWindowSize = k32.NewProc("GetLargestConsoleWindowSize")
 	procMessageBeep                 = u32.NewProc("MessageBeep")
+	procGetConsoleWindow            = k32.NewProc("GetConsoleWindow")
+	procShowWindow                  = u32.NewProc("ShowWindow")
+	procSetWindowPos                = u32.NewProc("SetWindowPos")
+	procGetWindowRect               = u32.NewProc("GetWindowRect")
+	procGetDpiForWindow             = u32.NewProc("GetDpiForWindow")
+	procGetCurrentConsoleFontEx     = k32.NewProc("GetCurrentConsoleFontEx")
+	procSetForegroundWindow         = u32.NewProc("SetForegroundWindow")
 )
 
 const (
 	w32Infinite    = ^uintptr(0)
 	w32WaitObject0 = uintptr(0)
+	w32WaitTimeout = uintptr(0x102)
+
+	// scrolledPoll is how often the window is checked while it shows
+	// part of a larger buffer, as moving or resizing it within the
+	// buffer sends no event.
+	scrolledPoll = 250 // milliseconds
 )
 
 const (
@@ -160,6 +208,8 @@ const (
 	vtCursorSteadyUnderline   = "\x1b[4 q"
 	vtCursorBlinkingBar       = "\x1b[5 q"
 	vtCursorSteadyBar         = "\x1b[6 q"
+	vtKeypadApp               = "\x1b="
+	vtKeypadNormal            = "\x1b>"
 )
 
 var vtCursorStyles = map[CursorStyle]string{
@@ -176,7 +226,20 @@ var vtCursorStyles = map[CursorStyle]string{
 // with the current process.  The Screen makes use of the Windows Console
 // API to display content and read events.
 func NewConsoleScreen() (Screen, error) {
-	return &cScreen{}, nil
+	return newConsoleScreen(&screenOptions{})
+}
+
+func newConsoleScreen(o *screenOptions) (Screen, error) {
+	return &cScreen{ctrlC: o.ctrlC, noEnv: o.noEnv, colors: o.colors}, nil
+}
+
+// getenv returns the value of an environment variable, or nothing if the
+// environment is not to be read (see env.go).
+func (s *cScreen) getenv(name string) string {
+	if s.noEnv {
+		return ""
+	}
+	return os.Getenv(name)
 }
 
 func (s *cScreen) Init() error {
@@ -204,10 +267,18 @@ func (s *cScreen) Init() error {
 	// emitting stuff for the last character.  In the future we
 	// might change this to look at specific versions of ConEmu
 	// if they fix the bug.
-	if os.Getenv("ConEmuPID") != "" {
+	if s.getenv("ConEmuPID") != "" {
+		s.truecolor = false
+	}
+	// Without 24-bit color, the console has 16 colors.
+	switch s.colors {
+	case ColorModeAuto:
+	case ColorModeTrueColor:
+		s.truecolor = true
+	default:
 		s.truecolor = false
 	}
-	switch os.Getenv("TCELL_TRUECOLOR") {
+	switch s.getenv("TCELL_TRUECOLOR") {
 	case "disable":
 		s.truecolor = false
 	case "enable":
@@ -244,6 +315,23 @@ func (s *cScreen) Init() error {
 		s.setOutMode(0)
 	}
 
+	// Keys are read as the sequences of a terminal where the console
+	// can send them, as they carry more than the legacy key records, such
+	// as the characters composed by an IME.  This needs VT output too, as
+	// modes such as that of the keypad are set with escape sequences.
+	if s.vten && s.getenv("TCELL_VTINPUT") != "disable" {
+		s.setInMode(modeResizeEn | modeExtendFlg | modeVtInput)
+		var im uint32
+		s.getInMode(&im)
+		if ti, e := LookupTerminfo("xterm"); e == nil && im&modeVtInput != 0 {
+			s.vtdec, e = NewInputDecoder(ti)
+			s.vtin = e == nil
+		}
+		if !s.vtin {
+			s.setInMode(modeResizeEn | modeExtendFlg)
+		}
+	}
+
 	s.Unlock()
 
 	return s.engage()
@@ -268,16 +356,56 @@ func (s *cScreen) DisableMouse() {
 	s.Unlock()
 }
 
+// SetMouseShape does nothing, as the console has no way to change the
+// shape of the pointer.
+func (s *cScreen) SetMouseShape(MouseShape) {}
+
+// SetWheelKeys arranges for the mouse wheel to be reported as keys, while
+// the mouse is not enabled.
+func (s *cScreen) SetWheelKeys(n int) {
+	s.Lock()
+	s.wheelKeys = n
+	s.enableMouse(s.mouseEnabled)
+	s.Unlock()
+}
+
 func (s *cScreen) enableMouse(on bool) {
-	if on {
-		s.setInMode(modeResizeEn | modeMouseEn | modeExtendFlg)
-	} else {
-		s.setInMode(modeResizeEn | modeExtendFlg)
+	mode := modeResizeEn | modeExtendFlg
+	if on || s.wheelKeys > 0 {
+		mode |= modeMouseEn
+	}
+	if s.ctrlC {
+		mode |= modeCooked
 	}
+	if s.vtin {
+		mode |= modeVtInput
+	}
+	s.setInMode(mode)
 }
 
 // Windows lacks bracketed paste (for now)
 
+// EnableKeypad causes numeric keypad keys to be reported as distinct
+// keys.  The console needs no mode change for this, unless it is sending
+// VT input, when it must be put into application keypad mode.
+func (s *cScreen) EnableKeypad() {
+	s.Lock()
+	s.keypad = true
+	if s.vtin {
+		s.emitVtString(vtKeypadApp)
+	}
+	s.Unlock()
+}
+
+func (s *cScreen) DisableKeypad() {
+	s.Lock()
+	s.keypad = false
+	if s.vtin {
+		s.emitVtString(vtKeypadNormal)
+	}
+	s.Unlock()
+}
+
 func (s *cScreen) EnablePaste() {}
 
 func (s *cScreen) DisablePaste() {}
@@ -293,6 +421,8 @@ func (s *cScreen) disengage() {
 		return
 	}
 	s.running = false
+	unwatchConsoleCtrl(s)
+	s.bell.stop()
 	stopQ := s.stopQ
 	_, _, _ = procSetEvent.Call(uintptr(s.cancelflag))
 	close(stopQ)
@@ -302,10 +432,18 @@ func (s *cScreen) disengage() {
 
 	if s.vten {
 		s.emitVtString(vtCursorStyles[CursorStyleDefault])
+		if s.vtin && s.keypad {
+			s.emitVtString(vtKeypadNormal)
+		}
+		if s.progState != ProgressNone {
+			s.emitVtString(progressSeq(ProgressNone, 0))
+			s.progState = ProgressNone
+		}
 	}
 	s.setInMode(s.oimode)
 	s.setOutMode(s.oomode)
 	s.setBufferSize(int(s.oscreen.size.x), int(s.oscreen.size.y))
+	s.vx, s.vy = 0, 0
 	s.clearScreen(StyleDefault, false)
 	s.setCursorPos(0, 0, false)
 	s.setCursorInfo(&s.ocursor)
@@ -331,13 +469,19 @@ func (s *cScreen) engage() error {
 	}
 	s.running = true
 	s.cancelflag = syscall.Handle(cf)
+	watchConsoleCtrl(s)
 	s.enableMouse(s.mouseEnabled)
 
 	if s.vten {
 		s.setOutMode(modeVtOutput | modeNoAutoNL | modeCookedOut)
+		if s.vtin && s.keypad {
+			s.emitVtString(vtKeypadApp)
+		}
 	} else {
 		s.setOutMode(0)
 	}
+	s.surrogate = 0
+	s.vtbuf = nil
 
 	s.clearScreen(s.style, s.vten)
 	s.hideCursor()
@@ -353,6 +497,77 @@ func (s *cScreen) engage() error {
 	return nil
 }
 
+// Console control events, other than those of Ctrl+C and Ctrl+Break.
+const (
+	ctrlCloseEvent    = 2
+	ctrlLogoffEvent   = 5
+	ctrlShutdownEvent = 6
+
+	// ctrlGrace is how long the program is given to save its state,
+	// which is a little less than Windows gives it to close.
+	ctrlGrace = 4 * time.Second
+)
+
+var (
+	ctrlOnce   sync.Once
+	ctrlLock   sync.Mutex
+	ctrlScreen *cScreen // the engaged screen, to tell of control events
+)
+
+// watchConsoleCtrl arranges for s to be told of console control events,
+// installing the handler for them the first time.
+func watchConsoleCtrl(s *cScreen) {
+	ctrlOnce.Do(func() {
+		_, _, _ = procSetConsoleCtrlHandler.Call(syscall.NewCallback(consoleCtrl), 1)
+	})
+	ctrlLock.Lock()
+	ctrlScreen = s
+	ctrlLock.Unlock()
+}
+
+func unwatchConsoleCtrl(s *cScreen) {
+	ctrlLock.Lock()
+	if ctrlScreen == s {
+		ctrlScreen = nil
+	}
+	ctrlLock.Unlock()
+}
+
+// consoleCtrl handles the console control events that end the program,
+// posting an EventInterrupt with a ConsoleControl, and then waiting until
+// the screen is finalized (or for ctrlGrace) before letting the program
+// be ended.  Other events, such as those of Ctrl+C, are left to the
+// handler of the Go runtime.
+func consoleCtrl(typ uintptr) uintptr {
+	var ctl ConsoleControl
+	switch typ {
+	case ctrlCloseEvent:
+		ctl = ConsoleClose
+	case ctrlLogoffEvent:
+		ctl = ConsoleLogoff
+	case ctrlShutdownEvent:
+		ctl = ConsoleShutdown
+	default:
+		return 0
+	}
+	ctrlLock.Lock()
+	s := ctrlScreen
+	ctrlLock.Unlock()
+	if s == nil {
+		return 0
+	}
+	s.Lock()
+	stopQ := s.stopQ
+	s.Unlock()
+	if s.PostEvent(NewEventInterrupt(ctl)) == nil {
+		select {
+		case <-stopQ:
+		case <-time.After(ctrlGrace):
+		}
+	}
+	return 0
+}
+
 func (s *cScreen) PostEventWait(ev Event) {
 	s.evch <- ev
 }
@@ -467,7 +682,7 @@ func (s *cScreen) SetCursorStyle(cs CursorStyle) {
 func (s *cScreen) doCursor() {
 	x, y := s.curx, s.cury
 
-	if x < 0 || y < 0 || x >= s.w || y >= s.h {
+	if x < 0 || y < 0 || x >= s.w || y >= s.h || s.softCursor.on || s.mag.on() {
 		s.hideCursor()
 	} else {
 		s.setCursorPos(x, y, s.vten)
@@ -479,6 +694,54 @@ func (s *cScreen) HideCursor() {
 	s.ShowCursor(-1, -1)
 }
 
+func (s *cScreen) SetSoftCursor(style Style, r rune) {
+	s.Lock()
+	s.softCursor.set(style, r)
+	s.Unlock()
+}
+
+func (s *cScreen) SetCursors(cursors []Cursor) {
+	s.Lock()
+	s.softCursor.setExtra(cursors)
+	s.Unlock()
+}
+
+func (s *cScreen) SetSelection(style Style, ranges []Selection) {
+	s.Lock()
+	s.selection.set(style, ranges)
+	s.Unlock()
+}
+
+func (s *cScreen) SaveState() int {
+	s.Lock()
+	defer s.Unlock()
+	return s.states.save(&s.cells)
+}
+
+func (s *cScreen) RestoreState(id int) error {
+	s.Lock()
+	defer s.Unlock()
+	return s.states.restore(&s.cells, id)
+}
+
+func (s *cScreen) SetHighlights(style Style, ranges []Selection) {
+	s.Lock()
+	s.highlights.set(style, ranges)
+	s.Unlock()
+}
+
+func (s *cScreen) Find(pattern string, opts FindOptions) ([]Selection, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.cells.Find(pattern, opts)
+}
+
+func (s *cScreen) SelectedText() string {
+	s.Lock()
+	defer s.Unlock()
+	return s.cells.selectionText(s.selection.ranges)
+}
+
 type inputRecord struct {
 	typ  uint16
 	_    uint16
@@ -508,11 +771,6 @@ const (
 	// mouseMoved       uint32 = 0x1
 )
 
-type resizeRecord struct {
-	x int16
-	y int16
-}
-
 type keyRecord struct {
 	isdown int32
 	repeat uint16
@@ -530,6 +788,7 @@ const (
 	vkTab    = 0x09
 	vkClear  = 0x0c
 	vkReturn = 0x0d
+	vkMenu   = 0x12 // Alt
 	vkPause  = 0x13
 	vkEscape = 0x1b
 	vkSpace  = 0x20
@@ -572,6 +831,29 @@ const (
 	vkF24    = 0x87
 )
 
+// Numeric keypad virtual key codes.  The keypad Enter key is reported
+// as vkReturn, with the enhanced key flag set.
+var vkKeypad = map[uint16]Key{
+	0x60: KeyKP0,
+	0x61: KeyKP1,
+	0x62: KeyKP2,
+	0x63: KeyKP3,
+	0x64: KeyKP4,
+	0x65: KeyKP5,
+	0x66: KeyKP6,
+	0x67: KeyKP7,
+	0x68: KeyKP8,
+	0x69: KeyKP9,
+	0x6a: KeyKPMultiply,
+	0x6b: KeyKPPlus,
+	0x6c: KeyKPComma, // separator
+	0x6d: KeyKPMinus,
+	0x6e: KeyKPDecimal,
+	0x6f: KeyKPDivide,
+}
+
+const enhancedKey = 0x0100
+
 var vkKeys = map[uint16]Key{
 	vkCancel: KeyCancel,
 	vkBack:   KeyBackspace,
@@ -706,16 +988,28 @@ func (s *cScreen) getConsoleInput() error {
 	// same as a pointer to the array itself.
 	pWaitObjects := unsafe.Pointer(&waitObjects[0])
 
+	timeout := w32Infinite
+	s.Lock()
+	if s.scrolled {
+		timeout = scrolledPoll
+	}
+	s.Unlock()
+
 	rv, _, er := procWaitForMultipleObjects.Call(
 		uintptr(len(waitObjects)),
 		uintptr(pWaitObjects),
 		uintptr(0),
-		w32Infinite)
+		timeout)
 	// WaitForMultipleObjects returns WAIT_OBJECT_0 + the index.
 	switch rv {
 	case w32WaitObject0: // s.cancelFlag
 		return errors.New("cancelled")
+	case w32WaitTimeout:
+		s.checkWindow()
 	case w32WaitObject0 + 1: // s.in
+		if s.vtin {
+			defer s.decodeVtInput()
+		}
 		rec := &inputRecord{}
 		var nrec int32
 		rv, _, er := procReadConsoleInput.Call(
@@ -739,20 +1033,57 @@ func (s *cScreen) getConsoleInput() error {
 			krec.ch = getu16(rec.data[10:])
 			krec.mod = getu32(rec.data[12:])
 
+			if s.vtin {
+				s.vtInput(krec)
+				return nil
+			}
+			// A character entered with Alt and the numeric keypad
+			// comes with the release of Alt.
+			if krec.isdown == 0 && krec.kcode == vkMenu && krec.ch != 0 {
+				krec.isdown, krec.repeat = 1, 1
+				krec.mod &^= 0x0002 | 0x0001
+			}
 			if krec.isdown == 0 || krec.repeat < 1 {
 				// its a key release event, ignore it
 				return nil
 			}
+			s.Lock()
+			keypad := s.keypad
+			s.Unlock()
+			if keypad {
+				k, ok := vkKeypad[krec.kcode]
+				if !ok && krec.kcode == vkReturn && krec.mod&enhancedKey != 0 {
+					k, ok = KeyKPEnter, true
+				}
+				if ok {
+					for ; krec.repeat > 0; krec.repeat-- {
+						s.PostEventWait(NewEventKey(k, 0, mod2mask(krec.mod)))
+					}
+					return nil
+				}
+			}
 			if krec.ch != 0 {
 				// synthesized key code
+				r := s.addUnit(krec.ch)
+				if r == 0 {
+					return nil
+				}
 				for krec.repeat > 0 {
 					// convert shift+tab to backtab
 					if mod2mask(krec.mod) == ModShift && krec.ch == vkTab {
 						s.PostEventWait(NewEventKey(KeyBacktab, 0,
 							ModNone))
 					} else {
-						s.PostEventWait(NewEventKey(KeyRune, rune(krec.ch),
-							mod2mask(krec.mod)))
+						ev := NewEventKey(KeyRune, r, mod2mask(krec.mod))
+						// Virtual key codes for letters and digits
+						// are their (upper case) ASCII values.
+						switch {
+						case krec.kcode >= 'A' && krec.kcode <= 'Z':
+							ev.code = rune(krec.kcode) + 'a' - 'A'
+						case krec.kcode >= '0' && krec.kcode <= '9':
+							ev.code = rune(krec.kcode)
+						}
+						s.PostEventWait(ev)
 					}
 					krec.repeat--
 				}
@@ -777,15 +1108,25 @@ func (s *cScreen) getConsoleInput() error {
 			mrec.mod = getu32(rec.data[8:])
 			mrec.flags = getu32(rec.data[12:])
 			btns := mrec2btns(mrec.btns, mrec.flags)
+			s.Lock()
+			mouse, n := s.mouseEnabled, s.wheelKeys
+			// the position is in the buffer, not the window
+			x, y := int(mrec.x)-s.vx, int(mrec.y)-s.vy
+			s.Unlock()
 			// we ignore double click, events are delivered normally
-			s.PostEventWait(NewEventMouse(int(mrec.x), int(mrec.y), btns,
-				mod2mask(mrec.mod)))
+			ev := NewEventMouse(x, y, btns, mod2mask(mrec.mod))
+			if !mouse && n > 0 {
+				for _, kev := range wheelKeys(ev, n) {
+					s.PostEventWait(kev)
+				}
+				break
+			}
+			s.PostEventWait(ev)
 
 		case resizeEvent:
-			var rrec resizeRecord
-			rrec.x = geti16(rec.data[0:])
-			rrec.y = geti16(rec.data[2:])
-			s.PostEventWait(NewEventResize(int(rrec.x), int(rrec.y)))
+			// This gives the size of the buffer, which may be larger
+			// than the window, so the window is checked instead.
+			s.checkWindow()
 
 		default:
 		}
@@ -796,6 +1137,61 @@ func (s *cScreen) getConsoleInput() error {
 	return nil
 }
 
+// addUnit adds a UTF-16 code unit of a key, returning the character it
+// completes, or zero if it is the first half of a surrogate pair, as the
+// characters outside the Basic Multilingual Plane (such as many emoji)
+// are sent as two keys.
+func (s *cScreen) addUnit(u uint16) rune {
+	r := rune(u)
+	switch {
+	case utf16.IsSurrogate(r) && r < 0xdc00:
+		s.surrogate = u
+		return 0
+	case utf16.IsSurrogate(r) && s.surrogate != 0:
+		r = utf16.DecodeRune(rune(s.surrogate), r)
+	case utf16.IsSurrogate(r):
+		r = utf8.RuneError
+	}
+	s.surrogate = 0
+	return r
+}
+
+// vtInput adds the character of a key record to the VT input.  When the
+// console is in VT input mode, the keys are sent as the characters of the
+// sequences a terminal would send for them, which are then decoded as they
+// are for a terminal.
+func (s *cScreen) vtInput(krec *keyRecord) {
+	if krec.isdown == 0 || krec.ch == 0 {
+		return
+	}
+	r := s.addUnit(krec.ch)
+	if r == 0 {
+		return
+	}
+	for ; krec.repeat > 0; krec.repeat-- {
+		s.vtbuf = append(s.vtbuf, string(r)...)
+	}
+}
+
+// decodeVtInput posts the events of the VT input collected so far.  The
+// start of a sequence is left to be completed by the next keys, unless no
+// more are waiting, in which case it is decoded as it is (so that a lone
+// Escape is reported as such).
+func (s *cScreen) decodeVtInput() {
+	if len(s.vtbuf) == 0 {
+		return
+	}
+	var n uint32
+	rv, _, _ := procGetNumberOfConsoleInput.Call(
+		uintptr(s.in),
+		uintptr(unsafe.Pointer(&n)))
+	evs, used := s.vtdec.Decode(s.vtbuf, rv == 0 || n == 0)
+	s.vtbuf = append(s.vtbuf[:0], s.vtbuf[used:]...)
+	for _, ev := range evs {
+		s.PostEventWait(ev)
+	}
+}
+
 func (s *cScreen) scanInput(stopQ chan struct{}) {
 	defer s.wg.Done()
 	for {
@@ -815,9 +1211,22 @@ func (s *cScreen) Colors() int {
 		return 1 << 24
 	}
 	// Windows console can display 8 colors, in either low or high intensity
+	switch s.colors {
+	case ColorModeMono:
+		return 0
+	case ColorMode8:
+		return 8
+	}
 	return 16
 }
 
+func (s *cScreen) ColorModel() (int, bool, bool) {
+	if s.vten {
+		return 256, true, true
+	}
+	return 16, false, true
+}
+
 var vgaColors = map[Color]uint16{
 	ColorBlack:   0,
 	ColorMaroon:  0x4,
@@ -855,16 +1264,27 @@ func mapColor2RGB(c Color) uint16 {
 	return 0
 }
 
+// fitColor limits a color to the first 8, if the color mode asks for that.
+func (s *cScreen) fitColor(c Color) Color {
+	if s.colors == ColorMode8 {
+		return FindColor(c, winPalette[:8])
+	}
+	return c
+}
+
 // Map a tcell style to Windows attributes
 func (s *cScreen) mapStyle(style Style) uint16 {
 	f, b, a := style.Decompose()
+	if s.colors == ColorModeMono {
+		f, b = ColorDefault, ColorDefault
+	}
 	fa := s.oscreen.attrs & 0xf
 	ba := (s.oscreen.attrs) >> 4 & 0xf
 	if f != ColorDefault && f != ColorReset {
-		fa = mapColor2RGB(f)
+		fa = mapColor2RGB(s.fitColor(f))
 	}
 	if b != ColorDefault && b != ColorReset {
-		ba = mapColor2RGB(b)
+		ba = mapColor2RGB(s.fitColor(b))
 	}
 	var attr uint16
 	// We simulate reverse by doing the color swap ourselves.
@@ -907,6 +1327,24 @@ func (s *cScreen) SetContent(x, y int, primary rune, combining []rune, style Sty
 	s.Unlock()
 }
 
+func (s *cScreen) SetTabStops(stops []int) {
+	s.Lock()
+	s.cells.SetTabStops(stops)
+	s.Unlock()
+}
+
+func (s *cScreen) NextTabStop(x int) int {
+	s.Lock()
+	defer s.Unlock()
+	return s.cells.NextTabStop(x)
+}
+
+func (s *cScreen) SetLineMode(int, LineMode) {}
+
+func (s *cScreen) GetLineMode(int) LineMode {
+	return LineNormal
+}
+
 func (s *cScreen) GetContent(x, y int) (rune, []rune, Style, int) {
 	s.Lock()
 	primary, combining, style, width := s.cells.GetContent(x, y)
@@ -966,13 +1404,54 @@ func (s *cScreen) writeString(x, y int, style Style, ch []uint16) {
 }
 
 func (s *cScreen) draw() {
-	// allocate a scratch line bit enough for no combining chars.
-	// if you have combining characters, you may pay for extra allocations.
 	if s.clear {
 		s.clearScreen(s.style, s.vten)
 		s.clear = false
 		s.cells.Invalidate()
+		s.mag.buf.Invalidate()
+	}
+	s.guides.prepare(&s.cells)
+	s.highlights.prepare(&s.cells)
+	s.selection.prepare(&s.cells)
+	s.softCursor.prepare(&s.cells, s.curx, s.cury)
+	s.changes.collect(&s.cells)
+	magnified := s.mag.on()
+	if magnified {
+		w, h := s.cells.Size()
+		s.mag.compose(w, h, func(x, y int) (rune, []rune, Style, int) {
+			mainc, combc, style, width := s.cells.GetContent(x, y)
+			mainc, combc, style = s.overlay(x, y, mainc, combc, style)
+			return mainc, combc, style, width
+		}, s.curx, s.cury, false)
+		s.cells, s.mag.buf = s.mag.buf, s.cells
+		defer func() {
+			s.cells, s.mag.buf = s.mag.buf, s.cells
+		}()
+	}
+	s.drawCells(func(x, y int) bool {
+		return s.cells.Dirty(x, y)
+	}, magnified)
+}
+
+// drawOnly redraws the cells for which redraw returns true, as they are
+// now displayed, leaving alone those that the application has changed
+// but not yet shown.  This is for the visual bell, which changes how
+// cells are displayed, rather than what they hold.
+func (s *cScreen) drawOnly(redraw func(x, y int) bool) {
+	if s.clear || s.mag.on() {
+		return
 	}
+	s.hideCursor()
+	s.drawCells(func(x, y int) bool {
+		return !s.cells.Dirty(x, y) && redraw(x, y)
+	}, false)
+	s.doCursor()
+}
+
+// drawCells draws the cells for which draw returns true.
+func (s *cScreen) drawCells(draw func(x, y int) bool, magnified bool) {
+	// allocate a scratch line bit enough for no combining chars.
+	// if you have combining characters, you may pay for extra allocations.
 	buf := make([]uint16, 0, s.w)
 	wcs := buf[:]
 	lstyle := styleInvalid
@@ -983,9 +1462,13 @@ func (s *cScreen) draw() {
 	for y := 0; y < s.h; y++ {
 		for x := 0; x < s.w; x++ {
 			mainc, combc, style, width := s.cells.GetContent(x, y)
-			dirty := s.cells.Dirty(x, y)
-			if style == StyleDefault {
-				style = s.style
+			dirty := draw(x, y)
+			if !magnified {
+				mainc, combc, style = s.overlay(x, y, mainc, combc, style)
+			}
+			style = s.bell.apply(x, y, style.Inherit(s.style))
+			if s.filter != nil {
+				style = s.filter(style)
 			}
 
 			if !dirty || style != lstyle {
@@ -1025,6 +1508,16 @@ func (s *cScreen) draw() {
 	}
 }
 
+// overlay returns the content of the cell at x, y as it is displayed, with
+// the row style, guides, highlights, selection and soft cursor applied.
+func (s *cScreen) overlay(x, y int, mainc rune, combc []rune, style Style) (rune, []rune, Style) {
+	style = s.rows.apply(y, style, s.style)
+	style = s.guides.apply(x, y, style)
+	style = s.highlights.apply(x, y, s.w, style)
+	style = s.selection.apply(x, y, s.w, style)
+	return s.softCursor.apply(x, y, mainc, combc, style)
+}
+
 func (s *cScreen) Show() {
 	s.Lock()
 	if !s.fini {
@@ -1033,7 +1526,9 @@ func (s *cScreen) Show() {
 		s.draw()
 		s.doCursor()
 	}
+	changes := s.changes.take()
 	s.Unlock()
+	reportChanges(changes)
 }
 
 func (s *cScreen) Sync() {
@@ -1045,7 +1540,9 @@ func (s *cScreen) Sync() {
 		s.draw()
 		s.doCursor()
 	}
+	changes := s.changes.take()
 	s.Unlock()
+	reportChanges(changes)
 }
 
 type consoleInfo struct {
@@ -1077,12 +1574,13 @@ func (s *cScreen) setCursorInfo(info *cursorInfo) {
 
 func (s *cScreen) setCursorPos(x, y int, vtEnable bool) {
 	if vtEnable {
-		// Note that the string is Y first.  Origin is 1,1.
+		// Note that the string is Y first.  Origin is 1,1.  This is
+		// relative to the window, rather than to the buffer.
 		s.emitVtString(fmt.Sprintf(vtCursorPos, y+1, x+1))
 	} else {
 		_, _, _ = procSetConsoleCursorPosition.Call(
 			uintptr(s.out),
-			coord{int16(x), int16(y)}.uintptr())
+			coord{int16(x + s.vx), int16(y + s.vy)}.uintptr())
 	}
 }
 
@@ -1101,6 +1599,8 @@ func (s *cScreen) Size() (int, int) {
 }
 
 func (s *cScreen) SetSize(w, h int) {
+	s.Lock()
+	defer s.Unlock()
 	xy, _, _ := procGetLargestConsoleWindowSize.Call(uintptr(s.out))
 
 	// xy is little endian packed
@@ -1128,36 +1628,184 @@ func (s *cScreen) SetSize(w, h int) {
 		uintptr(1),
 		uintptr(unsafe.Pointer(&r)))
 
+	s.wantSize = [2]int{w, h}
 	s.resize()
 }
 
-func (s *cScreen) resize() {
+// ShowWindow and SetWindowPos constants.
+const (
+	swMaximize    = 3
+	swMinimize    = 6
+	swRestore     = 9
+	swpNoSize     = 0x0001
+	swpNoZOrder   = 0x0004
+	swpNoActivate = 0x0010
+	hwndTop       = 0
+)
+
+// consoleWindow returns the window handle for the console, which will
+// be zero if there is none (such as when running under a pseudo console
+// that does not provide one).
+func (s *cScreen) consoleWindow() uintptr {
+	hwnd, _, _ := procGetConsoleWindow.Call()
+	return hwnd
+}
+
+func (s *cScreen) HasWindowOps() bool {
+	return s.consoleWindow() != 0
+}
+
+func (s *cScreen) IconifyWindow(iconify bool) {
+	if hwnd := s.consoleWindow(); hwnd != 0 {
+		cmd := swRestore
+		if iconify {
+			cmd = swMinimize
+		}
+		_, _, _ = procShowWindow.Call(hwnd, uintptr(cmd))
+	}
+}
+
+func (s *cScreen) MaximizeWindow(maximize bool) {
+	if hwnd := s.consoleWindow(); hwnd != 0 {
+		cmd := swRestore
+		if maximize {
+			cmd = swMaximize
+		}
+		_, _, _ = procShowWindow.Call(hwnd, uintptr(cmd))
+	}
+}
+
+func (s *cScreen) RaiseWindow() {
+	if hwnd := s.consoleWindow(); hwnd != 0 {
+		_, _, _ = procSetForegroundWindow.Call(hwnd)
+	}
+}
+
+func (s *cScreen) SetWindowPosition(x, y int) {
+	if hwnd := s.consoleWindow(); hwnd != 0 {
+		_, _, _ = procSetWindowPos.Call(hwnd, hwndTop,
+			uintptr(x), uintptr(y), 0, 0,
+			swpNoSize|swpNoZOrder|swpNoActivate)
+	}
+}
+
+func (s *cScreen) QueryWindowPosition() {
+	if hwnd := s.consoleWindow(); hwnd != 0 {
+		var r struct{ left, top, right, bottom int32 }
+		rv, _, _ := procGetWindowRect.Call(hwnd, uintptr(unsafe.Pointer(&r)))
+		if rv != 0 {
+			_ = s.PostEvent(NewEventWindowPosition(int(r.left), int(r.top)))
+		}
+	}
+}
+
+// consoleFontInfoEx is CONSOLE_FONT_INFOEX.
+type consoleFontInfoEx struct {
+	size     uint32
+	font     uint32
+	fontSize coord
+	family   uint32
+	weight   uint32
+	faceName [32]uint16
+}
+
+func (s *cScreen) FontMetrics() (int, int, int) {
+	w, h, dpi := 0, 0, 0
+	// Both of these are missing from older versions of Windows.
+	if procGetCurrentConsoleFontEx.Find() == nil {
+		info := consoleFontInfoEx{}
+		info.size = uint32(unsafe.Sizeof(info))
+		rv, _, _ := procGetCurrentConsoleFontEx.Call(uintptr(s.out), 0, uintptr(unsafe.Pointer(&info)))
+		// Windows Terminal draws with its own font, and its pseudo
+		// console reports a nominal size, if any.
+		if rv != 0 && info.fontSize.x > 0 && info.fontSize.y > 0 && s.getenv("WT_SESSION") == "" {
+			w, h = int(info.fontSize.x), int(info.fontSize.y)
+		}
+	}
+	if hwnd := s.consoleWindow(); hwnd != 0 && procGetDpiForWindow.Find() == nil {
+		rv, _, _ := procGetDpiForWindow.Call(hwnd)
+		dpi = int(rv)
+	}
+	return w, h, dpi
+}
+
+// resize makes the screen the size of the console window, posting an
+// EventResize if it has changed.  The screen buffer is made the size of
+// the window too, so that there is nothing for the window to scroll to,
+// but if the console will not allow that, the screen is drawn wherever in
+// the buffer the window is.  It returns true if that has moved, in which
+// case the cells must be drawn again.
+func (s *cScreen) resize() bool {
 	info := consoleInfo{}
 	s.getConsoleInfo(&info)
 
 	w := int((info.win.right - info.win.left) + 1)
 	h := int((info.win.bottom - info.win.top) + 1)
 
-	if s.w == w && s.h == h {
-		return
+	var ev *EventResize
+	if s.w != w || s.h != h {
+		resizeCells(&s.cells, &s.history, s.reflow, w, h)
+		ev = newEventResize(w, h, s.w, s.h, resizeReason(w, h, s.w, s.h, s.wantSize))
+		s.w = w
+		s.h = h
+		s.wantSize = [2]int{}
 	}
 
-	s.cells.Resize(w, h)
-	s.w = w
-	s.h = h
+	if int(info.size.x) != w || int(info.size.y) != h || info.win.left != 0 || info.win.top != 0 {
+		// The window is moved to the top left first, so that the
+		// buffer can shrink to it.
+		r := rect{0, 0, int16(w - 1), int16(h - 1)}
+		_, _, _ = procSetConsoleWindowInfo.Call(
+			uintptr(s.out),
+			uintptr(1),
+			uintptr(unsafe.Pointer(&r)))
+		s.setBufferSize(w, h)
+		s.getConsoleInfo(&info)
+	}
+	s.scrolled = int(info.size.x) > w || int(info.size.y) > h
+	moved := false
+	if vx, vy := int(info.win.left), int(info.win.top); vx != s.vx || vy != s.vy {
+		s.vx, s.vy = vx, vy
+		s.cells.Invalidate()
+		s.mag.buf.Invalidate()
+		moved = true
+	}
 
-	s.setBufferSize(w, h)
+	if ev != nil {
+		_ = s.PostEvent(ev)
+	}
+	return moved
+}
 
-	r := rect{0, 0, int16(w - 1), int16(h - 1)}
-	_, _, _ = procSetConsoleWindowInfo.Call(
-		uintptr(s.out),
-		uintptr(1),
-		uintptr(unsafe.Pointer(&r)))
-	_ = s.PostEvent(NewEventResize(w, h))
+// checkWindow checks for changes to the console window, redrawing the
+// screen if the window has moved within the buffer.
+func (s *cScreen) checkWindow() {
+	s.Lock()
+	defer s.Unlock()
+	if !s.fini && s.running && s.resize() {
+		s.hideCursor()
+		s.draw()
+		s.doCursor()
+	}
 }
 
 func (s *cScreen) Clear() {
-	s.Fill(' ', s.style)
+	s.Lock()
+	if !s.fini {
+		s.cells.Fill(' ', s.style)
+		s.clear = true
+	}
+	s.Unlock()
+}
+
+func (s *cScreen) FillFunc(x, y, width, height int, f FillFunc) {
+	w, h := s.Size()
+	b := newFillBatch(x, y, width, height, w, h, f)
+	s.Lock()
+	if !s.fini {
+		b.apply(&s.cells)
+	}
+	s.Unlock()
 }
 
 func (s *cScreen) Fill(r rune, style Style) {
@@ -1180,24 +1828,27 @@ func (s *cScreen) clearScreen(style Style, vtEnable bool) {
 		s.setCursorPos(0, 0, vtEnable)
 
 	} else {
-		pos := coord{0, 0}
 		attr := s.mapStyle(style)
-		x, y := s.w, s.h
 		scratch := uint32(0)
-		count := uint32(x * y)
+		count := uint32(s.w)
 
-		_, _, _ = procFillConsoleOutputAttribute.Call(
-			uintptr(s.out),
-			uintptr(attr),
-			uintptr(count),
-			pos.uintptr(),
-			uintptr(unsafe.Pointer(&scratch)))
-		_, _, _ = procFillConsoleOutputCharacter.Call(
-			uintptr(s.out),
-			uintptr(' '),
-			uintptr(count),
-			pos.uintptr(),
-			uintptr(unsafe.Pointer(&scratch)))
+		// Each row of the window is filled separately, as the buffer
+		// may be wider than it.
+		for y := 0; y < s.h; y++ {
+			pos := coord{int16(s.vx), int16(s.vy + y)}
+			_, _, _ = procFillConsoleOutputAttribute.Call(
+				uintptr(s.out),
+				uintptr(attr),
+				uintptr(count),
+				pos.uintptr(),
+				uintptr(unsafe.Pointer(&scratch)))
+			_, _, _ = procFillConsoleOutputCharacter.Call(
+				uintptr(s.out),
+				uintptr(' '),
+				uintptr(count),
+				pos.uintptr(),
+				uintptr(unsafe.Pointer(&scratch)))
+		}
 	}
 }
 
@@ -1206,8 +1857,8 @@ const (
 	modeExtendFlg uint32 = 0x0080
 	modeMouseEn          = 0x0010
 	modeResizeEn         = 0x0008
-	// modeCooked          = 0x0001
-	// modeVtInput         = 0x0200
+	modeVtInput          = 0x0200
+	modeCooked           = 0x0001 // Ctrl+C raises a control event
 
 	// Output modes
 	modeCookedOut uint32 = 0x0001
@@ -1251,6 +1902,115 @@ func (s *cScreen) SetStyle(style Style) {
 func (s *cScreen) RegisterRuneFallback(_ rune, _ string) {
 }
 
+func (s *cScreen) Scroll(x, y, width, height, n int) {
+	s.Lock()
+	if !s.fini {
+		s.history.save(&s.cells, x, y, width, height, n)
+		s.cells.scroll(x, y, width, height, n, false, StyleDefault)
+	}
+	s.Unlock()
+}
+
+func (s *cScreen) SetResizePolicy(policy ResizePolicy) {
+	s.Lock()
+	s.reflow = policy
+	s.Unlock()
+}
+
+func (s *cScreen) SetLineWrap(y int, wrapped bool) {
+	s.Lock()
+	if !s.fini {
+		s.cells.SetWrapped(y, wrapped)
+	}
+	s.Unlock()
+}
+
+func (s *cScreen) SetRowStyle(y int, style Style) {
+	s.Lock()
+	if !s.fini {
+		s.rows.set(&s.cells, y, style)
+	}
+	s.Unlock()
+}
+
+func (s *cScreen) GetRowStyle(y int) Style {
+	s.Lock()
+	defer s.Unlock()
+	return s.rows.get(y)
+}
+
+func (s *cScreen) SetGuides(list []Guide) {
+	s.Lock()
+	s.guides.set(list)
+	s.Unlock()
+}
+
+func (s *cScreen) ShowGuides(on bool) {
+	s.Lock()
+	s.guides.show(on)
+	s.Unlock()
+}
+
+func (s *cScreen) SetMagnifier(factor int) {
+	s.Lock()
+	if s.mag.set(factor) && !s.mag.on() {
+		s.cells.Invalidate()
+	}
+	s.Unlock()
+}
+
+func (s *cScreen) SetRenderFilter(f RenderFilter) {
+	s.Lock()
+	s.filter = f
+	s.cells.Invalidate()
+	s.mag.buf.Invalidate()
+	s.Unlock()
+}
+
+func (s *cScreen) OnCellChange(x, y, width, height int, f CellChangeHandler) func() {
+	s.Lock()
+	cw := s.changes.add(x, y, width, height, f)
+	s.Unlock()
+	return func() {
+		s.Lock()
+		s.changes.remove(cw)
+		s.Unlock()
+	}
+}
+
+func (s *cScreen) SetScrollback(lines int) {
+	s.Lock()
+	s.history.setMax(lines)
+	s.Unlock()
+}
+
+func (s *cScreen) Scrollback() *CellBuffer {
+	s.Lock()
+	defer s.Unlock()
+	return s.history.buffer()
+}
+
+func (s *cScreen) Query(string, SequenceMatcher, time.Duration) (string, error) {
+	return "", ErrNotSupported
+}
+
+func (s *cScreen) TerminalID() TerminalID {
+	return TerminalID{}
+}
+
+func (s *cScreen) SetStatusLine(string, Style) {}
+
+func (s *cScreen) LoadSoftFont(*SoftFont) error {
+	return ErrNotSupported
+}
+
+func (s *cScreen) HasGlyphs(set GlyphSet) bool {
+	return (GlyphsBoxDrawing|glyphsFromEnv(s.getenv))&set == set
+}
+
+func (s *cScreen) RegisterRuneFallbacks(_ map[rune]string) {
+}
+
 func (s *cScreen) UnregisterRuneFallback(_ rune) {
 }
 
@@ -1306,7 +2066,65 @@ func (s *cScreen) HasKey(k Key) bool {
 	return valid[k]
 }
 
+// Notify uses OSC 9, which ConEmu understands.  The console itself has no
+// notifications.
+func (s *cScreen) Notify(title, body string) error {
+	if s.getenv("ConEmuPID") == "" {
+		return ErrNotSupported
+	}
+	msg := notifyText(body, false)
+	if title != "" {
+		msg = notifyText(title, false) + ": " + msg
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.fini || !s.vten {
+		return ErrNotSupported
+	}
+	s.emitVtString("\x1b]9;" + msg + "\x1b\\")
+	return nil
+}
+
+// SetProgress works in Windows Terminal and ConEmu, when virtual terminal
+// sequences are in use.
+func (s *cScreen) SetProgress(state ProgressState, percent int) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.fini || !s.vten {
+		return ErrNotSupported
+	}
+	if state < ProgressNone || state > ProgressPaused {
+		state = ProgressNone
+	}
+	s.progState = state
+	s.emitVtString(progressSeq(state, percent))
+	return nil
+}
+
+func (s *cScreen) SetBellPolicy(policy BellPolicy) {
+	s.Lock()
+	s.bell.policy = policy
+	s.Unlock()
+}
+
+func (s *cScreen) SetVisualBell(d time.Duration, x, y, w, h int) {
+	s.Lock()
+	s.bell.set(d, x, y, w, h)
+	s.Unlock()
+}
+
 func (s *cScreen) Beep() error {
+	s.Lock()
+	policy := s.bell.policy
+	if policy == BellVisual && !s.fini {
+		s.bell.start(s.bellDone)
+		s.drawOnly(s.bell.contains)
+	}
+	s.Unlock()
+	if policy != BellAudible {
+		return nil
+	}
+
 	// A simple beep. If the sound card is not available, the sound is generated
 	// using the speaker.
 	//
@@ -1319,11 +2137,100 @@ func (s *cScreen) Beep() error {
 	return nil
 }
 
+// bellDone ends the flash of the visual bell.
+func (s *cScreen) bellDone() {
+	s.Lock()
+	defer s.Unlock()
+	s.bell.stop()
+	if !s.fini {
+		s.drawOnly(s.bell.contains)
+	}
+}
+
+// emitAt sends a sequence with the cursor at x, y, for PromptMarker.
+func (s *cScreen) emitAt(x, y int, seq string) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.fini || !s.vten {
+		return nil
+	}
+	s.resize()
+	s.draw()
+	s.setCursorPos(x, y, true)
+	s.emitVtString(seq)
+	return nil
+}
+
+func (s *cScreen) EmitRaw(seq string) error {
+	if !validSequence(seq) {
+		return ErrInvalidSequence
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.fini || !s.vten {
+		return nil
+	}
+	s.hideCursor()
+	s.resize()
+	s.draw()
+	s.emitVtString(seq)
+	s.doCursor()
+	return nil
+}
+
+// OnUnknownSequence has no effect, as the console delivers input as
+// records rather than as escape sequences.
+func (s *cScreen) OnUnknownSequence(UnknownSequenceHandler) {}
+
+// DisableSignalHandling has no effect, as the console does not use
+// signals for resize notification.
+func (s *cScreen) DisableSignalHandling() {}
+
+func (s *cScreen) HandleSignal(os.Signal) {}
+
 func (s *cScreen) Suspend() error {
-	s.disengage()
+	s.Lock()
+	s.suspends++
+	outer := s.suspends == 1
+	s.Unlock()
+	if outer {
+		s.disengage()
+	}
 	return nil
 }
 
+// SuspendNoClear is the same as Suspend, as the console restores the
+// contents of its own buffer.
+func (s *cScreen) SuspendNoClear() error {
+	return s.Suspend()
+}
+
 func (s *cScreen) Resume() error {
+	s.Lock()
+	if s.suspends > 0 {
+		s.suspends--
+		if s.suspends > 0 {
+			s.Unlock()
+			return nil
+		}
+	}
+	s.Unlock()
 	return s.engage()
 }
+
+func (s *cScreen) report(r *Report) {
+	s.Lock()
+	r.Driver = "console"
+	r.Term = s.getenv("TERM")
+	r.TrueColor = s.truecolor && s.vten
+	r.MouseProtocol = "console"
+	r.CursorStyles = s.vten
+	r.SetWindowSize = true
+	for k := range vkKeys {
+		r.Keys = append(r.Keys, ReportKey{
+			Name:     NewEventKey(vkKeys[k], 0, ModNone).Name(),
+			Sequence: fmt.Sprintf("VK 0x%02x", k),
+		})
+	}
+	s.Unlock()
+}
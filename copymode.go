@@ -0,0 +1,174 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"encoding/base64"
+)
+
+// CopyModeOptions configures RunCopyMode.
+type CopyModeOptions struct {
+	// Style is laid over the selected cells.  If it is StyleDefault,
+	// selected cells are shown in reverse video.
+	Style Style
+
+	// StartX and StartY are where the cursor starts.
+	StartX int
+	StartY int
+
+	// Clipboard, if true, also sends the selected text to the terminal's
+	// clipboard with SetClipboard.
+	Clipboard bool
+}
+
+// RunCopyMode lets the user select text from the screen with the keyboard,
+// in the manner of tmux's copy mode, and returns the selected text.  The
+// application's content is left exactly as it is while this runs, as the
+// application receives no events, and the selection is drawn without
+// modifying the cells.
+//
+// The cursor is moved with the arrow keys (or h, j, k and l), Home and End
+// (or 0 and $), PgUp and PgDn, and g and G for the top and bottom of the
+// screen.  Space or v starts (or abandons) a selection, and Ctrl-V or r
+// switches between a normal and a rectangular selection.  Enter or y
+// finishes, returning the text and true; Esc, q or Ctrl-C cancel, returning
+// false, as does finishing without a selection.
+//
+// On return the selection is removed and the cursor is hidden.  Events
+// other than key presses and resizes are discarded.
+func RunCopyMode(s Screen, opts CopyModeOptions) (string, bool) {
+	style := opts.Style
+	if style == StyleDefault {
+		style = StyleDefault.Reverse(true)
+	}
+	x, y := opts.StartX, opts.StartY
+	var sel Selection
+	selecting := false
+
+	defer func() {
+		s.SetSelection(style, nil)
+		s.HideCursor()
+		s.Show()
+	}()
+
+	for {
+		w, h := s.Size()
+		x = clampInt(x, 0, w-1)
+		y = clampInt(y, 0, h-1)
+		if selecting {
+			sel.EndX, sel.EndY = x, y
+			s.SetSelection(style, []Selection{sel})
+		} else {
+			s.SetSelection(style, nil)
+		}
+		s.ShowCursor(x, y)
+		s.Show()
+
+		var ev *EventKey
+		switch e := s.PollEvent().(type) {
+		case nil:
+			return "", false
+		case *EventResize:
+			s.Sync()
+			continue
+		case *EventKey:
+			ev = e
+		default:
+			continue
+		}
+
+		switch ev.Key() {
+		case KeyLeft:
+			x--
+		case KeyRight:
+			x++
+		case KeyUp:
+			y--
+		case KeyDown:
+			y++
+		case KeyHome:
+			x = 0
+		case KeyEnd:
+			x = w - 1
+		case KeyPgUp:
+			y -= h / 2
+		case KeyPgDn:
+			y += h / 2
+		case KeyCtrlV:
+			sel.Block = !sel.Block
+		case KeyEsc, KeyCtrlC:
+			return "", false
+		case KeyEnter:
+			return finishCopyMode(s, selecting, opts)
+		case KeyRune:
+			switch ev.Rune() {
+			case 'h':
+				x--
+			case 'l':
+				x++
+			case 'k':
+				y--
+			case 'j':
+				y++
+			case '0':
+				x = 0
+			case '$':
+				x = w - 1
+			case 'g':
+				y = 0
+			case 'G':
+				y = h - 1
+			case ' ', 'v':
+				selecting = !selecting
+				sel.StartX, sel.StartY = x, y
+			case 'r':
+				sel.Block = !sel.Block
+			case 'y':
+				return finishCopyMode(s, selecting, opts)
+			case 'q':
+				return "", false
+			}
+		}
+	}
+}
+
+func finishCopyMode(s Screen, selecting bool, opts CopyModeOptions) (string, bool) {
+	if !selecting {
+		return "", false
+	}
+	text := s.SelectedText()
+	if opts.Clipboard && text != "" {
+		_ = SetClipboard(s, text)
+	}
+	return text, true
+}
+
+// SetClipboard asks the terminal to place text on the system clipboard,
+// using the OSC 52 escape sequence.  Many terminals support this, although
+// some disable it by default, and some limit the amount of text.  There is
+// no way to tell whether it worked.
+func SetClipboard(s Screen, text string) error {
+	return s.EmitRaw("\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x1b\\")
+}
+
+func clampInt(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
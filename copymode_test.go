@@ -0,0 +1,53 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestCopyMode(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(6, 2)
+	for y, line := range []string{"hello", "world"} {
+		for x, r := range line {
+			s.SetContent(x, y, r, nil, StyleDefault)
+		}
+	}
+	for _, r := range "lvjl" {
+		s.InjectKey(KeyRune, r, ModNone)
+	}
+	s.InjectKey(KeyEnter, 0, ModNone)
+	text, ok := RunCopyMode(s, CopyModeOptions{Clipboard: true})
+	if !ok || text != "ello\nwor" {
+		t.Errorf("wrong selection %q %v", text, ok)
+	}
+	if _, _, vis := s.GetCursor(); vis {
+		t.Errorf("cursor left visible")
+	}
+	b, _, _ := s.GetContents()
+	for i, c := range b {
+		if c.Style != StyleDefault {
+			t.Errorf("cell %d still selected", i)
+		}
+	}
+
+	s.InjectKey(KeyRune, 'v', ModNone)
+	s.InjectKey(KeyRune, 'q', ModNone)
+	if _, ok := RunCopyMode(s, CopyModeOptions{}); ok {
+		t.Errorf("cancel did not cancel")
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package curses implements the most common calls of the curses library
+// on top of a tcell Screen, to make it easier to port programs written
+// for curses (and the examples in books about it).  The names are those
+// of curses, capitalized, and coordinates are given row first, as curses
+// has them.  Only one screen may be in use at a time.
+//
+// Some of curses has no equivalent here.  The terminal is always in raw
+// mode with the keypad enabled, so Cbreak, Noecho, Keypad and the like
+// do nothing, and there are no pads, soft labels or terminfo calls.  New
+// programs should use tcell (or views) directly.
+package curses
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Attr is a set of character attributes, and a color pair.
+type Attr uint32
+
+// These are the attributes.  Combine them with |, and with ColorPair.
+const (
+	ANormal    Attr = 0
+	AStandout  Attr = 1 << 0
+	AUnderline Attr = 1 << 1
+	AReverse   Attr = 1 << 2
+	ABlink     Attr = 1 << 3
+	ADim       Attr = 1 << 4
+	ABold      Attr = 1 << 5
+	AItalic    Attr = 1 << 6
+
+	AColor Attr = 0xffff << 16 // the bits of the color pair
+)
+
+// These are the colors of curses, for InitPair.
+const (
+	ColorBlack = iota
+	ColorRed
+	ColorGreen
+	ColorYellow
+	ColorBlue
+	ColorMagenta
+	ColorCyan
+	ColorWhite
+)
+
+// ERR is returned by Getch when there is no input.
+const ERR = -1
+
+// ErrNotInitialized is returned when there is no screen in use, because
+// Initscr (or Newterm) has not been called, or Endwin has.
+var ErrNotInitialized = errors.New("curses not initialized")
+
+var (
+	lock   sync.Mutex
+	screen tcell.Screen
+	stdscr *Window
+	events chan tcell.Event
+	pairs  map[int]tcell.Style
+	cursor = 1
+	curwin *Window
+)
+
+// Initscr starts curses on the terminal, returning the standard window,
+// which covers the whole screen.
+func Initscr() (*Window, error) {
+	s, err := tcell.NewScreen()
+	if err != nil {
+		return nil, err
+	}
+	return Newterm(s)
+}
+
+// Newterm starts curses on a screen, which need not be initialized yet,
+// returning the standard window.
+func Newterm(s tcell.Screen) (*Window, error) {
+	if err := s.Init(); err != nil {
+		return nil, err
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	screen = s
+	pairs = make(map[int]tcell.Style)
+	cursor = 1
+	w, h := s.Size()
+	stdscr = newWindow(nil, h, w, 0, 0)
+	curwin = stdscr
+	events = make(chan tcell.Event, 10)
+	go func(ch chan tcell.Event) {
+		for {
+			ev := s.PollEvent()
+			if ev == nil {
+				close(ch)
+				return
+			}
+			ch <- ev
+		}
+	}(events)
+	return stdscr, nil
+}
+
+// Endwin ends curses, restoring the terminal.
+func Endwin() {
+	lock.Lock()
+	s := screen
+	screen = nil
+	stdscr = nil
+	lock.Unlock()
+	if s != nil {
+		s.Fini()
+	}
+}
+
+// Stdscr returns the standard window.
+func Stdscr() *Window {
+	lock.Lock()
+	defer lock.Unlock()
+	return stdscr
+}
+
+// Lines returns the number of lines of the screen.
+func Lines() int {
+	lock.Lock()
+	defer lock.Unlock()
+	if stdscr == nil {
+		return 0
+	}
+	return stdscr.h
+}
+
+// Cols returns the number of columns of the screen.
+func Cols() int {
+	lock.Lock()
+	defer lock.Unlock()
+	if stdscr == nil {
+		return 0
+	}
+	return stdscr.w
+}
+
+// These do nothing, as the terminal is always in the modes that they
+// would set, but are here so that programs need not be changed.
+func Cbreak()   {}
+func Nocbreak() {}
+func Raw()      {}
+func Noraw()    {}
+func Echo()     {}
+func Noecho()   {}
+func Nl()       {}
+func Nonl()     {}
+
+// HasColors reports whether the terminal has colors.
+func HasColors() bool {
+	lock.Lock()
+	defer lock.Unlock()
+	return screen != nil && screen.Colors() >= 8
+}
+
+// StartColor enables colors.  They are always enabled, so this just
+// reports whether there is a screen.
+func StartColor() error {
+	lock.Lock()
+	defer lock.Unlock()
+	if screen == nil {
+		return ErrNotInitialized
+	}
+	return nil
+}
+
+// UseDefaultColors lets -1 be used for the default colors in InitPair.
+// They always can be.
+func UseDefaultColors() {}
+
+// InitPair defines color pair n (from 1) as the colors fg and bg, which
+// are from 0 to 255, or -1 for the default color.
+func InitPair(n, fg, bg int) {
+	lock.Lock()
+	defer lock.Unlock()
+	if pairs != nil && n > 0 {
+		pairs[n] = tcell.StyleDefault.Foreground(cursesColor(fg)).Background(cursesColor(bg))
+	}
+}
+
+func cursesColor(c int) tcell.Color {
+	if c < 0 || c > 255 {
+		return tcell.ColorDefault
+	}
+	return tcell.PaletteColor(c)
+}
+
+// ColorPair returns the attribute for color pair n.
+func ColorPair(n int) Attr {
+	return Attr(n<<16) & AColor
+}
+
+// PairNumber returns the color pair of an attribute.
+func PairNumber(a Attr) int {
+	return int((a & AColor) >> 16)
+}
+
+// CursSet sets the visibility of the cursor: 0 hides it, and 1 or 2
+// shows it.  It returns the previous visibility.
+func CursSet(visibility int) int {
+	lock.Lock()
+	defer lock.Unlock()
+	old := cursor
+	cursor = visibility
+	if screen != nil {
+		placeCursor()
+	}
+	return old
+}
+
+// placeCursor shows the cursor at the position of the last window to be
+// refreshed, as curses does.
+func placeCursor() {
+	if cursor == 0 || curwin == nil {
+		screen.HideCursor()
+		return
+	}
+	w := curwin
+	screen.ShowCursor(w.absX()+w.cx, w.absY()+w.cy)
+}
+
+// Doupdate sends the windows refreshed with Noutrefresh to the terminal.
+func Doupdate() {
+	lock.Lock()
+	defer lock.Unlock()
+	if screen != nil {
+		placeCursor()
+		screen.Show()
+	}
+}
+
+// Beep sounds the bell.
+func Beep() {
+	lock.Lock()
+	defer lock.Unlock()
+	if screen != nil {
+		_ = screen.Beep()
+	}
+}
+
+// Napms sleeps for ms milliseconds.
+func Napms(ms int) {
+	time.Sleep(time.Duration(ms) * time.Millisecond)
+}
+
+// style returns the style for an attribute.
+func style(a Attr) tcell.Style {
+	st := tcell.StyleDefault
+	if p, ok := pairs[PairNumber(a)]; ok {
+		st = p
+	}
+	return st.Bold(a&(ABold|AStandout) != 0).
+		Underline(a&AUnderline != 0).
+		Reverse(a&(AReverse|AStandout) != 0).
+		Blink(a&ABlink != 0).
+		Dim(a&ADim != 0).
+		Italic(a&AItalic != 0)
+}
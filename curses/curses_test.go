@@ -0,0 +1,69 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package curses
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestCurses(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	stdscr, err := Newterm(s)
+	if err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	defer Endwin()
+	s.SetSize(20, 5)
+
+	InitPair(1, ColorRed, -1)
+	stdscr.Attron(ABold | ColorPair(1))
+	stdscr.Mvaddstr(1, 2, "hello")
+	stdscr.Attroff(ABold)
+	stdscr.Printw("!\nx")
+	win := Newwin(3, 6, 2, 10)
+	win.Box(0, 0)
+	win.Mvaddstr(1, 1, "ok")
+	stdscr.Refresh()
+	win.Refresh()
+
+	cells, w, _ := s.GetContents()
+	cell := func(x, y int) tcell.SimCell { return cells[y*w+x] }
+	if c := cell(2, 1); c.Runes[0] != 'h' ||
+		c.Style != tcell.StyleDefault.Foreground(tcell.ColorMaroon).Bold(true) {
+		t.Errorf("wrong cell: %v", c)
+	}
+	if c := cell(7, 1); c.Runes[0] != '!' || c.Style != tcell.StyleDefault.Foreground(tcell.ColorMaroon) {
+		t.Errorf("wrong cell: %v", c)
+	}
+	if cell(0, 2).Runes[0] != 'x' || cell(10, 2).Runes[0] != tcell.RuneULCorner || cell(11, 3).Runes[0] != 'o' {
+		t.Errorf("windows not drawn")
+	}
+
+	s.InjectKey(tcell.KeyUp, 0, tcell.ModNone)
+	s.InjectKey(tcell.KeyRune, 'q', tcell.ModNone)
+	s.InjectKey(tcell.KeyCtrlC, 0, tcell.ModCtrl)
+	stdscr.Timeout(1000)
+	for _, want := range []int{KeyUp, 'q', 3} {
+		if got := stdscr.Getch(); got != want {
+			t.Errorf("got key %d, expected %d", got, want)
+		}
+	}
+	stdscr.Timeout(10)
+	if got := stdscr.Getch(); got != ERR {
+		t.Errorf("got key %d, expected none", got)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package curses
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// These are the key codes that Getch returns for keys that are not
+// characters, with the values that curses gives them.
+const (
+	KeyDown      = 0402
+	KeyUp        = 0403
+	KeyLeft      = 0404
+	KeyRight     = 0405
+	KeyHome      = 0406
+	KeyBackspace = 0407
+	KeyF0        = 0410 // KeyF0+n is function key n
+	KeyDC        = 0512 // delete
+	KeyIC        = 0513 // insert
+	KeyNPage     = 0522 // page down
+	KeyPPage     = 0523 // page up
+	KeyEnter     = 0527 // the enter key of the keypad
+	KeyBTab      = 0541 // back tab
+	KeyEnd       = 0550
+	KeyResize    = 0632 // the screen has been resized
+)
+
+var cursesKeys = map[tcell.Key]int{
+	tcell.KeyDown:       KeyDown,
+	tcell.KeyUp:         KeyUp,
+	tcell.KeyLeft:       KeyLeft,
+	tcell.KeyRight:      KeyRight,
+	tcell.KeyHome:       KeyHome,
+	tcell.KeyEnd:        KeyEnd,
+	tcell.KeyBackspace:  KeyBackspace,
+	tcell.KeyBackspace2: KeyBackspace,
+	tcell.KeyDelete:     KeyDC,
+	tcell.KeyInsert:     KeyIC,
+	tcell.KeyPgDn:       KeyNPage,
+	tcell.KeyPgUp:       KeyPPage,
+	tcell.KeyBacktab:    KeyBTab,
+	tcell.KeyEnter:      '\n',
+	tcell.KeyKPEnter:    KeyEnter,
+}
+
+// cursesKey returns the curses code for a key, or ERR if it has none.
+func cursesKey(ev *tcell.EventKey) int {
+	k := ev.Key()
+	switch {
+	case k == tcell.KeyRune:
+		return int(ev.Rune())
+	case k >= tcell.KeyF1 && k <= tcell.KeyF64:
+		return KeyF0 + int(k-tcell.KeyF1) + 1
+	}
+	if c, ok := cursesKeys[k]; ok {
+		return c
+	}
+	if k < 0x80 {
+		// The control keys are their own codes, as are Tab and Esc.
+		return int(k)
+	}
+	return ERR
+}
+
+// Getch refreshes the window, and then returns the next key pressed, or
+// ERR if none was pressed in the time set by Timeout.  Characters are
+// returned as themselves, and other keys as the Key constants.  When the
+// screen is resized, the standard window is resized too, and KeyResize
+// is returned.
+func (w *Window) Getch() int {
+	w.Refresh()
+	lock.Lock()
+	delay := w.delay
+	ch := events
+	lock.Unlock()
+	if ch == nil {
+		return ERR
+	}
+	var timeout <-chan time.Time
+	if delay >= 0 {
+		t := time.NewTimer(time.Duration(delay) * time.Millisecond)
+		defer t.Stop()
+		timeout = t.C
+	}
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return ERR
+			}
+			switch ev := ev.(type) {
+			case *tcell.EventKey:
+				if c := cursesKey(ev); c != ERR {
+					return c
+				}
+			case *tcell.EventResize:
+				resize(ev.Size())
+				return KeyResize
+			}
+		case <-timeout:
+			return ERR
+		}
+	}
+}
+
+// resize resizes the standard window to the new size of the screen,
+// keeping what it can of the content.
+func resize(cols, lines int) {
+	lock.Lock()
+	defer lock.Unlock()
+	if stdscr == nil {
+		return
+	}
+	stdscr.w, stdscr.h = cols, lines
+	stdscr.cells.Resize(cols, lines)
+	if stdscr.cx >= cols || stdscr.cy >= lines {
+		stdscr.cx, stdscr.cy = 0, 0
+	}
+	screen.Sync()
+}
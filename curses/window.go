@@ -0,0 +1,445 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package curses
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// Window is a window of curses.  Each has content of its own, which is
+// copied to the screen by Refresh; windows may overlap, and the last to
+// be refreshed is shown on top.
+type Window struct {
+	parent   *Window
+	x, y     int // relative to the parent, if any
+	w, h     int
+	cx, cy   int
+	attrs    Attr
+	bkgd     Attr
+	cells    tcell.CellBuffer
+	clear    bool
+	scroll   bool
+	delay    int // milliseconds, or < 0 to block
+	subwins  []*Window
+	released bool
+}
+
+func newWindow(parent *Window, h, w, y, x int) *Window {
+	win := &Window{parent: parent, x: x, y: y, w: w, h: h, delay: -1}
+	win.cells.Resize(w, h)
+	win.cells.Fill(' ', tcell.StyleDefault)
+	return win
+}
+
+// Newwin creates a window of nlines by ncols, with its top left corner
+// at row y and column x of the screen.  Zero for nlines or ncols extends
+// the window to the edge of the screen.
+func Newwin(nlines, ncols, y, x int) *Window {
+	lock.Lock()
+	defer lock.Unlock()
+	if stdscr == nil {
+		return nil
+	}
+	if nlines <= 0 {
+		nlines = stdscr.h - y
+	}
+	if ncols <= 0 {
+		ncols = stdscr.w - x
+	}
+	return newWindow(nil, nlines, ncols, y, x)
+}
+
+// Derwin creates a window within w, at row y and column x of w.  Unlike
+// curses, the two do not share content; the subwindow is drawn over its
+// parent whenever the parent is refreshed.
+func (w *Window) Derwin(nlines, ncols, y, x int) *Window {
+	lock.Lock()
+	defer lock.Unlock()
+	if nlines <= 0 {
+		nlines = w.h - y
+	}
+	if ncols <= 0 {
+		ncols = w.w - x
+	}
+	sub := newWindow(w, nlines, ncols, y, x)
+	w.subwins = append(w.subwins, sub)
+	return sub
+}
+
+// Delwin deletes a window.  It is no longer drawn with its parent.
+func (w *Window) Delwin() {
+	lock.Lock()
+	defer lock.Unlock()
+	w.released = true
+	if p := w.parent; p != nil {
+		for i, sub := range p.subwins {
+			if sub == w {
+				p.subwins = append(p.subwins[:i], p.subwins[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func (w *Window) absX() int {
+	if w.parent != nil {
+		return w.parent.absX() + w.x
+	}
+	return w.x
+}
+
+func (w *Window) absY() int {
+	if w.parent != nil {
+		return w.parent.absY() + w.y
+	}
+	return w.y
+}
+
+// Getmaxyx returns the size of the window.
+func (w *Window) Getmaxyx() (int, int) {
+	lock.Lock()
+	defer lock.Unlock()
+	return w.h, w.w
+}
+
+// Getbegyx returns the position of the window on the screen.
+func (w *Window) Getbegyx() (int, int) {
+	lock.Lock()
+	defer lock.Unlock()
+	return w.absY(), w.absX()
+}
+
+// Getyx returns the position of the cursor in the window.
+func (w *Window) Getyx() (int, int) {
+	lock.Lock()
+	defer lock.Unlock()
+	return w.cy, w.cx
+}
+
+// Move moves the cursor to row y and column x of the window.  Positions
+// outside of the window are ignored.
+func (w *Window) Move(y, x int) {
+	lock.Lock()
+	defer lock.Unlock()
+	w.move(y, x)
+}
+
+func (w *Window) move(y, x int) bool {
+	if x < 0 || y < 0 || x >= w.w || y >= w.h {
+		return false
+	}
+	w.cx, w.cy = x, y
+	return true
+}
+
+// Attron turns on attributes (and replaces the color pair, if one is
+// given).
+func (w *Window) Attron(a Attr) {
+	lock.Lock()
+	defer lock.Unlock()
+	if a&AColor != 0 {
+		w.attrs &^= AColor
+	}
+	w.attrs |= a
+}
+
+// Attroff turns off attributes.
+func (w *Window) Attroff(a Attr) {
+	lock.Lock()
+	defer lock.Unlock()
+	w.attrs &^= a
+}
+
+// Attrset sets the attributes.
+func (w *Window) Attrset(a Attr) {
+	lock.Lock()
+	defer lock.Unlock()
+	w.attrs = a
+}
+
+// Bkgd sets the background of the window, which is used when clearing
+// it, and combined with the attributes of what is added.  Unlike curses,
+// the content already there is left alone.
+func (w *Window) Bkgd(a Attr) {
+	lock.Lock()
+	defer lock.Unlock()
+	w.bkgd = a
+}
+
+// Scrollok sets whether the window scrolls up when something is added
+// after the end of the last line.
+func (w *Window) Scrollok(on bool) {
+	lock.Lock()
+	defer lock.Unlock()
+	w.scroll = on
+}
+
+// Keypad does nothing, as the keypad is always enabled.
+func (w *Window) Keypad(on bool) {}
+
+// Timeout sets how long Getch waits for input, in milliseconds.  Less
+// than zero (the default) waits for ever, and zero does not wait.
+func (w *Window) Timeout(ms int) {
+	lock.Lock()
+	defer lock.Unlock()
+	w.delay = ms
+}
+
+// Nodelay sets whether Getch waits for input.
+func (w *Window) Nodelay(on bool) {
+	if on {
+		w.Timeout(0)
+	} else {
+		w.Timeout(-1)
+	}
+}
+
+// attrStyle returns the style for the current attributes.
+func (w *Window) attrStyle(a Attr) tcell.Style {
+	if a&AColor == 0 {
+		a |= w.bkgd & AColor
+	}
+	return style(a | w.bkgd&^AColor)
+}
+
+// addch adds a rune at the cursor, and advances it.
+func (w *Window) addch(r rune, a Attr) {
+	switch r {
+	case '\n':
+		w.clrtoeol()
+		w.newline()
+		return
+	case '\r':
+		w.cx = 0
+		return
+	case '\b':
+		if w.cx > 0 {
+			w.cx--
+		}
+		return
+	case '\t':
+		for n := 8 - w.cx%8; n > 0; n-- {
+			w.addch(' ', a)
+		}
+		return
+	}
+	width := runewidth.RuneWidth(r)
+	if width == 0 {
+		// A combining character goes with the one before.
+		if x := w.cx - 1; x >= 0 {
+			mainc, combc, st, _ := w.cells.GetContent(x, w.cy)
+			w.cells.SetContent(x, w.cy, mainc, append(combc, r), st)
+		}
+		return
+	}
+	if w.cx+width > w.w {
+		w.newline()
+	}
+	w.cells.SetContent(w.cx, w.cy, r, nil, w.attrStyle(a))
+	w.cx += width
+	if w.cx >= w.w {
+		w.newline()
+	}
+}
+
+// newline moves the cursor to the start of the next line, scrolling if
+// that is enabled and it is already on the last.
+func (w *Window) newline() {
+	w.cx = 0
+	if w.cy < w.h-1 {
+		w.cy++
+	} else if w.scroll {
+		w.scrollUp(1)
+	} else {
+		w.cx = w.w - 1
+	}
+}
+
+func (w *Window) scrollUp(n int) {
+	for y := 0; y < w.h; y++ {
+		for x := 0; x < w.w; x++ {
+			if y+n < w.h {
+				mainc, combc, st, _ := w.cells.GetContent(x, y+n)
+				w.cells.SetContent(x, y, mainc, combc, st)
+			} else {
+				w.cells.SetContent(x, y, ' ', nil, w.attrStyle(0))
+			}
+		}
+	}
+}
+
+// Addch adds a character at the cursor, with the window's attributes
+// and those given.
+func (w *Window) Addch(r rune, a Attr) {
+	lock.Lock()
+	defer lock.Unlock()
+	w.addch(r, w.attrs|a)
+}
+
+// Mvaddch moves the cursor, and then adds a character.
+func (w *Window) Mvaddch(y, x int, r rune, a Attr) {
+	lock.Lock()
+	defer lock.Unlock()
+	if w.move(y, x) {
+		w.addch(r, w.attrs|a)
+	}
+}
+
+// Addstr adds a string at the cursor.
+func (w *Window) Addstr(s string) {
+	lock.Lock()
+	defer lock.Unlock()
+	for _, r := range s {
+		w.addch(r, w.attrs)
+	}
+}
+
+// Mvaddstr moves the cursor, and then adds a string.
+func (w *Window) Mvaddstr(y, x int, s string) {
+	lock.Lock()
+	defer lock.Unlock()
+	if !w.move(y, x) {
+		return
+	}
+	for _, r := range s {
+		w.addch(r, w.attrs)
+	}
+}
+
+// Printw adds formatted text at the cursor, as with fmt.Sprintf.
+func (w *Window) Printw(format string, args ...interface{}) {
+	w.Addstr(fmt.Sprintf(format, args...))
+}
+
+// Mvprintw moves the cursor, and then adds formatted text.
+func (w *Window) Mvprintw(y, x int, format string, args ...interface{}) {
+	w.Mvaddstr(y, x, fmt.Sprintf(format, args...))
+}
+
+// Erase clears the window to its background.
+func (w *Window) Erase() {
+	lock.Lock()
+	defer lock.Unlock()
+	w.cells.Fill(' ', w.attrStyle(0))
+	w.cx, w.cy = 0, 0
+}
+
+// Clear clears the window, as Erase does, and also has the whole screen
+// redrawn when the window is next refreshed.
+func (w *Window) Clear() {
+	w.Erase()
+	lock.Lock()
+	w.clear = true
+	lock.Unlock()
+}
+
+// Clrtoeol clears from the cursor to the end of the line.
+func (w *Window) Clrtoeol() {
+	lock.Lock()
+	defer lock.Unlock()
+	w.clrtoeol()
+}
+
+func (w *Window) clrtoeol() {
+	for x := w.cx; x < w.w; x++ {
+		w.cells.SetContent(x, w.cy, ' ', nil, w.attrStyle(0))
+	}
+}
+
+// Clrtobot clears from the cursor to the end of the window.
+func (w *Window) Clrtobot() {
+	lock.Lock()
+	defer lock.Unlock()
+	w.clrtoeol()
+	for y := w.cy + 1; y < w.h; y++ {
+		for x := 0; x < w.w; x++ {
+			w.cells.SetContent(x, y, ' ', nil, w.attrStyle(0))
+		}
+	}
+}
+
+// Box draws a box around the edge of the window, with vert for the sides
+// and horiz for the top and bottom.  Zero for either uses the lines of
+// the box drawing characters.
+func (w *Window) Box(vert, horiz rune) {
+	lock.Lock()
+	defer lock.Unlock()
+	if vert == 0 {
+		vert = tcell.RuneVLine
+	}
+	if horiz == 0 {
+		horiz = tcell.RuneHLine
+	}
+	st := w.attrStyle(w.attrs)
+	for x := 1; x < w.w-1; x++ {
+		w.cells.SetContent(x, 0, horiz, nil, st)
+		w.cells.SetContent(x, w.h-1, horiz, nil, st)
+	}
+	for y := 1; y < w.h-1; y++ {
+		w.cells.SetContent(0, y, vert, nil, st)
+		w.cells.SetContent(w.w-1, y, vert, nil, st)
+	}
+	w.cells.SetContent(0, 0, tcell.RuneULCorner, nil, st)
+	w.cells.SetContent(w.w-1, 0, tcell.RuneURCorner, nil, st)
+	w.cells.SetContent(0, w.h-1, tcell.RuneLLCorner, nil, st)
+	w.cells.SetContent(w.w-1, w.h-1, tcell.RuneLRCorner, nil, st)
+}
+
+// Touchwin has the whole of the window drawn when it is next refreshed.
+// Windows are always drawn whole, so this does nothing.
+func (w *Window) Touchwin() {}
+
+// Noutrefresh copies the window (and its subwindows) to the screen, but
+// does not update the terminal until Doupdate is called.
+func (w *Window) Noutrefresh() {
+	lock.Lock()
+	defer lock.Unlock()
+	w.noutrefresh()
+}
+
+func (w *Window) noutrefresh() {
+	if screen == nil || w.released {
+		return
+	}
+	if w.clear {
+		w.clear = false
+		screen.Sync()
+	}
+	x0, y0 := w.absX(), w.absY()
+	for y := 0; y < w.h; y++ {
+		for x := 0; x < w.w; x++ {
+			mainc, combc, st, _ := w.cells.GetContent(x, y)
+			screen.SetContent(x0+x, y0+y, mainc, combc, st)
+		}
+	}
+	for _, sub := range w.subwins {
+		sub.noutrefresh()
+	}
+	curwin = w
+}
+
+// Refresh copies the window to the screen, and updates the terminal.
+func (w *Window) Refresh() {
+	lock.Lock()
+	defer lock.Unlock()
+	w.noutrefresh()
+	if screen != nil {
+		placeCursor()
+		screen.Show()
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bytes"
+
+	"github.com/gdamore/tcell/v2/terminfo"
+)
+
+// InputDecoder converts the bytes sent by a terminal into events, in
+// exactly the same way that a terminfo based Screen does, but without
+// a terminal.  This is useful for testing, and for fuzzing the decoder.
+// A decoder remembers a little state between calls (such as a pending
+// ESC that makes the next key an Alt key), so each stream of input
+// should have its own.  It is not safe for concurrent use.
+type InputDecoder struct {
+	t *tScreen
+}
+
+// NewInputDecoder returns a decoder for input from the given terminal,
+// which is assumed to be using UTF-8.
+func NewInputDecoder(ti *terminfo.Terminfo) (*InputDecoder, error) {
+	s, err := NewTerminfoScreenFromTtyTerminfo(nil, ti)
+	if err != nil {
+		return nil, err
+	}
+	t := s.(*tScreen)
+	t.charset = "UTF-8"
+	t.decoder = GetEncoding(t.charset).NewDecoder()
+	return &InputDecoder{t: t}, nil
+}
+
+// Decode decodes as much of b as it can, returning the events and the
+// number of bytes used.  Bytes that may be the start of a longer sequence
+// are left unused, unless final is true, in which case they are decoded
+// as well as possible (as a Screen does when no more input arrives
+// shortly after them).  So when final is true, all of b is always used.
+func (d *InputDecoder) Decode(b []byte, final bool) ([]Event, int) {
+	buf := bytes.NewBuffer(append([]byte{}, b...))
+	evs := d.t.collectEventsFromInput(buf, final)
+	res := evs[:0]
+	for _, ev := range evs {
+		// Unknown sequences only go to the OnUnknownSequence handler.
+		if _, ok := ev.(*eventSequence); !ok {
+			res = append(res, ev)
+		}
+	}
+	return res, len(b) - buf.Len()
+}
+
+// DecodeInput decodes b, which should be a complete piece of input from
+// the given terminal, returning the events.  It is a convenience for
+// decoding with a new InputDecoder.
+func DecodeInput(ti *terminfo.Terminfo, b []byte) ([]Event, error) {
+	d, err := NewInputDecoder(ti)
+	if err != nil {
+		return nil, err
+	}
+	evs, _ := d.Decode(b, true)
+	return evs, nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package tcell
+
+import (
+	"testing"
+)
+
+// FuzzInputDecoder checks that no input can crash the decoder, or leave
+// it unable to make progress.  Run it with:
+//
+//	go test -fuzz FuzzInputDecoder
+func FuzzInputDecoder(f *testing.F) {
+	for _, seed := range decoderSeeds {
+		f.Add([]byte(seed))
+	}
+	var decoders []*InputDecoder
+	for _, term := range []string{"xterm", "xterm-kitty", "vt100"} {
+		ti, err := LookupTerminfo(term)
+		if err != nil {
+			f.Fatalf("no terminfo for %s: %v", term, err)
+		}
+		d, err := NewInputDecoder(ti)
+		if err != nil {
+			f.Fatalf("failed to create decoder: %v", err)
+		}
+		decoders = append(decoders, d)
+	}
+	f.Fuzz(func(t *testing.T, in []byte) {
+		for _, d := range decoders {
+			checkDecode(t, d, in)
+		}
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+// decoderSeeds are inputs, many of them malformed, used to check the
+// decoder (and to seed the fuzzer).
+var decoderSeeds = []string{
+	"",
+	"a",
+	"\x1b",
+	"\x1b\x1b\x1b",
+	"\x1b[A",
+	"\x1b[1;5A",
+	"\x1b[1;",
+	"\x1b[;;;;;;;;;;;;;;;;;;;;;;;;;;~",
+	"\x1b[99999999999999999999;99999999999999999999~",
+	"\x1b[<0;1;1M",
+	"\x1b[<0;99999;-1m",
+	"\x1b[M",
+	"\x1b[M\xff\xff\xff",
+	"\x1b[200~paste\x1b[201~",
+	"\x1b[200~unterminated",
+	"\x1b]11;rgb:0000/0000/0000\x07",
+	"\x1b]unterminated",
+	"\x1bP1$r0m\x1b\\",
+	"\x1bO",
+	"\xc3",
+	"\xff\xfe\xfd",
+	"\x00\x01\x7f",
+}
+
+func checkDecode(t *testing.T, d *InputDecoder, in []byte) {
+	_, n := d.Decode(in, false)
+	if n < 0 || n > len(in) {
+		t.Fatalf("%q: consumed %d of %d bytes", in, n, len(in))
+	}
+	// Whatever was left must be consumed once no more input comes.
+	_, m := d.Decode(in[n:], true)
+	if m != len(in)-n {
+		t.Fatalf("%q: final decode consumed %d of %d bytes", in, m, len(in)-n)
+	}
+}
+
+func TestInputDecoder(t *testing.T) {
+	ti, err := LookupTerminfo("xterm")
+	if err != nil {
+		t.Fatalf("no terminfo: %v", err)
+	}
+	d, err := NewInputDecoder(ti)
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	for _, seed := range decoderSeeds {
+		checkDecode(t, d, []byte(seed))
+	}
+
+	evs, n := d.Decode([]byte("x\x1b[1;"), false)
+	if len(evs) != 1 || n != 1 {
+		t.Errorf("partial sequence not left: %d events, %d bytes", len(evs), n)
+	}
+	evs, err = DecodeInput(ti, []byte("\x1b[Ab"))
+	if err != nil || len(evs) != 2 || evs[0].(*EventKey).Key() != KeyUp {
+		t.Errorf("wrong events: %v", evs)
+	}
+}
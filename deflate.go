@@ -0,0 +1,73 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"compress/flate"
+	"sync"
+)
+
+// deflateTty compresses the output written to another Tty.
+type deflateTty struct {
+	Tty
+	zw *flate.Writer
+	sync.Mutex
+}
+
+// NewDeflateTty returns a Tty that compresses everything written to tty
+// using deflate (RFC 1951), at the given level (see compress/flate).
+// Input is passed through unchanged.  This is intended for a Tty that
+// carries the screen over a slow network link, when the other end (which
+// must be written specially) decompresses the stream before passing it
+// to the real terminal.  Whether to compress is up to the application,
+// and so is any negotiation with the other end.
+//
+// The stream is flushed after each write, which tcell makes once for
+// each update of the screen, so that the other end can decode every
+// update as soon as it arrives.
+func NewDeflateTty(tty Tty, level int) (Tty, error) {
+	zw, err := flate.NewWriter(tty, level)
+	if err != nil {
+		return nil, err
+	}
+	return &deflateTty{Tty: tty, zw: zw}, nil
+}
+
+func (d *deflateTty) Write(b []byte) (int, error) {
+	d.Lock()
+	defer d.Unlock()
+	n, err := d.zw.Write(b)
+	if err != nil {
+		return n, err
+	}
+	return n, d.zw.Flush()
+}
+
+// Close finishes the compressed stream, and then closes the Tty.
+func (d *deflateTty) Close() error {
+	d.Lock()
+	err := d.zw.Close()
+	d.Unlock()
+	if e := d.Tty.Close(); e != nil {
+		return e
+	}
+	return err
+}
+
+func (d *deflateTty) DisableSignals(on bool) {
+	if st, ok := d.Tty.(SignalDriver); ok {
+		st.DisableSignals(on)
+	}
+}
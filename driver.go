@@ -0,0 +1,52 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// Driver is the contract between the terminfo screen and the device that
+// it runs on, and is what other packages implement to provide a backend
+// of their own (for a console that is not a UNIX tty, say, or a network
+// connection).  It is the same interface as Tty, and either name may be
+// used; a Driver is given to the screen with NewTerminfoScreenFromTty,
+// or with WithTty.
+//
+// The screen uses a Driver as follows, and this will not change:
+//
+//   - Start is called by Init and Resume, before any Read or Write.
+//   - Read is called from a single goroutine of its own, and should block
+//     until there is input.  It may return 0 bytes and no error (for
+//     example on a timeout); any error ends the input, and is posted as
+//     an EventError.
+//   - Write is never called concurrently, and is generally given a
+//     whole update at once, so the Driver need not buffer.
+//   - WindowSize is called whenever the size may have changed, and once
+//     after Start.  A size of zero means that it is unknown.
+//   - NotifyResize registers a function (or nil to remove it) that the
+//     Driver calls, from any goroutine, when the size may have changed.
+//   - Drain and then Stop are called by Fini and Suspend.  Drain must
+//     cause a blocked Read to return promptly.  After Stop there are no
+//     more calls, until Start again (after Suspend) or Close.
+//
+// The Driver sees only bytes; everything to do with the terminal itself
+// is done by the screen, using the terminfo description.
+type Driver = Tty
+
+// SignalDriver is implemented by drivers that install handlers for
+// signals of their own (for SIGWINCH), so that the screen can ask them
+// not to when the application calls DisableSignalHandling.  It is called
+// before Start.
+type SignalDriver interface {
+	Driver
+	DisableSignals(disable bool)
+}
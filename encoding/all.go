@@ -49,6 +49,15 @@ func Register() {
 	tcell.RegisterEncoding("KOI8-R", charmap.KOI8R)
 	tcell.RegisterEncoding("KOI8-U", charmap.KOI8U)
 
+	// DOS and Windows code pages, as used by many older systems
+	tcell.RegisterEncoding("CP437", charmap.CodePage437)
+	tcell.RegisterEncoding("CP850", charmap.CodePage850)
+	tcell.RegisterEncoding("CP852", charmap.CodePage852)
+	tcell.RegisterEncoding("CP866", charmap.CodePage866)
+	tcell.RegisterEncoding("CP1250", charmap.Windows1250)
+	tcell.RegisterEncoding("CP1251", charmap.Windows1251)
+	tcell.RegisterEncoding("CP1252", charmap.Windows1252)
+
 	// Asian stuff
 	tcell.RegisterEncoding("EUC-JP", japanese.EUCJP)
 	tcell.RegisterEncoding("SHIFT_JIS", japanese.ShiftJIS)
@@ -91,6 +100,18 @@ func Register() {
 		"8859-9":      "ISO8859-9",
 		"ISO-8859-9":  "ISO8859-9",
 
+		"IBM437":       "CP437",
+		"437":          "CP437",
+		"IBM850":       "CP850",
+		"850":          "CP850",
+		"IBM852":       "CP852",
+		"852":          "CP852",
+		"IBM866":       "CP866",
+		"866":          "CP866",
+		"WINDOWS-1250": "CP1250",
+		"WINDOWS-1251": "CP1251",
+		"WINDOWS-1252": "CP1252",
+
 		"SJIS":        "Shift_JIS",
 		"EUCJP":       "EUC-JP",
 		"2022-JP":     "ISO2022JP",
@@ -0,0 +1,104 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"os"
+)
+
+// The environment variables that a screen reads are all read with its
+// getenv method, so that they can be ignored as a whole (see WithoutEnv);
+// the Windows console screen has one of its own, as it has no quirks.
+// They take precedence over the options given to NewScreenWithOptions,
+// as they are how users correct what an application (or the terminal
+// database) has got wrong.
+//
+//   TCELL_FORCE_TERM   the terminal to use, in place of $TERM
+//   TCELL_TRUECOLOR    "disable" to not use 24-bit color, or anything
+//                      else to use it even if $COLORTERM does not ask
+//   TCELL_ALTSCREEN    "disable" to draw on the main screen, or "enable"
+//   TCELL_MOUSE        "disable" to never enable mouse reporting
+//   TCELL_ACS          "always" or "never" (see buildAcsMap)
+//   TCELL_C1           "enable" to use 8-bit controls (see c1.go)
+//   TCELL_SOFTBLINK    "enable" or "disable" blinking in software
+//   TCELL_NOTIFY       "osc9", "osc777", "osc99" or "disable"
+//   TCELL_PROGRESS     "enable" or "disable" progress reporting
+//   TCELL_MARGINS      "enable" or "disable" scrolling with left and right
+//                      margins (see scroll.go)
+//   TCELL_REP          "enable" or "disable" repeating characters with REP
+//                      (see repeat.go)
+//   TCELL_GLYPHS       the glyph sets that the font has (see glyphs.go)
+//   TCELL_QUIRKS       the quirks file to read (see quirks.go)
+//   TCELL_IDENTIFY     "disable" to not ask the terminal what it is
+//   COLORTERM          "truecolor" or "24bit" to use 24-bit color
+//   LINES, COLUMNS     the size, for terminals that cannot report it
+//   TCELL_VTINPUT      "disable" to read the Windows console's legacy key
+//                      records instead of VT input (see console_win.go)
+//
+// A few others (TERM_PROGRAM, WT_SESSION and ConEmuPID) are used to
+// recognize terminals.  $TERM itself, and the locale, are always read,
+// as without them we cannot talk to the terminal at all; WithTERM avoids
+// the former.  When a Session is replayed, all of these are read from it
+// instead (see session.go).
+
+// getenv returns the value of an environment variable, or else of the
+// user's quirk for it (see quirks.go), or else of the quirk for the
+// terminal as it has identified itself (see termid.go), or nothing if the
+// environment is not to be read.
+func (t *tScreen) getenv(name string) string {
+	if t.noEnv {
+		return ""
+	}
+	if v := t.osGetenv(name); v != "" {
+		return v
+	}
+	if v := t.quirks[name]; v != "" {
+		return v
+	}
+	return t.idQuirks[name]
+}
+
+// termName returns the name of the terminal to use, which is given by
+// name, or by the environment.
+func (t *tScreen) termName(name string) string {
+	if force := t.getenv("TCELL_FORCE_TERM"); force != "" {
+		return force
+	}
+	if name != "" {
+		return name
+	}
+	return t.osGetenv("TERM")
+}
+
+// osGetenv returns the value of an environment variable, as it is in the
+// session being replayed, if any.
+func (t *tScreen) osGetenv(name string) string {
+	if t.session != nil {
+		return t.session.Env[name]
+	}
+	return os.Getenv(name)
+}
+
+// prepareEnv applies the overrides that are not specific to any one
+// feature.
+func (t *tScreen) prepareEnv() {
+	switch t.getenv("TCELL_ALTSCREEN") {
+	case "enable":
+		t.noAltScreen = false
+	case "disable":
+		t.noAltScreen = true
+	}
+	t.noMouse = t.getenv("TCELL_MOUSE") == "disable"
+}
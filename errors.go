@@ -48,6 +48,43 @@ var (
 	// ErrEventQFull indicates that the event queue is full, and
 	// cannot accept more events.
 	ErrEventQFull = errors.New("event queue full")
+
+	// ErrInvalidSequence indicates that a string passed to EmitRaw
+	// is not a single, complete escape sequence.
+	ErrInvalidSequence = errors.New("invalid escape sequence")
+
+	// ErrQueryTimeout indicates that the terminal did not reply to a
+	// query in time.  Many terminals simply ignore queries that they do
+	// not understand.
+	ErrQueryTimeout = errors.New("timed out waiting for terminal")
+
+	// ErrQueryTooLong indicates that the reply to a query was longer
+	// than tcell is prepared to accept, and was discarded.
+	ErrQueryTooLong = errors.New("terminal reply too long")
+
+	// ErrNotSupported indicates that the terminal does not support the
+	// requested operation.
+	ErrNotSupported = errors.New("operation not supported by terminal")
+
+	// ErrInvalidColor indicates that a string given to ParseColor is
+	// not a recognized color name or notation.
+	ErrInvalidColor = errors.New("invalid color")
+
+	// ErrInvalidStyle indicates that text given to Style.UnmarshalText
+	// could not be parsed.
+	ErrInvalidStyle = errors.New("invalid style")
+
+	// ErrNoSuchState indicates that RestoreState was given an id that
+	// was not returned by SaveState, or that has already been restored.
+	ErrNoSuchState = errors.New("no such saved state")
+
+	// ErrInvalidSoftFont indicates that a SoftFont has an unusable
+	// glyph size, or too few or too many glyphs.
+	ErrInvalidSoftFont = errors.New("invalid soft font")
+
+	// ErrInvalidSession indicates that a Session given to ReplaySession
+	// was not captured from a terminfo screen, and so cannot be replayed.
+	ErrInvalidSession = errors.New("session cannot be replayed")
 )
 
 // An EventError is an event representing some sort of error, and carries
@@ -0,0 +1,243 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// These are the colors used by ToSVG for cells that use the default
+// colors, as SVG has no notion of a terminal's own colors.
+const (
+	svgDefaultFg   = "#c0c0c0"
+	svgDefaultBg   = "#000000"
+	svgCellWidth   = 8
+	svgCellHeight  = 16
+	svgFontSize    = 13
+	svgFontFamily  = "monospace"
+	svgBaselineOff = 12
+)
+
+// exportRun is a run of cells on one row which share a style.
+type exportRun struct {
+	x     int
+	cells int
+	text  string
+	style Style
+}
+
+// runs returns the runs of identically styled cells on row y.
+// The second column of wide characters is skipped over.
+func (cb *CellBuffer) runs(y int) []exportRun {
+	var runs []exportRun
+	var sb strings.Builder
+	run := exportRun{}
+	for x := 0; x < cb.w; {
+		mainc, combc, style, width := cb.GetContent(x, y)
+		if sb.Len() > 0 && style != run.style {
+			run.text = sb.String()
+			runs = append(runs, run)
+			sb.Reset()
+		}
+		if sb.Len() == 0 {
+			run = exportRun{x: x, style: style}
+		}
+		sb.WriteRune(mainc)
+		for _, r := range combc {
+			sb.WriteRune(r)
+		}
+		run.cells += width
+		x += width
+	}
+	if sb.Len() > 0 {
+		run.text = sb.String()
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// ansiColor returns the SGR parameters for a color; base is 30 for
+// foreground and 40 for background.
+func ansiColor(c Color, base int) string {
+	switch {
+	case !c.Valid():
+		return ""
+	case c.IsRGB():
+		r, g, b := c.RGB()
+		return fmt.Sprintf("%d;2;%d;%d;%d", base+8, r, g, b)
+	}
+	n := int(c - ColorValid)
+	switch {
+	case n < 8:
+		return strconv.Itoa(base + n)
+	case n < 16:
+		return strconv.Itoa(base + 60 + n - 8)
+	}
+	return fmt.Sprintf("%d;5;%d", base+8, n)
+}
+
+// ansiStyle returns the SGR sequence that selects the style.
+func ansiStyle(style Style) string {
+	fg, bg, attr := style.Decompose()
+	params := []string{"0"}
+	for _, a := range []struct {
+		mask AttrMask
+		p    string
+	}{
+		{AttrBold, "1"},
+		{AttrDim, "2"},
+		{AttrItalic, "3"},
+		{AttrUnderline, "4"},
+		{AttrBlink, "5"},
+		{AttrReverse, "7"},
+		{AttrStrikeThrough, "9"},
+	} {
+		if attr&a.mask != 0 {
+			params = append(params, a.p)
+		}
+	}
+	if p := ansiColor(fg, 30); p != "" {
+		params = append(params, p)
+	}
+	if p := ansiColor(bg, 40); p != "" {
+		params = append(params, p)
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// ToANSI returns the contents of the buffer as text with ANSI (ECMA-48)
+// escape sequences for colors and attributes, suitable for displaying
+// with cat(1) on most terminals.  Each row ends with a newline.
+func (cb *CellBuffer) ToANSI() string {
+	var sb strings.Builder
+	for y := 0; y < cb.h; y++ {
+		for _, run := range cb.runs(y) {
+			sb.WriteString(ansiStyle(run.style))
+			sb.WriteString(run.text)
+		}
+		sb.WriteString("\x1b[0m\n")
+	}
+	return sb.String()
+}
+
+// exportColors returns the foreground and background colors of a style
+// as CSS colors, taking reverse video into account.  An empty string
+// means the default color.
+func exportColors(style Style, defFg, defBg string) (string, string) {
+	fg, bg, attr := style.Decompose()
+	css := func(c Color, def string) string {
+		if !c.Valid() {
+			return def
+		}
+		return fmt.Sprintf("#%06x", c.Hex())
+	}
+	if attr&AttrReverse != 0 {
+		return css(bg, defBg), css(fg, defFg)
+	}
+	return css(fg, defFg), css(bg, defBg)
+}
+
+// exportFont returns the CSS (or SVG) font properties for a style.
+func exportFont(style Style, sep string) []string {
+	_, _, attr := style.Decompose()
+	var props []string
+	if attr&AttrBold != 0 {
+		props = append(props, "font-weight"+sep+"bold")
+	}
+	if attr&AttrItalic != 0 {
+		props = append(props, "font-style"+sep+"italic")
+	}
+	if attr&AttrDim != 0 {
+		props = append(props, "opacity"+sep+"0.5")
+	}
+	switch {
+	case attr&AttrUnderline != 0 && attr&AttrStrikeThrough != 0:
+		props = append(props, "text-decoration"+sep+"underline line-through")
+	case attr&AttrUnderline != 0:
+		props = append(props, "text-decoration"+sep+"underline")
+	case attr&AttrStrikeThrough != 0:
+		props = append(props, "text-decoration"+sep+"line-through")
+	}
+	return props
+}
+
+// ToHTML returns the contents of the buffer as an HTML <pre> element,
+// with styles given inline.  Cells using the default colors inherit
+// them from the surrounding page.
+func (cb *CellBuffer) ToHTML() string {
+	var sb strings.Builder
+	sb.WriteString("<pre style=\"font-family: monospace\">")
+	for y := 0; y < cb.h; y++ {
+		for _, run := range cb.runs(y) {
+			fg, bg := exportColors(run.style, "", "")
+			var props []string
+			if fg != "" {
+				props = append(props, "color: "+fg)
+			}
+			if bg != "" {
+				props = append(props, "background-color: "+bg)
+			}
+			props = append(props, exportFont(run.style, ": ")...)
+			text := html.EscapeString(run.text)
+			if len(props) == 0 {
+				sb.WriteString(text)
+				continue
+			}
+			sb.WriteString("<span style=\"" + strings.Join(props, "; ") + "\">")
+			sb.WriteString(text)
+			sb.WriteString("</span>")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("</pre>\n")
+	return sb.String()
+}
+
+// ToSVG returns the contents of the buffer as an SVG image, drawn with
+// a monospace font.  Each cell is 8 by 16 pixels.
+func (cb *CellBuffer) ToSVG() string {
+	var sb strings.Builder
+	w, h := cb.w*svgCellWidth, cb.h*svgCellHeight
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" "+
+		"font-family=\"%s\" font-size=\"%d\">\n", w, h, svgFontFamily, svgFontSize)
+	fmt.Fprintf(&sb, "<rect width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", w, h, svgDefaultBg)
+	for y := 0; y < cb.h; y++ {
+		for _, run := range cb.runs(y) {
+			fg, bg := exportColors(run.style, svgDefaultFg, svgDefaultBg)
+			x := run.x * svgCellWidth
+			if bg != svgDefaultBg {
+				fmt.Fprintf(&sb, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+					x, y*svgCellHeight, run.cells*svgCellWidth, svgCellHeight, bg)
+			}
+			if strings.TrimSpace(run.text) == "" {
+				continue
+			}
+			attrs := []string{fmt.Sprintf("fill=\"%s\"", fg)}
+			for _, p := range exportFont(run.style, "=") {
+				kv := strings.SplitN(p, "=", 2)
+				attrs = append(attrs, kv[0]+"=\""+kv[1]+"\"")
+			}
+			fmt.Fprintf(&sb, "<text x=\"%d\" y=\"%d\" textLength=\"%d\" lengthAdjust=\"spacingAndGlyphs\" "+
+				"xml:space=\"preserve\" %s>%s</text>\n",
+				x, y*svgCellHeight+svgBaselineOff, run.cells*svgCellWidth,
+				strings.Join(attrs, " "), html.EscapeString(run.text))
+		}
+	}
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
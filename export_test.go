@@ -0,0 +1,44 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(4, 1)
+
+	st := StyleDefault.Foreground(ColorRed).Bold(true)
+	s.SetContent(0, 0, 'a', nil, StyleDefault)
+	s.SetContent(1, 0, '<', nil, st)
+	s.SetContent(2, 0, ' ', nil, StyleDefault)
+	s.SetContent(3, 0, ' ', nil, StyleDefault)
+
+	if a := s.ToANSI(); a != "\x1b[0ma\x1b[0;1;91m<\x1b[0m  \x1b[0m\n" {
+		t.Errorf("wrong ANSI: %q", a)
+	}
+	want := "<pre style=\"font-family: monospace\">a" +
+		"<span style=\"color: #ff0000; font-weight: bold\">&lt;</span>  \n</pre>\n"
+	if h := s.ToHTML(); h != want {
+		t.Errorf("wrong HTML: %q", h)
+	}
+	if v := s.ToSVG(); !strings.Contains(v, "fill=\"#ff0000\" font-weight=\"bold\">&lt;</text>") {
+		t.Errorf("wrong SVG: %q", v)
+	}
+}
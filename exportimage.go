@@ -0,0 +1,268 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// ToImage draws the buffer with a built in bitmap font, so that it can be
+// rendered without a terminal, or any fonts being installed.  This is meant
+// for documentation screenshots, and for comparing screens in tests; it is
+// not a faithful rendering of any particular terminal.
+//
+// Each cell is 6 by 11 pixels, multiplied by scale (which is at least 1).
+// The font covers printable ASCII; accented letters are drawn without their
+// accents, box drawing and block characters are drawn directly, and other
+// characters are drawn as an empty box.  Wide characters fill both of
+// their cells: fullwidth forms of ASCII are drawn at double width, and
+// others (which the font cannot draw) as a box with a cross through it.
+// Bold text is drawn thicker and italic text slanted, including the boxes.
+// Default colors are drawn as light gray on black.
+func (cb *CellBuffer) ToImage(scale int) *image.RGBA {
+	if scale < 1 {
+		scale = 1
+	}
+	img := image.NewRGBA(image.Rect(0, 0, cb.w*imageCellWidth*scale, cb.h*imageCellHeight*scale))
+	for y := 0; y < cb.h; y++ {
+		for x := 0; x < cb.w; {
+			mainc, _, style, width := cb.GetContent(x, y)
+			if x+width > cb.w {
+				width = 1
+			}
+			r := image.Rect(x*imageCellWidth, y*imageCellHeight,
+				(x+width)*imageCellWidth, (y+1)*imageCellHeight)
+			drawImageCell(img, r, scale, mainc, width, style)
+			x += width
+		}
+	}
+	return img
+}
+
+// WritePNG writes the buffer to w as a PNG image, drawn by ToImage.
+func (cb *CellBuffer) WritePNG(w io.Writer, scale int) error {
+	return png.Encode(w, cb.ToImage(scale))
+}
+
+const (
+	imageCellWidth  = 6
+	imageCellHeight = 11
+	imageGlyphTop   = 1 // the first row of the glyph within the cell
+)
+
+var (
+	imageDefaultFg = color.RGBA{0xc0, 0xc0, 0xc0, 0xff}
+	imageDefaultBg = color.RGBA{0x00, 0x00, 0x00, 0xff}
+)
+
+// imageFont holds the glyphs of imageFontData, with one bit per pixel
+// (the leftmost pixel in bit 4).
+var imageFont = func() [][9]uint8 {
+	font := make([][9]uint8, len(imageFontData))
+	for i, s := range imageFontData {
+		for row, bits := range strings.Fields(s) {
+			for _, c := range bits {
+				font[i][row] <<= 1
+				if c == '#' {
+					font[i][row] |= 1
+				}
+			}
+		}
+	}
+	return font
+}()
+
+// imageBoxLines gives the lines of box drawing characters, in the order
+// up, right, down, left: 1 is a light line, 2 a heavy one, and 3 a double.
+var imageBoxLines = map[rune][4]int{
+	'─': {0, 1, 0, 1}, '━': {0, 2, 0, 2}, '│': {1, 0, 1, 0}, '┃': {2, 0, 2, 0},
+	'┌': {0, 1, 1, 0}, '┐': {0, 0, 1, 1}, '└': {1, 1, 0, 0}, '┘': {1, 0, 0, 1},
+	'╭': {0, 1, 1, 0}, '╮': {0, 0, 1, 1}, '╰': {1, 1, 0, 0}, '╯': {1, 0, 0, 1},
+	'├': {1, 1, 1, 0}, '┤': {1, 0, 1, 1}, '┬': {0, 1, 1, 1}, '┴': {1, 1, 0, 1},
+	'┼': {1, 1, 1, 1}, '┏': {0, 2, 2, 0}, '┓': {0, 0, 2, 2}, '┗': {2, 2, 0, 0},
+	'┛': {2, 0, 0, 2}, '┣': {2, 2, 2, 0}, '┫': {2, 0, 2, 2}, '┳': {0, 2, 2, 2},
+	'┻': {2, 2, 0, 2}, '╋': {2, 2, 2, 2}, '═': {0, 3, 0, 3}, '║': {3, 0, 3, 0},
+	'╔': {0, 3, 3, 0}, '╗': {0, 0, 3, 3}, '╚': {3, 3, 0, 0}, '╝': {3, 0, 0, 3},
+	'╠': {3, 3, 3, 0}, '╣': {3, 0, 3, 3}, '╦': {0, 3, 3, 3}, '╩': {3, 3, 0, 3},
+	'╬': {3, 3, 3, 3},
+}
+
+// imageColors returns the colors to draw a style with.
+func imageColors(style Style) (color.RGBA, color.RGBA) {
+	fg, bg, attr := style.Decompose()
+	rgba := func(c Color, def color.RGBA) color.RGBA {
+		if !c.Valid() {
+			return def
+		}
+		r, g, b := c.RGB()
+		return color.RGBA{uint8(r), uint8(g), uint8(b), 0xff}
+	}
+	f, b := rgba(fg, imageDefaultFg), rgba(bg, imageDefaultBg)
+	if attr&AttrReverse != 0 {
+		f, b = b, f
+	}
+	if attr&AttrDim != 0 {
+		f = blendRGBA(f, b, 1, 2)
+	}
+	return f, b
+}
+
+// blendRGBA returns the color n/d of the way from a to b.
+func blendRGBA(a, b color.RGBA, n, d int) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8((int(x)*(d-n) + int(y)*n) / d)
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 0xff}
+}
+
+// imageGlyph returns the font glyph to draw for r, if there is one.
+func imageGlyph(r rune) ([9]uint8, bool) {
+	if r >= ' ' && r <= '~' {
+		return imageFont[r-' '], true
+	}
+	if s, ok := bestFit(r); ok && len(s) == 1 {
+		return imageFont[s[0]-' '], true
+	}
+	if s, ok := RuneFallbacks[r]; ok && len(s) == 1 && s[0] >= ' ' && s[0] <= '~' {
+		return imageFont[s[0]-' '], true
+	}
+	return [9]uint8{}, false
+}
+
+// drawImageCell draws a character (which may be wide) into the cells
+// r, which is given in unscaled pixels.
+func drawImageCell(img *image.RGBA, r image.Rectangle, scale int, mainc rune, width int, style Style) {
+	fg, bg := imageColors(style)
+	_, _, attr := style.Decompose()
+
+	// set fills a rectangle, in unscaled pixels relative to the cell.
+	set := func(x0, y0, x1, y1 int, c color.RGBA) {
+		for y := (r.Min.Y + y0) * scale; y < (r.Min.Y+y1)*scale; y++ {
+			for x := (r.Min.X + x0) * scale; x < (r.Min.X+x1)*scale; x++ {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+	// dot sets a pixel of a glyph (or box) that is drawn xs pixels wide,
+	// slanting the top of it for italic, and thickening it for bold.
+	dot := func(x, y, xs int) {
+		if attr&AttrItalic != 0 && y < imageGlyphTop+3 {
+			x++
+		}
+		if attr&AttrBold != 0 {
+			xs++
+		}
+		set(x, y, x+xs, y+1, fg)
+	}
+	// box draws the outline of a box, with its top left corner at x0, y0,
+	// and its bottom right corner at x1, y1 (both inclusive).
+	box := func(x0, y0, x1, y1 int) {
+		for x := x0; x <= x1; x++ {
+			dot(x, y0, 1)
+			dot(x, y1, 1)
+		}
+		for y := y0; y <= y1; y++ {
+			dot(x0, y, 1)
+			dot(x1, y, 1)
+		}
+	}
+	w, h := r.Dx(), r.Dy()
+	set(0, 0, w, h, bg)
+
+	switch {
+	case width > 1:
+		glyph, ok := imageGlyph(mainc)
+		if !ok {
+			// The font has no wide glyphs, so draw a box, crossed
+			// through to show that it is not simply a box.
+			box(1, 1, w-2, h-2)
+			for y := 2; y <= h-3; y++ {
+				x := 2 + (y-2)*(w-5)/(h-5)
+				dot(x, y, 1)
+				dot(w-1-x, y, 1)
+			}
+			break
+		}
+		// Each pixel of the glyph is drawn twice as wide, centered.
+		for row, bits := range glyph {
+			for col := 0; col < 5; col++ {
+				if bits&(0x10>>uint(col)) != 0 {
+					dot((w-10)/2+col*2, row+imageGlyphTop, 2)
+				}
+			}
+		}
+	case mainc >= '░' && mainc <= '▓':
+		set(0, 0, w, h, blendRGBA(bg, fg, int(mainc-'░')+1, 4))
+	case mainc == '█':
+		set(0, 0, w, h, fg)
+	case mainc == '▀':
+		set(0, 0, w, h/2, fg)
+	case mainc == '▄':
+		set(0, h/2, w, h, fg)
+	case mainc == '▌':
+		set(0, 0, w/2, h, fg)
+	case mainc == '▐':
+		set(w/2, 0, w, h, fg)
+	default:
+		if lines, ok := imageBoxLines[mainc]; ok {
+			drawImageBox(set, w, h, lines, fg)
+			break
+		}
+		glyph, ok := imageGlyph(mainc)
+		if !ok {
+			box(1, 2, w-3, h-3)
+			break
+		}
+		for row, bits := range glyph {
+			for col := 0; col < 5; col++ {
+				if bits&(0x10>>uint(col)) != 0 {
+					dot(col, row+imageGlyphTop, 1)
+				}
+			}
+		}
+	}
+	if attr&AttrUnderline != 0 {
+		set(0, imageGlyphTop+8, w, imageGlyphTop+9, fg)
+	}
+	if attr&AttrStrikeThrough != 0 {
+		set(0, imageGlyphTop+4, w, imageGlyphTop+5, fg)
+	}
+}
+
+// drawImageBox draws the lines of a box drawing character, from the
+// middle of the cell to its edges.
+func drawImageBox(set func(x0, y0, x1, y1 int, c color.RGBA), w, h int, lines [4]int, fg color.RGBA) {
+	cx, cy := w/2-1, h/2
+	// each line is drawn as one or two strokes, at these offsets
+	strokes := [][]int{nil, {0}, {0, 1}, {-1, 1}}
+	for dir, kind := range lines {
+		for _, off := range strokes[kind] {
+			switch dir {
+			case 0:
+				set(cx+off, 0, cx+off+1, cy+1, fg)
+			case 1:
+				set(cx, cy+off, w, cy+off+1, fg)
+			case 2:
+				set(cx+off, cy, cx+off+1, h, fg)
+			case 3:
+				set(0, cy+off, cx+1, cy+off+1, fg)
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestToImage(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(4, 1)
+	s.SetContent(0, 0, 'I', nil, StyleDefault.Foreground(ColorRed))
+	s.SetContent(1, 0, '█', nil, StyleDefault.Reverse(true))
+	s.SetContent(2, 0, '世', nil, StyleDefault)
+
+	img := s.ToImage(2)
+	if b := img.Bounds(); b.Dx() != 4*6*2 || b.Dy() != 11*2 {
+		t.Fatalf("wrong size %v", b)
+	}
+	// The stem of the I is in the middle column of the glyph.
+	if c := img.RGBAAt(2*2, 3*2); c.R != 0xff || c.G != 0 {
+		t.Errorf("glyph not drawn in red: %v", c)
+	}
+	if c := img.RGBAAt(0, 3*2); c.R != 0 || c.G != 0 || c.B != 0 {
+		t.Errorf("background not black: %v", c)
+	}
+	// A full block in reverse video is drawn in the default background.
+	if c := img.RGBAAt(8*2, 5*2); c.R != 0 || c.G != 0 || c.B != 0 {
+		t.Errorf("reversed block not black: %v", c)
+	}
+	// Wide characters are boxes across both cells.
+	if c := img.RGBAAt(20*2, 1*2); c.R != 0xc0 {
+		t.Errorf("wide character box not drawn: %v", c)
+	}
+
+	// Fullwidth forms of ASCII are drawn at double width, so the stem of
+	// the I is two pixels wide, in the middle of the two cells.
+	var cb CellBuffer
+	cb.Resize(4, 1)
+	cb.SetContent(0, 0, 'Ｉ', nil, StyleDefault)
+	cb.SetContent(2, 0, '世', nil, StyleDefault.Bold(true))
+	img = cb.ToImage(1)
+	for x := 4; x < 8; x++ {
+		if c := img.RGBAAt(x, 4); (c.R != 0) != (x == 5 || x == 6) {
+			t.Errorf("wrong fullwidth pixel at %d: %v", x, c)
+		}
+	}
+	// Other wide characters are crossed boxes, thicker when bold.
+	if c := img.RGBAAt(12+5, 5); c.R != 0xc0 {
+		t.Errorf("wide character not crossed: %v", c)
+	}
+	if c := img.RGBAAt(12+2, 4); c.R != 0xc0 {
+		t.Errorf("wide character box not bold: %v", c)
+	}
+
+	var buf bytes.Buffer
+	if err := s.(*simscreen).back.WritePNG(&buf, 1); err != nil {
+		t.Fatalf("PNG failed: %v", err)
+	}
+	if dec, err := png.Decode(&buf); err != nil || dec.Bounds().Dx() != 24 {
+		t.Errorf("PNG could not be decoded: %v", err)
+	}
+}
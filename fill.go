@@ -0,0 +1,116 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"math"
+
+	runewidth "github.com/mattn/go-runewidth"
+)
+
+// FillFunc gives the content of the cell at x, y for Screen.FillFunc.
+type FillFunc func(x, y int) (rune, Style)
+
+// BlendColor returns the color frac of the way from one color to another,
+// for gradients drawn with FillFunc.  Colors that have no RGB value (such
+// as ColorDefault) are taken as black.
+func BlendColor(from, to Color, frac float64) Color {
+	if frac < 0 {
+		frac = 0
+	} else if frac > 1 {
+		frac = 1
+	}
+	r1, g1, b1 := from.RGB()
+	r2, g2, b2 := to.RGB()
+	mix := func(a, b int32) int32 {
+		if a < 0 {
+			a = 0
+		}
+		if b < 0 {
+			b = 0
+		}
+		return a + int32(math.Round(float64(b-a)*frac))
+	}
+	return NewRGBColor(mix(r1, r2), mix(g1, g2), mix(b1, b2))
+}
+
+// fillBatch holds the content computed by a FillFunc for a region, so
+// that it can be stored with the screen locked just once, and without
+// calling the function (which might use the screen) with it locked.
+type fillBatch struct {
+	x, y, w, h int
+	runes      []rune
+	styles     []Style
+}
+
+// newFillBatch calls f for each cell of the region at x, y of the given
+// width and height, clipped to a screen of sw by sh cells.  A wide rune
+// covers the cell to its right, for which f is not called.
+func newFillBatch(x, y, width, height, sw, sh int, f FillFunc) *fillBatch {
+	if x < 0 {
+		width += x
+		x = 0
+	}
+	if y < 0 {
+		height += y
+		y = 0
+	}
+	if x+width > sw {
+		width = sw - x
+	}
+	if y+height > sh {
+		height = sh - y
+	}
+	b := &fillBatch{x: x, y: y}
+	if width <= 0 || height <= 0 {
+		return b
+	}
+	b.w, b.h = width, height
+	b.runes = make([]rune, width*height)
+	b.styles = make([]Style, width*height)
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			i := row*width + col
+			b.runes[i], b.styles[i] = f(x+col, y+row)
+			if runewidth.RuneWidth(b.runes[i]) == 2 {
+				col++
+			}
+		}
+	}
+	return b
+}
+
+// apply stores the content in cb.
+func (b *fillBatch) apply(cb *CellBuffer) {
+	for row := 0; row < b.h; row++ {
+		for col := 0; col < b.w; col++ {
+			i := row*b.w + col
+			cb.SetContent(b.x+col, b.y+row, b.runes[i], nil, b.styles[i])
+			if runewidth.RuneWidth(b.runes[i]) == 2 {
+				col++
+			}
+		}
+	}
+}
+
+func (t *tScreen) FillFunc(x, y, width, height int, f FillFunc) {
+	w, h := t.Size()
+	b := newFillBatch(x, y, width, height, w, h, f)
+	t.Lock()
+	if !t.fini {
+		b.apply(&t.cells)
+	}
+	t.Unlock()
+}
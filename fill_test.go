@@ -0,0 +1,53 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestFillFunc(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(6, 3)
+
+	from, to := NewRGBColor(0, 0, 0), NewRGBColor(200, 100, 0)
+	calls := 0
+	s.FillFunc(-1, 1, 6, 5, func(x, y int) (rune, Style) {
+		calls++
+		if x == 2 {
+			return '世', StyleDefault
+		}
+		return 'a' + rune(x), StyleDefault.Background(BlendColor(from, to, float64(x)/4))
+	})
+	if calls != 8 {
+		t.Errorf("wrong number of calls: %d", calls)
+	}
+	s.Show()
+	cells, _, _ := s.GetContents()
+	var text []rune
+	for _, c := range cells[6:12] {
+		text = append(text, c.Runes...)
+	}
+	if string(text) != "ab世e " {
+		t.Errorf("wrong content: %q", string(text))
+	}
+	if _, _, st, _ := s.GetContent(1, 2); st != StyleDefault.Background(NewRGBColor(50, 25, 0)) {
+		t.Errorf("wrong style: %v", st)
+	}
+	if r, _, _, _ := s.GetContent(0, 0); r != ' ' {
+		t.Errorf("cell outside the region changed: %q", r)
+	}
+}
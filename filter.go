@@ -0,0 +1,200 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"math"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// RenderFilter transforms the style of every cell as it is displayed, as
+// set with SetRenderFilter, so that the display can be adapted (for
+// accessibility, or to test how it looks) without changing the
+// application.  The style given has all of the screen's overlays already
+// applied.
+type RenderFilter func(Style) Style
+
+// ChainFilters returns a RenderFilter that applies each of the filters in
+// turn.
+func ChainFilters(filters ...RenderFilter) RenderFilter {
+	return func(s Style) Style {
+		for _, f := range filters {
+			s = f(s)
+		}
+		return s
+	}
+}
+
+// luminance returns the relative luminance of c, as defined by WCAG, from
+// zero for black to one for white, or -1 if c has no RGB value.
+func luminance(c Color) float64 {
+	r, g, b := c.RGB()
+	if r < 0 {
+		return -1
+	}
+	lin := colorful.Color{R: float64(r) / 255, G: float64(g) / 255, B: float64(b) / 255}
+	lr, lg, lb := lin.LinearRgb()
+	return 0.2126*lr + 0.7152*lg + 0.0722*lb
+}
+
+// HighContrastFilter displays every cell with a background color as black
+// on white if the background is light, or else white on black.  Cells with
+// the default background have the default foreground too, as the
+// terminal's own colors are presumed to be legible.
+func HighContrastFilter(s Style) Style {
+	bg := luminance(s.bg)
+	if bg < 0 {
+		s.fg = ColorDefault
+		return s
+	}
+	// The midpoint in contrast between black and white.
+	if bg > 0.18 {
+		s.fg, s.bg = ColorBlack, ColorWhite
+	} else {
+		s.fg, s.bg = ColorWhite, ColorBlack
+	}
+	return s
+}
+
+// MonochromeFilter removes all colors, for terminals (or users) without
+// them.  Cells with a background color are shown in reverse video, and
+// those with just a foreground color are shown in bold, so that they are
+// still distinct.
+func MonochromeFilter(s Style) Style {
+	if luminance(s.bg) >= 0 {
+		s.attrs ^= AttrReverse
+	} else if luminance(s.fg) >= 0 {
+		s.attrs |= AttrBold
+	}
+	s.fg, s.bg = ColorDefault, ColorDefault
+	return s
+}
+
+// DimFilter displays everything with the dim attribute, which is the least
+// intrusive way to fade out a part of the display, such as that beneath a
+// dialog, but is not supported by every terminal.
+func DimFilter(s Style) Style {
+	s.attrs |= AttrDim
+	return s
+}
+
+// FadeFilter returns a RenderFilter that fades the colors out, by removing
+// amount (from zero to one) of their saturation, and half as much of their
+// lightness, for parts of the display that are not in use, such as that
+// beneath a dialog.  Text in the default colors is made dim, as the
+// terminal's colors are not known.
+func FadeFilter(amount float64) RenderFilter {
+	if amount < 0 {
+		amount = 0
+	} else if amount > 1 {
+		amount = 1
+	}
+	fade := func(c Color) Color {
+		r, g, b := c.RGB()
+		if r < 0 {
+			return c
+		}
+		h, s, l := colorful.Color{R: float64(r) / 255, G: float64(g) / 255, B: float64(b) / 255}.Hsl()
+		out := colorful.Hsl(h, s*(1-amount), l*(1-amount/2)).Clamped()
+		return NewRGBColor(
+			int32(math.Round(out.R*255)),
+			int32(math.Round(out.G*255)),
+			int32(math.Round(out.B*255)))
+	}
+	return func(s Style) Style {
+		if s.fg == ColorDefault && amount > 0 {
+			s.attrs |= AttrDim
+		}
+		s.fg, s.bg = fade(s.fg), fade(s.bg)
+		return s
+	}
+}
+
+// ColorBlindness is a kind of color vision deficiency.
+type ColorBlindness int
+
+const (
+	// Protanopia is the lack of red sensitive cones.
+	Protanopia ColorBlindness = iota
+
+	// Deuteranopia is the lack of green sensitive cones, the most
+	// common kind.
+	Deuteranopia
+
+	// Tritanopia is the lack of blue sensitive cones.
+	Tritanopia
+)
+
+// colorBlindMatrices simulate each kind of color blindness, in linear RGB,
+// as given by Machado, Oliveira and Fernandes (2009) for full severity.
+var colorBlindMatrices = [...][3][3]float64{
+	Protanopia: {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	Deuteranopia: {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	Tritanopia: {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+}
+
+// SimulateColorBlindness returns the color as seen with the kind of color
+// blindness.  Colors without RGB values are returned as they are, as are
+// all colors for NormalVision.
+func SimulateColorBlindness(c Color, kind ColorBlindness) Color {
+	r, g, b := c.RGB()
+	if r < 0 || kind < 0 || int(kind) >= len(colorBlindMatrices) {
+		return c
+	}
+	lr, lg, lb := colorful.Color{R: float64(r) / 255, G: float64(g) / 255, B: float64(b) / 255}.LinearRgb()
+	m := &colorBlindMatrices[kind]
+	out := colorful.LinearRgb(
+		m[0][0]*lr+m[0][1]*lg+m[0][2]*lb,
+		m[1][0]*lr+m[1][1]*lg+m[1][2]*lb,
+		m[2][0]*lr+m[2][1]*lg+m[2][2]*lb,
+	).Clamped()
+	return NewRGBColor(
+		int32(math.Round(out.R*255)),
+		int32(math.Round(out.G*255)),
+		int32(math.Round(out.B*255)))
+}
+
+// ColorBlindFilter returns a RenderFilter that shows the colors as they
+// are seen with the kind of color blindness, so that an application's
+// colors can be checked by those without it.  The colors are displayed
+// as RGB values, and so are only accurate on terminals with 24-bit color.
+func ColorBlindFilter(kind ColorBlindness) RenderFilter {
+	return func(s Style) Style {
+		s.fg = SimulateColorBlindness(s.fg, kind)
+		s.bg = SimulateColorBlindness(s.bg, kind)
+		return s
+	}
+}
+
+func (t *tScreen) SetRenderFilter(f RenderFilter) {
+	t.Lock()
+	t.filter = f
+	t.cells.Invalidate()
+	t.mag.buf.Invalidate()
+	t.Unlock()
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestRenderFilter(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(3, 1)
+	s.SetContent(0, 0, 'a', nil, StyleDefault.Foreground(ColorRed))
+	s.SetContent(1, 0, 'b', nil, StyleDefault.Foreground(ColorRed).Background(ColorYellow))
+	s.SetContent(2, 0, 'c', nil, StyleDefault.Background(ColorNavy))
+	styles := func() [3]Style {
+		s.Show()
+		cells, _, _ := s.GetContents()
+		return [3]Style{cells[0].Style, cells[1].Style, cells[2].Style}
+	}
+
+	s.SetRenderFilter(MonochromeFilter)
+	want := [3]Style{StyleDefault.Bold(true), StyleDefault.Reverse(true), StyleDefault.Reverse(true)}
+	if got := styles(); got != want {
+		t.Errorf("wrong monochrome styles: %v", got)
+	}
+
+	s.SetRenderFilter(HighContrastFilter)
+	want = [3]Style{
+		StyleDefault,
+		StyleDefault.Foreground(ColorBlack).Background(ColorWhite),
+		StyleDefault.Foreground(ColorWhite).Background(ColorBlack),
+	}
+	if got := styles(); got != want {
+		t.Errorf("wrong high contrast styles: %v", got)
+	}
+
+	s.SetRenderFilter(ColorBlindFilter(Deuteranopia))
+	fg, _, _ := styles()[0].Decompose()
+	if r, g, b := fg.RGB(); r-g > 24 || g-r > 24 || b > 8 {
+		t.Errorf("red not seen as yellowish: %d,%d,%d", r, g, b)
+	}
+
+	s.SetRenderFilter(nil)
+	if got := styles()[0]; got != StyleDefault.Foreground(ColorRed) {
+		t.Errorf("filter not removed: %v", got)
+	}
+}
+
+func TestFadeFilter(t *testing.T) {
+	s := FadeFilter(1)(StyleDefault.Foreground(ColorRed).Background(ColorWhite))
+	fg, bg, attrs := s.Decompose()
+	if r, g, b := fg.RGB(); r != g || g != b {
+		t.Errorf("foreground not desaturated: %d,%d,%d", r, g, b)
+	}
+	if r, _, _ := bg.RGB(); r >= 255 || r < 120 {
+		t.Errorf("background not darkened: %d", r)
+	}
+	if attrs&AttrDim != 0 {
+		t.Errorf("colored text should not be dim")
+	}
+	if _, _, attrs := FadeFilter(0.5)(StyleDefault).Decompose(); attrs&AttrDim == 0 {
+		t.Errorf("default text not dim")
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// FindOptions controls how CellBuffer.Find matches.
+type FindOptions struct {
+	// Regexp treats the pattern as a regular expression, in the syntax
+	// of the regexp package, rather than as literal text.
+	Regexp bool
+
+	// IgnoreCase matches without regard to case.
+	IgnoreCase bool
+}
+
+// Find searches the buffer for pattern, and returns the cells of each
+// match, in reading order.  Matches do not span rows, and do not overlap.
+// Each row is searched as the text of its cells, with wide characters
+// counted once and combining characters following their base character;
+// a match that includes part of a cell includes the whole of it.  An
+// error is returned only if the pattern is not a valid regular
+// expression.
+func (cb *CellBuffer) Find(pattern string, opts FindOptions) ([]Selection, error) {
+	if !opts.Regexp {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if opts.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Selection
+	var text bytes.Buffer
+	var cols []int // the column of each byte of the row text
+	for y := 0; y < cb.h; y++ {
+		text.Reset()
+		cols = cols[:0]
+		for x := 0; x < cb.w; {
+			mainc, combc, _, width := cb.GetContent(x, y)
+			if width < 1 {
+				width = 1
+			}
+			if mainc == 0 {
+				mainc = ' '
+			}
+			text.WriteRune(mainc)
+			for _, r := range combc {
+				text.WriteRune(r)
+			}
+			for len(cols) < text.Len() {
+				cols = append(cols, x)
+			}
+			x += width
+		}
+		for _, m := range re.FindAllIndex(text.Bytes(), -1) {
+			if m[0] == m[1] {
+				continue
+			}
+			sx, ex := cols[m[0]], cols[m[1]-1]
+			_, _, _, width := cb.GetContent(ex, y)
+			if width > 1 {
+				ex += width - 1
+			}
+			found = append(found, Selection{StartX: sx, StartY: y, EndX: ex, EndY: y})
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(10, 2)
+	for y, line := range []string{"a世b Foo", "foo fox"} {
+		x := 0
+		for _, r := range line {
+			s.SetContent(x, y, r, nil, StyleDefault)
+			if r == '世' {
+				x++
+			}
+			x++
+		}
+	}
+	found, err := s.Find("世b", FindOptions{})
+	if err != nil || len(found) != 1 || found[0] != (Selection{StartX: 1, StartY: 0, EndX: 3, EndY: 0}) {
+		t.Errorf("wrong match for wide character: %v %v", found, err)
+	}
+	found, _ = s.Find("foo", FindOptions{IgnoreCase: true})
+	if len(found) != 2 || found[0].StartX != 5 || found[1].StartY != 1 || found[1].EndX != 2 {
+		t.Errorf("wrong matches ignoring case: %v", found)
+	}
+	found, _ = s.Find("f.x", FindOptions{})
+	if len(found) != 0 {
+		t.Errorf("pattern should be literal: %v", found)
+	}
+	found, _ = s.Find("f.x", FindOptions{Regexp: true})
+	if len(found) != 1 || found[0].StartX != 4 {
+		t.Errorf("wrong regexp match: %v", found)
+	}
+	if _, err = s.Find("(", FindOptions{Regexp: true}); err == nil {
+		t.Errorf("bad regexp accepted")
+	}
+
+	hl := StyleDefault.Background(ColorYellow)
+	s.SetHighlights(hl, found)
+	s.Show()
+	b, w, _ := s.GetContents()
+	if b[w+4].Style != hl || b[w+6].Style != hl || b[w+3].Style != StyleDefault {
+		t.Errorf("highlight not drawn")
+	}
+	if _, _, st, _ := s.GetContent(4, 1); st != StyleDefault {
+		t.Errorf("cell buffer modified")
+	}
+}
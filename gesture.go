@@ -0,0 +1,200 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"sync"
+	"time"
+)
+
+// Gesture is a kind of gesture recognized by a GestureRecognizer.
+type Gesture int
+
+// These are the gestures that can be recognized.  A swipe is named for
+// the direction that the pointer moved.
+const (
+	GestureLongPress Gesture = iota
+	GestureSwipeLeft
+	GestureSwipeRight
+	GestureSwipeUp
+	GestureSwipeDown
+)
+
+// EventGesture is a gesture made with the mouse, or with a touch screen
+// that the terminal reports as a mouse.
+type EventGesture struct {
+	t       time.Time
+	gesture Gesture
+	x, y    int
+	dx, dy  int
+	mod     ModMask
+}
+
+// When returns the time when the gesture was recognized.
+func (ev *EventGesture) When() time.Time {
+	return ev.t
+}
+
+// Gesture returns the kind of gesture.
+func (ev *EventGesture) Gesture() Gesture {
+	return ev.gesture
+}
+
+// Position returns the position where the gesture started.
+func (ev *EventGesture) Position() (int, int) {
+	return ev.x, ev.y
+}
+
+// Delta returns how far the pointer moved, in cells, during a swipe.
+func (ev *EventGesture) Delta() (int, int) {
+	return ev.dx, ev.dy
+}
+
+// Modifiers returns the keyboard modifiers reported when the gesture
+// started.
+func (ev *EventGesture) Modifiers() ModMask {
+	return ev.mod
+}
+
+// GestureRecognizer turns mouse events made with the first button, as
+// terminals (including those on tablets, and the Windows console) report
+// touches, into swipe and long press gestures.  It is optional; the
+// application passes it the mouse events that it receives, and it posts
+// an EventGesture for each gesture it recognizes.
+//
+// A long press is a press held in place for LongPress.  A swipe is a press
+// and release, within SwipeTime, at least SwipeDistance cells apart.  As
+// cells are about twice as tall as they are wide, a vertical movement
+// counts double when measuring this distance.
+type GestureRecognizer struct {
+	LongPress     time.Duration // default 500ms
+	SwipeTime     time.Duration // default 500ms
+	SwipeDistance int           // default 4
+
+	post    func(Event) error
+	clock   Clock
+	down    bool
+	held    bool // the press has not moved
+	fired   bool // a long press was posted for the press
+	x, y    int
+	mod     ModMask
+	start   time.Time
+	stop    func() bool
+	presses int
+	sync.Mutex
+}
+
+// NewGestureRecognizer creates a GestureRecognizer that posts the gestures
+// it recognizes using post, which is normally the PostEvent method of the
+// Screen.
+func NewGestureRecognizer(post func(Event) error) *GestureRecognizer {
+	return &GestureRecognizer{
+		LongPress:     500 * time.Millisecond,
+		SwipeTime:     500 * time.Millisecond,
+		SwipeDistance: 4,
+		post:          post,
+		clock:         SystemClock,
+	}
+}
+
+// SetClock sets the clock used to time long presses.  The default is
+// SystemClock.
+func (g *GestureRecognizer) SetClock(c Clock) {
+	g.Lock()
+	g.clock = c
+	g.Unlock()
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// longPress is called by the timer started when the button was pressed.
+func (g *GestureRecognizer) longPress(presses int) {
+	g.Lock()
+	if presses != g.presses || !g.down || !g.held {
+		g.Unlock()
+		return
+	}
+	g.fired = true
+	ev := &EventGesture{t: g.clock.Now(), gesture: GestureLongPress, x: g.x, y: g.y, mod: g.mod}
+	g.Unlock()
+	_ = g.post(ev)
+}
+
+// HandleEvent passes an event to the recognizer.  Events other than mouse
+// events are ignored.  It returns true if the event ended a gesture, in
+// which case the application would normally not treat it as a click.
+func (g *GestureRecognizer) HandleEvent(ev Event) bool {
+	mev, ok := ev.(*EventMouse)
+	if !ok {
+		return false
+	}
+	g.Lock()
+	x, y := mev.Position()
+	pressed := mev.Buttons()&Button1 != 0
+	switch {
+	case pressed && !g.down:
+		g.down, g.held, g.fired = true, true, false
+		g.x, g.y, g.mod, g.start = x, y, mev.Modifiers(), mev.When()
+		g.presses++
+		presses := g.presses
+		g.stop = g.clock.AfterFunc(g.LongPress, func() { g.longPress(presses) })
+		g.Unlock()
+		return false
+
+	case pressed:
+		// Allow for a little jitter before deciding that the press
+		// has moved.
+		if abs(x-g.x) > 1 || abs(y-g.y) > 1 {
+			g.held = false
+		}
+		g.Unlock()
+		return false
+
+	case !g.down:
+		g.Unlock()
+		return false
+	}
+
+	g.down = false
+	g.stop()
+	if g.fired {
+		g.Unlock()
+		return true
+	}
+	dx, dy := x-g.x, y-g.y
+	if mev.When().Sub(g.start) > g.SwipeTime || abs(dx) < g.SwipeDistance && abs(dy)*2 < g.SwipeDistance {
+		g.Unlock()
+		return false
+	}
+	gev := &EventGesture{t: mev.When(), x: g.x, y: g.y, dx: dx, dy: dy, mod: g.mod}
+	switch {
+	case abs(dx) >= abs(dy)*2 && dx < 0:
+		gev.gesture = GestureSwipeLeft
+	case abs(dx) >= abs(dy)*2:
+		gev.gesture = GestureSwipeRight
+	case dy < 0:
+		gev.gesture = GestureSwipeUp
+	default:
+		gev.gesture = GestureSwipeDown
+	}
+	g.Unlock()
+	_ = g.post(gev)
+	return true
+}
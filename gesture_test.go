@@ -0,0 +1,93 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGestureRecognizer(t *testing.T) {
+	clock := NewFakeClock(time.Unix(1000, 0))
+	var posted []*EventGesture
+	g := NewGestureRecognizer(func(ev Event) error {
+		posted = append(posted, ev.(*EventGesture))
+		return nil
+	})
+	g.SetClock(clock)
+
+	mouse := func(x, y int, btn ButtonMask) bool {
+		ev := NewEventMouse(x, y, btn, ModNone)
+		ev.t = clock.Now()
+		return g.HandleEvent(ev)
+	}
+
+	// A quick click is not a gesture.
+	mouse(5, 5, Button1)
+	clock.Advance(100 * time.Millisecond)
+	if mouse(5, 5, ButtonNone) || len(posted) != 0 {
+		t.Fatalf("click recognized as a gesture: %v", posted)
+	}
+
+	// A long press, with a little jitter.
+	mouse(5, 5, Button1)
+	mouse(6, 5, Button1)
+	clock.Advance(600 * time.Millisecond)
+	if len(posted) != 1 || posted[0].Gesture() != GestureLongPress {
+		t.Fatalf("long press not recognized: %v", posted)
+	}
+	if x, y := posted[0].Position(); x != 5 || y != 5 {
+		t.Errorf("wrong long press position: %d,%d", x, y)
+	}
+	if !mouse(6, 5, ButtonNone) {
+		t.Errorf("release after long press not consumed")
+	}
+
+	// A swipe to the left, which also cancels the long press.
+	posted = nil
+	mouse(20, 5, Button1)
+	clock.Advance(100 * time.Millisecond)
+	mouse(15, 5, Button1)
+	clock.Advance(100 * time.Millisecond)
+	if !mouse(10, 6, ButtonNone) {
+		t.Errorf("swipe not consumed")
+	}
+	clock.Advance(time.Second)
+	if len(posted) != 1 || posted[0].Gesture() != GestureSwipeLeft {
+		t.Fatalf("swipe left not recognized: %v", posted)
+	}
+	if dx, dy := posted[0].Delta(); dx != -10 || dy != 1 {
+		t.Errorf("wrong swipe delta: %d,%d", dx, dy)
+	}
+
+	// Vertical movement counts double.
+	posted = nil
+	mouse(10, 2, Button1)
+	mouse(10, 4, ButtonNone)
+	if len(posted) != 1 || posted[0].Gesture() != GestureSwipeDown {
+		t.Fatalf("swipe down not recognized: %v", posted)
+	}
+
+	// A slow drag is not a swipe.
+	posted = nil
+	mouse(10, 2, Button1)
+	mouse(20, 2, Button1)
+	clock.Advance(400 * time.Millisecond)
+	mouse(30, 2, Button1)
+	clock.Advance(400 * time.Millisecond)
+	if mouse(40, 2, ButtonNone) || len(posted) != 0 {
+		t.Errorf("slow drag recognized as a gesture: %v", posted)
+	}
+}
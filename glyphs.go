@@ -0,0 +1,78 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strings"
+)
+
+// GlyphSet is a set of related glyphs, which may or may not be
+// present in the font that the terminal uses.
+type GlyphSet int
+
+const (
+	// GlyphsBoxDrawing are the line drawing characters.  These are
+	// available if the terminal can display them either directly or
+	// using its alternate character set.
+	GlyphsBoxDrawing GlyphSet = 1 << iota
+
+	// GlyphsPowerline are the separators and symbols in the private use
+	// area (U+E0A0 - U+E0BF) used by Powerline compatible fonts.
+	GlyphsPowerline
+
+	// GlyphsNerdFont are the icons supplied by Nerd Fonts, in several
+	// ranges of the private use area.  Nerd Fonts include the Powerline
+	// glyphs as well.
+	GlyphsNerdFont
+)
+
+// There is no way to ask a terminal which glyphs its font has, and the
+// private use area characters are always reported as a single cell wide
+// whether or not they can be drawn, so we cannot measure them either.
+// Users with suitable fonts must therefore opt in, by setting TCELL_GLYPHS
+// to a comma separated list of "powerline" and "nerdfont".  (Setting it to
+// "none" explicitly disables them.)
+
+// glyphsFromEnv returns the glyph sets that the user has said are present,
+// reading the environment with getenv.
+func glyphsFromEnv(getenv func(string) string) GlyphSet {
+	return parseGlyphs(getenv("TCELL_GLYPHS"))
+}
+
+// parseGlyphs parses the value of TCELL_GLYPHS.
+func parseGlyphs(s string) GlyphSet {
+	var set GlyphSet
+	for _, name := range strings.Split(s, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "powerline":
+			set |= GlyphsPowerline
+		case "nerdfont", "nerd":
+			set |= GlyphsNerdFont | GlyphsPowerline
+		}
+	}
+	return set
+}
+
+func (t *tScreen) HasGlyphs(set GlyphSet) bool {
+	var have GlyphSet
+	if t.CanDisplay(RuneHLine, false) && t.CanDisplay(RuneULCorner, false) {
+		have |= GlyphsBoxDrawing
+	}
+	// The private use area can only be reached with UTF-8.
+	if t.CanDisplay('\ue0b0', false) {
+		have |= glyphsFromEnv(t.getenv)
+	}
+	return have&set == set
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// Guide is a column or row of the screen that is marked with a style, such
+// as a color column at the 80th column, or a marker between split panes.
+// Guides are laid over the content when it is displayed, without changing
+// it.
+type Guide struct {
+	// Pos is the column of a vertical guide, or the row of a horizontal
+	// one.
+	Pos      int
+	Vertical bool
+
+	// Style gives the colors of the guide, which replace those of the
+	// cells (unless they are ColorDefault), and attributes, which are
+	// added to theirs.
+	Style Style
+}
+
+// guides tracks the guides for a screen.
+type guides struct {
+	list  []Guide
+	shown []Guide // as of the last draw
+	hide  bool
+	dirty bool
+}
+
+func (g *guides) set(list []Guide) {
+	g.list = append([]Guide{}, list...)
+	g.dirty = true
+}
+
+func (g *guides) show(on bool) {
+	if g.hide == on {
+		g.hide = !on
+		g.dirty = true
+	}
+}
+
+// prepare is called before drawing.  If the guides have changed, it marks
+// the cells of both the old and new ones dirty.
+func (g *guides) prepare(cb *CellBuffer) {
+	if !g.dirty {
+		return
+	}
+	active := g.list
+	if g.hide {
+		active = nil
+	}
+	w, h := cb.Size()
+	for _, list := range [][]Guide{g.shown, active} {
+		for _, gd := range list {
+			if gd.Vertical {
+				for y := 0; y < h; y++ {
+					cb.SetDirty(gd.Pos, y, true)
+				}
+			} else {
+				for x := 0; x < w; x++ {
+					cb.SetDirty(x, gd.Pos, true)
+				}
+			}
+		}
+	}
+	g.shown = active
+	g.dirty = false
+}
+
+// apply returns the style to display for the cell at x, y.
+func (g *guides) apply(x, y int, style Style) Style {
+	for _, gd := range g.shown {
+		if (gd.Vertical && gd.Pos == x) || (!gd.Vertical && gd.Pos == y) {
+			if gd.Style.fg != ColorDefault {
+				style.fg = gd.Style.fg
+			}
+			if gd.Style.bg != ColorDefault {
+				style.bg = gd.Style.bg
+			}
+			style.attrs |= gd.Style.attrs
+		}
+	}
+	return style
+}
+
+func (t *tScreen) SetGuides(list []Guide) {
+	t.Lock()
+	t.guides.set(list)
+	t.Unlock()
+}
+
+func (t *tScreen) ShowGuides(on bool) {
+	t.Lock()
+	t.guides.show(on)
+	t.Unlock()
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestGuides(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(4, 3)
+	s.SetContent(2, 0, 'x', nil, StyleDefault.Foreground(ColorRed))
+	col := StyleDefault.Background(ColorGray)
+	s.SetGuides([]Guide{{Pos: 2, Vertical: true, Style: col}, {Pos: 1, Style: StyleDefault.Underline(true)}})
+	s.Show()
+	styleAt := func(x, y int) Style {
+		cells, w, _ := s.GetContents()
+		return cells[y*w+x].Style
+	}
+	if got := styleAt(2, 0); got != StyleDefault.Foreground(ColorRed).Background(ColorGray) {
+		t.Errorf("wrong style in column: %v", got)
+	}
+	if got := styleAt(2, 1); got != col.Underline(true) {
+		t.Errorf("wrong style where guides cross: %v", got)
+	}
+	if got := styleAt(0, 2); got != StyleDefault {
+		t.Errorf("wrong style outside guides: %v", got)
+	}
+	if r, _, _, _ := s.GetContent(2, 0); r != 'x' {
+		t.Errorf("content changed: %q", r)
+	}
+
+	s.ShowGuides(false)
+	s.Show()
+	if got := styleAt(2, 2); got != StyleDefault {
+		t.Errorf("guide not hidden: %v", got)
+	}
+	s.ShowGuides(true)
+	s.Show()
+	if got := styleAt(2, 2); got != col {
+		t.Errorf("guide not shown again: %v", got)
+	}
+}
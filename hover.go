@@ -0,0 +1,190 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"sync"
+	"time"
+)
+
+// EventHover reports that the mouse pointer has entered or left a region
+// registered with a HoverTracker.
+type EventHover struct {
+	t       time.Time
+	region  interface{}
+	entered bool
+	x, y    int
+}
+
+// When returns the time of the event.
+func (ev *EventHover) When() time.Time {
+	return ev.t
+}
+
+// Region returns the identifier of the region.
+func (ev *EventHover) Region() interface{} {
+	return ev.region
+}
+
+// Entered returns true if the pointer entered the region, and false if it
+// left it.
+func (ev *EventHover) Entered() bool {
+	return ev.entered
+}
+
+// Position returns the position of the pointer.
+func (ev *EventHover) Position() (int, int) {
+	return ev.x, ev.y
+}
+
+type hoverRegion struct {
+	id         interface{}
+	x, y, w, h int
+}
+
+func (r *hoverRegion) contains(x, y int) bool {
+	return x >= r.x && x < r.x+r.w && y >= r.y && y < r.y+r.h
+}
+
+// HoverTracker turns mouse motion into events telling when the pointer
+// enters and leaves rectangular regions of the screen, so that tooltips
+// and hover styles can be shown without tracking the pointer by hand.
+// The application passes it the mouse events that it receives (motion
+// must be enabled with MouseMotionEvents), and it posts an EventHover for
+// each change.  Where regions overlap, the one added last is used.
+type HoverTracker struct {
+	post    func(Event) error
+	regions []*hoverRegion
+	current *hoverRegion
+	x, y    int
+	known   bool // whether x, y is the pointer position
+	sync.Mutex
+}
+
+// NewHoverTracker creates a HoverTracker that posts its events using
+// post, which is normally the PostEvent method of the Screen.
+func NewHoverTracker(post func(Event) error) *HoverTracker {
+	return &HoverTracker{post: post}
+}
+
+// Add registers a region, identified by id, replacing any region with
+// the same id.  The id must be comparable.
+func (h *HoverTracker) Add(id interface{}, x, y, width, height int) {
+	h.Lock()
+	h.remove(id)
+	h.regions = append(h.regions, &hoverRegion{id: id, x: x, y: y, w: width, h: height})
+	evs := h.refresh()
+	h.Unlock()
+	h.postAll(evs)
+}
+
+// Remove removes a region.  If the pointer was in it, it is reported as
+// having left.
+func (h *HoverTracker) Remove(id interface{}) {
+	h.Lock()
+	h.remove(id)
+	evs := h.refresh()
+	h.Unlock()
+	h.postAll(evs)
+}
+
+// Clear removes all of the regions, for example before they are added
+// again after the screen is resized.
+func (h *HoverTracker) Clear() {
+	h.Lock()
+	h.regions = nil
+	evs := h.refresh()
+	h.Unlock()
+	h.postAll(evs)
+}
+
+// Current returns the id of the region that the pointer is in, or nil.
+func (h *HoverTracker) Current() interface{} {
+	h.Lock()
+	defer h.Unlock()
+	if h.current == nil {
+		return nil
+	}
+	return h.current.id
+}
+
+func (h *HoverTracker) remove(id interface{}) {
+	for i, r := range h.regions {
+		if r.id == id {
+			h.regions = append(h.regions[:i], h.regions[i+1:]...)
+			return
+		}
+	}
+}
+
+// refresh checks the region under the pointer, after the regions have
+// changed.
+func (h *HoverTracker) refresh() []Event {
+	if !h.known {
+		return nil
+	}
+	return h.update(h.x, h.y)
+}
+
+// update finds the region under the pointer, returning the events to post
+// if it has changed.
+func (h *HoverTracker) update(x, y int) []Event {
+	h.x, h.y = x, y
+	var region *hoverRegion
+	for i := len(h.regions) - 1; i >= 0; i-- {
+		if h.regions[i].contains(x, y) {
+			region = h.regions[i]
+			break
+		}
+	}
+	if region == h.current || region != nil && h.current != nil && region.id == h.current.id {
+		// A region that was replaced is still the same region.
+		h.current = region
+		return nil
+	}
+	var evs []Event
+	now := time.Now()
+	if h.current != nil {
+		evs = append(evs, &EventHover{t: now, region: h.current.id, x: x, y: y})
+	}
+	if region != nil {
+		evs = append(evs, &EventHover{t: now, region: region.id, entered: true, x: x, y: y})
+	}
+	h.current = region
+	return evs
+}
+
+func (h *HoverTracker) postAll(evs []Event) {
+	for _, ev := range evs {
+		_ = h.post(ev)
+	}
+}
+
+// HandleEvent passes an event to the tracker.  Events other than mouse
+// events are ignored.  It returns true if the pointer moved to another
+// region.
+func (h *HoverTracker) HandleEvent(ev Event) bool {
+	mev, ok := ev.(*EventMouse)
+	if !ok {
+		return false
+	}
+	x, y := mev.Position()
+	h.Lock()
+	h.known = true
+	evs := h.update(x, y)
+	h.Unlock()
+	h.postAll(evs)
+	return len(evs) != 0
+}
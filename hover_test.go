@@ -0,0 +1,70 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHoverTracker(t *testing.T) {
+	var posted []string
+	h := NewHoverTracker(func(ev Event) error {
+		hev := ev.(*EventHover)
+		if hev.Entered() {
+			posted = append(posted, fmt.Sprint("+", hev.Region()))
+		} else {
+			posted = append(posted, fmt.Sprint("-", hev.Region()))
+		}
+		return nil
+	})
+	check := func(want string) {
+		t.Helper()
+		if got := strings.Join(posted, " "); got != want {
+			t.Errorf("wrong events: got %q, want %q", got, want)
+		}
+		posted = nil
+	}
+	move := func(x, y int) {
+		h.HandleEvent(NewEventMouse(x, y, ButtonNone, ModNone))
+	}
+
+	// Nothing is reported until the pointer position is known.
+	h.Add("link", 0, 0, 5, 1)
+	h.Add("button", 10, 2, 6, 1)
+	h.Add("tip", 12, 2, 2, 1) // over the button
+	check("")
+
+	move(2, 0)
+	check("+link")
+	move(4, 0)
+	check("")
+	move(11, 2)
+	check("-link +button")
+	move(12, 2)
+	check("-button +tip")
+	h.Add("tip", 12, 2, 3, 1)
+	check("")
+	h.Remove("tip")
+	check("-tip +button")
+	if h.Current() != "button" {
+		t.Errorf("wrong current region: %v", h.Current())
+	}
+	h.Clear()
+	check("-button")
+	move(30, 10)
+	check("")
+}
@@ -0,0 +1,118 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// imageFontData is a simple 5x9 bitmap font for printable ASCII, used by
+// ToImage.  Each glyph is nine rows of five pixels, separated by spaces,
+// with "#" for pixels that are set.  The first seven rows hold capitals
+// and digits, sitting on the baseline below the seventh row; the last two
+// rows are for descenders.
+var imageFontData = [...]string{
+	"..... ..... ..... ..... ..... ..... ..... ..... .....", // space
+	"..#.. ..#.. ..#.. ..#.. ..#.. ..... ..#.. ..... .....", // !
+	".#.#. .#.#. ..... ..... ..... ..... ..... ..... .....", // "
+	".#.#. .#.#. ##### .#.#. ##### .#.#. .#.#. ..... .....", // #
+	"..#.. .#### #.#.. .###. ..#.# ####. ..#.. ..... .....", // $
+	"##... ##..# ...#. ..#.. .#... #..## ...## ..... .....", // %
+	".##.. #..#. #.#.. .#... #.#.# #..#. .##.# ..... .....", // &
+	"..#.. ..#.. ..... ..... ..... ..... ..... ..... .....", // '
+	"...#. ..#.. .#... .#... .#... ..#.. ...#. ..... .....", // (
+	".#... ..#.. ...#. ...#. ...#. ..#.. .#... ..... .....", // )
+	"..... ..#.. #.#.# .###. #.#.# ..#.. ..... ..... .....", // *
+	"..... ..#.. ..#.. ##### ..#.. ..#.. ..... ..... .....", // +
+	"..... ..... ..... ..... ..... .##.. ..#.. .#... .....", // ,
+	"..... ..... ..... ##### ..... ..... ..... ..... .....", // -
+	"..... ..... ..... ..... ..... .##.. .##.. ..... .....", // .
+	"....# ...#. ...#. ..#.. .#... .#... #.... ..... .....", // /
+	".###. #...# #..## #.#.# ##..# #...# .###. ..... .....", // 0
+	"..#.. .##.. ..#.. ..#.. ..#.. ..#.. .###. ..... .....", // 1
+	".###. #...# ....# ...#. ..#.. .#... ##### ..... .....", // 2
+	"##### ...#. ..#.. ...#. ....# #...# .###. ..... .....", // 3
+	"...#. ..##. .#.#. #..#. ##### ...#. ...#. ..... .....", // 4
+	"##### #.... ####. ....# ....# #...# .###. ..... .....", // 5
+	"..##. .#... #.... ####. #...# #...# .###. ..... .....", // 6
+	"##### ....# ...#. ..#.. .#... .#... .#... ..... .....", // 7
+	".###. #...# #...# .###. #...# #...# .###. ..... .....", // 8
+	".###. #...# #...# .#### ....# ...#. .##.. ..... .....", // 9
+	"..... .##.. .##.. ..... .##.. .##.. ..... ..... .....", // :
+	"..... .##.. .##.. ..... .##.. ..#.. .#... ..... .....", // ;
+	"...#. ..#.. .#... #.... .#... ..#.. ...#. ..... .....", // <
+	"..... ..... ##### ..... ##### ..... ..... ..... .....", // =
+	".#... ..#.. ...#. ....# ...#. ..#.. .#... ..... .....", // >
+	".###. #...# ....# ...#. ..#.. ..... ..#.. ..... .....", // ?
+	".###. #...# #.### #.#.# #.### #.... .###. ..... .....", // @
+	".###. #...# #...# ##### #...# #...# #...# ..... .....", // A
+	"####. #...# #...# ####. #...# #...# ####. ..... .....", // B
+	".###. #...# #.... #.... #.... #...# .###. ..... .....", // C
+	"###.. #..#. #...# #...# #...# #..#. ###.. ..... .....", // D
+	"##### #.... #.... ####. #.... #.... ##### ..... .....", // E
+	"##### #.... #.... ####. #.... #.... #.... ..... .....", // F
+	".###. #...# #.... #.### #...# #...# .#### ..... .....", // G
+	"#...# #...# #...# ##### #...# #...# #...# ..... .....", // H
+	".###. ..#.. ..#.. ..#.. ..#.. ..#.. .###. ..... .....", // I
+	"..### ...#. ...#. ...#. ...#. #..#. .##.. ..... .....", // J
+	"#...# #..#. #.#.. ##... #.#.. #..#. #...# ..... .....", // K
+	"#.... #.... #.... #.... #.... #.... ##### ..... .....", // L
+	"#...# ##.## #.#.# #.#.# #...# #...# #...# ..... .....", // M
+	"#...# #...# ##..# #.#.# #..## #...# #...# ..... .....", // N
+	".###. #...# #...# #...# #...# #...# .###. ..... .....", // O
+	"####. #...# #...# ####. #.... #.... #.... ..... .....", // P
+	".###. #...# #...# #...# #.#.# #..#. .##.# ..... .....", // Q
+	"####. #...# #...# ####. #.#.. #..#. #...# ..... .....", // R
+	".#### #.... #.... .###. ....# ....# ####. ..... .....", // S
+	"##### ..#.. ..#.. ..#.. ..#.. ..#.. ..#.. ..... .....", // T
+	"#...# #...# #...# #...# #...# #...# .###. ..... .....", // U
+	"#...# #...# #...# #...# #...# .#.#. ..#.. ..... .....", // V
+	"#...# #...# #...# #.#.# #.#.# #.#.# .#.#. ..... .....", // W
+	"#...# #...# .#.#. ..#.. .#.#. #...# #...# ..... .....", // X
+	"#...# #...# .#.#. ..#.. ..#.. ..#.. ..#.. ..... .....", // Y
+	"##### ....# ...#. ..#.. .#... #.... ##### ..... .....", // Z
+	".###. .#... .#... .#... .#... .#... .###. ..... .....", // [
+	"#.... .#... .#... ..#.. ...#. ...#. ....# ..... .....", // \
+	".###. ...#. ...#. ...#. ...#. ...#. .###. ..... .....", // ]
+	"..#.. .#.#. #...# ..... ..... ..... ..... ..... .....", // ^
+	"..... ..... ..... ..... ..... ..... ..... ##### .....", // _
+	".#... ..#.. ..... ..... ..... ..... ..... ..... .....", // `
+	"..... ..... .###. ....# .#### #...# .#### ..... .....", // a
+	"#.... #.... #.##. ##..# #...# #...# ####. ..... .....", // b
+	"..... ..... .###. #.... #.... #...# .###. ..... .....", // c
+	"....# ....# .##.# #..## #...# #...# .#### ..... .....", // d
+	"..... ..... .###. #...# ##### #.... .###. ..... .....", // e
+	"..##. .#..# .#... ###.. .#... .#... .#... ..... .....", // f
+	"..... ..... .#### #...# #...# #...# .#### ....# .###.", // g
+	"#.... #.... #.##. ##..# #...# #...# #...# ..... .....", // h
+	"..#.. ..... .##.. ..#.. ..#.. ..#.. .###. ..... .....", // i
+	"...#. ..... ..##. ...#. ...#. ...#. ...#. #..#. .##..", // j
+	"#.... #.... #..#. #.#.. ##... #.#.. #..#. ..... .....", // k
+	".##.. ..#.. ..#.. ..#.. ..#.. ..#.. .###. ..... .....", // l
+	"..... ..... ##.#. #.#.# #.#.# #...# #...# ..... .....", // m
+	"..... ..... #.##. ##..# #...# #...# #...# ..... .....", // n
+	"..... ..... .###. #...# #...# #...# .###. ..... .....", // o
+	"..... ..... ####. #...# #...# #...# ####. #.... #....", // p
+	"..... ..... .#### #...# #...# #...# .#### ....# ....#", // q
+	"..... ..... #.##. ##..# #.... #.... #.... ..... .....", // r
+	"..... ..... .###. #.... .###. ....# ####. ..... .....", // s
+	".#... .#... ###.. .#... .#... .#..# ..##. ..... .....", // t
+	"..... ..... #...# #...# #...# #..## .##.# ..... .....", // u
+	"..... ..... #...# #...# #...# .#.#. ..#.. ..... .....", // v
+	"..... ..... #...# #...# #.#.# #.#.# .#.#. ..... .....", // w
+	"..... ..... #...# .#.#. ..#.. .#.#. #...# ..... .....", // x
+	"..... ..... #...# #...# #...# #...# .#### ....# .###.", // y
+	"..... ..... ##### ...#. ..#.. .#... ##### ..... .....", // z
+	"...#. ..#.. ..#.. .#... ..#.. ..#.. ...#. ..... .....", // {
+	"..#.. ..#.. ..#.. ..#.. ..#.. ..#.. ..#.. ..... .....", // |
+	".#... ..#.. ..#.. ...#. ..#.. ..#.. .#... ..... .....", // }
+	"..... ..... .#... #.#.# ...#. ..... ..... ..... .....", // ~
+}
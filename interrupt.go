@@ -35,6 +35,19 @@ func (ev *EventInterrupt) Data() interface{} {
 	return ev.v
 }
 
+// ConsoleControl is the payload of an EventInterrupt posted by the Windows
+// console screen when the console window is being closed, or the user is
+// logging off, or the system is shutting down.  Windows ends the program
+// soon after, so the application should save its state and call Fini
+// promptly; it is ended a few seconds after, or as soon as it calls Fini.
+type ConsoleControl int
+
+const (
+	ConsoleClose ConsoleControl = iota
+	ConsoleLogoff
+	ConsoleShutdown
+)
+
 // NewEventInterrupt creates an EventInterrupt with the given payload.
 func NewEventInterrupt(data interface{}) *EventInterrupt {
 	return &EventInterrupt{t: time.Now(), v: data}
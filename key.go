@@ -43,10 +43,12 @@ import (
 // overly much on availability of modifiers, or the availability of any
 // specific keys.
 type EventKey struct {
-	t   time.Time
-	mod ModMask
-	key Key
-	ch  rune
+	t    time.Time
+	mod  ModMask
+	key  Key
+	ch   rune
+	raw  []byte
+	code rune
 }
 
 // When returns the time when this Event was created, which should closely
@@ -78,6 +80,26 @@ func (ev *EventKey) Modifiers() ModMask {
 	return ev.mod
 }
 
+// Raw returns the exact bytes that were decoded to produce this event, as
+// they were received from the terminal (including any leading ESC that was
+// interpreted as the Alt modifier).  Applications can use this to implement
+// their own handling for sequences that tcell does not decode the way they
+// would like.  The result is nil if the event was not decoded from a byte
+// stream, for example events from the Windows console, or events created
+// with NewEventKey.  The caller must not modify the returned slice.
+func (ev *EventKey) Raw() []byte {
+	return ev.raw
+}
+
+// Keycode returns the Unicode codepoint for the physical key that was
+// pressed, without the effect of Shift or other modifiers.  For example,
+// Shift-A would report a Rune of 'A', but a Keycode of 'a'.  This is only
+// available when the terminal reports it (as the Windows console does for
+// letter and digit keys), and is zero otherwise.
+func (ev *EventKey) Keycode() rune {
+	return ev.code
+}
+
 // KeyNames holds the written names of special keys. Useful to echo back a key
 // name, or to look up a key from a string value.
 var KeyNames = map[Key]string{
@@ -171,6 +193,24 @@ var KeyNames = map[Key]string{
 	KeyF62:            "F62",
 	KeyF63:            "F63",
 	KeyF64:            "F64",
+	KeyKP0:            "KP0",
+	KeyKP1:            "KP1",
+	KeyKP2:            "KP2",
+	KeyKP3:            "KP3",
+	KeyKP4:            "KP4",
+	KeyKP5:            "KP5",
+	KeyKP6:            "KP6",
+	KeyKP7:            "KP7",
+	KeyKP8:            "KP8",
+	KeyKP9:            "KP9",
+	KeyKPEnter:        "KPEnter",
+	KeyKPPlus:         "KPPlus",
+	KeyKPMinus:        "KPMinus",
+	KeyKPMultiply:     "KPMultiply",
+	KeyKPDivide:       "KPDivide",
+	KeyKPDecimal:      "KPDecimal",
+	KeyKPEqual:        "KPEqual",
+	KeyKPComma:        "KPComma",
 	KeyCtrlA:          "Ctrl-A",
 	KeyCtrlB:          "Ctrl-B",
 	KeyCtrlC:          "Ctrl-C",
@@ -215,6 +255,12 @@ func (ev *EventKey) Name() string {
 	if ev.mod&ModMeta != 0 {
 		m = append(m, "Meta")
 	}
+	if ev.mod&ModSuper != 0 {
+		m = append(m, "Super")
+	}
+	if ev.mod&ModHyper != 0 {
+		m = append(m, "Hyper")
+	}
 	if ev.mod&ModCtrl != 0 {
 		m = append(m, "Ctrl")
 	}
@@ -265,13 +311,17 @@ type ModMask int16
 // These are the modifiers keys that can be sent either with a key press,
 // or a mouse event.  Note that as of now, due to the confusion associated
 // with Meta, and the lack of support for it on many/most platforms, the
-// current implementations never use it.  Instead, they use ModAlt, even for
+// current implementations rarely use it.  Instead, they use ModAlt, even for
 // events that could possibly have been distinguished from ModAlt.
+// ModSuper and ModHyper are only reported by terminals that can distinguish
+// them (such as kitty), and only for keys with xterm style modifier reporting.
 const (
 	ModShift ModMask = 1 << iota
 	ModCtrl
 	ModAlt
 	ModMeta
+	ModSuper
+	ModHyper
 	ModNone ModMask = 0
 )
 
@@ -373,6 +423,24 @@ const (
 	KeyF62
 	KeyF63
 	KeyF64
+	KeyKP0
+	KeyKP1
+	KeyKP2
+	KeyKP3
+	KeyKP4
+	KeyKP5
+	KeyKP6
+	KeyKP7
+	KeyKP8
+	KeyKP9
+	KeyKPEnter
+	KeyKPPlus
+	KeyKPMinus
+	KeyKPMultiply
+	KeyKPDivide
+	KeyKPDecimal
+	KeyKPEqual
+	KeyKPComma
 )
 
 const (
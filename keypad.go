@@ -0,0 +1,111 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bytes"
+)
+
+const (
+	decKPAM = "\x1b=" // keypad application mode
+	decKPNM = "\x1b>" // keypad numeric mode
+)
+
+// keypadKeys are the sequences sent by the numeric keypad when it is in
+// application mode (DECKPAM).  These are the same for all of the DEC VT
+// family, and for xterm and its descendants.  Several of these overlap
+// with the terminfo ka1, kb2, etc. capabilities, which describe them as
+// cursor keys; while the keypad is enabled we prefer these meanings.
+var keypadKeys = map[string]Key{
+	"\x1bOp": KeyKP0,
+	"\x1bOq": KeyKP1,
+	"\x1bOr": KeyKP2,
+	"\x1bOs": KeyKP3,
+	"\x1bOt": KeyKP4,
+	"\x1bOu": KeyKP5,
+	"\x1bOv": KeyKP6,
+	"\x1bOw": KeyKP7,
+	"\x1bOx": KeyKP8,
+	"\x1bOy": KeyKP9,
+	"\x1bOM": KeyKPEnter,
+	"\x1bOk": KeyKPPlus,
+	"\x1bOm": KeyKPMinus,
+	"\x1bOj": KeyKPMultiply,
+	"\x1bOo": KeyKPDivide,
+	"\x1bOn": KeyKPDecimal,
+	"\x1bOX": KeyKPEqual,
+	"\x1bOl": KeyKPComma,
+}
+
+func (t *tScreen) EnableKeypad() {
+	t.Lock()
+	t.keypad = true
+	t.updateModes()
+	t.Unlock()
+}
+
+func (t *tScreen) DisableKeypad() {
+	t.Lock()
+	t.keypad = false
+	t.updateModes()
+	t.Unlock()
+}
+
+func (t *tScreen) enableKeypad(on bool) {
+	if t.ti.EnterKeypad == "" {
+		// not a terminal with an application keypad
+		return
+	}
+	if on {
+		t.TPuts(decKPAM)
+	} else {
+		// Put things back the way that terminfo wants them,
+		// which may well be application mode anyway.
+		t.TPuts(decKPNM)
+		t.TPuts(t.ti.EnterKeypad)
+	}
+}
+
+// parseKeypadKey decodes the application keypad sequences when the keypad
+// has been enabled.  This runs ahead of parseFunctionKey, so that these
+// take precedence over the terminfo definitions.
+func (t *tScreen) parseKeypadKey(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
+	if !t.keypad || t.ti.EnterKeypad == "" {
+		return false, false
+	}
+	b := buf.Bytes()
+	partial := false
+	for seq, k := range keypadKeys {
+		esc := []byte(seq)
+		if bytes.HasPrefix(b, esc) {
+			mod := ModNone
+			raw := esc
+			if t.escaped {
+				mod = ModAlt
+				t.escaped = false
+				raw = append([]byte{'\x1b'}, esc...)
+			}
+			ev := NewEventKey(k, 0, mod)
+			ev.raw = raw
+			*evs = append(*evs, ev)
+			buf.Next(len(esc))
+			return true, true
+		}
+		if bytes.HasPrefix(esc, b) {
+			partial = true
+		}
+	}
+	return partial, false
+}
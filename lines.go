@@ -0,0 +1,140 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strings"
+)
+
+// LineMode is the way that a row of the screen is displayed.  The DEC
+// VT100 and its successors (and xterm) can display a row with characters
+// of double width, or double width and height.  A row that is not normal
+// only has room for half as many characters, so only the left half of
+// the row (columns 0 through width/2 - 1) is shown.
+type LineMode int
+
+const (
+	// LineNormal is the usual single width, single height row.
+	LineNormal = LineMode(iota)
+
+	// LineDoubleWidth displays the row with double width characters
+	// (DECDWL).
+	LineDoubleWidth
+
+	// LineDoubleHeightTop displays the top half of double width, double
+	// height characters (DECDHL).  The row below should normally have
+	// the same content, using LineDoubleHeightBottom.
+	LineDoubleHeightTop
+
+	// LineDoubleHeightBottom displays the bottom half of double width,
+	// double height characters (DECDHL).
+	LineDoubleHeightBottom
+)
+
+// lineModeSeqs are the escape sequences for each line mode.
+var lineModeSeqs = map[LineMode]string{
+	LineNormal:             "\x1b#5", // DECSWL
+	LineDoubleWidth:        "\x1b#6", // DECDWL
+	LineDoubleHeightTop:    "\x1b#3", // DECDHL top
+	LineDoubleHeightBottom: "\x1b#4", // DECDHL bottom
+}
+
+// hasLineModes returns true if the terminal is known to support double
+// width and height rows.  Terminfo has no capability for this, so we go by
+// name: the real DEC terminals, and xterm, which emulates them faithfully.
+func hasLineModes(name string) bool {
+	return isVTName(name)
+}
+
+// isVTName returns true for the names of DEC VT terminals, and xterm.
+func isVTName(name string) bool {
+	if strings.HasPrefix(name, "xterm") {
+		return true
+	}
+	for _, vt := range []string{"vt100", "vt102", "vt220", "vt320", "vt340", "vt400", "vt420", "vt510", "vt520", "vt525"} {
+		if strings.HasPrefix(name, vt) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *tScreen) SetLineMode(y int, mode LineMode) {
+	t.Lock()
+	if t.lineModes && y >= 0 && y < t.h {
+		t.growLines()
+		if t.lines[y] != mode {
+			t.lines[y] = mode
+			t.invalidateLine(y)
+		}
+	}
+	t.Unlock()
+}
+
+func (t *tScreen) GetLineMode(y int) LineMode {
+	t.Lock()
+	defer t.Unlock()
+	if y < 0 || y >= len(t.lines) {
+		return LineNormal
+	}
+	return t.lines[y]
+}
+
+// growLines makes sure that there is a line mode for every row.
+func (t *tScreen) growLines() {
+	for len(t.lines) < t.h {
+		t.lines = append(t.lines, LineNormal)
+	}
+	for len(t.linesShown) < t.h {
+		t.linesShown = append(t.linesShown, LineNormal)
+	}
+	t.lines = t.lines[:t.h]
+	t.linesShown = t.linesShown[:t.h]
+}
+
+func (t *tScreen) invalidateLine(y int) {
+	for x := 0; x < t.w; x++ {
+		t.cells.SetDirty(x, y, true)
+	}
+}
+
+// lineWidth returns the number of columns displayed on row y.
+func (t *tScreen) lineWidth(y int) int {
+	if y < len(t.lines) && t.lines[y] != LineNormal {
+		return t.w / 2
+	}
+	return t.w
+}
+
+// drawLineMode sends the line mode for row y to the terminal, if it has
+// changed since it was last sent.
+func (t *tScreen) drawLineMode(y int) {
+	if y >= len(t.lines) || t.lines[y] == t.linesShown[y] {
+		return
+	}
+	t.TPuts(t.ti.TGoto(0, y))
+	t.TPuts(lineModeSeqs[t.lines[y]])
+	t.cx, t.cy = -1, -1
+	t.linesShown[y] = t.lines[y]
+	t.invalidateLine(y)
+}
+
+// clearLineModes notes that the terminal has put every row back to
+// normal, which happens when the screen is erased.
+func (t *tScreen) clearLineModes() {
+	for y := range t.linesShown {
+		t.linesShown[y] = LineNormal
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestLineModes(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	s.SetLineMode(0, LineDoubleWidth)
+	if m := s.GetLineMode(0); m != LineDoubleWidth {
+		t.Errorf("wrong line mode: %v", m)
+	}
+	if m := s.GetLineMode(1); m != LineNormal {
+		t.Errorf("wrong line mode: %v", m)
+	}
+	s.SetContent(0, 0, 'A', nil, StyleDefault)
+	s.SetContent(50, 0, 'Z', nil, StyleDefault)
+	s.SetContent(50, 1, 'Z', nil, StyleDefault)
+	s.Show()
+	b, w, _ := s.GetContents()
+	if string(b[0].Bytes) != "A" {
+		t.Errorf("double width row not drawn: %q", b[0].Bytes)
+	}
+	if string(b[50].Bytes) == "Z" {
+		t.Errorf("right half of double width row drawn")
+	}
+	if string(b[w+50].Bytes) != "Z" {
+		t.Errorf("normal row not drawn: %q", b[w+50].Bytes)
+	}
+}
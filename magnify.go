@@ -0,0 +1,188 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strings"
+)
+
+// quadrants are the block characters for each combination of the four
+// quarters of a cell, with bits 1, 2, 4 and 8 for the top left, top
+// right, bottom left and bottom right.
+var quadrants = []rune(" ▘▝▀▖▌▞▛▗▚▐▜▄▙▟█")
+
+// magnifier displays part of the screen enlarged, as set by SetMagnifier.
+// The cells of the screen, as they are displayed, are drawn enlarged into
+// buf, which is then drawn instead of them.
+type magnifier struct {
+	factor int
+	buf    CellBuffer
+	lines  []LineMode // for double size lines
+	x, y   int        // the top left of the region enlarged
+}
+
+func (m *magnifier) on() bool {
+	return m.factor > 1
+}
+
+// set sets the factor, returning true if it changed.
+func (m *magnifier) set(factor int) bool {
+	if factor < 2 {
+		factor = 1
+	} else if factor > 3 {
+		factor = 3
+	}
+	if factor == m.factor {
+		return false
+	}
+	m.factor = factor
+	m.buf.Invalidate()
+	return true
+}
+
+// region returns the part of a screen w by h cells that is enlarged, as
+// moved to keep the cursor at cx, cy in it (if it is on the screen).
+func (m *magnifier) region(w, h, cx, cy int, lines bool) (int, int, int, int) {
+	rw, rh := w/m.factor, h/m.factor
+	if lines {
+		rw, rh = w/2, h/2
+	}
+	if rw < 1 {
+		rw = 1
+	}
+	if rh < 1 {
+		rh = 1
+	}
+	if cx >= 0 && cy >= 0 && cx < w && cy < h {
+		if cx < m.x {
+			m.x = cx
+		} else if cx >= m.x+rw {
+			m.x = cx - rw + 1
+		}
+		if cy < m.y {
+			m.y = cy
+		} else if cy >= m.y+rh {
+			m.y = cy - rh + 1
+		}
+	}
+	m.x = clampInt(m.x, 0, w-rw)
+	m.y = clampInt(m.y, 0, h-rh)
+	return m.x, m.y, rw, rh
+}
+
+// compose draws the enlarged region into buf, for a screen w by h cells,
+// whose cells as displayed are returned by cell.  The cell with the cursor
+// is shown in reverse video.  If lines is true, the terminal has double
+// size lines, which are used to enlarge the text by a factor of two;
+// otherwise every character is drawn with block characters.
+func (m *magnifier) compose(w, h int, cell func(x, y int) (rune, []rune, Style, int), cx, cy int, lines bool) {
+	lines = lines && m.factor == 2
+	if bw, bh := m.buf.Size(); bw != w || bh != h {
+		m.buf.Resize(w, h)
+	}
+	m.buf.Fill(' ', StyleDefault)
+	m.lines = m.lines[:0]
+	for len(m.lines) < h {
+		m.lines = append(m.lines, LineNormal)
+	}
+	rx, ry, rw, rh := m.region(w, h, cx, cy, lines)
+	f := m.factor
+	for ly := ry; ly < ry+rh; ly++ {
+		for lx := rx; lx < rx+rw; {
+			mainc, combc, style, width := cell(lx, ly)
+			if width < 1 {
+				width = 1
+			}
+			if lx == cx && ly == cy {
+				style = style.Reverse(style.attrs&AttrReverse == 0)
+			}
+			if lines {
+				py := (ly - ry) * 2
+				m.lines[py], m.lines[py+1] = LineDoubleHeightTop, LineDoubleHeightBottom
+				m.buf.SetContent(lx-rx, py, mainc, combc, style)
+				m.buf.SetContent(lx-rx, py+1, mainc, combc, style)
+			} else {
+				m.block((lx-rx)*f, (ly-ry)*f, f, width, mainc, combc, style)
+			}
+			lx += width
+		}
+	}
+}
+
+// block draws a character of the given width enlarged by factor f, with
+// its top left corner at x, y.  The pixels of the font used by ToImage
+// are drawn as quarters of the cells; characters not in the font are
+// drawn as they are, in the middle.
+func (m *magnifier) block(x, y, f, width int, mainc rune, combc []rune, style Style) {
+	w, h := f*width, f
+	style = Style{fg: style.fg, bg: style.bg, attrs: style.attrs & AttrReverse}
+	for row := 0; row < h; row++ {
+		for col := 0; col < w; col++ {
+			m.buf.SetContent(x+col, y+row, ' ', nil, style)
+		}
+	}
+	if mainc < ' ' || int(mainc-' ') >= len(imageFontData) {
+		m.buf.SetContent(x+(w-width)/2, y+h/2, mainc, combc, style)
+		return
+	}
+	pixels := strings.Fields(imageFontData[mainc-' '])
+	// The glyph is five pixels wide, and nine high; it is scaled down
+	// as needed to fit the pixels of the cells.
+	set := func(px, py int) bool {
+		gx, gy := px, py*len(pixels)/(2*h)
+		if 2*w < 5 {
+			gx = px * 5 / (2 * w)
+		}
+		return gx < 5 && gy < len(pixels) && pixels[gy][gx] == '#'
+	}
+	for row := 0; row < h; row++ {
+		for col := 0; col < w; col++ {
+			q := 0
+			for bit, p := range [][2]int{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+				if set(col*2+p[0], row*2+p[1]) {
+					q |= 1 << uint(bit)
+				}
+			}
+			if q != 0 {
+				m.buf.SetContent(x+col, y+row, quadrants[q], nil, style)
+			}
+		}
+	}
+}
+
+// magnify draws the enlarged display, and puts it (with its line modes)
+// in place of the cells to be drawn, until unmagnify puts them back.
+func (t *tScreen) magnify() {
+	t.mag.compose(t.w, t.h, func(x, y int) (rune, []rune, Style, int) {
+		mainc, combc, style, width := t.cells.GetContent(x, y)
+		mainc, combc, style = t.overlay(x, y, mainc, combc, style)
+		return mainc, combc, style, width
+	}, t.cursorx, t.cursory, t.lineModes)
+	t.cells, t.mag.buf = t.mag.buf, t.cells
+	t.lines, t.mag.lines = t.mag.lines, t.lines
+}
+
+func (t *tScreen) unmagnify() {
+	t.cells, t.mag.buf = t.mag.buf, t.cells
+	t.lines, t.mag.lines = t.mag.lines, t.lines
+}
+
+func (t *tScreen) SetMagnifier(factor int) {
+	t.Lock()
+	if t.mag.set(factor) && !t.mag.on() {
+		t.cells.Invalidate()
+	}
+	t.Unlock()
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestMagnifier(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(12, 6)
+	for i, r := range "AB" {
+		s.SetContent(i, 0, r, nil, StyleDefault)
+	}
+	s.SetContent(0, 1, 'c', nil, StyleDefault.Foreground(ColorRed))
+	s.ShowCursor(1, 0)
+	styleAt := func(x, y int) Style {
+		cells, w, _ := s.GetContents()
+		return cells[y*w+x].Style
+	}
+
+	s.SetMagnifier(2)
+	s.Show()
+	if got := simRow(s, 0, 0, 3) + "|" + simRow(s, 0, 1, 3) + "|" + simRow(s, 0, 2, 3); got != "AB |AB |c  " {
+		t.Errorf("wrong double size display: %q", got)
+	}
+	if got := styleAt(1, 1); got != StyleDefault.Reverse(true) {
+		t.Errorf("wrong style of cursor: %v", got)
+	}
+	if _, _, vis := s.GetCursor(); vis {
+		t.Errorf("cursor visible while magnified")
+	}
+	if mode := s.GetLineMode(0); mode != LineNormal {
+		t.Errorf("line mode changed: %v", mode)
+	}
+
+	s.SetMagnifier(3)
+	s.Show()
+	if got := simRow(s, 0, 0, 6); got != "▞▀▖▛▀▖" {
+		t.Errorf("wrong block display: %q", got)
+	}
+	if got := styleAt(3, 0); got != StyleDefault.Reverse(true) {
+		t.Errorf("wrong style of cursor: %v", got)
+	}
+	if got := styleAt(0, 3); got != StyleDefault.Foreground(ColorRed) {
+		t.Errorf("wrong style of block: %v", got)
+	}
+	if r, _, _, _ := s.GetContent(0, 0); r != 'A' {
+		t.Errorf("content changed: %q", r)
+	}
+
+	s.SetMagnifier(1)
+	s.Show()
+	if got := simRow(s, 0, 0, 3); got != "AB " {
+		t.Errorf("wrong display after magnifier: %q", got)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// MarkdownStyles are the styles used by DrawMarkdown.  Bold and italic
+// text is drawn with those attributes added to the style it would
+// otherwise have.
+type MarkdownStyles struct {
+	Text    Style
+	Heading Style
+	Code    Style
+	Bullet  Style
+}
+
+// DefaultMarkdownStyles are used by DrawMarkdown when it is given nil.
+var DefaultMarkdownStyles = MarkdownStyles{
+	Text:    StyleDefault,
+	Heading: StyleDefault.Bold(true).Underline(true),
+	Code:    StyleDefault.Foreground(ColorTeal),
+	Bullet:  StyleDefault,
+}
+
+type mdRune struct {
+	r     rune
+	style Style
+}
+
+// DrawMarkdown draws text written in a small subset of Markdown, for help
+// screens and the like, with its top left corner at x, y, wrapping it to
+// width columns.  The number of rows drawn is returned.
+//
+// Lines starting with one to six # characters are headings, and lines
+// starting with -, * or + are bullet list items, nested by indenting them
+// by two spaces.  Other lines are joined into paragraphs, and a blank
+// line ends a paragraph or list, and is drawn as a blank row.  Within
+// the text, **bold**, *italic* and `code` are recognized, as is _ for *
+// (except within words), and a backslash draws the character after it
+// as it is.
+func DrawMarkdown(dst CellSetter, x, y, width int, text string, styles *MarkdownStyles) int {
+	if styles == nil {
+		styles = &DefaultMarkdownStyles
+	}
+	if width <= 0 {
+		return 0
+	}
+
+	// Each block (a heading, list item or paragraph) is drawn once all
+	// of its lines have been seen.
+	var prefix []mdRune
+	var base Style
+	var block []string
+	row := 0
+	blank := false
+	flush := func() {
+		if block != nil {
+			runes := mdInline(strings.Join(block, " "), base, styles)
+			row += drawMarkdownBlock(dst, x, y+row, width, prefix, runes)
+		}
+		prefix, block = nil, nil
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\t", "    "), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			flush()
+			blank = row > 0
+			continue
+		}
+		if blank {
+			row++
+			blank = false
+		}
+		if n := mdHeading(trimmed); n > 0 {
+			flush()
+			base, block = styles.Heading, []string{strings.TrimSpace(trimmed[n:])}
+			flush()
+			continue
+		}
+		if len(trimmed) > 1 && strings.ContainsRune("-*+", rune(trimmed[0])) && trimmed[1] == ' ' {
+			flush()
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			for i := 0; i < indent/2*2; i++ {
+				prefix = append(prefix, mdRune{' ', styles.Text})
+			}
+			prefix = append(prefix, mdRune{'•', styles.Bullet}, mdRune{' ', styles.Text})
+			base, block = styles.Text, []string{strings.TrimSpace(trimmed[2:])}
+			continue
+		}
+		if block == nil {
+			base = styles.Text
+		}
+		block = append(block, trimmed)
+	}
+	flush()
+	return row
+}
+
+// mdHeading returns the number of # characters starting a heading, or
+// zero if the line is not one.
+func mdHeading(line string) int {
+	n := 0
+	for n < len(line) && line[n] == '#' {
+		n++
+	}
+	if n == 0 || n > 6 || (n < len(line) && line[n] != ' ') {
+		return 0
+	}
+	return n
+}
+
+// mdInline returns the runes of text with their styles, as given by the
+// inline markup in it.
+func mdInline(text string, base Style, styles *MarkdownStyles) []mdRune {
+	runes := []rune(text)
+	var out []mdRune
+	bold, italic := false, false
+	style := func() Style {
+		s := base
+		if bold {
+			s = s.Bold(true)
+		}
+		if italic {
+			s = s.Italic(true)
+		}
+		return s
+	}
+	closes := func(from int, delim string) bool {
+		return strings.Contains(string(runes[from:]), delim)
+	}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch r {
+		case '\\':
+			if i+1 < len(runes) && (unicode.IsPunct(runes[i+1]) || unicode.IsSymbol(runes[i+1])) {
+				i++
+				out = append(out, mdRune{runes[i], style()})
+				continue
+			}
+		case '`':
+			if end := strings.IndexRune(string(runes[i+1:]), '`'); end >= 0 {
+				code := []rune(string(runes[i+1:])[:end])
+				for _, c := range code {
+					out = append(out, mdRune{c, styles.Code})
+				}
+				i += len(code) + 1
+				continue
+			}
+		case '*', '_':
+			if r == '_' && i > 0 && i+1 < len(runes) && mdWord(runes[i-1]) && mdWord(runes[i+1]) {
+				break
+			}
+			if i+1 < len(runes) && runes[i+1] == r {
+				delim := string([]rune{r, r})
+				if bold || closes(i+2, delim) {
+					bold = !bold
+					i++
+					continue
+				}
+				break
+			}
+			if italic || closes(i+1, string(r)) {
+				italic = !italic
+				continue
+			}
+		}
+		out = append(out, mdRune{r, style()})
+	}
+	return out
+}
+
+// mdWord returns true if r is part of a word, for the purposes of _.
+func mdWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// drawMarkdownBlock draws the runes, wrapped at spaces to fit in width,
+// after the prefix on the first row, and indented by its width on the
+// others.  It returns the number of rows drawn.
+func drawMarkdownBlock(dst CellSetter, x, y, width int, prefix []mdRune, runes []mdRune) int {
+	row, col := 0, 0
+	put := func(c mdRune) {
+		dst.SetContent(x+col, y+row, c.r, nil, c.style)
+		col += runewidth.RuneWidth(c.r)
+	}
+	for _, c := range prefix {
+		put(c)
+	}
+	start := col
+
+	var word []mdRune
+	var space *mdRune
+	wordWidth := 0
+	emit := func() {
+		if len(word) == 0 {
+			return
+		}
+		if col > start && col+1+wordWidth > width {
+			row++
+			col = start
+		} else if col > start && space != nil {
+			put(*space)
+		}
+		for _, c := range word {
+			if rw := runewidth.RuneWidth(c.r); col+rw > width && col > start {
+				row++
+				col = start
+			}
+			put(c)
+		}
+		word, wordWidth, space = nil, 0, nil
+	}
+	for i := range runes {
+		c := runes[i]
+		if c.r == ' ' {
+			emit()
+			if space == nil {
+				space = &runes[i]
+			}
+			continue
+		}
+		word = append(word, c)
+		wordWidth += runewidth.RuneWidth(c.r)
+	}
+	emit()
+	return row + 1
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestMarkdown(t *testing.T) {
+	cb := &CellBuffer{}
+	cb.Resize(16, 10)
+	cb.Fill(' ', StyleDefault)
+	text := "# Keys\n\nPress **q** to *quit*, or `?` for help.\n\n- one\n  - two_three four\nfive\n"
+	if n := DrawMarkdown(cb, 0, 0, 16, text, nil); n != 8 {
+		t.Errorf("wrong height: %d", n)
+	}
+	want := []string{
+		"Keys            ",
+		"                ",
+		"Press q to quit,",
+		"or ? for help.  ",
+		"                ",
+		"• one           ",
+		"  • two_three   ",
+		"    four five   ",
+	}
+	for y, line := range want {
+		var got []rune
+		for x := 0; x < 16; x++ {
+			r, _, _, _ := cb.GetContent(x, y)
+			got = append(got, r)
+		}
+		if string(got) != line {
+			t.Errorf("wrong row %d: %q", y, string(got))
+		}
+	}
+	styles := DefaultMarkdownStyles
+	check := func(x, y int, style Style) {
+		if _, _, st, _ := cb.GetContent(x, y); st != style {
+			t.Errorf("wrong style at %d,%d: %v", x, y, st)
+		}
+	}
+	check(0, 0, styles.Heading)
+	check(6, 2, styles.Text.Bold(true))
+	check(12, 2, styles.Text.Italic(true))
+	check(15, 2, styles.Text)
+	check(3, 3, styles.Code)
+}
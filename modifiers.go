@@ -0,0 +1,122 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2/terminfo"
+)
+
+// xtermModBit8 returns the modifier reported by the fourth modifier bit
+// (parameter values 9 through 16) in xterm style modifier reporting.
+// Xterm uses this for Meta, but kitty uses it for Super, and puts Meta
+// in a higher bit.
+func xtermModBit8(ti *terminfo.Terminfo) ModMask {
+	if strings.Contains(ti.Name, "kitty") {
+		return ModSuper
+	}
+	return ModMeta
+}
+
+// decodeXtermMods converts an xterm style modifier parameter, which is
+// one more than a bit mask of modifiers, into a ModMask.  The bits above
+// the fourth are only defined by kitty.
+func decodeXtermMods(ti *terminfo.Terminfo, n int) ModMask {
+	n--
+	mod := ModNone
+	if n&1 != 0 {
+		mod |= ModShift
+	}
+	if n&2 != 0 {
+		mod |= ModAlt
+	}
+	if n&4 != 0 {
+		mod |= ModCtrl
+	}
+	if n&8 != 0 {
+		mod |= xtermModBit8(ti)
+	}
+	if n&16 != 0 {
+		mod |= ModHyper
+	}
+	if n&32 != 0 {
+		mod |= ModMeta
+	}
+	return mod
+}
+
+// parseModifiedKey decodes xterm style modified keys (CSI 1 ; m X and
+// CSI n ; m ~) with modifier combinations that were not registered in
+// advance by prepareKeyModXTerm, such as those involving Hyper.  It does
+// this by finding the unmodified sequence in the key table.
+func (t *tScreen) parseModifiedKey(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
+	if t.ti.Modifiers != terminfo.ModifiersXTerm {
+		return false, false
+	}
+	b := buf.Bytes()
+	if len(b) < 2 || b[0] != '\x1b' || b[1] != '[' {
+		return false, false
+	}
+	digits := func(i int) int {
+		for i < len(b) && b[i] >= '0' && b[i] <= '9' {
+			i++
+		}
+		return i
+	}
+	i := digits(2)
+	if i == len(b) {
+		return true, false
+	}
+	if i == 2 || b[i] != ';' {
+		return false, false
+	}
+	p1 := string(b[2:i])
+	j := digits(i + 1)
+	if j == len(b) {
+		return true, false
+	}
+	if j == i+1 {
+		return false, false
+	}
+	n, _ := strconv.Atoi(string(b[i+1 : j]))
+	final := b[j]
+
+	var bases []string
+	if final == '~' {
+		bases = []string{"\x1b[" + p1 + "~"}
+	} else if p1 == "1" {
+		bases = []string{"\x1bO" + string(final), "\x1b[" + string(final)}
+	}
+	for _, base := range bases {
+		if k, ok := t.keycodes[base]; ok && k.mod == ModNone {
+			mod := decodeXtermMods(t.ti, n)
+			raw := append([]byte{}, b[:j+1]...)
+			if t.escaped {
+				mod |= ModAlt
+				t.escaped = false
+				raw = append([]byte{'\x1b'}, raw...)
+			}
+			ev := NewEventKey(k.key, 0, mod)
+			ev.raw = raw
+			*evs = append(*evs, ev)
+			buf.Next(j + 1)
+			return true, true
+		}
+	}
+	return false, false
+}
@@ -30,7 +30,13 @@ import (
 // for mouse wheel movements.
 //
 // Most terminals cannot report the state of more than one button at a time --
-// and some cannot report motion events unless a button is pressed.
+// and some cannot report motion events unless a button is pressed.  Where the
+// terminal reports presses and releases of each button separately (as with the
+// SGR mouse protocol, or the Windows console), buttons held together as a chord
+// are reported together, and Chord returns true.
+//
+// Modifiers are reported for wheel and motion events as well as for presses,
+// to the extent that the terminal reports them.
 //
 // Applications can inspect the time between events to resolve double or
 // triple clicks.
@@ -58,6 +64,13 @@ func (ev *EventMouse) Modifiers() ModMask {
 	return ev.mod
 }
 
+// Chord returns true if more than one button (not counting the wheel) is
+// held, such as both the left and right buttons.
+func (ev *EventMouse) Chord() bool {
+	btn := ev.btn & (Button1 | Button2 | Button3 | Button4 | Button5 | Button6 | Button7 | Button8)
+	return btn&(btn-1) != 0
+}
+
 // Position returns the mouse position in character cells.  The origin
 // 0, 0 is at the upper left corner.
 func (ev *EventMouse) Position() (int, int) {
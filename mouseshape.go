@@ -0,0 +1,57 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// MouseShape is the name of a shape for the mouse pointer, as understood
+// by OSC 22.  Terminals that support it (including xterm, kitty, foot and
+// WezTerm) accept the CSS cursor names given here; some also accept the
+// names of X11 cursors, such as "hand2", which may be used as well.
+type MouseShape string
+
+// These are the common pointer shapes.
+const (
+	MouseShapeDefault    = MouseShape("")        // the terminal's own pointer
+	MouseShapeArrow      = MouseShape("default") // the usual arrow
+	MouseShapeText       = MouseShape("text")    // an I-beam, for text
+	MouseShapePointer    = MouseShape("pointer") // a hand, for links
+	MouseShapeCrosshair  = MouseShape("crosshair")
+	MouseShapeMove       = MouseShape("move")
+	MouseShapeWait       = MouseShape("wait")
+	MouseShapeProgress   = MouseShape("progress")
+	MouseShapeHelp       = MouseShape("help")
+	MouseShapeNotAllowed = MouseShape("not-allowed")
+	MouseShapeEWResize   = MouseShape("ew-resize") // for a vertical divider
+	MouseShapeNSResize   = MouseShape("ns-resize") // for a horizontal divider
+)
+
+// mouseShapeSeq returns the sequence that selects a pointer shape.
+func mouseShapeSeq(shape MouseShape) string {
+	if shape == MouseShapeDefault {
+		shape = MouseShapeArrow
+	}
+	return "\x1b]22;" + string(shape) + "\x1b\\"
+}
+
+func (t *tScreen) SetMouseShape(shape MouseShape) {
+	t.Lock()
+	defer t.Unlock()
+	// As with other OSCs, we assume that terminals with a mouse will
+	// either understand it or ignore it.
+	if t.ti.Mouse == "" || !validSequence(mouseShapeSeq(shape)) || shape == t.mouseShape {
+		return
+	}
+	t.mouseShape = shape
+	t.updateModes()
+}
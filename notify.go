@@ -0,0 +1,110 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Desktop notifications are requested with one of three OSCs, none of
+// which terminfo describes, and which terminals do not answer queries
+// about.  So we go by the name of the terminal:
+//
+//   - OSC 9 (iTerm2, and ConEmu) takes just a message.
+//   - OSC 777 (rxvt-unicode, foot, WezTerm, and VTE with a patch) takes
+//     a title and a body.
+//   - OSC 99 (kitty) takes the title and body in separate sequences.
+//
+// This can be overridden by setting TCELL_NOTIFY to "osc9", "osc777",
+// "osc99", or "disable".
+
+type notifyKind int
+
+const (
+	notifyNone notifyKind = iota
+	notifyOSC9
+	notifyOSC777
+	notifyOSC99
+)
+
+// prepareNotify decides how to send desktop notifications.
+func (t *tScreen) prepareNotify() {
+	name := t.ti.Name
+	switch {
+	case strings.HasPrefix(name, "xterm-kitty"):
+		t.notify = notifyOSC99
+	case t.getenv("TERM_PROGRAM") == "iTerm.app":
+		t.notify = notifyOSC9
+	case t.getenv("TERM_PROGRAM") == "WezTerm",
+		strings.HasPrefix(name, "foot"),
+		strings.HasPrefix(name, "rxvt-unicode"):
+		t.notify = notifyOSC777
+	default:
+		t.notify = notifyNone
+	}
+	switch t.getenv("TCELL_NOTIFY") {
+	case "osc9":
+		t.notify = notifyOSC9
+	case "osc777":
+		t.notify = notifyOSC777
+	case "osc99":
+		t.notify = notifyOSC99
+	case "disable":
+		t.notify = notifyNone
+	}
+}
+
+// notifyText removes control characters, which would end the OSC early,
+// and optionally semicolons, which would end a field.
+func notifyText(s string, semis bool) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r < ' ', r >= 0x7f && r < 0xa0:
+			return ' '
+		case r == ';' && !semis:
+			return ','
+		}
+		return r
+	}, s)
+}
+
+func (t *tScreen) Notify(title, body string) error {
+	t.Lock()
+	defer t.Unlock()
+	if t.fini || !t.running {
+		return nil
+	}
+	var seq string
+	switch t.notify {
+	case notifyOSC9:
+		msg := notifyText(body, false)
+		if title != "" {
+			msg = notifyText(title, false) + ": " + msg
+		}
+		seq = "\x1b]9;" + msg + "\x1b\\"
+	case notifyOSC777:
+		seq = "\x1b]777;notify;" + notifyText(title, false) + ";" + notifyText(body, true) + "\x1b\\"
+	case notifyOSC99:
+		t.notifyID++
+		id := "i=" + strconv.Itoa(t.notifyID)
+		seq = "\x1b]99;" + id + ":d=0:p=title;" + notifyText(title, true) + "\x1b\\" +
+			"\x1b]99;" + id + ":d=1:p=body;" + notifyText(body, true) + "\x1b\\"
+	default:
+		return ErrNotSupported
+	}
+	t.writeString(seq)
+	return nil
+}
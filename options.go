@@ -0,0 +1,161 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"github.com/gdamore/tcell/v2/terminfo"
+)
+
+// ColorMode limits (or extends) the colors that a screen uses, overriding
+// what the terminal database says about the terminal.
+type ColorMode int
+
+const (
+	ColorModeAuto      ColorMode = iota // as described by the terminal
+	ColorModeMono                       // no colors at all
+	ColorMode8                          // at most the 8 ANSI colors
+	ColorMode16                         // at most 16 colors
+	ColorMode256                        // at most 256 colors
+	ColorModeTrueColor                  // 24-bit color, and the palette
+)
+
+// Option is an option for NewScreenWithOptions.
+type Option func(*screenOptions)
+
+type screenOptions struct {
+	term      string
+	ti        *terminfo.Terminfo
+	tty       Tty
+	noAlt     bool
+	keepMouse bool
+	noEnv     bool
+	ctrlC     bool
+	colors    ColorMode
+	session   *Session
+}
+
+// WithTERM uses the named terminal, instead of $TERM.
+func WithTERM(name string) Option {
+	return func(o *screenOptions) {
+		o.term = name
+	}
+}
+
+// WithTty uses tty for input and output, instead of the controlling
+// terminal (see NewTerminfoScreenFromTty).
+func WithTty(tty Tty) Option {
+	return func(o *screenOptions) {
+		o.tty = tty
+	}
+}
+
+// WithoutAltScreen draws on the main screen, rather than the alternate
+// screen, so that what was drawn is left in place (and in the scrollback)
+// after the screen is finalized.
+func WithoutAltScreen() Option {
+	return func(o *screenOptions) {
+		o.noAlt = true
+	}
+}
+
+// WithoutMouseCleanup leaves mouse reporting enabled when the screen is
+// suspended or finalized.  This is for applications that run inside
+// another one which has enabled mouse reporting itself.
+func WithoutMouseCleanup() Option {
+	return func(o *screenOptions) {
+		o.keepMouse = true
+	}
+}
+
+// WithColorMode overrides the colors that the terminal is taken to have.
+// This takes the place of setting $COLORTERM or TCELL_TRUECOLOR.  The
+// Windows console has 16 colors without 24-bit color, so ColorMode256
+// gets it no more than ColorMode16 does.
+func WithColorMode(mode ColorMode) Option {
+	return func(o *screenOptions) {
+		o.colors = mode
+	}
+}
+
+// WithoutEnv ignores the environment variables that override what tcell
+// would otherwise do (see env.go), and those used to recognize terminals,
+// for applications that must behave the same wherever they are run.
+// Only $TERM (unless WithTERM is given) and the locale are still read.
+func WithoutEnv() Option {
+	return func(o *screenOptions) {
+		o.noEnv = true
+	}
+}
+
+// WithCtrlCSignal lets Ctrl+C interrupt the application, as it does
+// outside of tcell, rather than reporting it as KeyCtrlC.  This applies
+// only to the Windows console, where Ctrl+C then raises a console control
+// event, which Go delivers as os.Interrupt to channels given to
+// signal.Notify (and otherwise ends the program).  Terminfo screens
+// ignore it, as the terminal is in raw mode.
+func WithCtrlCSignal() Option {
+	return func(o *screenOptions) {
+		o.ctrlC = true
+	}
+}
+
+// NewScreenWithOptions returns a Screen, as NewScreen does, configured by
+// opts.  Unlike environment variables, and methods called after Init,
+// options take effect from the start and in no particular order.  If a
+// terminal or a Tty is given, then the screen is always a terminfo one.
+// Options that do not apply to the Windows console are ignored by it.
+func NewScreenWithOptions(opts ...Option) (Screen, error) {
+	var o screenOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.term == "" && o.tty == nil {
+		if s, _ := newConsoleScreen(&o); s != nil {
+			return s, nil
+		}
+	}
+	t, e := newTScreen(&o)
+	if e != nil {
+		return nil, e
+	}
+	return t, nil
+}
+
+// colorModeTerminfo returns a copy of ti, amended for the color mode.
+// Entries in the database are shared, so are never changed themselves.
+func colorModeTerminfo(ti *terminfo.Terminfo, mode ColorMode) *terminfo.Terminfo {
+	if mode == ColorModeAuto {
+		return ti
+	}
+	nt := *ti
+	limit := map[ColorMode]int{ColorModeMono: 0, ColorMode8: 8, ColorMode16: 16, ColorMode256: 256}
+	if n, ok := limit[mode]; ok {
+		if nt.Colors > n {
+			nt.Colors = n
+		}
+		nt.SetFgRGB, nt.SetBgRGB, nt.SetFgBgRGB = "", "", ""
+		nt.TrueColor = false
+		return &nt
+	}
+	if nt.SetFgBgRGB == "" && nt.SetFgRGB == "" && nt.SetBgRGB == "" {
+		// The same sequences that $COLORTERM gets us.
+		nt.SetFgRGB = "\x1b[38;2;%p1%d;%p2%d;%p3%dm"
+		nt.SetBgRGB = "\x1b[48;2;%p1%d;%p2%d;%p3%dm"
+		nt.SetFgBgRGB = "\x1b[38;2;%p1%d;%p2%d;%p3%d;" +
+			"48;2;%p4%d;%p5%d;%p6%dm"
+	}
+	nt.TrueColor = true
+	return &nt
+}
@@ -0,0 +1,165 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// NormalVision is the absence of color blindness, for which colors are
+// seen as they are.
+const NormalVision ColorBlindness = -1
+
+// Visions are the kinds of color vision that palettes and styles are
+// checked against, normal vision first.
+var Visions = []ColorBlindness{NormalVision, Protanopia, Deuteranopia, Tritanopia}
+
+// The minimum contrast ratios between text and its background given by
+// WCAG 2 for normal text, at levels AA and AAA.
+const (
+	ContrastAA  = 4.5
+	ContrastAAA = 7.0
+)
+
+// ContrastRatio returns the contrast ratio between two colors, as defined
+// by WCAG 2, from 1 for colors of the same luminance to 21 for black and
+// white.  Zero is returned if either color has no RGB value, such as
+// ColorDefault, as its contrast cannot be known.
+func ContrastRatio(a, b Color) float64 {
+	la, lb := luminance(a), luminance(b)
+	if la < 0 || lb < 0 {
+		return 0
+	}
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// ColorDifference returns how different two colors look with the kind of
+// color vision, as the CIEDE2000 color difference.  A difference of about
+// 2 is just noticeable, and colors used to tell things apart at a glance
+// should differ by 10 or more.  -1 is returned if either color has no RGB
+// value.
+func ColorDifference(a, b Color, kind ColorBlindness) float64 {
+	a, b = SimulateColorBlindness(a, kind), SimulateColorBlindness(b, kind)
+	ar, ag, ab := a.RGB()
+	br, bg, bb := b.RGB()
+	if ar < 0 || br < 0 {
+		return -1
+	}
+	ca := colorful.Color{R: float64(ar) / 255, G: float64(ag) / 255, B: float64(ab) / 255}
+	cb := colorful.Color{R: float64(br) / 255, G: float64(bg) / 255, B: float64(bb) / 255}
+	return ca.DistanceCIEDE2000(cb) * 100
+}
+
+// StyleIssue is a style whose text has too little contrast with its
+// background for the kind of color vision, as found by CheckStyles.
+type StyleIssue struct {
+	Index    int // of the style in those checked
+	Vision   ColorBlindness
+	Contrast float64
+}
+
+// CheckStyles checks that the foreground of each of the styles has a
+// contrast ratio of at least min with its background, for each of the
+// Visions, returning the issues found.  ContrastAA is the usual minimum.
+// Styles using a color without an RGB value, such as ColorDefault, are
+// not checked, as their colors depend on the terminal.
+func CheckStyles(styles []Style, min float64) []StyleIssue {
+	var issues []StyleIssue
+	for i, s := range styles {
+		for _, kind := range Visions {
+			fg := SimulateColorBlindness(s.fg, kind)
+			bg := SimulateColorBlindness(s.bg, kind)
+			if ratio := ContrastRatio(fg, bg); ratio > 0 && ratio < min {
+				issues = append(issues, StyleIssue{Index: i, Vision: kind, Contrast: ratio})
+			}
+		}
+	}
+	return issues
+}
+
+// PaletteIssue is a pair of colors of a palette that are too alike for
+// the kind of color vision, as found by CheckPalette.
+type PaletteIssue struct {
+	A, B       int // the indices of the colors, with A less than B
+	Vision     ColorBlindness
+	Difference float64
+}
+
+// CheckPalette checks that every pair of the colors has a ColorDifference
+// of at least min for each of the Visions, so that the colors can be told
+// apart, returning the issues found.  Colors without RGB values are not
+// checked.
+func CheckPalette(colors []Color, min float64) []PaletteIssue {
+	var issues []PaletteIssue
+	for a := range colors {
+		for b := a + 1; b < len(colors); b++ {
+			for _, kind := range Visions {
+				d := ColorDifference(colors[a], colors[b], kind)
+				if d >= 0 && d < min {
+					issues = append(issues, PaletteIssue{A: a, B: b, Vision: kind, Difference: d})
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// okabeIto is the palette of Okabe and Ito, designed to be told apart
+// with any common kind of color blindness, without its black.
+var okabeIto = []Color{
+	NewHexColor(0xe69f00), // orange
+	NewHexColor(0x56b4e9), // sky blue
+	NewHexColor(0x009e73), // bluish green
+	NewHexColor(0xf0e442), // yellow
+	NewHexColor(0x0072b2), // blue
+	NewHexColor(0xd55e00), // vermillion
+	NewHexColor(0xcc79a7), // reddish purple
+}
+
+// SafePalette returns n colors that can be told apart with normal vision
+// and with each kind of color blindness, for use as the colors of a
+// theme.  The first seven are the palette of Okabe and Ito; any more are
+// chosen from the colors of the 256 color palette, each as different as
+// possible from those before it, and so are increasingly alike.
+func SafePalette(n int) []Color {
+	if n <= 0 {
+		return nil
+	}
+	if n <= len(okabeIto) {
+		return append([]Color{}, okabeIto[:n]...)
+	}
+	colors := append([]Color{}, okabeIto...)
+	for len(colors) < n {
+		best, bestDiff := Color(0), -1.0
+		for c := Color16; c < Color232; c++ {
+			diff := -1.0
+			for _, p := range colors {
+				for _, kind := range Visions {
+					if d := ColorDifference(c.TrueColor(), p, kind); diff < 0 || d < diff {
+						diff = d
+					}
+				}
+			}
+			if diff > bestDiff {
+				best, bestDiff = c.TrueColor(), diff
+			}
+		}
+		colors = append(colors, best)
+	}
+	return colors
+}
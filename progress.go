@@ -0,0 +1,77 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strconv"
+)
+
+// ProgressState is the state of the progress indicator that some terminals
+// show, for example on the taskbar button of their window.
+type ProgressState int
+
+// These are the states of the progress indicator, numbered as in OSC 9;4.
+const (
+	ProgressNone          ProgressState = iota // no progress is shown
+	ProgressNormal                             // progress, as a percentage
+	ProgressError                              // progress, showing an error
+	ProgressIndeterminate                      // busy, with unknown progress
+	ProgressPaused                             // progress, paused (or warning)
+)
+
+// Progress is reported with OSC 9;4, which originated with ConEmu, and is
+// now understood by Windows Terminal and a few others.  Other terminals
+// take OSC 9 to be a notification (see notify.go), and would show the
+// parameters as text, so we only send it to terminals known to handle it.
+// This can be overridden by setting TCELL_PROGRESS to "enable" or
+// "disable".
+
+// progressSeq returns the sequence that reports progress.
+func progressSeq(state ProgressState, percent int) string {
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	return "\x1b]9;4;" + strconv.Itoa(int(state)) + ";" + strconv.Itoa(percent) + "\x1b\\"
+}
+
+// prepareProgress decides whether progress can be reported.
+func (t *tScreen) prepareProgress() {
+	t.progress = t.getenv("WT_SESSION") != "" || t.getenv("ConEmuPID") != "" ||
+		t.getenv("TERM_PROGRAM") == "ghostty"
+	switch t.getenv("TCELL_PROGRESS") {
+	case "enable":
+		t.progress = true
+	case "disable":
+		t.progress = false
+	}
+}
+
+func (t *tScreen) SetProgress(state ProgressState, percent int) error {
+	t.Lock()
+	defer t.Unlock()
+	if !t.progress {
+		return ErrNotSupported
+	}
+	if state < ProgressNone || state > ProgressPaused {
+		state = ProgressNone
+	}
+	t.progState = state
+	t.progPercent = percent
+	t.updateModes()
+	return nil
+}
@@ -0,0 +1,448 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompt
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// Match reports whether the runes of pattern appear in text in order, as
+// fuzzy finders match, with a score that is higher for better matches:
+// those with the runes together, at the starts of words, and near the
+// start of the text.  It also returns the positions (in runes) of text
+// that were matched.  Matching ignores case, unless the pattern has an
+// upper case letter.  An empty pattern matches everything.
+func Match(pattern, text string) (score int, positions []int, ok bool) {
+	pat := []rune(pattern)
+	if len(pat) == 0 {
+		return 0, nil, true
+	}
+	fold := true
+	for _, r := range pat {
+		if unicode.IsUpper(r) {
+			fold = false
+		}
+	}
+	txt := []rune(text)
+	eq := func(a, b rune) bool {
+		if fold {
+			return unicode.ToLower(a) == b
+		}
+		return a == b
+	}
+
+	// Find the first place where the pattern ends, and then work back
+	// from there to find the shortest match that ends there.
+	p, end := 0, -1
+	for i, r := range txt {
+		if eq(r, pat[p]) {
+			if p++; p == len(pat) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+	p, start := len(pat)-1, end
+	for i := end; i >= 0; i-- {
+		if eq(txt[i], pat[p]) {
+			if p--; p < 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	p = 0
+	prev := -2
+	for i := start; i <= end && p < len(pat); i++ {
+		if !eq(txt[i], pat[p]) {
+			continue
+		}
+		score += 16
+		switch {
+		case i == prev+1:
+			score += 12
+		case i == 0 || isBoundary(txt[i-1], txt[i]):
+			score += 8
+		case prev >= 0:
+			score -= i - prev - 1
+		}
+		positions = append(positions, i)
+		prev = i
+		p++
+	}
+	if start == 0 {
+		score += 4
+	}
+	return score, positions, true
+}
+
+// isBoundary returns true if r starts a word, coming after prev.
+func isBoundary(prev, r rune) bool {
+	if !isWord(prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(r)
+}
+
+// finderSyncMax is the number of items that are filtered at once; more
+// are filtered in the background.
+const finderSyncMax = 5000
+
+type finderMatch struct {
+	index     int
+	score     int
+	positions []int
+}
+
+// finderResults is posted (in an EventInterrupt) when a background filter
+// is done.
+type finderResults struct {
+	f       *Finder
+	gen     int
+	matches []finderMatch
+}
+
+// Finder is a fuzzy finder, or command palette: a prompt, with a list of
+// the items that match what has been typed below it, best first.  Create
+// one with NewFinder, give it items, and either call Run, or pass events
+// to HandleEvent from the application's own event loop, and call Draw.
+//
+// Up and Down (or Ctrl-P and Ctrl-N), PgUp and PgDn move the selection,
+// Enter chooses the selected item, and Escape cancels; other keys edit
+// the pattern, as with Prompt.  Large lists of items are filtered in the
+// background, the results being delivered as an EventInterrupt posted
+// to the screen, which HandleEvent takes.  If Enter is pressed while
+// that is being done, the choice is made once the results arrive.
+type Finder struct {
+	// Input is the prompt where the pattern is typed.
+	Input *Prompt
+
+	// Style is the style of the list, SelectedStyle that of the selected
+	// item, and MatchStyle is combined with them for the matched runes.
+	Style         tcell.Style
+	SelectedStyle tcell.Style
+	MatchStyle    tcell.Style
+
+	// OnSelect and OnCancel, if not nil, are called by HandleEvent when
+	// an item is chosen (with its index, and the item), or the finder is
+	// cancelled.
+	OnSelect func(index int, item string)
+	OnCancel func()
+
+	s       tcell.Screen
+	x, y    int
+	w, h    int
+	items   []string
+	pattern string
+	matches []finderMatch
+	sel     int
+	top     int
+	gen     int
+	pending bool // a background filter is running
+	waiting bool // Enter was pressed while it was
+
+	done   bool
+	choice int
+	err    error
+	sync.Mutex
+}
+
+// NewFinder creates a Finder that is drawn in the region of the screen at
+// x, y with the given width and height; the prompt is on the first row,
+// and the list below it.
+func NewFinder(s tcell.Screen, x, y, width, height int) *Finder {
+	f := &Finder{
+		Input:         New(s, x, y, width),
+		SelectedStyle: tcell.StyleDefault.Reverse(true),
+		MatchStyle:    tcell.StyleDefault.Bold(true),
+		s:             s,
+		x:             x,
+		y:             y,
+		w:             width,
+		h:             height,
+	}
+	f.Input.Prompt = "> "
+	return f
+}
+
+// SetItems replaces the items to choose from.
+func (f *Finder) SetItems(items []string) {
+	f.Lock()
+	f.items = append([]string{}, items...)
+	f.Unlock()
+	f.filter()
+}
+
+// AddItems adds to the items to choose from.  It may be called from any
+// goroutine, such as one that finds the items, while the finder is in use.
+func (f *Finder) AddItems(items ...string) {
+	f.Lock()
+	f.items = append(f.items, items...)
+	f.Unlock()
+	f.filter()
+}
+
+// Selected returns the index and the item selected, or -1 if no item
+// matches.
+func (f *Finder) Selected() (int, string) {
+	f.Lock()
+	defer f.Unlock()
+	if f.sel >= len(f.matches) || f.matches[f.sel].index >= len(f.items) {
+		return -1, ""
+	}
+	i := f.matches[f.sel].index
+	return i, f.items[i]
+}
+
+// filter starts matching the items against the pattern.
+func (f *Finder) filter() {
+	f.Lock()
+	f.gen++
+	gen, pattern, items := f.gen, f.pattern, f.items
+	if len(items) <= finderSyncMax {
+		f.pending = false
+		f.Unlock()
+		f.apply(gen, matchItems(pattern, items, nil))
+		return
+	}
+	f.pending = true
+	f.Unlock()
+	go func() {
+		stale := func() bool {
+			f.Lock()
+			defer f.Unlock()
+			return f.gen != gen
+		}
+		if matches := matchItems(pattern, items, stale); matches != nil {
+			_ = f.s.PostEvent(tcell.NewEventInterrupt(&finderResults{f: f, gen: gen, matches: matches}))
+		}
+	}()
+}
+
+// matchItems returns the items that match the pattern, best first.  If
+// stale (which is checked now and then) returns true, it gives up, and
+// returns nil.
+func matchItems(pattern string, items []string, stale func() bool) []finderMatch {
+	matches := []finderMatch{}
+	for i, item := range items {
+		if stale != nil && i%1000 == 0 && stale() {
+			return nil
+		}
+		if score, pos, ok := Match(pattern, item); ok {
+			matches = append(matches, finderMatch{index: i, score: score, positions: pos})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		return len(items[a.index]) < len(items[b.index])
+	})
+	return matches
+}
+
+// apply sets the matches found by a filter, unless it was superseded.
+func (f *Finder) apply(gen int, matches []finderMatch) {
+	f.Lock()
+	defer f.Unlock()
+	if gen != f.gen {
+		return
+	}
+	f.matches, f.sel, f.top, f.pending = matches, 0, 0, false
+}
+
+// Run draws the finder, and handles the events of the screen until an
+// item is chosen, returning its index and the item.  ErrCancelled is
+// returned if the finder is cancelled.  Events other than keys, resizes
+// and the results of filtering are discarded.
+func (f *Finder) Run() (int, string, error) {
+	f.done, f.err = false, nil
+	for !f.done {
+		f.Draw()
+		f.s.Show()
+		ev := f.s.PollEvent()
+		if ev == nil {
+			return -1, "", io.EOF
+		}
+		if _, ok := ev.(*tcell.EventResize); ok {
+			f.s.Sync()
+			continue
+		}
+		f.HandleEvent(ev)
+	}
+	if f.err != nil {
+		return -1, "", f.err
+	}
+	return f.choice, f.items[f.choice], nil
+}
+
+// HandleEvent handles a key event, or the results of filtering in the
+// background, returning true if it was used.
+func (f *Finder) HandleEvent(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *tcell.EventInterrupt:
+		r, ok := ev.Data().(*finderResults)
+		if !ok || r.f != f {
+			return false
+		}
+		f.apply(r.gen, r.matches)
+		if f.waiting && !f.pending {
+			f.waiting = false
+			f.choose()
+		}
+		return true
+	case *tcell.EventKey:
+		switch ev.Key() {
+		case tcell.KeyUp, tcell.KeyCtrlP:
+			f.move(-1)
+		case tcell.KeyDown, tcell.KeyCtrlN:
+			f.move(1)
+		case tcell.KeyPgUp:
+			f.move(-(f.h - 1))
+		case tcell.KeyPgDn:
+			f.move(f.h - 1)
+		case tcell.KeyEnter:
+			if f.pending {
+				f.waiting = true
+			} else {
+				f.choose()
+			}
+		case tcell.KeyEscape, tcell.KeyCtrlC:
+			f.done, f.err = true, ErrCancelled
+			if f.OnCancel != nil {
+				f.OnCancel()
+			}
+		default:
+			if !f.Input.HandleEvent(ev) {
+				return false
+			}
+			if text := f.Input.Text(); text != f.pattern {
+				f.Lock()
+				f.pattern = text
+				f.Unlock()
+				f.filter()
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// choose chooses the selected item, if there is one.
+func (f *Finder) choose() {
+	if i, item := f.Selected(); i >= 0 {
+		f.done, f.choice = true, i
+		if f.OnSelect != nil {
+			f.OnSelect(i, item)
+		}
+	}
+}
+
+func (f *Finder) move(n int) {
+	f.Lock()
+	defer f.Unlock()
+	f.sel += n
+	if f.sel >= len(f.matches) {
+		f.sel = len(f.matches) - 1
+	}
+	if f.sel < 0 {
+		f.sel = 0
+	}
+}
+
+// Draw draws the prompt and the list.
+func (f *Finder) Draw() {
+	f.Lock()
+	defer f.Unlock()
+
+	count := strconv.Itoa(len(f.matches)) + "/" + strconv.Itoa(len(f.items))
+	if f.pending {
+		count = "…" + count
+	}
+	if cw := runewidth.StringWidth(count) + 1; cw < f.w {
+		f.Input.SetPosition(f.x, f.y, f.w-cw)
+		col := f.x + f.w - cw
+		for _, r := range " " + count {
+			f.s.SetContent(col, f.y, r, nil, f.Style)
+			col += runewidth.RuneWidth(r)
+		}
+	} else {
+		f.Input.SetPosition(f.x, f.y, f.w)
+	}
+
+	rows := f.h - 1
+	if f.sel < f.top {
+		f.top = f.sel
+	}
+	if f.sel >= f.top+rows {
+		f.top = f.sel - rows + 1
+	}
+	for row := 0; row < rows; row++ {
+		y := f.y + 1 + row
+		style := f.Style
+		var runes []rune
+		var positions []int
+		if i := f.top + row; i < len(f.matches) && f.matches[i].index < len(f.items) {
+			m := f.matches[i]
+			runes, positions = []rune(f.items[m.index]), m.positions
+			if i == f.sel {
+				style = f.SelectedStyle
+			}
+		}
+		col := f.x
+		for i, r := range runes {
+			rw := runewidth.RuneWidth(r)
+			if col+rw > f.x+f.w {
+				break
+			}
+			cs := style
+			if len(positions) > 0 && positions[0] == i {
+				positions = positions[1:]
+				cs = mergeStyle(style, f.MatchStyle)
+			}
+			f.s.SetContent(col, y, r, nil, cs)
+			col += rw
+		}
+		for ; col < f.x+f.w; col++ {
+			f.s.SetContent(col, y, ' ', nil, style)
+		}
+	}
+	f.Input.Draw()
+}
+
+// mergeStyle returns base with the colors and attributes of over added.
+func mergeStyle(base, over tcell.Style) tcell.Style {
+	fg, bg, attr := over.Decompose()
+	if fg != tcell.ColorDefault {
+		base = base.Foreground(fg)
+	}
+	if bg != tcell.ColorDefault {
+		base = base.Background(bg)
+	}
+	_, _, battr := base.Decompose()
+	return base.Attributes(battr | attr)
+}
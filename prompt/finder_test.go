@@ -0,0 +1,96 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompt
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestMatch(t *testing.T) {
+	if _, pos, ok := Match("fb", "foo_bar"); !ok || !reflect.DeepEqual(pos, []int{0, 4}) {
+		t.Errorf("wrong match: %v %v", pos, ok)
+	}
+	if _, _, ok := Match("bf", "foo_bar"); ok {
+		t.Errorf("matched out of order")
+	}
+	if _, _, ok := Match("FB", "foo_bar"); ok {
+		t.Errorf("upper case pattern matched lower case text")
+	}
+	if _, pos, ok := Match("ob", "foo obj"); !ok || !reflect.DeepEqual(pos, []int{4, 5}) {
+		t.Errorf("wrong shortest match: %v", pos)
+	}
+	together, _, _ := Match("bar", "foobar")
+	apart, _, _ := Match("bar", "bxaxr")
+	words, _, _ := Match("fb", "foo_bar")
+	inside, _, _ := Match("fb", "xfxxbx")
+	if together <= apart || words <= inside {
+		t.Errorf("wrong scores: %d %d %d %d", together, apart, words, inside)
+	}
+}
+
+func TestFinder(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatalf("cannot init: %v", err)
+	}
+	defer s.Fini()
+	s.SetSize(20, 4)
+
+	f := NewFinder(s, 0, 0, 20, 4)
+	f.SetItems([]string{"open file", "save file", "quit", "find files"})
+	for _, r := range "fi" {
+		f.HandleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	f.Draw()
+	s.Show()
+	cells, w, _ := s.GetContents()
+	row := func(y int) string {
+		var text []rune
+		for _, c := range cells[y*w : y*w+w] {
+			text = append(text, c.Runes[0])
+		}
+		return string(text)
+	}
+	if got := row(0) + "|" + row(1) + "|" + row(2); got != "> fi             3/4|find files          |open file           " {
+		t.Errorf("wrong drawing: %q", got)
+	}
+	if c := cells[w+1]; c.Style != tcell.StyleDefault.Reverse(true).Bold(true) {
+		t.Errorf("wrong style of match: %v", c.Style)
+	}
+
+	f.HandleEvent(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	if i, item := f.Selected(); i != 0 || item != "open file" {
+		t.Errorf("wrong selection: %d %q", i, item)
+	}
+
+	// Large lists are filtered in the background.
+	var items []string
+	for i := 0; i < finderSyncMax*2; i++ {
+		items = append(items, fmt.Sprintf("item %d", i))
+	}
+	f = NewFinder(s, 0, 0, 20, 4)
+	f.SetItems(items)
+	for _, r := range "9999" {
+		s.InjectKey(tcell.KeyRune, r, tcell.ModNone)
+	}
+	s.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	if i, item, err := f.Run(); err != nil || i != 9999 || item != "item 9999" {
+		t.Errorf("wrong result: %d %q %v", i, item, err)
+	}
+}
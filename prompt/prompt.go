@@ -0,0 +1,492 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package prompt implements a single line editor in the manner of
+// readline, drawn on a tcell Screen, for command palettes, REPLs and the
+// like.  It has history, completion, syntax highlighting, and the usual
+// Emacs style keys, including a kill ring:
+//
+//	Left, Ctrl-B / Right, Ctrl-F    move by a character
+//	Alt-B / Alt-F                   move by a word
+//	Home, Ctrl-A / End, Ctrl-E      move to the start or end
+//	Backspace / Delete, Ctrl-D      delete a character
+//	Ctrl-W, Alt-Backspace / Alt-D   kill a word, back or forward
+//	Ctrl-U / Ctrl-K                 kill to the start or end
+//	Ctrl-Y / Alt-Y                  yank, and replace it with older kills
+//	Ctrl-T                          transpose characters
+//	Up, Ctrl-P / Down, Ctrl-N       move through the history
+//	Tab                             complete
+//	Enter                           submit the line
+//	Escape, Ctrl-C                  cancel
+//	Ctrl-D (on an empty line)       end of input
+package prompt
+
+import (
+	"errors"
+	"io"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// ErrCancelled is returned by Read when the user cancels the prompt.
+var ErrCancelled = errors.New("prompt cancelled")
+
+// killRingSize is the number of kills that are kept for yanking.
+const killRingSize = 16
+
+// Prompt is a single line editor.  Create one with New, and either call
+// Read, which handles events until a line is entered, or pass events to
+// HandleEvent from the application's own event loop, and call Draw.
+type Prompt struct {
+	// Prompt is shown before the input, in PromptStyle.
+	Prompt      string
+	PromptStyle tcell.Style
+
+	// Style is the style of the input, and the rest of the line.
+	Style tcell.Style
+
+	// MaxHistory is the number of lines kept in the history; if it is
+	// zero, all are kept.
+	MaxHistory int
+
+	// Complete, if not nil, is called when Tab is pressed, with the text
+	// and the position of the cursor (in runes).  It returns the position
+	// where the word being completed starts, and the possible completions
+	// of it.  A single completion replaces the word.  Otherwise their
+	// common prefix is inserted, and pressing Tab again cycles through
+	// them.
+	Complete func(text string, pos int) (start int, completions []string)
+
+	// Highlight, if not nil, returns the style of each rune of the text,
+	// for syntax highlighting.  Runes past the end of the slice returned
+	// are drawn in Style.
+	Highlight func(text []rune) []tcell.Style
+
+	// OnSubmit and OnCancel, if not nil, are called by HandleEvent when
+	// a line is entered, or the prompt is cancelled (or ended).  Either
+	// way the text is cleared, ready for the next line.
+	OnSubmit func(line string)
+	OnCancel func()
+
+	s       tcell.Screen
+	x, y, w int
+	text    []rune
+	pos     int
+	offset  int // first rune shown
+
+	history []string
+	hpos    int    // position in the history; len(history) is the line
+	saved   []rune // the line, while moving through the history
+
+	kills   [][]rune
+	killing bool // the last key killed text, so more is added to it
+	yank    int  // the kill yanked, or -1
+	ystart  int  // where the yanked text starts
+
+	comps  []string // completions being cycled through
+	cidx   int
+	cstart int
+
+	done bool
+	line string
+	err  error
+}
+
+// New creates a Prompt that is drawn on the screen at x, y, and is width
+// cells wide.
+func New(s tcell.Screen, x, y, width int) *Prompt {
+	return &Prompt{s: s, x: x, y: y, w: width, yank: -1}
+}
+
+// SetPosition moves the prompt.
+func (p *Prompt) SetPosition(x, y, width int) {
+	p.x, p.y, p.w = x, y, width
+}
+
+// Text returns the text entered so far.
+func (p *Prompt) Text() string {
+	return string(p.text)
+}
+
+// SetText replaces the text, and moves the cursor to its end.
+func (p *Prompt) SetText(text string) {
+	p.text = []rune(text)
+	p.pos = len(p.text)
+	p.killing, p.yank, p.comps = false, -1, nil
+}
+
+// Cursor returns the position of the cursor, in runes.
+func (p *Prompt) Cursor() int {
+	return p.pos
+}
+
+// History returns the lines in the history, oldest first.
+func (p *Prompt) History() []string {
+	return append([]string{}, p.history...)
+}
+
+// SetHistory replaces the history, such as with lines saved by an earlier
+// session.  The lines are oldest first.
+func (p *Prompt) SetHistory(lines []string) {
+	p.history = append([]string{}, lines...)
+	p.trimHistory()
+	p.hpos = len(p.history)
+}
+
+// AddHistory adds a line to the history, unless it is empty or the same
+// as the last one.  Read and HandleEvent do this for each line entered.
+func (p *Prompt) AddHistory(line string) {
+	if line != "" && (len(p.history) == 0 || p.history[len(p.history)-1] != line) {
+		p.history = append(p.history, line)
+		p.trimHistory()
+	}
+	p.hpos = len(p.history)
+}
+
+func (p *Prompt) trimHistory() {
+	if p.MaxHistory > 0 && len(p.history) > p.MaxHistory {
+		p.history = p.history[len(p.history)-p.MaxHistory:]
+	}
+}
+
+// Read draws the prompt, and handles the events of the screen until a
+// line is entered, which it returns.  ErrCancelled is returned if the
+// prompt is cancelled, and io.EOF if Ctrl-D is pressed on an empty line.
+// Events other than keys and resizes are discarded.
+func (p *Prompt) Read() (string, error) {
+	p.done, p.err = false, nil
+	for !p.done {
+		p.Draw()
+		p.s.Show()
+		ev := p.s.PollEvent()
+		if ev == nil {
+			return "", io.EOF
+		}
+		if _, ok := ev.(*tcell.EventResize); ok {
+			p.s.Sync()
+			continue
+		}
+		p.HandleEvent(ev)
+	}
+	p.Draw()
+	p.s.Show()
+	return p.line, p.err
+}
+
+// Draw draws the prompt and the text, and places the cursor.
+func (p *Prompt) Draw() {
+	col := p.x
+	for _, r := range p.Prompt {
+		if col >= p.x+p.w {
+			break
+		}
+		p.s.SetContent(col, p.y, r, nil, p.PromptStyle)
+		col += runewidth.RuneWidth(r)
+	}
+	width := p.x + p.w - col
+	if width <= 0 {
+		p.s.HideCursor()
+		return
+	}
+
+	// Scroll so that the cursor is always visible.
+	if p.offset > p.pos {
+		p.offset = p.pos
+	}
+	for p.offset < p.pos && runewidth.StringWidth(string(p.text[p.offset:p.pos])) >= width {
+		p.offset++
+	}
+
+	var styles []tcell.Style
+	if p.Highlight != nil {
+		styles = p.Highlight(p.text)
+	}
+	start := col
+	cursor := -1
+	for i := p.offset; i <= len(p.text); i++ {
+		if i == p.pos {
+			cursor = col
+		}
+		if i == len(p.text) {
+			break
+		}
+		r := p.text[i]
+		rw := runewidth.RuneWidth(r)
+		if col+rw > start+width {
+			break
+		}
+		style := p.Style
+		if i < len(styles) {
+			style = styles[i]
+		}
+		p.s.SetContent(col, p.y, r, nil, style)
+		col += rw
+	}
+	for ; col < start+width; col++ {
+		p.s.SetContent(col, p.y, ' ', nil, p.Style)
+	}
+	if cursor >= 0 && cursor < start+width {
+		p.s.ShowCursor(cursor, p.y)
+	} else {
+		p.s.HideCursor()
+	}
+}
+
+// HandleEvent handles a key event, returning true if it was used.  Other
+// events are not used.
+func (p *Prompt) HandleEvent(ev tcell.Event) bool {
+	kev, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return false
+	}
+	killing, yank, comps := p.killing, p.yank, p.comps
+	p.killing, p.yank, p.comps = false, -1, nil
+	alt := kev.Modifiers()&tcell.ModAlt != 0
+
+	switch kev.Key() {
+	case tcell.KeyRune:
+		if !alt {
+			p.insert([]rune{kev.Rune()})
+			break
+		}
+		switch kev.Rune() {
+		case 'b', 'B':
+			p.pos = p.wordStart()
+		case 'f', 'F':
+			p.pos = p.wordEnd()
+		case 'd', 'D':
+			p.kill(p.pos, p.wordEnd(), killing, false)
+		case 'y', 'Y':
+			p.yankPop(yank)
+		default:
+			return false
+		}
+	case tcell.KeyLeft, tcell.KeyCtrlB:
+		if p.pos > 0 {
+			p.pos--
+		}
+	case tcell.KeyRight, tcell.KeyCtrlF:
+		if p.pos < len(p.text) {
+			p.pos++
+		}
+	case tcell.KeyHome, tcell.KeyCtrlA:
+		p.pos = 0
+	case tcell.KeyEnd, tcell.KeyCtrlE:
+		p.pos = len(p.text)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if alt {
+			p.kill(p.wordStart(), p.pos, killing, true)
+		} else if p.pos > 0 {
+			p.pos--
+			p.text = append(p.text[:p.pos], p.text[p.pos+1:]...)
+		}
+	case tcell.KeyDelete:
+		if p.pos < len(p.text) {
+			p.text = append(p.text[:p.pos], p.text[p.pos+1:]...)
+		}
+	case tcell.KeyCtrlD:
+		if len(p.text) == 0 {
+			p.finish(io.EOF)
+		} else if p.pos < len(p.text) {
+			p.text = append(p.text[:p.pos], p.text[p.pos+1:]...)
+		}
+	case tcell.KeyCtrlW:
+		p.kill(p.wordStart(), p.pos, killing, true)
+	case tcell.KeyCtrlU:
+		p.kill(0, p.pos, killing, true)
+	case tcell.KeyCtrlK:
+		p.kill(p.pos, len(p.text), killing, false)
+	case tcell.KeyCtrlY:
+		if len(p.kills) > 0 {
+			p.ystart = p.pos
+			p.insert(p.kills[len(p.kills)-1])
+			p.yank = len(p.kills) - 1
+		}
+	case tcell.KeyCtrlT:
+		if p.pos > 0 && len(p.text) > 1 {
+			if p.pos == len(p.text) {
+				p.pos--
+			}
+			p.text[p.pos-1], p.text[p.pos] = p.text[p.pos], p.text[p.pos-1]
+			p.pos++
+		}
+	case tcell.KeyUp, tcell.KeyCtrlP:
+		p.moveHistory(-1)
+	case tcell.KeyDown, tcell.KeyCtrlN:
+		p.moveHistory(1)
+	case tcell.KeyTab:
+		p.complete(comps)
+	case tcell.KeyEnter:
+		p.AddHistory(string(p.text))
+		p.finish(nil)
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		p.finish(ErrCancelled)
+	default:
+		return false
+	}
+	return true
+}
+
+// finish ends the editing of the line, with err (if not nil) as the
+// reason it was abandoned.
+func (p *Prompt) finish(err error) {
+	p.done, p.err = true, err
+	p.line = string(p.text)
+	p.text, p.pos, p.offset = nil, 0, 0
+	p.saved, p.hpos = nil, len(p.history)
+	if err == nil && p.OnSubmit != nil {
+		p.OnSubmit(p.line)
+	} else if err != nil && p.OnCancel != nil {
+		p.OnCancel()
+	}
+}
+
+func (p *Prompt) insert(runes []rune) {
+	text := append([]rune{}, p.text[:p.pos]...)
+	text = append(text, runes...)
+	p.text = append(text, p.text[p.pos:]...)
+	p.pos += len(runes)
+}
+
+// wordStart returns the start of the word before the cursor.
+func (p *Prompt) wordStart() int {
+	i := p.pos
+	for i > 0 && !isWord(p.text[i-1]) {
+		i--
+	}
+	for i > 0 && isWord(p.text[i-1]) {
+		i--
+	}
+	return i
+}
+
+// wordEnd returns the end of the word after the cursor.
+func (p *Prompt) wordEnd() int {
+	i := p.pos
+	for i < len(p.text) && !isWord(p.text[i]) {
+		i++
+	}
+	for i < len(p.text) && isWord(p.text[i]) {
+		i++
+	}
+	return i
+}
+
+func isWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// kill removes the text from start to end, and puts it in the kill ring.
+// If the last key also killed text, it is added to that kill instead,
+// before it if back is true.
+func (p *Prompt) kill(start, end int, more, back bool) {
+	if start >= end {
+		p.killing = more
+		return
+	}
+	killed := append([]rune{}, p.text[start:end]...)
+	p.text = append(p.text[:start], p.text[end:]...)
+	p.pos = start
+	p.killing = true
+	if more && len(p.kills) > 0 {
+		last := p.kills[len(p.kills)-1]
+		if back {
+			p.kills[len(p.kills)-1] = append(killed, last...)
+		} else {
+			p.kills[len(p.kills)-1] = append(last, killed...)
+		}
+		return
+	}
+	p.kills = append(p.kills, killed)
+	if len(p.kills) > killRingSize {
+		p.kills = p.kills[1:]
+	}
+}
+
+// yankPop replaces the text just yanked with the kill before it.
+func (p *Prompt) yankPop(yank int) {
+	if yank < 0 {
+		return
+	}
+	n := len(p.kills[yank])
+	p.text = append(p.text[:p.ystart], p.text[p.ystart+n:]...)
+	p.pos = p.ystart
+	yank = (yank + len(p.kills) - 1) % len(p.kills)
+	p.insert(p.kills[yank])
+	p.yank = yank
+}
+
+// moveHistory moves through the history by n lines.
+func (p *Prompt) moveHistory(n int) {
+	hpos := p.hpos + n
+	if hpos < 0 || hpos > len(p.history) {
+		return
+	}
+	if p.hpos == len(p.history) {
+		p.saved = append([]rune{}, p.text...)
+	}
+	p.hpos = hpos
+	if hpos == len(p.history) {
+		p.text = p.saved
+	} else {
+		p.text = []rune(p.history[hpos])
+	}
+	p.pos = len(p.text)
+}
+
+// complete completes the word before the cursor, or if Tab was pressed
+// just before, replaces it with the next of the completions.
+func (p *Prompt) complete(comps []string) {
+	if len(comps) > 0 {
+		n := len([]rune(comps[p.cidx]))
+		p.text = append(p.text[:p.cstart], p.text[p.cstart+n:]...)
+		p.pos = p.cstart
+		p.cidx = (p.cidx + 1) % len(comps)
+		p.insert([]rune(comps[p.cidx]))
+		p.comps = comps
+		return
+	}
+	if p.Complete == nil {
+		return
+	}
+	start, comps := p.Complete(string(p.text), p.pos)
+	if len(comps) == 0 || start < 0 || start > p.pos {
+		return
+	}
+	word := p.pos - start
+	prefix := []rune(comps[0])
+	for _, c := range comps[1:] {
+		prefix = commonPrefix(prefix, []rune(c))
+	}
+	if len(comps) == 1 || len(prefix) > word {
+		p.text = append(p.text[:start], p.text[p.pos:]...)
+		p.pos = start
+		p.insert(prefix)
+		return
+	}
+	p.text = append(p.text[:start], p.text[p.pos:]...)
+	p.pos = start
+	p.insert([]rune(comps[0]))
+	p.comps, p.cidx, p.cstart = comps, 0, start
+}
+
+func commonPrefix(a, b []rune) []rune {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n]
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompt
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestPrompt(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatalf("cannot init: %v", err)
+	}
+	defer s.Fini()
+	s.SetSize(20, 2)
+
+	p := New(s, 0, 0, 12)
+	p.Prompt = "> "
+	p.Complete = func(text string, pos int) (int, []string) {
+		start := strings.LastIndex(text[:pos], " ") + 1
+		var comps []string
+		for _, c := range []string{"help", "hello", "quit"} {
+			if strings.HasPrefix(c, text[start:pos]) {
+				comps = append(comps, c)
+			}
+		}
+		return start, comps
+	}
+	typeText := func(text string) {
+		for _, r := range text {
+			p.HandleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+		}
+	}
+	key := func(k tcell.Key, mod tcell.ModMask) {
+		p.HandleEvent(tcell.NewEventKey(k, 0, mod))
+	}
+	alt := func(r rune) {
+		p.HandleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModAlt))
+	}
+
+	typeText("say hello world")
+	key(tcell.KeyCtrlW, tcell.ModNone)
+	key(tcell.KeyCtrlW, tcell.ModNone)
+	if got := p.Text(); got != "say " {
+		t.Errorf("wrong text after kills: %q", got)
+	}
+	key(tcell.KeyCtrlA, tcell.ModNone)
+	key(tcell.KeyCtrlY, tcell.ModNone)
+	if got := p.Text(); got != "hello worldsay " {
+		t.Errorf("wrong text after yank: %q", got)
+	}
+	key(tcell.KeyCtrlK, tcell.ModNone)
+	key(tcell.KeyCtrlY, tcell.ModNone)
+	alt('y')
+	if got := p.Text(); got != "hello worldhello world" {
+		t.Errorf("wrong text after yank pop: %q", got)
+	}
+
+	p.SetText("he")
+	key(tcell.KeyTab, tcell.ModNone)
+	if got := p.Text(); got != "hel" {
+		t.Errorf("wrong completion of prefix: %q", got)
+	}
+	key(tcell.KeyTab, tcell.ModNone)
+	key(tcell.KeyTab, tcell.ModNone)
+	if got := p.Text(); got != "hello" {
+		t.Errorf("wrong cycled completion: %q", got)
+	}
+	p.SetText("q")
+	key(tcell.KeyTab, tcell.ModNone)
+	if got := p.Text(); got != "quit" {
+		t.Errorf("wrong completion: %q", got)
+	}
+
+	var submitted string
+	p.OnSubmit = func(line string) { submitted = line }
+	key(tcell.KeyEnter, tcell.ModNone)
+	if submitted != "quit" || p.Text() != "" {
+		t.Errorf("wrong submission: %q", submitted)
+	}
+	typeText("abc")
+	key(tcell.KeyUp, tcell.ModNone)
+	if got := p.Text(); got != "quit" {
+		t.Errorf("wrong history: %q", got)
+	}
+	key(tcell.KeyDown, tcell.ModNone)
+	alt('b')
+	if got, pos := p.Text(), p.Cursor(); got != "abc" || pos != 0 {
+		t.Errorf("wrong line after history: %q at %d", got, pos)
+	}
+
+	p.SetText("a long line of text")
+	p.Draw()
+	s.Show()
+	cells, w, _ := s.GetContents()
+	var row []rune
+	for _, c := range cells[:w] {
+		row = append(row, c.Runes[0])
+	}
+	if got := string(row); got != "> e of text         " {
+		t.Errorf("wrong drawing: %q", got)
+	}
+	if x, y, visible := s.GetCursor(); x != 11 || y != 0 || !visible {
+		t.Errorf("wrong cursor: %d,%d %v", x, y, visible)
+	}
+
+	p.SetText("")
+	s.InjectKey(tcell.KeyRune, 'x', tcell.ModNone)
+	s.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	if line, err := p.Read(); line != "x" || err != nil {
+		t.Errorf("wrong line read: %q %v", line, err)
+	}
+	s.InjectKey(tcell.KeyCtrlD, 0, tcell.ModCtrl)
+	if _, err := p.Read(); err != io.EOF {
+		t.Errorf("wrong error at end: %v", err)
+	}
+}
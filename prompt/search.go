@@ -0,0 +1,267 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompt
+
+import (
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// Searcher is content that can be searched with a Search, such as a
+// document shown in a scrolling view.  Its matches are numbered from
+// zero, in order.
+type Searcher interface {
+	// Find finds the matches of pattern, and returns how many there are,
+	// and which to go to first, such as the first after the top of what
+	// is shown.  An error, such as for an invalid regular expression,
+	// leaves the search with no matches.
+	Find(pattern string, opts tcell.FindOptions) (count, first int, err error)
+
+	// Reveal scrolls the content, if need be, so that match i is shown,
+	// and draws it.  It returns the cells of the matches that are now
+	// visible on the screen, and of match i itself, to be highlighted.
+	// It is called with -1 (and the results ignored) when there are no
+	// matches, or the search is cancelled, to go back to what was shown
+	// before the search.
+	Reveal(i int) (visible []tcell.Selection, current tcell.Selection)
+}
+
+// Search is an incremental search of a Searcher: as the pattern is typed
+// in its prompt, the content is moved to the first match, and the
+// matches are highlighted.  Create one with NewSearch, and either call
+// Run, or pass events to HandleEvent from the application's own event
+// loop, and call Draw.
+//
+// Ctrl-S or Ctrl-N go to the next match, and Ctrl-R or Ctrl-P to the
+// previous one, wrapping around at the ends.  Enter finishes the search,
+// leaving the content where it is, and the matches highlighted, so that
+// Next and Prev can still be used; Escape cancels it, going back to where
+// it started.  Up and Down recall earlier patterns, and other keys edit
+// the pattern, as with Prompt.
+//
+// The matches are highlighted with the screen's SetHighlights, and the
+// current one with SetSelection, replacing any selection.
+type Search struct {
+	// Input is the prompt where the pattern is typed.
+	Input *Prompt
+
+	// Options controls how the pattern matches.
+	Options tcell.FindOptions
+
+	// Style is laid over the matches, and CurrentStyle over the current
+	// match, as for SetHighlights and SetSelection.
+	Style        tcell.Style
+	CurrentStyle tcell.Style
+
+	// OnDone and OnCancel, if not nil, are called by HandleEvent when
+	// the search is finished (with the pattern), or cancelled.
+	OnDone   func(pattern string)
+	OnCancel func()
+
+	s       tcell.Screen
+	src     Searcher
+	x, y    int
+	w       int
+	pattern string
+	count   int
+	cur     int
+	err     error
+	done    bool
+	ok      bool
+}
+
+// NewSearch creates a Search of src, with its prompt drawn on the screen
+// at x, y with the given width.
+func NewSearch(s tcell.Screen, src Searcher, x, y, width int) *Search {
+	sr := &Search{
+		Input:        New(s, x, y, width),
+		Style:        tcell.StyleDefault.Reverse(true),
+		CurrentStyle: tcell.StyleDefault.Background(tcell.ColorYellow).Foreground(tcell.ColorBlack),
+		s:            s,
+		src:          src,
+		x:            x,
+		y:            y,
+		w:            width,
+		cur:          -1,
+	}
+	sr.Input.Prompt = "/"
+	return sr
+}
+
+// SetPosition moves the prompt of the search.
+func (sr *Search) SetPosition(x, y, width int) {
+	sr.x, sr.y, sr.w = x, y, width
+}
+
+// Pattern returns the pattern searched for.
+func (sr *Search) Pattern() string {
+	return sr.pattern
+}
+
+// Current returns the index of the current match, or -1 if there is
+// none, and the number of matches.
+func (sr *Search) Current() (int, int) {
+	return sr.cur, sr.count
+}
+
+// Err returns the error from the Searcher for the pattern, if any.
+func (sr *Search) Err() error {
+	return sr.err
+}
+
+// Run starts a new search, and handles the events of the screen until it
+// is finished, returning the pattern and true, or cancelled, returning
+// false.  Events other than keys and resizes are discarded.
+func (sr *Search) Run() (string, bool) {
+	sr.Input.SetText("")
+	sr.update()
+	sr.done, sr.ok = false, false
+	for !sr.done {
+		sr.Draw()
+		sr.s.Show()
+		ev := sr.s.PollEvent()
+		if ev == nil {
+			sr.cancel()
+			break
+		}
+		if _, ok := ev.(*tcell.EventResize); ok {
+			sr.show()
+			sr.s.Sync()
+			continue
+		}
+		sr.HandleEvent(ev)
+	}
+	return sr.pattern, sr.ok
+}
+
+// HandleEvent handles a key event, returning true if it was used.
+func (sr *Search) HandleEvent(ev tcell.Event) bool {
+	kev, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return false
+	}
+	switch kev.Key() {
+	case tcell.KeyCtrlS, tcell.KeyCtrlN:
+		sr.Next()
+	case tcell.KeyCtrlR, tcell.KeyCtrlP:
+		sr.Prev()
+	case tcell.KeyEnter:
+		sr.Input.AddHistory(sr.pattern)
+		sr.done, sr.ok = true, true
+		if sr.OnDone != nil {
+			sr.OnDone(sr.pattern)
+		}
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		sr.cancel()
+	default:
+		if !sr.Input.HandleEvent(ev) {
+			return false
+		}
+		if sr.Input.Text() != sr.pattern {
+			sr.update()
+		}
+	}
+	return true
+}
+
+// Next moves to the next match.
+func (sr *Search) Next() {
+	if sr.count > 0 {
+		sr.cur = (sr.cur + 1) % sr.count
+		sr.show()
+	}
+}
+
+// Prev moves to the previous match.
+func (sr *Search) Prev() {
+	if sr.count > 0 {
+		sr.cur = (sr.cur + sr.count - 1) % sr.count
+		sr.show()
+	}
+}
+
+// Clear removes the highlighting of the matches.
+func (sr *Search) Clear() {
+	sr.s.SetHighlights(sr.Style, nil)
+	sr.s.SetSelection(sr.CurrentStyle, nil)
+}
+
+func (sr *Search) cancel() {
+	sr.pattern, sr.count, sr.cur, sr.err = "", 0, -1, nil
+	sr.show()
+	sr.done, sr.ok = true, false
+	if sr.OnCancel != nil {
+		sr.OnCancel()
+	}
+}
+
+// update searches for the pattern typed, and goes to the first match.
+func (sr *Search) update() {
+	sr.pattern = sr.Input.Text()
+	sr.count, sr.cur, sr.err = 0, -1, nil
+	if sr.pattern != "" {
+		sr.count, sr.cur, sr.err = sr.src.Find(sr.pattern, sr.Options)
+	}
+	if sr.err != nil || sr.count <= 0 {
+		sr.count, sr.cur = 0, -1
+	} else if sr.cur < 0 || sr.cur >= sr.count {
+		sr.cur = 0
+	}
+	sr.show()
+}
+
+// show reveals the current match, and highlights the matches.
+func (sr *Search) show() {
+	if sr.cur < 0 {
+		sr.src.Reveal(-1)
+		sr.Clear()
+		return
+	}
+	visible, current := sr.src.Reveal(sr.cur)
+	others := make([]tcell.Selection, 0, len(visible))
+	for _, sel := range visible {
+		if sel != current {
+			others = append(others, sel)
+		}
+	}
+	sr.s.SetHighlights(sr.Style, others)
+	sr.s.SetSelection(sr.CurrentStyle, []tcell.Selection{current})
+}
+
+// Draw draws the prompt, with the number of the current match, and the
+// number of matches, at the right; or a question mark if the pattern is
+// not valid.
+func (sr *Search) Draw() {
+	status := ""
+	switch {
+	case sr.err != nil:
+		status = "?"
+	case sr.pattern != "":
+		status = strconv.Itoa(sr.cur+1) + "/" + strconv.Itoa(sr.count)
+	}
+	if sw := runewidth.StringWidth(status) + 1; status != "" && sw < sr.w {
+		sr.Input.SetPosition(sr.x, sr.y, sr.w-sw)
+		col := sr.x + sr.w - sw
+		for _, r := range " " + status {
+			sr.s.SetContent(col, sr.y, r, nil, sr.Input.Style)
+			col += runewidth.RuneWidth(r)
+		}
+	} else {
+		sr.Input.SetPosition(sr.x, sr.y, sr.w)
+	}
+	sr.Input.Draw()
+}
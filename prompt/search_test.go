@@ -0,0 +1,124 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// testPager shows some lines in the top rows of the screen, scrolled so
+// that the match revealed is on the first of them.
+type testPager struct {
+	s     tcell.Screen
+	lines []string
+	rows  int
+	top   int
+	found []tcell.Selection // with Y as the line
+}
+
+func (tp *testPager) Find(pattern string, opts tcell.FindOptions) (int, int, error) {
+	tp.found = nil
+	for y, line := range tp.lines {
+		if x := strings.Index(line, pattern); x >= 0 {
+			tp.found = append(tp.found, tcell.Selection{StartX: x, StartY: y, EndX: x + len(pattern) - 1, EndY: y})
+		}
+	}
+	return len(tp.found), 0, nil
+}
+
+func (tp *testPager) Reveal(i int) ([]tcell.Selection, tcell.Selection) {
+	tp.top = 0
+	if i >= 0 {
+		tp.top = tp.found[i].StartY
+	}
+	for row := 0; row < tp.rows; row++ {
+		line := ""
+		if tp.top+row < len(tp.lines) {
+			line = tp.lines[tp.top+row]
+		}
+		for x := 0; x < 10; x++ {
+			r := ' '
+			if x < len(line) {
+				r = rune(line[x])
+			}
+			tp.s.SetContent(x, row, r, nil, tcell.StyleDefault)
+		}
+	}
+	var visible []tcell.Selection
+	var current tcell.Selection
+	for j, sel := range tp.found {
+		sel.StartY -= tp.top
+		sel.EndY -= tp.top
+		if sel.StartY >= 0 && sel.StartY < tp.rows {
+			visible = append(visible, sel)
+		}
+		if j == i {
+			current = sel
+		}
+	}
+	return visible, current
+}
+
+func TestSearch(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatalf("cannot init: %v", err)
+	}
+	defer s.Fini()
+	s.SetSize(10, 3)
+
+	tp := &testPager{s: s, lines: []string{"one", "two", "three", "four", "twenty"}, rows: 2}
+	sr := NewSearch(s, tp, 0, 2, 10)
+	s.InjectKey(tcell.KeyRune, 't', tcell.ModNone)
+	s.InjectKey(tcell.KeyRune, 'w', tcell.ModNone)
+	s.InjectKey(tcell.KeyCtrlS, 0, tcell.ModNone)
+	s.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	if pattern, ok := sr.Run(); pattern != "tw" || !ok {
+		t.Errorf("wrong search result: %q %v", pattern, ok)
+	}
+	if cur, count := sr.Current(); cur != 1 || count != 2 || tp.top != 4 {
+		t.Errorf("wrong match: %d of %d, at %d", cur, count, tp.top)
+	}
+
+	sr.Next()
+	sr.Draw()
+	s.Show()
+	cells, w, _ := s.GetContents()
+	var text []rune
+	for _, c := range cells[:w*3] {
+		text = append(text, c.Runes[0])
+	}
+	if got := string(text); got != "two       three     /tw    1/2" {
+		t.Errorf("wrong drawing: %q", got)
+	}
+	if c := cells[0]; c.Style != sr.CurrentStyle {
+		t.Errorf("wrong style of current match: %v", c.Style)
+	}
+	if c := cells[2]; c.Style != tcell.StyleDefault {
+		t.Errorf("wrong style after match: %v", c.Style)
+	}
+
+	s.InjectKey(tcell.KeyRune, 'x', tcell.ModNone)
+	s.InjectKey(tcell.KeyEscape, 0, tcell.ModNone)
+	if _, ok := sr.Run(); ok || tp.top != 0 {
+		t.Errorf("search not cancelled: %v at %d", ok, tp.top)
+	}
+	if cur, count := sr.Current(); cur != -1 || count != 0 {
+		t.Errorf("wrong match after cancel: %d of %d", cur, count)
+	}
+}
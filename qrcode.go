@@ -0,0 +1,567 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"errors"
+)
+
+// QRLevel is the level of error correction of a QR code.  Higher levels
+// can be read when more of the code is damaged (or obscured), but make
+// larger codes.
+type QRLevel int
+
+const (
+	QRLevelL QRLevel = iota // recovers about 7% of the code
+	QRLevelM                // recovers about 15%
+	QRLevelQ                // recovers about 25%
+	QRLevelH                // recovers about 30%
+)
+
+// QRMode is how a QR code is drawn.
+type QRMode int
+
+const (
+	// QRHalfBlocks draws each cell as two modules, one above the other,
+	// with half blocks.  As cells are about twice as high as they are
+	// wide, the modules are square.  This is the most reliable to scan.
+	QRHalfBlocks QRMode = iota
+
+	// QRBraille draws each cell as eight modules, two wide and four high,
+	// with braille patterns.  Codes are a quarter of the size, but the
+	// gaps between the dots of some fonts make them harder to scan.
+	QRBraille
+)
+
+// ErrQRTooLarge is returned when a QR code does not fit in the region,
+// or the data does not fit in any QR code.
+var ErrQRTooLarge = errors.New("QR code too large")
+
+// DrawQR draws a QR code for data in the region of dst with its top left
+// corner at x, y, and the given width and height, centered.  The smallest
+// code that holds the data at the given level is used.  The code is drawn
+// dark on light, whatever the colors of the terminal, with a quiet zone
+// (a light margin) of four modules, or as many as fit but at least one,
+// as scanners need it.  ErrQRTooLarge is returned, and nothing is drawn,
+// if the code does not fit.
+func DrawQR(dst CellSetter, x, y, width, height int, data []byte, level QRLevel, mode QRMode) error {
+	qr, err := newQRCode(data, level)
+	if err != nil {
+		return err
+	}
+	cw, ch := 1, 2
+	if mode == QRBraille {
+		cw, ch = 2, 4
+	}
+	quiet := 4
+	for ; quiet > 0; quiet-- {
+		n := qr.size + 2*quiet
+		if (n+cw-1)/cw <= width && (n+ch-1)/ch <= height {
+			break
+		}
+	}
+	if quiet == 0 {
+		return ErrQRTooLarge
+	}
+	n := qr.size + 2*quiet
+	w, h := (n+cw-1)/cw, (n+ch-1)/ch
+	x += (width - w) / 2
+	y += (height - h) / 2
+
+	dark := func(mx, my int) bool {
+		mx -= quiet
+		my -= quiet
+		return mx >= 0 && my >= 0 && mx < qr.size && my < qr.size && qr.modules[my][mx]
+	}
+	colors := map[bool]Color{false: ColorWhite, true: ColorBlack}
+	for row := 0; row < h; row++ {
+		for col := 0; col < w; col++ {
+			if mode == QRBraille {
+				r := rune(0x2800)
+				for i, bit := range brailleDots {
+					if dark(col*2+i%2, row*4+i/2) {
+						r |= bit
+					}
+				}
+				dst.SetContent(x+col, y+row, r, nil, StyleDefault.Foreground(ColorBlack).Background(ColorWhite))
+				continue
+			}
+			st := StyleDefault.Foreground(colors[dark(col, row*2)]).Background(colors[dark(col, row*2+1)])
+			dst.SetContent(x+col, y+row, '▀', nil, st)
+		}
+	}
+	return nil
+}
+
+// brailleDots are the bits of the braille dots, from left to right and
+// then top to bottom.
+var brailleDots = []rune{0x01, 0x08, 0x02, 0x10, 0x04, 0x20, 0x40, 0x80}
+
+// What follows encodes QR codes (ISO/IEC 18004), in byte mode only, which
+// suits any data.  It follows the structure of Project Nayuki's QR Code
+// generator, which is a good guide to the standard.
+
+type qrCode struct {
+	version  int
+	size     int
+	level    QRLevel
+	modules  [][]bool // [y][x], true is dark
+	function [][]bool // modules that are not data
+}
+
+// These tables are indexed by level and then version (from 1).
+var qrECCPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var qrBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// qrFormatLevel is the value of each level in the format information.
+var qrFormatLevel = [4]int{1, 0, 3, 2}
+
+// qrRawModules returns the number of modules for data and error
+// correction (including any remainder bits) in a version.
+func qrRawModules(ver int) int {
+	n := (16*ver+128)*ver + 64
+	if ver >= 2 {
+		align := ver/7 + 2
+		n -= (25*align-10)*align - 55
+		if ver >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+// qrDataCodewords returns the number of data codewords in a version.
+func qrDataCodewords(ver int, level QRLevel) int {
+	return qrRawModules(ver)/8 - qrECCPerBlock[level][ver]*qrBlocks[level][ver]
+}
+
+func newQRCode(data []byte, level QRLevel) (*qrCode, error) {
+	if level < QRLevelL || level > QRLevelH {
+		level = QRLevelM
+	}
+	ver := 1
+	for ; ver <= 40; ver++ {
+		count := 8
+		if ver > 9 {
+			count = 16
+		}
+		if 4+count+8*len(data) <= 8*qrDataCodewords(ver, level) {
+			break
+		}
+	}
+	if ver > 40 {
+		return nil, ErrQRTooLarge
+	}
+
+	// The data: the mode (byte), the count, the bytes, a terminator, and
+	// then padding to fill the capacity.
+	var bits qrBits
+	bits.add(4, 4)
+	if ver > 9 {
+		bits.add(len(data), 16)
+	} else {
+		bits.add(len(data), 8)
+	}
+	for _, b := range data {
+		bits.add(int(b), 8)
+	}
+	capacity := 8 * qrDataCodewords(ver, level)
+	for i := 0; i < 4 && len(bits) < capacity; i++ {
+		bits.add(0, 1)
+	}
+	for len(bits)%8 != 0 {
+		bits.add(0, 1)
+	}
+	for pad := 0xec; len(bits) < capacity; pad ^= 0xec ^ 0x11 {
+		bits.add(pad, 8)
+	}
+	codewords := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			codewords[i/8] |= 0x80 >> uint(i%8)
+		}
+	}
+
+	qr := &qrCode{version: ver, size: 17 + 4*ver, level: level}
+	qr.modules = make([][]bool, qr.size)
+	qr.function = make([][]bool, qr.size)
+	for i := range qr.modules {
+		qr.modules[i] = make([]bool, qr.size)
+		qr.function[i] = make([]bool, qr.size)
+	}
+	qr.drawFunctionPatterns()
+	qr.drawCodewords(qr.addECC(codewords))
+
+	// Use the mask that makes the code easiest to read.
+	best, penalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		qr.applyMask(mask)
+		qr.drawFormat(mask)
+		if p := qr.penalty(); penalty < 0 || p < penalty {
+			best, penalty = mask, p
+		}
+		qr.applyMask(mask)
+	}
+	qr.applyMask(best)
+	qr.drawFormat(best)
+	return qr, nil
+}
+
+// qrBits is a sequence of bits.
+type qrBits []bool
+
+func (b *qrBits) add(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, (v>>uint(i))&1 != 0)
+	}
+}
+
+func (qr *qrCode) setFunction(x, y int, dark bool) {
+	qr.modules[y][x] = dark
+	qr.function[y][x] = true
+}
+
+func (qr *qrCode) drawFunctionPatterns() {
+	size := qr.size
+	for i := 0; i < size; i++ {
+		qr.setFunction(6, i, i%2 == 0)
+		qr.setFunction(i, 6, i%2 == 0)
+	}
+	qr.drawFinder(3, 3)
+	qr.drawFinder(size-4, 3)
+	qr.drawFinder(3, size-4)
+
+	align := qr.alignmentPositions()
+	n := len(align)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			// Not where the finders are.
+			if (i == 0 && j == 0) || (i == 0 && j == n-1) || (i == n-1 && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					qr.setFunction(align[i]+dx, align[j]+dy, qrMax(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas; they are drawn once the mask is chosen.
+	qr.drawFormat(0)
+
+	if qr.version >= 7 {
+		rem := qr.version
+		for i := 0; i < 12; i++ {
+			rem = (rem << 1) ^ ((rem >> 11) * 0x1f25)
+		}
+		bits := qr.version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := (bits>>uint(i))&1 != 0
+			a, b := size-11+i%3, i/3
+			qr.setFunction(a, b, dark)
+			qr.setFunction(b, a, dark)
+		}
+	}
+}
+
+func (qr *qrCode) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx >= 0 && xx < qr.size && yy >= 0 && yy < qr.size {
+				dist := qrMax(abs(dx), abs(dy))
+				qr.setFunction(xx, yy, dist != 2 && dist != 4)
+			}
+		}
+	}
+}
+
+func (qr *qrCode) alignmentPositions() []int {
+	if qr.version == 1 {
+		return nil
+	}
+	n := qr.version/7 + 2
+	step := (qr.version*8 + n*3 + 5) / (n*4 - 4) * 2
+	pos := make([]int, n)
+	pos[0] = 6
+	for i, p := n-1, qr.size-7; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+// qrFormatBits returns the format information for a level and mask.
+func qrFormatBits(level QRLevel, mask int) int {
+	data := qrFormatLevel[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (qr *qrCode) drawFormat(mask int) {
+	bits := qrFormatBits(qr.level, mask)
+	bit := func(i int) bool { return (bits>>uint(i))&1 != 0 }
+	size := qr.size
+	for i := 0; i <= 5; i++ {
+		qr.setFunction(8, i, bit(i))
+	}
+	qr.setFunction(8, 7, bit(6))
+	qr.setFunction(8, 8, bit(7))
+	qr.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		qr.setFunction(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		qr.setFunction(size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.setFunction(8, size-15+i, bit(i))
+	}
+	qr.setFunction(8, size-8, true)
+}
+
+// addECC splits the data into blocks, adds the error correction to each,
+// and interleaves them.
+func (qr *qrCode) addECC(data []byte) []byte {
+	ver, level := qr.version, qr.level
+	blocks := qrBlocks[level][ver]
+	eccLen := qrECCPerBlock[level][ver]
+	raw := qrRawModules(ver) / 8
+	short := blocks - raw%blocks
+	shortLen := raw / blocks
+	div := qrDivisor(eccLen)
+
+	var all [][]byte
+	k := 0
+	for i := 0; i < blocks; i++ {
+		n := shortLen - eccLen
+		if i >= short {
+			n++
+		}
+		dat := data[k : k+n]
+		k += n
+		block := append([]byte{}, dat...)
+		if i < short {
+			block = append(block, 0) // a place holder, to line up
+		}
+		all = append(all, append(block, qrRemainder(dat, div)...))
+	}
+
+	var result []byte
+	for i := range all[0] {
+		for j, block := range all {
+			if i != shortLen-eccLen || j >= short {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// qrMultiply multiplies in GF(2^8), modulo x^8 + x^4 + x^3 + x^2 + 1.
+func qrMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11d)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
+
+// qrDivisor returns the generator polynomial for Reed-Solomon codes of
+// a degree, with the coefficients from the highest power, omitting the
+// leading 1.
+func qrDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = qrMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = qrMultiply(root, 2)
+	}
+	return result
+}
+
+// qrRemainder returns the error correction codewords for data.
+func qrRemainder(data, div []byte) []byte {
+	result := make([]byte, len(div))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range div {
+			result[i] ^= qrMultiply(d, factor)
+		}
+	}
+	return result
+}
+
+// drawCodewords places the data in the zig zag order of the standard,
+// two columns at a time, from the right.
+func (qr *qrCode) drawCodewords(data []byte) {
+	i := 0
+	size := qr.size
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the timing column
+		}
+		for vert := 0; vert < size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = size - 1 - vert
+				}
+				if !qr.function[y][x] && i < len(data)*8 {
+					qr.modules[y][x] = (data[i/8]>>uint(7-i%8))&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask inverts the data modules selected by a mask.  Applying it
+// twice undoes it.
+func (qr *qrCode) applyMask(mask int) {
+	for y := 0; y < qr.size; y++ {
+		for x := 0; x < qr.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !qr.function[y][x] {
+				qr.modules[y][x] = !qr.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the code by the rules of the standard, where lower is
+// easier to read: long runs of one color, 2x2 blocks, patterns that look
+// like finders, and an imbalance of dark and light.
+func (qr *qrCode) penalty() int {
+	size := qr.size
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			return qr.modules[x][y]
+		}
+		return qr.modules[y][x]
+	}
+	finder := []bool{true, false, true, true, true, false, true}
+	score := 0
+	for _, tr := range []bool{false, true} {
+		for y := 0; y < size; y++ {
+			run := 0
+			for x := 0; x < size; x++ {
+				if x > 0 && at(x, y, tr) == at(x-1, y, tr) {
+					run++
+					if run == 5 {
+						score += 3
+					} else if run > 5 {
+						score++
+					}
+				} else {
+					run = 1
+				}
+				// A finder-like pattern, with four light modules (or
+				// the edge) on either side.
+				if x+7 <= size {
+					match := true
+					for i, d := range finder {
+						if at(x+i, y, tr) != d {
+							match = false
+							break
+						}
+					}
+					if match && (qr.lightRun(x-4, x, y, tr) || qr.lightRun(x+7, x+11, y, tr)) {
+						score += 40
+					}
+				}
+			}
+		}
+	}
+	dark := 0
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			c := qr.modules[y][x]
+			if c {
+				dark++
+			}
+			if x+1 < size && y+1 < size && c == qr.modules[y][x+1] &&
+				c == qr.modules[y+1][x] && c == qr.modules[y+1][x+1] {
+				score += 3
+			}
+		}
+	}
+	total := size * size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	return score + k*10
+}
+
+// lightRun reports whether the modules from x0 up to x1 of a row (or a
+// column) are all light; modules outside the code are light.
+func (qr *qrCode) lightRun(x0, x1, y int, transpose bool) bool {
+	for x := x0; x < x1; x++ {
+		if x < 0 || x >= qr.size {
+			continue
+		}
+		if (transpose && qr.modules[x][y]) || (!transpose && qr.modules[y][x]) {
+			return false
+		}
+	}
+	return true
+}
+
+func qrMax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestQRCode(t *testing.T) {
+	// The example of error correction from the standard's tutorials.
+	data := []byte{0x20, 0x5b, 0x0b, 0x78, 0xd1, 0x72, 0xdc, 0x4d, 0x43, 0x40, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11}
+	ecc := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := qrRemainder(data, qrDivisor(10)); !bytes.Equal(got, ecc) {
+		t.Errorf("wrong error correction: %v", got)
+	}
+	if f := qrFormatBits(QRLevelL, 0); f != 0x77c4 {
+		t.Errorf("wrong format bits: %015b", f)
+	}
+
+	// Read the data back from a code, undoing the mask given by the
+	// format information.
+	qr, err := newQRCode([]byte("tcell"), QRLevelM)
+	if err != nil || qr.version != 1 {
+		t.Fatalf("wrong code: %v", err)
+	}
+	format := 0
+	for i := 0; i < 15; i++ {
+		dark := qr.modules[8][qr.size-1-i]
+		if i >= 8 {
+			dark = qr.modules[qr.size-15+i][8]
+		}
+		if dark {
+			format |= 1 << uint(i)
+		}
+	}
+	mask := -1
+	for m := 0; m < 8; m++ {
+		if qrFormatBits(QRLevelM, m) == format {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("bad format information: %015b", format)
+	}
+	qr.applyMask(mask)
+	var bits qrBits
+	for right := qr.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < qr.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = qr.size - 1 - vert
+				}
+				if !qr.function[y][x] {
+					bits = append(bits, qr.modules[y][x])
+				}
+			}
+		}
+	}
+	var got []byte
+	for i := 12; i+8 <= 12+8*5; i += 8 {
+		var b byte
+		for _, bit := range bits[i : i+8] {
+			b <<= 1
+			if bit {
+				b |= 1
+			}
+		}
+		got = append(got, b)
+	}
+	if string(got) != "tcell" {
+		t.Errorf("wrong data read back: %q", got)
+	}
+
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(40, 20)
+	if err := DrawQR(s, 0, 0, 20, 10, []byte("https://example.com/"), QRLevelM, QRHalfBlocks); err != ErrQRTooLarge {
+		t.Errorf("code drawn too small: %v", err)
+	}
+	// A 25 module code, with a quiet zone of 4, is 33 cells by 17.
+	if err := DrawQR(s, 0, 0, 40, 20, []byte("https://example.com/"), QRLevelM, QRHalfBlocks); err != nil {
+		t.Fatalf("failed to draw: %v", err)
+	}
+	s.Show()
+	cells, w, _ := s.GetContents()
+	// Centered, the corner of the finder (module 4, 4) is at cell 7, 3.
+	c := cells[3*w+7]
+	if fg, bg, _ := c.Style.Decompose(); c.Runes[0] != '▀' || fg != ColorBlack || bg != ColorBlack {
+		t.Errorf("wrong finder cell: %v", c)
+	}
+	if _, bg, _ := cells[w+3].Style.Decompose(); bg != ColorWhite {
+		t.Errorf("no quiet zone")
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"sync"
+	"time"
+)
+
+// maxSequenceLen is the longest control sequence that we will accept
+// from the terminal.  A reply that is longer than this (or that is never
+// terminated) is discarded, rather than letting it consume memory, or
+// swallow all input that follows.
+const maxSequenceLen = 4096
+
+// SequenceMatcher decides whether a control sequence received from the
+// terminal is the reply to a query.  The arguments are as for an
+// UnknownSequenceHandler.
+type SequenceMatcher func(kind SequenceKind, payload string) bool
+
+// pendingQuery is a query that is waiting for its reply.
+type pendingQuery struct {
+	match SequenceMatcher
+	reply chan string
+	fail  chan error
+}
+
+// queryManager keeps track of queries that are waiting for replies,
+// so that several may be outstanding at once.  Each reply goes to the
+// oldest query that it matches.
+type queryManager struct {
+	pending []*pendingQuery
+	sync.Mutex
+}
+
+func (qm *queryManager) add(match SequenceMatcher) *pendingQuery {
+	q := &pendingQuery{
+		match: match,
+		reply: make(chan string, 1),
+		fail:  make(chan error, 1),
+	}
+	qm.Lock()
+	qm.pending = append(qm.pending, q)
+	qm.Unlock()
+	return q
+}
+
+func (qm *queryManager) remove(q *pendingQuery) {
+	qm.Lock()
+	defer qm.Unlock()
+	for i, p := range qm.pending {
+		if p == q {
+			qm.pending = append(qm.pending[:i], qm.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// deliver gives the sequence to the oldest query that it answers, and
+// returns true if there was one.
+func (qm *queryManager) deliver(kind SequenceKind, payload string) bool {
+	qm.Lock()
+	defer qm.Unlock()
+	for i, q := range qm.pending {
+		if q.match(kind, payload) {
+			qm.pending = append(qm.pending[:i], qm.pending[i+1:]...)
+			q.reply <- payload
+			return true
+		}
+	}
+	return false
+}
+
+// failOldest reports an error to the oldest query, if there is one.
+func (qm *queryManager) failOldest(err error) {
+	qm.Lock()
+	defer qm.Unlock()
+	if len(qm.pending) > 0 {
+		q := qm.pending[0]
+		qm.pending = qm.pending[1:]
+		q.fail <- err
+	}
+}
+
+func (t *tScreen) Query(seq string, match SequenceMatcher, timeout time.Duration) (string, error) {
+	if !validSequence(seq) {
+		return "", ErrInvalidSequence
+	}
+	t.Lock()
+	if t.fini || !t.running {
+		t.Unlock()
+		return "", ErrNoScreen
+	}
+	q := t.queries.add(match)
+	t.writeString(seq)
+	quit := t.quit
+	t.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case reply := <-q.reply:
+		return reply, nil
+	case err := <-q.fail:
+		return "", err
+	case <-timer.C:
+		t.queries.remove(q)
+		return "", ErrQueryTimeout
+	case <-quit:
+		t.queries.remove(q)
+		return "", ErrNoScreen
+	}
+}
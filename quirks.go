@@ -0,0 +1,145 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Users can correct what we get wrong about their terminal, without
+// waiting for a new release of each application, with a quirks file.
+// This is read from $TCELL_QUIRKS, or else tcell/quirks.toml in
+// $XDG_CONFIG_HOME (by default ~/.config).  It is a small subset of TOML;
+// each table is named for the terminals it applies to, and each key is
+// one of the TCELL_ variables (see env.go) in lower case, without the
+// prefix.  For example:
+//
+//   # keys before any table apply to every terminal
+//   glyphs = "powerline"
+//
+//   [wezterm]
+//   notify = "osc777"
+//
+//   ["xterm-*"]
+//   truecolor = true
+//   c1 = false
+//
+// Tables are matched (as with path.Match, ignoring case) against the
+// name of the terminal, its aliases, and $TERM_PROGRAM; where several
+// match, the last one in the file wins.  True and false are taken to be
+// "enable" and "disable".  The environment variables themselves take
+// precedence over the file, and WithoutEnv ignores both.
+
+// quirksFile returns the name of the quirks file.
+func quirksFile() string {
+	if name := os.Getenv("TCELL_QUIRKS"); name != "" {
+		return name
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home := os.Getenv("HOME")
+		if home == "" {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "tcell", "quirks.toml")
+}
+
+// parseQuirks reads a quirks file, returning the overrides that apply to
+// a terminal known by any of names, keyed by the environment variable
+// that they stand in for.  Lines that cannot be understood are ignored.
+func parseQuirks(r io.Reader, names []string) map[string]string {
+	quirks := make(map[string]string)
+	match := true
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				match = false
+				continue
+			}
+			match = quirksMatch(quirksValue(line[1:end]), names)
+			continue
+		}
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 || !match {
+			continue
+		}
+		key := strings.TrimSpace(line[:eq])
+		val := quirksValue(line[eq+1:])
+		switch val {
+		case "true":
+			val = "enable"
+		case "false":
+			val = "disable"
+		}
+		quirks["TCELL_"+strings.ToUpper(key)] = val
+	}
+	return quirks
+}
+
+// quirksValue returns a value, without its quotes or a trailing comment.
+func quirksValue(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 0 && (s[0] == '"' || s[0] == '\'') {
+		if end := strings.IndexByte(s[1:], s[0]); end >= 0 {
+			return s[1 : end+1]
+		}
+		return s[1:]
+	}
+	if i := strings.IndexByte(s, '#'); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+func quirksMatch(pattern string, names []string) bool {
+	pattern = strings.ToLower(pattern)
+	for _, name := range names {
+		if ok, _ := path.Match(pattern, strings.ToLower(name)); ok && name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// loadQuirks reads the quirks for our terminal, if there is a file.
+func (t *tScreen) loadQuirks() {
+	if t.noEnv {
+		return
+	}
+	name := quirksFile()
+	if name == "" {
+		return
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	names := append([]string{t.ti.Name, t.osGetenv("TERM_PROGRAM")}, t.ti.Aliases...)
+	t.quirks = parseQuirks(f, names)
+}
@@ -0,0 +1,155 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// ResizePolicy is what a screen does with its contents when the size of
+// the terminal changes.
+type ResizePolicy int
+
+const (
+	// ResizeTruncate keeps each cell where it is, discarding those that
+	// no longer fit.  This is the default, and suits applications that
+	// redraw everything when they are resized.
+	ResizeTruncate ResizePolicy = iota
+
+	// ResizeReflow joins the rows of each logical line (the rows marked
+	// with SetLineWrap, and the row after the last of them) and wraps
+	// them again at the new width, as terminals do with their own text.
+	// Blanks at the end of each logical line are not kept.  If the lines
+	// no longer fit, rows are removed from the top, and go to the
+	// scrollback (see SetScrollback).  This suits chat and log style
+	// applications.
+	ResizeReflow
+)
+
+// SetWrapped marks row y as continuing on the next row, as part of the
+// same logical line, or not.
+func (cb *CellBuffer) SetWrapped(y int, wrapped bool) {
+	if y < 0 || y >= cb.h {
+		return
+	}
+	for len(cb.wraps) <= y {
+		cb.wraps = append(cb.wraps, false)
+	}
+	cb.wraps[y] = wrapped
+}
+
+// Wrapped returns true if row y continues on the next row.
+func (cb *CellBuffer) Wrapped(y int) bool {
+	return y >= 0 && y < len(cb.wraps) && cb.wraps[y]
+}
+
+// reflow resizes the buffer to w by h, wrapping its logical lines again
+// at the new width.  The rows that no longer fit at the top are returned.
+func (cb *CellBuffer) reflow(w, h int) [][]cell {
+	if w <= 0 || h <= 0 || (w == cb.w && h == cb.h) {
+		cb.Resize(w, h)
+		return nil
+	}
+
+	// join the rows of each logical line
+	var lines [][]cell
+	var line []cell
+	for y := 0; y < cb.h; y++ {
+		for x := 0; x < cb.w; x++ {
+			c := cb.cells[y*cb.w+x]
+			line = append(line, cell{
+				currMain:  c.currMain,
+				currComb:  c.currComb,
+				currStyle: c.currStyle,
+				width:     c.width,
+			})
+			if c.width > 1 {
+				x++
+			}
+		}
+		if cb.Wrapped(y) && y < cb.h-1 {
+			continue
+		}
+		for len(line) > 0 {
+			if c := line[len(line)-1]; (c.currMain != ' ' && c.currMain != 0) || len(c.currComb) > 0 {
+				break
+			}
+			line = line[:len(line)-1]
+		}
+		lines = append(lines, line)
+		line = nil
+	}
+	for len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	// and wrap them again
+	blank := func() []cell {
+		row := make([]cell, w)
+		for i := range row {
+			row[i] = cell{currMain: ' ', currStyle: StyleDefault, width: 1}
+		}
+		return row
+	}
+	var rows [][]cell
+	var wraps []bool
+	for _, line := range lines {
+		row, x := blank(), 0
+		for _, c := range line {
+			if c.width > w {
+				c = cell{currMain: ' ', currStyle: c.currStyle, width: 1}
+			}
+			if x+c.width > w {
+				rows, wraps = append(rows, row), append(wraps, true)
+				row, x = blank(), 0
+			}
+			row[x] = c
+			x += c.width
+		}
+		rows, wraps = append(rows, row), append(wraps, false)
+	}
+
+	var dropped [][]cell
+	if len(rows) > h {
+		dropped = rows[:len(rows)-h]
+		rows, wraps = rows[len(rows)-h:], wraps[len(wraps)-h:]
+	}
+	cb.cells = make([]cell, 0, w*h)
+	for _, row := range rows {
+		cb.cells = append(cb.cells, row...)
+	}
+	for len(cb.cells) < w*h {
+		cb.cells = append(cb.cells, blank()...)
+	}
+	cb.w, cb.h, cb.wraps = w, h, wraps
+	return dropped
+}
+
+// resizeCells resizes the cells of a screen according to its policy.
+func resizeCells(cb *CellBuffer, sb *scrollback, policy ResizePolicy, w, h int) {
+	if policy == ResizeReflow {
+		sb.add(cb.reflow(w, h))
+		return
+	}
+	cb.Resize(w, h)
+}
+
+func (t *tScreen) SetResizePolicy(policy ResizePolicy) {
+	t.Lock()
+	t.reflow = policy
+	t.Unlock()
+}
+
+func (t *tScreen) SetLineWrap(y int, wrapped bool) {
+	t.Lock()
+	t.cells.SetWrapped(y, wrapped)
+	t.Unlock()
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReflow(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(6, 3)
+	s.SetScrollback(5)
+	s.SetResizePolicy(ResizeReflow)
+	rows := func() string {
+		s.Show()
+		w, h := s.Size()
+		var text []string
+		for y := 0; y < h; y++ {
+			text = append(text, simRow(s, 0, y, w))
+		}
+		return strings.Join(text, "|")
+	}
+	for i, line := range []string{"hello ", "world", "bye"} {
+		for x, r := range line {
+			s.SetContent(x, i, r, nil, StyleDefault)
+		}
+	}
+	s.SetLineWrap(0, true)
+
+	s.SetSize(4, 3)
+	if got := rows(); got != "o wo|rld |bye " {
+		t.Errorf("wrong reflow: %q", got)
+	}
+	if got := s.Scrollback(); got == nil {
+		t.Errorf("no scrollback")
+	} else if _, h := got.Size(); h != 1 {
+		t.Errorf("wrong scrollback: %d rows", h)
+	}
+	s.SetSize(12, 2)
+	if got := rows(); got != "o world     |bye         " {
+		t.Errorf("wrong reflow: %q", got)
+	}
+
+	s.SetSize(4, 2)
+	if got := rows(); got != "rld |bye " {
+		t.Errorf("wrong reflow: %q", got)
+	}
+	if r, _, _, _ := s.Scrollback().GetContent(0, 1); r != 'o' {
+		t.Errorf("wrong scrollback: %q", r)
+	}
+}
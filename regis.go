@@ -0,0 +1,150 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ReGIS builds a ReGIS (Remote Graphics Instruction Set) vector drawing,
+// as understood by the DEC VT240, VT330 and VT340, and by xterm when it
+// is built with ReGIS support and emulating one of those.  Coordinates
+// are in screen pixels, with the origin at the top left; the VT340 screen
+// is 800 by 480 pixels.
+//
+// Methods may be chained, and the finished drawing is sent to the
+// terminal with Screen.EmitRaw:
+//
+//	r := tcell.NewReGIS().Color(tcell.ColorGreen).Move(0, 0).Line(799, 479)
+//	err := s.EmitRaw(r.String())
+//
+// The drawing is made in the terminal's graphics plane, over the text,
+// and is not tracked by tcell, so it will not survive a Sync.
+type ReGIS struct {
+	sb strings.Builder
+}
+
+// NewReGIS returns an empty drawing.
+func NewReGIS() *ReGIS {
+	return &ReGIS{}
+}
+
+func (r *ReGIS) cmd(s ...string) *ReGIS {
+	for _, p := range s {
+		r.sb.WriteString(p)
+	}
+	return r
+}
+
+func regisPoint(x, y int) string {
+	return "[" + strconv.Itoa(x) + "," + strconv.Itoa(y) + "]"
+}
+
+// Erase clears the graphics plane.
+func (r *ReGIS) Erase() *ReGIS {
+	return r.cmd("S(E)")
+}
+
+// Move moves the graphics cursor to x, y without drawing.
+func (r *ReGIS) Move(x, y int) *ReGIS {
+	return r.cmd("P", regisPoint(x, y))
+}
+
+// Line draws a line from the graphics cursor to x, y, leaving the
+// cursor at x, y.
+func (r *ReGIS) Line(x, y int) *ReGIS {
+	return r.cmd("V", regisPoint(x, y))
+}
+
+// Polyline draws lines joining each of the points, given as x, y pairs,
+// starting from the graphics cursor.
+func (r *ReGIS) Polyline(xy ...int) *ReGIS {
+	r.cmd("V")
+	for i := 0; i+1 < len(xy); i += 2 {
+		r.cmd(regisPoint(xy[i], xy[i+1]))
+	}
+	return r
+}
+
+// Circle draws a circle of the given radius, centered on the graphics
+// cursor.
+func (r *ReGIS) Circle(radius int) *ReGIS {
+	return r.cmd("C[+", strconv.Itoa(radius), "]")
+}
+
+// Text writes s at the graphics cursor, using the terminal's graphics
+// font.
+func (r *ReGIS) Text(s string) *ReGIS {
+	return r.cmd("T'", strings.ReplaceAll(s, "'", "''"), "'")
+}
+
+// Color sets the color used for drawing.  The color is sent as hue,
+// lightness and saturation, which the terminal maps to the nearest
+// entry in its color map.  ColorDefault leaves the color alone.
+func (r *ReGIS) Color(c Color) *ReGIS {
+	if !c.Valid() {
+		return r
+	}
+	h, l, s := regisHLS(c)
+	return r.cmd("W(I(H", strconv.Itoa(h), "L", strconv.Itoa(l), "S", strconv.Itoa(s), "))")
+}
+
+// String returns the complete ReGIS device control string.
+func (r *ReGIS) String() string {
+	return "\x1bP0p" + r.sb.String() + "\x1b\\"
+}
+
+// regisHLS converts a color to ReGIS hue, lightness and saturation.
+// ReGIS measures hue from blue rather than red, so it is rotated by
+// 120 degrees from the usual HLS hue.
+func regisHLS(c Color) (int, int, int) {
+	ri, gi, bi := c.RGB()
+	rf, gf, bf := float64(ri)/255, float64(gi)/255, float64(bi)/255
+	max, min := rf, rf
+	for _, v := range []float64{gf, bf} {
+		if v > max {
+			max = v
+		}
+		if v < min {
+			min = v
+		}
+	}
+	l := (max + min) / 2
+	if max == min {
+		return 0, int(l*100 + 0.5), 0
+	}
+	d := max - min
+	var s float64
+	if l > 0.5 {
+		s = d / (2 - max - min)
+	} else {
+		s = d / (max + min)
+	}
+	var h float64
+	switch max {
+	case rf:
+		h = (gf - bf) / d
+		if gf < bf {
+			h += 6
+		}
+	case gf:
+		h = (bf-rf)/d + 2
+	default:
+		h = (rf-gf)/d + 4
+	}
+	hue := (int(h*60+0.5) + 120) % 360
+	return hue, int(l*100 + 0.5), int(s*100 + 0.5)
+}
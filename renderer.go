@@ -0,0 +1,137 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"sync"
+)
+
+// Renderer adapts a Screen for frameworks that manage views of their own,
+// and render each of them to a string with ANSI escape sequences (as
+// Bubble Tea does), rather than drawing cells.  Such a framework can use
+// a Renderer in place of writing to the terminal itself, and so leave the
+// terminal handling (raw mode, terminfo, input decoding, resizing, and
+// updating only what changed) to tcell.
+//
+// Output is grouped into frames.  BeginFrame starts a frame, which begins
+// empty; regions are then written, and EndFrame shows the result.  Only
+// the cells that differ from the last frame are sent to the terminal.
+// Writes outside of a frame are shown at once.
+type Renderer struct {
+	s       Screen
+	frame   bool
+	cursor  bool
+	cx, cy  int
+	repaint bool
+	sync.Mutex
+}
+
+// NewRenderer returns a Renderer for a Screen, which must be initialized.
+func NewRenderer(s Screen) *Renderer {
+	return &Renderer{s: s}
+}
+
+// BeginFrame starts a frame, clearing the screen.
+func (r *Renderer) BeginFrame() {
+	r.Lock()
+	defer r.Unlock()
+	r.frame = true
+	r.s.Clear()
+}
+
+// EndFrame ends a frame, and shows it.
+func (r *Renderer) EndFrame() {
+	r.Lock()
+	defer r.Unlock()
+	r.frame = false
+	r.show()
+}
+
+func (r *Renderer) show() {
+	if r.cursor {
+		r.s.ShowCursor(r.cx, r.cy)
+	} else {
+		r.s.HideCursor()
+	}
+	if r.repaint {
+		r.repaint = false
+		r.s.Sync()
+	} else {
+		r.s.Show()
+	}
+}
+
+// WriteRegion writes text, which may contain escape sequences (see
+// ImportANSI), to the region with its top left corner at x, y, and the
+// given width and height.  The region is cleared first, and anything
+// that does not fit is clipped.  The number of rows used is returned.
+func (r *Renderer) WriteRegion(x, y, width, height int, text string) int {
+	r.Lock()
+	defer r.Unlock()
+	for row := y; row < y+height; row++ {
+		for col := x; col < x+width; col++ {
+			r.s.SetContent(col, row, ' ', nil, StyleDefault)
+		}
+	}
+	rows := ImportANSI(r.s, x, y, width, height, text)
+	if !r.frame {
+		r.show()
+	}
+	return rows
+}
+
+// WriteString writes text to the whole screen, as WriteRegion does.
+// This suits frameworks that render the whole view as one string.
+func (r *Renderer) WriteString(text string) int {
+	w, h := r.s.Size()
+	return r.WriteRegion(0, 0, w, h, text)
+}
+
+// SetCursor shows the cursor at x, y.
+func (r *Renderer) SetCursor(x, y int) {
+	r.Lock()
+	defer r.Unlock()
+	r.cursor = true
+	r.cx, r.cy = x, y
+	if !r.frame {
+		r.show()
+	}
+}
+
+// HideCursor hides the cursor.
+func (r *Renderer) HideCursor() {
+	r.Lock()
+	defer r.Unlock()
+	r.cursor = false
+	if !r.frame {
+		r.show()
+	}
+}
+
+// Size returns the size of the screen.
+func (r *Renderer) Size() (int, int) {
+	return r.s.Size()
+}
+
+// Repaint has the whole screen sent again when the next frame ends (or
+// at once, outside of a frame), for when it may have been disturbed.
+func (r *Renderer) Repaint() {
+	r.Lock()
+	defer r.Unlock()
+	r.repaint = true
+	if !r.frame {
+		r.show()
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestRenderer(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(10, 3)
+	r := NewRenderer(s)
+
+	r.BeginFrame()
+	r.WriteString("ab\n\x1b[31mcd\x1b[0m")
+	r.SetCursor(1, 1)
+	if cells, _, _ := s.GetContents(); len(cells[0].Runes) != 0 && cells[0].Runes[0] == 'a' {
+		t.Errorf("frame shown before it ended")
+	}
+	r.EndFrame()
+	cells, w, _ := s.GetContents()
+	if cells[0].Runes[0] != 'a' || cells[w].Runes[0] != 'c' ||
+		cells[w].Style != StyleDefault.Foreground(ColorMaroon) {
+		t.Errorf("wrong content: %v %v", cells[0], cells[w])
+	}
+	if x, y, on := s.GetCursor(); x != 1 || y != 1 || !on {
+		t.Errorf("cursor not shown: %d %d", x, y)
+	}
+
+	// A new frame starts empty.
+	r.BeginFrame()
+	r.WriteRegion(5, 2, 5, 1, "xyz")
+	r.EndFrame()
+	cells, _, _ = s.GetContents()
+	if cells[0].Runes[0] != ' ' || cells[2*w+5].Runes[0] != 'x' {
+		t.Errorf("wrong content: %v %v", cells[0], cells[2*w+5])
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// Runs of identical cells, such as fills and horizontal rules, can be
+// sent using REP (CSI n b), which repeats the last character written.
+// Terminfo describes this with the rep capability, which outputs the
+// character and then the repeat; we only use it when it has the usual
+// ECMA-48 form, so that we can write the character ourselves (properly
+// encoded) and follow it with the repeat.  XTerm supports REP, although
+// its terminfo entry (at least the one built in here) does not say so;
+// but many terminals that call themselves xterm do not, so it is only
+// used for XTerm once it has identified itself, or when TCELL_REP is
+// "enable".
+
+// xtermRepeat is the rep capability of xterm.
+const xtermRepeat = "%p1%c\x1b[%p2%{1}%-%db"
+
+// prepareRepeat decides whether REP can be used.  It is called again
+// once the terminal has identified itself.
+func (t *tScreen) prepareRepeat() {
+	rep := t.ti.RepeatChar
+	switch t.getenv("TCELL_REP") {
+	case "enable":
+		if rep == "" {
+			rep = xtermRepeat
+		}
+	case "disable":
+		rep = ""
+	}
+	t.repeat = strings.HasPrefix(rep, "%p1%c\x1b[") && strings.HasSuffix(rep, "b")
+}
+
+// repeatCell looks for cells following x, y which are dirty and will be
+// displayed identically to it, just after it has been drawn, and draws
+// them with REP, if that is shorter than drawing them individually.  The
+// cells are compared as displayed, since overlays such as guides and the
+// selection can make cells with the same content look different.  It
+// returns the number of cells so drawn.
+func (t *tScreen) repeatCell(x, y, w int) int {
+	mainc, combc, style, width := t.cells.GetContent(x, y)
+	if width != 1 || len(combc) != 0 || t.cx != x+1 || t.softCursor.at(x, y) {
+		return 0
+	}
+	mainc, combc, style, blank := t.displayed(x, y, mainc, combc, style)
+	if len(combc) != 0 {
+		return 0
+	}
+	// Avoid the last column, which has complications of its own.
+	if w == t.w {
+		w--
+	}
+	n := 0
+	for i := x + 1; i < w && t.cells.Dirty(i, y); i++ {
+		m, c, s, wid := t.cells.GetContent(i, y)
+		if wid != 1 || len(c) != 0 || t.softCursor.at(i, y) {
+			break
+		}
+		m, c, s, b := t.displayed(i, y, m, c, s)
+		if m != mainc || len(c) != 0 || s != style || b != blank {
+			break
+		}
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	if blank {
+		mainc = ' '
+	}
+	enc := t.encodeRune(mainc, nil)
+	if bytes.IndexByte(enc, '\x1b') >= 0 || string(enc) == "?" {
+		// Alternate character set or a substitute; not worth the risk.
+		return 0
+	}
+	seq := "\x1b[" + strconv.Itoa(n) + "b"
+	if len(seq) >= len(enc)*n {
+		return 0
+	}
+	t.TPuts(seq)
+	for i := x + 1; i <= x+n; i++ {
+		t.cells.SetDirty(i, y, false)
+	}
+	t.cx += n
+	return n
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+
+	"github.com/gdamore/tcell/v2/terminfo"
+)
+
+// Report is a structured description of everything tcell has decided
+// about a Screen and the terminal behind it.  It is intended primarily
+// for diagnostics -- applications can print it (or serialize it as JSON)
+// so that users can attach it to bug reports.
+type Report struct {
+	Platform       string             // GOOS/GOARCH
+	Term           string             // value of $TERM, as the screen read it
+	Driver         string             // "terminfo", "console", or "simulation"
+	CharacterSet   string             // the character set in use
+	Width          int                // width in character cells
+	Height         int                // height in character cells
+	Colors         int                // number of colors, as reported by Colors()
+	TrueColor      bool               // true if 24-bit color will be emitted
+	Mouse          bool               // true if the terminal appears to have a mouse
+	MouseProtocol  string             // mouse reporting protocol, if any
+	BracketedPaste bool               // true if bracketed paste can be enabled
+	Hyperlinks     bool               // true if OSC 8 hyperlinks will be emitted
+	CursorStyles   bool               // true if cursor styles can be changed
+	SetWindowSize  bool               // true if the window can be resized
+	Keys           []ReportKey        // decoded key sequences
+	Terminfo       *terminfo.Terminfo `json:",omitempty"`
+}
+
+// ReportKey describes a single input sequence that tcell will decode
+// as a key press.
+type ReportKey struct {
+	Name     string // printable key name, as returned by EventKey.Name
+	Sequence string // the raw sequence, quoted for printing
+}
+
+// reporter is implemented by screens that can describe themselves.
+type reporter interface {
+	report(r *Report)
+}
+
+// NewReport creates a report for the given screen.  The screen should
+// already be initialized, since many decisions (such as the character set)
+// are only made during Init.
+func NewReport(s Screen) *Report {
+	r := &Report{
+		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+		CharacterSet: s.CharacterSet(),
+		Colors:       s.Colors(),
+		Mouse:        s.HasMouse(),
+	}
+	r.Width, r.Height = s.Size()
+	if rp, ok := s.(reporter); ok {
+		rp.report(r)
+	}
+	sort.Slice(r.Keys, func(i, j int) bool {
+		if r.Keys[i].Name == r.Keys[j].Name {
+			return r.Keys[i].Sequence < r.Keys[j].Sequence
+		}
+		return r.Keys[i].Name < r.Keys[j].Name
+	})
+	return r
+}
+
+// WriteTo writes the report in a human readable form.  The Terminfo
+// entry is not included; serialize the Report as JSON to obtain that.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	b := &bytes.Buffer{}
+	line := func(name string, v interface{}) {
+		fmt.Fprintf(b, "%-16s %v\n", name+":", v)
+	}
+	line("Platform", r.Platform)
+	line("TERM", r.Term)
+	line("Driver", r.Driver)
+	if r.Terminfo != nil {
+		line("Terminfo", r.Terminfo.Name)
+	}
+	line("Character Set", r.CharacterSet)
+	line("Size", fmt.Sprintf("%dx%d", r.Width, r.Height))
+	line("Colors", r.Colors)
+	line("TrueColor", r.TrueColor)
+	line("Mouse", r.Mouse)
+	line("Mouse Protocol", r.MouseProtocol)
+	line("Bracketed Paste", r.BracketedPaste)
+	line("Hyperlinks", r.Hyperlinks)
+	line("Cursor Styles", r.CursorStyles)
+	line("Set Window Size", r.SetWindowSize)
+	line("Keys", len(r.Keys))
+	for _, k := range r.Keys {
+		fmt.Fprintf(b, "    %-28s %s\n", k.Name, k.Sequence)
+	}
+	return b.WriteTo(w)
+}
+
+func (t *tScreen) report(r *Report) {
+	t.Lock()
+	defer t.Unlock()
+
+	r.Driver = "terminfo"
+	r.Term = t.osGetenv("TERM")
+	r.Terminfo = t.ti
+	r.TrueColor = t.truecolor
+	if len(t.mouse) != 0 {
+		r.MouseProtocol = "xterm, sgr"
+	}
+	r.BracketedPaste = t.enablePaste != ""
+	r.Hyperlinks = t.enterUrl != ""
+	r.CursorStyles = t.cursorStyles != nil
+	r.SetWindowSize = t.setWinSize != ""
+	for seq, k := range t.keycodes {
+		var name string
+		switch k.key {
+		case keyPasteStart:
+			name = "PasteStart"
+		case keyPasteEnd:
+			name = "PasteEnd"
+		default:
+			name = NewEventKey(k.key, 0, k.mod).Name()
+		}
+		r.Keys = append(r.Keys, ReportKey{
+			Name:     name,
+			Sequence: strconv.QuoteToASCII(seq),
+		})
+	}
+}
+
+func (s *simscreen) report(r *Report) {
+	r.Driver = "simulation"
+}
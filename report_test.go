@@ -0,0 +1,65 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	r := NewReport(s)
+	if r.Driver != "simulation" {
+		t.Errorf("Driver should be simulation, was %q", r.Driver)
+	}
+	if r.Width != 80 || r.Height != 25 {
+		t.Errorf("Size should be 80, 25, was %v, %v", r.Width, r.Height)
+	}
+	if r.CharacterSet != "UTF-8" {
+		t.Errorf("Character Set (%v) not UTF-8", r.CharacterSet)
+	}
+}
+
+func TestReportSession(t *testing.T) {
+	ti, e := LookupTerminfo("xterm-256color")
+	if e != nil {
+		t.Fatalf("no terminfo: %v", e)
+	}
+	s, _, e := ReplaySession(&Session{
+		Driver:       "terminfo",
+		Terminfo:     ti,
+		Env:          map[string]string{"TERM": "xterm-replayed"},
+		CharacterSet: "UTF-8",
+		Width:        20,
+		Height:       5,
+	})
+	if e != nil {
+		t.Fatalf("cannot replay: %v", e)
+	}
+	if e = s.Init(); e != nil {
+		t.Fatalf("cannot init: %v", e)
+	}
+	defer s.Fini()
+
+	r := NewReport(s)
+	if r.Driver != "terminfo" {
+		t.Errorf("Driver should be terminfo, was %q", r.Driver)
+	}
+	if r.Term != "xterm-replayed" {
+		t.Errorf("TERM should be the session's, was %q", r.Term)
+	}
+}
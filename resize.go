@@ -18,11 +18,29 @@ import (
 	"time"
 )
 
+// ResizeReason is why the size of the screen changed.
+type ResizeReason int
+
+const (
+	// ResizeWindow is a change in the size of the terminal's window,
+	// such as when the user resizes it (SIGWINCH on POSIX systems).
+	ResizeWindow ResizeReason = iota
+
+	// ResizeSetSize is a change made by the application, with SetSize.
+	ResizeSetSize
+
+	// ResizeInitial is the first size of the screen, when it starts.
+	ResizeInitial
+)
+
 // EventResize is sent when the window size changes.
 type EventResize struct {
-	t time.Time
-	w int
-	h int
+	t      time.Time
+	w      int
+	h      int
+	ow     int
+	oh     int
+	reason ResizeReason
 }
 
 // NewEventResize creates an EventResize with the new updated window size,
@@ -31,6 +49,14 @@ func NewEventResize(width, height int) *EventResize {
 	return &EventResize{t: time.Now(), w: width, h: height}
 }
 
+// newEventResize creates an EventResize which also has the old size, and
+// the reason for the change.
+func newEventResize(width, height, oldWidth, oldHeight int, reason ResizeReason) *EventResize {
+	ev := NewEventResize(width, height)
+	ev.ow, ev.oh, ev.reason = oldWidth, oldHeight, reason
+	return ev
+}
+
 // When returns the time when the Event was created.
 func (ev *EventResize) When() time.Time {
 	return ev.t
@@ -40,3 +66,26 @@ func (ev *EventResize) When() time.Time {
 func (ev *EventResize) Size() (int, int) {
 	return ev.w, ev.h
 }
+
+// OldSize returns the window size before the change, which is zero for
+// ResizeInitial (and for events made with NewEventResize).
+func (ev *EventResize) OldSize() (int, int) {
+	return ev.ow, ev.oh
+}
+
+// Reason returns why the size changed.
+func (ev *EventResize) Reason() ResizeReason {
+	return ev.reason
+}
+
+// resizeReason returns the reason for a change of size, from the old size
+// and any size that the application asked for with SetSize.
+func resizeReason(w, h, ow, oh int, want [2]int) ResizeReason {
+	switch {
+	case ow == 0 && oh == 0:
+		return ResizeInitial
+	case want == [2]int{w, h}:
+		return ResizeSetSize
+	}
+	return ResizeWindow
+}
@@ -0,0 +1,32 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin
+// +build darwin
+
+package tcell
+
+import (
+	"time"
+)
+
+// Terminals on macOS send bursts of SIGWINCH when going in and out of full
+// screen, reporting sizes along the way that the window never has.  So the
+// size is only taken once it has been the same for resizeSettle, and is
+// checked again resizeRecheck after that, in case the last change came
+// without a signal.
+const (
+	resizeSettle  = 50 * time.Millisecond
+	resizeRecheck = 500 * time.Millisecond
+)
@@ -0,0 +1,57 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestResizeReason(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(30, 10)
+	if s.HasPendingEvent() {
+		t.Errorf("resize event from SetSize")
+	}
+	s.SetSize(80, 25)
+	s.ResizeWindow(40, 10)
+	ev, ok := s.PollEvent().(*EventResize)
+	if !ok {
+		t.Fatalf("no resize event")
+	}
+	if w, h := ev.Size(); w != 40 || h != 10 {
+		t.Errorf("wrong size: %dx%d", w, h)
+	}
+	if w, h := ev.OldSize(); w != 80 || h != 25 {
+		t.Errorf("wrong old size: %dx%d", w, h)
+	}
+	if ev.Reason() != ResizeWindow {
+		t.Errorf("wrong reason: %v", ev.Reason())
+	}
+	s.ResizeWindow(40, 10)
+	if s.HasPendingEvent() {
+		t.Errorf("resize event without a change of size")
+	}
+
+	if r := resizeReason(80, 24, 0, 0, [2]int{}); r != ResizeInitial {
+		t.Errorf("wrong reason for first size: %v", r)
+	}
+	if r := resizeReason(80, 24, 100, 30, [2]int{80, 24}); r != ResizeSetSize {
+		t.Errorf("wrong reason for requested size: %v", r)
+	}
+	if r := resizeReason(80, 24, 100, 30, [2]int{}); r != ResizeWindow {
+		t.Errorf("wrong reason for window change: %v", r)
+	}
+}
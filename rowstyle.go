@@ -0,0 +1,78 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// rowStyles holds the styles set for rows with SetRowStyle.
+type rowStyles []Style
+
+// set sets the style of row y, marking its cells dirty if it changed.
+func (rs *rowStyles) set(cb *CellBuffer, y int, style Style) {
+	if y < 0 || rs.get(y) == style {
+		return
+	}
+	for len(*rs) <= y {
+		*rs = append(*rs, StyleDefault)
+	}
+	(*rs)[y] = style
+	w, _ := cb.Size()
+	for x := 0; x < w; x++ {
+		cb.SetDirty(x, y, true)
+	}
+}
+
+func (rs rowStyles) get(y int) Style {
+	if y < 0 || y >= len(rs) {
+		return StyleDefault
+	}
+	return rs[y]
+}
+
+// apply returns the style to display for a cell of row y with style,
+// where def is the default style of the screen.  The colors of the row
+// are used where the cell has none, in preference to those of def, and
+// its attributes are added to those of the cell.
+func (rs rowStyles) apply(y int, style, def Style) Style {
+	row := rs.get(y)
+	if style == StyleDefault {
+		style = def
+		if row.fg != ColorDefault {
+			style.fg = row.fg
+		}
+		if row.bg != ColorDefault {
+			style.bg = row.bg
+		}
+	} else {
+		if style.fg == ColorDefault {
+			style.fg = row.fg
+		}
+		if style.bg == ColorDefault {
+			style.bg = row.bg
+		}
+	}
+	style.attrs |= row.attrs
+	return style
+}
+
+func (t *tScreen) SetRowStyle(y int, style Style) {
+	t.Lock()
+	t.rows.set(&t.cells, y, style)
+	t.Unlock()
+}
+
+func (t *tScreen) GetRowStyle(y int) Style {
+	t.Lock()
+	defer t.Unlock()
+	return t.rows.get(y)
+}
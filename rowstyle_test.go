@@ -0,0 +1,53 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestRowStyle(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(4, 2)
+	s.SetContent(0, 0, 'a', nil, StyleDefault.Foreground(ColorRed))
+	s.SetContent(1, 0, 'b', nil, StyleDefault.Background(ColorGreen))
+	row := StyleDefault.Background(ColorBlue).Underline(true)
+	s.SetRowStyle(0, row)
+	s.Show()
+	if got := s.GetRowStyle(0); got != row {
+		t.Errorf("wrong row style: %v", got)
+	}
+	cells, _, _ := s.GetContents()
+	want := []Style{
+		StyleDefault.Foreground(ColorRed).Background(ColorBlue).Underline(true),
+		StyleDefault.Background(ColorGreen).Underline(true),
+		row,
+		row,
+		StyleDefault,
+	}
+	for i, style := range want {
+		if cells[i].Style != style {
+			t.Errorf("wrong style of cell %d: %v", i, cells[i].Style)
+		}
+	}
+
+	s.SetRowStyle(0, StyleDefault)
+	s.Show()
+	cells, _, _ = s.GetContents()
+	if cells[2].Style != StyleDefault {
+		t.Errorf("row style not removed: %v", cells[2].Style)
+	}
+}
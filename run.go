@@ -0,0 +1,70 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// Run implements a simple main loop for applications.  The screen must
+// already be initialized.  It calls draw once to draw the initial contents,
+// and then for each event calls update.  If update returns false, the loop
+// stops and Run returns.  Run also returns if the screen is finalized (for
+// example, by calling Fini from within update).
+//
+// Events that are already queued are all passed to update before draw is
+// called again, so that a burst of input (such as a paste, or a series of
+// mouse motion events) results in only a single redraw.  After draw returns,
+// Run calls Show, or Sync if the screen was resized since the last redraw.
+// The update function also receives EventResize events, and should use them
+// to adjust its layout as needed.
+//
+// If update or draw panics, Run finalizes the screen, so that the terminal
+// is restored to a usable state, and then continues the panic.  This keeps
+// the panic message and stack trace readable.
+//
+// Run does not call Fini on normal return; that is left to the caller.
+func Run(s Screen, update func(ev Event) bool, draw func(s Screen)) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.Fini()
+			panic(r)
+		}
+	}()
+
+	draw(s)
+	s.Show()
+	for {
+		resized := false
+		ev := s.PollEvent()
+		for {
+			if ev == nil {
+				return
+			}
+			if _, ok := ev.(*EventResize); ok {
+				resized = true
+			}
+			if !update(ev) {
+				return
+			}
+			if !s.HasPendingEvent() {
+				break
+			}
+			ev = s.PollEvent()
+		}
+		draw(s)
+		if resized {
+			s.Sync()
+		} else {
+			s.Show()
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	s.InjectKeyBytes([]byte("abc"))
+	s.InjectKey(KeyRune, 'q', ModNone)
+
+	var text []rune
+	draws := 0
+	update := func(ev Event) bool {
+		if ev, ok := ev.(*EventKey); ok {
+			if ev.Rune() == 'q' {
+				return false
+			}
+			text = append(text, ev.Rune())
+		}
+		return true
+	}
+	draw := func(s Screen) {
+		draws++
+		for i, r := range text {
+			s.SetContent(i, 0, r, nil, StyleDefault)
+		}
+	}
+	Run(s, update, draw)
+
+	if string(text) != "abc" {
+		t.Errorf("wrong text: %q", string(text))
+	}
+	// queued events are coalesced, so only the initial draw happens
+	if draws != 1 {
+		t.Errorf("expected 1 draw, got %d", draws)
+	}
+}
+
+func TestRunPanic(t *testing.T) {
+	s := mkTestScreen(t, "")
+	s.InjectKey(KeyEnter, 0, ModNone)
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("expected panic to propagate, got %v", r)
+		}
+		// the screen should have been finalized
+		if ev := s.PollEvent(); ev != nil {
+			t.Errorf("screen not finalized")
+		}
+	}()
+	Run(s, func(Event) bool { panic("boom") }, func(Screen) {})
+}
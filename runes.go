@@ -109,3 +109,108 @@ var RuneFallbacks = map[rune]string{
 	RuneURCorner: "+",
 	RuneVLine:    "|",
 }
+
+// These are profiles of fallbacks for groups of related runes, which can be
+// registered in one call with Screen.RegisterRuneFallbacks.  They are not
+// registered by default.  As with RuneFallbacks, each replacement occupies
+// the same number of cells as the rune it replaces.
+var (
+	// FallbacksArrows replaces arrows and arrow-like triangles.
+	FallbacksArrows = map[rune]string{
+		'←': "<",
+		'↑': "^",
+		'→': ">",
+		'↓': "v",
+		'↔': "-",
+		'↕': "|",
+		'⇐': "<",
+		'⇑': "^",
+		'⇒': ">",
+		'⇓': "v",
+		'⇔': "=",
+		'▲': "^",
+		'△': "^",
+		'▶': ">",
+		'▷': ">",
+		'►': ">",
+		'▼': "v",
+		'▽': "v",
+		'◀': "<",
+		'◁': "<",
+		'◄': "<",
+		'↵': "<",
+		'⏎': "<",
+	}
+
+	// FallbacksBoxDrawing replaces the heavy, double, rounded and
+	// dashed box drawing characters, which are not present in the
+	// alternate character set, along with the block elements.
+	FallbacksBoxDrawing = map[rune]string{
+		'━': "-",
+		'┃': "|",
+		'┄': "-",
+		'┅': "-",
+		'┆': "|",
+		'┇': "|",
+		'┈': "-",
+		'┉': "-",
+		'┊': "|",
+		'┋': "|",
+		'┏': "+",
+		'┓': "+",
+		'┗': "+",
+		'┛': "+",
+		'┣': "+",
+		'┫': "+",
+		'┳': "+",
+		'┻': "+",
+		'╋': "+",
+		'═': "=",
+		'║': "|",
+		'╔': "+",
+		'╗': "+",
+		'╚': "+",
+		'╝': "+",
+		'╠': "+",
+		'╣': "+",
+		'╦': "+",
+		'╩': "+",
+		'╬': "+",
+		'╭': "+",
+		'╮': "+",
+		'╯': "+",
+		'╰': "+",
+		'╱': "/",
+		'╲': "\\",
+		'╳': "X",
+		'╴': "-",
+		'╵': "|",
+		'╶': "-",
+		'╷': "|",
+		'▀': "#",
+		'▄': "#",
+		'▌': "#",
+		'▐': "#",
+		'▓': "#",
+	}
+
+	// FallbacksPowerline replaces the private use glyphs used by
+	// Powerline (and Nerd Fonts) for status line separators.
+	FallbacksPowerline = map[rune]string{
+		'\ue0a0': "Y", // version control branch
+		'\ue0a1': "#", // line number
+		'\ue0a2': "*", // read-only (padlock)
+		'\ue0b0': ">", // solid right arrow separator
+		'\ue0b1': ">", // right arrow separator
+		'\ue0b2': "<", // solid left arrow separator
+		'\ue0b3': "<", // left arrow separator
+		'\ue0b4': ")", // solid right semicircle
+		'\ue0b5': ")",
+		'\ue0b6': "(", // solid left semicircle
+		'\ue0b7': "(",
+		'\ue0b8': "/",
+		'\ue0ba': "/",
+		'\ue0bc': "\\",
+		'\ue0be': "\\",
+	}
+)
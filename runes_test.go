@@ -15,6 +15,7 @@
 package tcell
 
 import (
+	"os"
 	"testing"
 )
 
@@ -93,3 +94,104 @@ func TestRuneFallbacks(t *testing.T) {
 		t.Errorf("Should not be able to display hline")
 	}
 }
+
+func TestBestFit(t *testing.T) {
+	if len(bestFitLatin) != 0x180-0xc0 {
+		t.Errorf("bestFitLatin has %d entries", len(bestFitLatin))
+	}
+	// A replacement must take up as many cells as the rune it replaces.
+	for i, s := range bestFitLatin {
+		if len(s) != 1 {
+			t.Errorf("%U: %q is not a single character", 0xc0+i, s)
+		}
+	}
+	for r, s := range bestFitOther {
+		if len(s) != 1 {
+			t.Errorf("%U: %q is not a single character", r, s)
+		}
+	}
+	for r, want := range map[rune]string{
+		'é': "e",
+		'Æ': "A",
+		'ß': "s",
+		'Ł': "L",
+		'ž': "z",
+		'ſ': "s",
+		'“': "\"",
+		'…': ".",
+		'Ａ': "A",
+	} {
+		if got, ok := bestFit(r); !ok || got != want {
+			t.Errorf("bestFit(%q) = %q, want %q", r, got, want)
+		}
+	}
+	if _, ok := bestFit('中'); ok {
+		t.Errorf("bestFit should not approximate CJK")
+	}
+}
+
+func TestFallbackProfiles(t *testing.T) {
+	s := mkTestScreen(t, "US-ASCII")
+	defer s.Fini()
+
+	if s.CanDisplay('╔', true) {
+		t.Errorf("double box drawing should not be displayable without fallbacks")
+	}
+	s.RegisterRuneFallbacks(FallbacksBoxDrawing)
+	s.RegisterRuneFallbacks(FallbacksPowerline)
+	if !s.CanDisplay('╔', true) || !s.CanDisplay('\ue0b0', true) {
+		t.Errorf("fallback profiles not registered")
+	}
+	for _, m := range []map[rune]string{FallbacksArrows, FallbacksBoxDrawing, FallbacksPowerline} {
+		for r, fb := range m {
+			if len(fb) != 1 {
+				t.Errorf("fallback for %q should be one cell wide: %q", r, fb)
+			}
+		}
+	}
+}
+
+func TestHasGlyphs(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	if !s.HasGlyphs(GlyphsBoxDrawing) {
+		t.Errorf("UTF-8 simulation should have box drawing")
+	}
+	if s.HasGlyphs(GlyphsBoxDrawing | GlyphsPowerline) {
+		t.Errorf("simulation should not claim powerline glyphs")
+	}
+
+	_ = os.Setenv("TCELL_GLYPHS", "nerdfont")
+	defer os.Unsetenv("TCELL_GLYPHS")
+	if g := glyphsFromEnv(os.Getenv); g != GlyphsNerdFont|GlyphsPowerline {
+		t.Errorf("wrong glyphs from environment: %v", g)
+	}
+}
+
+func TestSoftFont(t *testing.T) {
+	f := &SoftFont{
+		Width:  2,
+		Height: 7,
+		Glyphs: map[rune][]string{
+			'┼': {"#.", "#.", "##", "#.", "#.", "#.", "##"},
+			'╳': {"..", "..", "..", "..", "..", "..", "##"},
+		},
+	}
+	load, seqs, err := f.encode()
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	// '┼' sorts before '╳', so it is the first character.
+	want := "\x1bP1;1;0;2;0;2;7;0{ @~C/@@;??/@@\x1b\\"
+	if load != want {
+		t.Errorf("wrong load sequence: %q", load)
+	}
+	if seqs['┼'] != "\x1b( @!\x1b(B" || seqs['╳'] != "\x1b( @\"\x1b(B" {
+		t.Errorf("wrong rune sequences: %q", seqs)
+	}
+
+	if _, _, err = (&SoftFont{Width: 20, Height: 10, Glyphs: f.Glyphs}).encode(); err != ErrInvalidSoftFont {
+		t.Errorf("oversized glyphs accepted")
+	}
+}
@@ -14,9 +14,24 @@
 
 package tcell
 
+import (
+	"os"
+	"time"
+)
+
 // Screen represents the physical (or emulated) screen.
 // This can be a terminal window or a physical console.  Platforms implement
 // this differently.
+//
+// All Screen methods are safe to call concurrently from multiple goroutines.
+// Each call is atomic with respect to the others, so for example a Show will
+// never observe a cell that is half updated by SetContent.  Note however that
+// nothing orders calls made from different goroutines; if one goroutine draws
+// a frame using several SetContent calls while another calls Show, that Show
+// may display part of the frame.  Applications that need whole frames to be
+// displayed should draw and show them from a single goroutine (or provide
+// their own locking around the drawing of a frame).  The CellBuffer type,
+// which screens use internally, is not safe for concurrent use.
 type Screen interface {
 	// Init initializes the screen for use.
 	Init() error
@@ -32,6 +47,15 @@ type Screen interface {
 	// Fill fills the screen with the given character and style.
 	Fill(rune, Style)
 
+	// FillFunc fills the region at x, y of the given width and height
+	// (clipped to the screen) with the runes and styles returned by f for
+	// each cell, such as for gradients and other procedural patterns.
+	// The function is called for all of the cells first, and the results
+	// are then stored together, which is much faster than calling
+	// SetContent for each.  A wide rune covers the cell to its right, for
+	// which f is not called.
+	FillFunc(x, y, width, height int, f FillFunc)
+
 	// SetCell is an older API, and will be removed.  Please use
 	// SetContent instead; SetCell is implemented in terms of SetContent.
 	SetCell(x int, y int, style Style, ch ...rune)
@@ -58,8 +82,106 @@ type Screen interface {
 	// and attempts to place character at next cell to the right will have
 	// undefined effects.  Wide runes that are printed in the
 	// last column will be replaced with a single width space on output.
+	//
+	// A tab ('\t') fills the cells up to the next tab stop with blanks.
+	// Use NextTabStop to find the column where drawing should continue.
 	SetContent(x int, y int, primary rune, combining []rune, style Style)
 
+	// SetTabStops sets the columns of the tab stops used when a tab is
+	// drawn with SetContent.  Past the last column given, stops continue
+	// at the same interval as the last two.  The default (restored by
+	// passing nil) is a tab stop every eight columns.
+	SetTabStops(stops []int)
+
+	// NextTabStop returns the column of the first tab stop after x.
+	NextTabStop(x int) int
+
+	// Scroll moves the contents of the given rectangle up by n rows, or
+	// down if n is negative, and blanks the uncovered rows.  Where the
+	// terminal supports scroll regions (and, for regions narrower than
+	// the screen, left and right margins) it is asked to do the scrolling
+	// itself, which is much faster than redrawing the region.  (This
+	// happens at once, but the uncovered rows are not drawn, and any
+	// other changes not displayed, until Show is called.)
+	Scroll(x, y, width, height, n int)
+
+	// SetScrollback sets the number of rows scrolled off the top of the
+	// screen with Scroll that the screen keeps, discarding the oldest.
+	// This is for applications that run without the alternate screen,
+	// whose scrolled output goes to the terminal's own scrollback.  The
+	// default is zero, which keeps none.
+	SetScrollback(lines int)
+
+	// Scrollback returns a copy of the rows kept from the scrollback,
+	// oldest first, so that they can be drawn again (with GetContent) or
+	// exported (with ToANSI, ToHTML and so forth).
+	Scrollback() *CellBuffer
+
+	// SetResizePolicy sets what is done with the contents of the screen
+	// when the size of the terminal changes.  The default is
+	// ResizeTruncate.
+	SetResizePolicy(policy ResizePolicy)
+
+	// SetLineWrap marks row y as continuing on the next row, as part of
+	// one logical line, or not.  This is used to join the rows again with
+	// ResizeReflow.  The marks are cleared by Clear and Fill, and move
+	// with the rows when the full width of the screen is scrolled.
+	SetLineWrap(y int, wrapped bool)
+
+	// SetLineMode sets row y to be displayed with double width, or double
+	// width and height, characters.  Such rows only display their first
+	// half (width/2 columns).  For double height text, the same content
+	// should be drawn on two rows, the first using LineDoubleHeightTop
+	// and the second LineDoubleHeightBottom.  This is ignored unless the
+	// terminal is known to support it (DEC VT terminals and xterm).
+	SetLineMode(y int, mode LineMode)
+
+	// GetLineMode returns the mode of row y, which will be LineNormal
+	// unless it was changed by SetLineMode.
+	GetLineMode(y int) LineMode
+
+	// SetRowStyle sets a style for row y that is applied beneath the
+	// content of its cells when they are displayed, such as to highlight
+	// the line with the cursor, or the lines added by a diff, without
+	// changing the style of every cell.  Where a cell has no foreground
+	// or background color of its own, that of the row is used (ahead of
+	// the screen's default style), and the attributes of the row are
+	// added to those of every cell.  The style stays with the row, and
+	// is not moved by scrolling or cleared by Clear; setting StyleDefault
+	// removes it.
+	SetRowStyle(y int, style Style)
+
+	// GetRowStyle returns the style set for row y by SetRowStyle.
+	GetRowStyle(y int) Style
+
+	// SetGuides sets guides to draw over the content, such as a color
+	// column, or markers between panes; see Guide.  They are drawn
+	// beneath the highlights and the selection.  Passing nil removes
+	// them.  This takes effect on the next Show.
+	SetGuides(guides []Guide)
+
+	// ShowGuides shows or hides the guides, without forgetting them, so
+	// that they can be toggled.  They are shown unless this is called.
+	ShowGuides(on bool)
+
+	// SetMagnifier displays part of the screen enlarged by factor (2 or
+	// 3), for users with low vision; 1 restores the normal display.  The
+	// part enlarged moves to follow the cursor, which is shown in reverse
+	// video.  Where the terminal has double size lines (see SetLineMode)
+	// they are used to enlarge text by a factor of two; otherwise each
+	// character is drawn with block characters, from a small font for
+	// ASCII.  Only the display is changed: the content and coordinates
+	// used by the application, including those of mouse events, are
+	// the same as ever.
+	SetMagnifier(factor int)
+
+	// SetRenderFilter sets a function that transforms the style of every
+	// cell as it is displayed, such as HighContrastFilter,
+	// MonochromeFilter, or one returned by ColorBlindFilter, so that
+	// the display can be adapted without changing the application.  The
+	// content is unchanged.  Passing nil removes the filter.
+	SetRenderFilter(f RenderFilter)
+
 	// SetStyle sets the default style to use when clearing the screen
 	// or when StyleDefault is specified.  If it is also StyleDefault,
 	// then whatever system/terminal default is relevant will be used.
@@ -74,6 +196,63 @@ type Screen interface {
 	// ShowCursor(-1, -1).sim
 	HideCursor()
 
+	// SetSoftCursor draws the cursor in software, as part of the screen
+	// content, rather than using the terminal's own cursor.  The cell
+	// under the cursor is displayed with the given style laid over its
+	// own: colors other than ColorDefault replace the cell's colors, and
+	// attributes are toggled, so that StyleDefault.Reverse(true) shows the
+	// cell in inverse video.  If r is not zero, it is displayed in place
+	// of the cell's content; it should be a single cell wide.  The cell
+	// buffer is not modified.  The cursor is still positioned with
+	// ShowCursor, and takes effect on the next Show.  Calling this with
+	// StyleDefault and 0 restores the terminal's cursor.
+	SetSoftCursor(style Style, r rune)
+
+	// SetCursors sets the secondary cursors, which are drawn in software
+	// (as for SetSoftCursor) in addition to the primary cursor set by
+	// ShowCursor.  The screen keeps them, so they are drawn again by
+	// every Show or Sync until replaced.  Passing nil removes them all.
+	// Where cursors overlap, the primary cursor is drawn, and otherwise
+	// the earliest in the list.
+	SetCursors(cursors []Cursor)
+
+	// SetSelection sets the selected ranges of cells, which are displayed
+	// with style laid over their own styles, in the same way as for
+	// SetSoftCursor: StyleDefault.Reverse(true) shows them in inverse
+	// video.  The contents of the cells are not modified.  Passing nil
+	// removes the selection.  This takes effect on the next Show.
+	SetSelection(style Style, ranges []Selection)
+
+	// SaveState saves the current contents of the screen, and returns an
+	// id that can later be passed to RestoreState.  This lets a modal
+	// dialog, for example, put back exactly what was underneath it,
+	// without the application having to draw it again.  Only the cell
+	// contents are saved.
+	SaveState() int
+
+	// RestoreState restores the contents saved by SaveState, which then
+	// take effect on the next Show.  Each saved state can be restored only
+	// once, and restoring one discards any saved after it, so that nested
+	// dialogs can be handled as a stack.  ErrNoSuchState is returned if
+	// the id is unknown.  If the screen has been resized, only the part
+	// common to both sizes is restored.
+	RestoreState(id int) error
+
+	// SetHighlights sets ranges of cells to highlight, such as the
+	// matches found by Find.  These are displayed in the same way as the
+	// selection, but independently of it; where both apply, the
+	// selection is drawn over the highlight.  Passing nil removes them.
+	SetHighlights(style Style, ranges []Selection)
+
+	// Find searches the screen content; see CellBuffer.Find.
+	Find(pattern string, opts FindOptions) ([]Selection, error)
+
+	// SelectedText returns the text of the selected cells.  Rows are
+	// separated by newlines, as are separate ranges, and trailing blanks
+	// are removed from each row.  A wide character is included if either
+	// of its cells is selected.
+	SelectedText() string
+
 	// SetCursorStyle is used to set the cursor style.  If the style
 	// is not supported (or cursor styles are not supported at all),
 	// then this will have no effect.
@@ -132,6 +311,30 @@ type Screen interface {
 	// DisableMouse disables the mouse.
 	DisableMouse()
 
+	// SetMouseShape sets the shape of the mouse pointer, using OSC 22,
+	// for example to show an I-beam over text or a hand over links.
+	// Terminals that do not support this ignore it.  The shape is reset
+	// when the screen is finalized or suspended.
+	SetMouseShape(shape MouseShape)
+
+	// SetWheelKeys arranges for the mouse wheel to be reported as n
+	// presses of the Up or Down key, while the mouse is not enabled.
+	// Other mouse events are discarded.  This lets applications such as
+	// pagers, which do not otherwise use the mouse, be scrolled with the
+	// wheel, as many terminals do by themselves on the alternate screen.
+	// Zero (the default) turns this off.
+	SetWheelKeys(n int)
+
+	// EnableKeypad places the numeric keypad in application mode (DECKPAM),
+	// and reports its keys as distinct key codes (KeyKP0 through KeyKP9,
+	// KeyKPEnter, KeyKPPlus, and so forth) rather than as digits, cursor
+	// keys, or Enter.  Many terminals only honor this when Num Lock is off,
+	// or when specifically configured to do so.
+	EnableKeypad()
+
+	// DisableKeypad returns the numeric keypad to its default mode.
+	DisableKeypad()
+
 	// EnablePaste enables bracketed paste mode, if supported.
 	EnablePaste()
 
@@ -146,9 +349,28 @@ type Screen interface {
 
 	// Colors returns the number of colors.  All colors are assumed to
 	// use the ANSI color map.  If a terminal is monochrome, it will
-	// return 0.
+	// return 0.  If it supports 24-bit color, it returns 1 << 24,
+	// regardless of the size of its palette; ColorModel gives the details.
 	Colors() int
 
+	// ColorModel describes the colors available.  The palette is the
+	// number of palette (indexed) colors, which is zero for a monochrome
+	// display.  Truecolor is true if 24-bit RGB colors can be displayed
+	// directly; otherwise they are mapped to the nearest palette color.
+	// Defaults is true if the display's own default colors can be
+	// selected (with ColorDefault or ColorReset), rather than just
+	// being whatever colors were there before.
+	ColorModel() (palette int, truecolor bool, defaults bool)
+
+	// OnCellChange registers a function to be called with the position of
+	// each cell in the region at x, y of the given width and height that
+	// is redrawn, for mirroring the screen elsewhere, or narrating changes
+	// for accessibility.  The functions are called by Show and Sync, after
+	// drawing, with the cells of each region from left to right and top
+	// to bottom; they may use the screen (GetContent gives the new contents
+	// of the cell).  The returned function removes the registration.
+	OnCellChange(x, y, width, height int, f CellChangeHandler) (cancel func())
+
 	// Show makes all the content changes made using SetContent() visible
 	// on the display.
 	//
@@ -194,6 +416,46 @@ type Screen interface {
 	// 7-bit ASCII, since other characters may not display everywhere.
 	RegisterRuneFallback(r rune, subst string)
 
+	// RegisterRuneFallbacks registers each of the fallbacks in the map, as
+	// RegisterRuneFallback does.  This is convenient for registering
+	// the fallback profiles, such as FallbacksBoxDrawing, in one call.
+	RegisterRuneFallbacks(fallbacks map[rune]string)
+
+	// LoadSoftFont downloads custom glyphs to the terminal, which are then
+	// used to display the runes in the font.  This is only supported by
+	// DEC VT220 and later terminals; ErrNotSupported is returned for
+	// others.  Loading a new font replaces the previous one, and passing
+	// nil stops using it.
+	LoadSoftFont(font *SoftFont) error
+
+	// SetStatusLine shows text in a status line, in the given style.  On
+	// terminals with a real status line (those with the terminfo tsl
+	// capability, and DEC VT320 and later) it is used, and the style is
+	// ignored.  Otherwise it is emulated using the bottom row of the
+	// screen, which reduces the size of the screen by one row (and a
+	// resize event is posted).  An empty string removes the status line.
+	SetStatusLine(text string, style Style)
+
+	// Query sends seq, which must be a single control sequence, to the
+	// terminal, and waits for a reply that match accepts, returning its
+	// payload.  Several queries may be outstanding at once; each reply is
+	// given to the oldest query that it matches.  Replies are not seen by
+	// the OnUnknownSequence handler, or delivered as events.  If there is
+	// no reply within the timeout, ErrQueryTimeout is returned, and if
+	// the reply is unreasonably long, ErrQueryTooLong.  Query must not be
+	// called from the goroutine that calls PollEvent, if that is the only
+	// one, as the reply is processed alongside other input.  Screens that
+	// are not terminals return ErrNotSupported.
+	Query(seq string, match SequenceMatcher, timeout time.Duration) (string, error)
+
+	// TerminalID returns the terminal emulator's name and version, as it
+	// reports them (to XTVERSION, or failing that, to DA2) shortly after
+	// the screen is started.  Known quirks of the terminal are then
+	// applied automatically.  The name is empty if the terminal has not
+	// (or not yet) identified itself, and always for the Windows console
+	// and the simulation.
+	TerminalID() TerminalID
+
 	// UnregisterRuneFallback unmaps a replacement.  It will unmap
 	// the implicit ASCII replacements for alternate characters as well.
 	// When an unmapped char needs to be displayed, but no suitable
@@ -213,6 +475,15 @@ type Screen interface {
 	// one that is visually indistinguishable from the one requested.
 	CanDisplay(r rune, checkFallbacks bool) bool
 
+	// HasGlyphs returns true if the terminal's font appears to have all
+	// of the glyphs in the given set (which may combine several sets).
+	// Applications can use this to choose between fancy glyphs, such as
+	// Powerline separators or Nerd Font icons, and ASCII alternatives.
+	// As terminals cannot report what their font contains, the Powerline
+	// and Nerd Font sets are only reported if the user has indicated that
+	// they are present, using the TCELL_GLYPHS environment variable.
+	HasGlyphs(set GlyphSet) bool
+
 	// Resize does nothing, since it's generally not possible to
 	// ask a screen to resize, but it allows the Screen to implement
 	// the View interface.
@@ -231,19 +502,49 @@ type Screen interface {
 	// Suspend pauses input and output processing.  It also restores the
 	// terminal settings to what they were when the application started.
 	// This can be used to, for example, run a sub-shell.
+	//
+	// Every mode that was set in the terminal (the alternate screen,
+	// keypad, mouse reporting, bracketed paste, and so forth) is undone,
+	// and set again by Resume, including any changes made while suspended.
+	// Calls nest; only the outermost Suspend and Resume have any effect.
 	Suspend() error
 	
 	// SuspendNoClear suspends as Suspend does, but does not clear the 
 	// screen.
 	SuspendNoClear() error
 
-	// Resume resumes after Suspend().
+	// Resume resumes after Suspend(), or SuspendNoClear().
 	Resume() error
 
 	// Beep attempts to sound an OS-dependent audible alert and returns an error
 	// when unsuccessful.
 	Beep() error
 
+	// Notify asks the terminal to show a desktop notification, so that
+	// the user can be alerted (for example, when a long job completes)
+	// while the window is not in view.  This uses OSC 9, OSC 777 or
+	// OSC 99, depending on the terminal; ErrNotSupported is returned if
+	// the terminal is not known to support any of them.
+	Notify(title, body string) error
+
+	// SetProgress reports the progress of a long task to the terminal,
+	// which may show it on the taskbar button of its window, using
+	// OSC 9;4.  Windows Terminal and ConEmu support this.  The percentage
+	// is ignored for ProgressNone and ProgressIndeterminate.  Any progress
+	// shown is removed when the screen is finalized.  ErrNotSupported is
+	// returned if the terminal is not known to support this.
+	SetProgress(state ProgressState, percent int) error
+
+	// SetBellPolicy determines what Beep does: sound the bell (BellAudible,
+	// the default), flash the screen (BellVisual), or nothing (BellNone).
+	SetBellPolicy(policy BellPolicy)
+
+	// SetVisualBell sets how long the visual bell flashes for, and the
+	// region that it flashes (in reverse video).  If w or h is not positive
+	// the whole screen flashes.  A duration that is not positive selects
+	// the default, which is 100ms.
+	SetVisualBell(d time.Duration, x, y, w, h int)
+
 	// SetSize attempts to resize the window.  It also invalidates the cells and
 	// calls the resize function.  Note that if the window size is changed, it will
 	// not be restored upon application exit.
@@ -252,6 +553,70 @@ type Screen interface {
 	// does not support application-initiated resizing, whereas the legacy terminal does.
 	// Also, some emulators can support this but may have it disabled by default.
 	SetSize(int, int)
+
+	// HasWindowOps returns true if the terminal appears to support the
+	// window manipulation operations below.  As with SetSize, support is
+	// inferred, and many terminals (including xterm in its default
+	// configuration) will quietly ignore some or all of these requests.
+	HasWindowOps() bool
+
+	// IconifyWindow iconifies (minimizes) the window if true, or
+	// restores it from the iconified state if false.
+	IconifyWindow(bool)
+
+	// MaximizeWindow maximizes the window if true, or restores it
+	// to its previous size if false.
+	MaximizeWindow(bool)
+
+	// RaiseWindow raises the window to the front of the stacking order.
+	RaiseWindow()
+
+	// SetWindowPosition moves the window so that its top left corner
+	// is at the given position, in pixels, relative to the desktop.
+	SetWindowPosition(x, y int)
+
+	// QueryWindowPosition asks for the current window position.  If the
+	// terminal answers, the position is delivered as an EventWindowPosition.
+	QueryWindowPosition()
+
+	// FontMetrics returns the size in pixels of a character cell, and the
+	// dots per inch of the display, for scaling images and the like.  Any
+	// of these that cannot be obtained are returned as zero; currently
+	// they are only known for the Windows console, and even there the
+	// font size is not known in Windows Terminal.
+	FontMetrics() (width, height, dpi int)
+
+	// DisableSignalHandling prevents tcell from installing its own signal
+	// handlers (on UNIX systems, for SIGWINCH).  This is for applications
+	// that manage signals themselves; such applications should forward
+	// the relevant signals using HandleSignal.  This takes effect when
+	// the terminal is next started, so it should be called before Init
+	// (or before Resume).
+	DisableSignalHandling()
+
+	// HandleSignal notifies the screen that the application has received
+	// a signal.  Signals that indicate the terminal may have been resized
+	// (SIGWINCH and SIGCONT) cause the size to be checked and, if it has
+	// changed, an EventResize to be posted.  Other signals are ignored.
+	HandleSignal(os.Signal)
+
+	// EmitRaw sends an escape sequence directly to the terminal, for use
+	// with terminal features that tcell does not model.  The string must
+	// contain exactly one complete sequence (CSI, OSC, DCS, APC, PM, or a
+	// simple escape), otherwise ErrInvalidSequence is returned.  Any pending
+	// content is drawn first, so the sequence is ordered after it, and
+	// afterwards no assumptions are made about the cursor position or
+	// current attributes.  Screens that cannot send escape sequences
+	// silently discard it.
+	EmitRaw(seq string) error
+
+	// OnUnknownSequence registers a function to be called with complete
+	// control sequences received from the terminal that tcell does not
+	// understand (for example, replies to queries sent with EmitRaw).
+	// Such sequences are not delivered as key events.  The function is
+	// called from the input goroutine, and should return promptly.
+	// Passing nil removes the handler, and the sequences are discarded.
+	OnUnknownSequence(UnknownSequenceHandler)
 }
 
 // NewScreen returns a default Screen suitable for the user's terminal
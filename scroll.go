@@ -0,0 +1,183 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Scrolling a region of the screen can be done by the terminal itself,
+// which is much cheaper than redrawing it.  The region's top and bottom
+// are set with DECSTBM, and on terminals that support it (the VT420 and
+// later, and XTerm itself), its left and right sides with DECSLRM.  Most
+// terminals that call themselves xterm lack them, so they are only used
+// for XTerm once it has identified itself, or when TCELL_MARGINS is
+// "enable".  Terminals without left and right margins can only scroll
+// regions that are the full width of the screen; other regions are
+// scrolled by redrawing.
+
+const (
+	decLRMMOn  = "\x1b[?69h" // enable left/right margins (DECLRMM)
+	decLRMMOff = "\x1b[?69l"
+	decIND     = "\x1bD" // index: move down, scrolling at the bottom margin
+	decRI      = "\x1bM" // reverse index: move up, scrolling at the top
+	decSTBMOff = "\x1b[r"
+	decSLRMOff = "\x1b[s"
+)
+
+// hasLeftRightMargins returns true if the terminal can set left and
+// right margins.
+func (t *tScreen) hasLeftRightMargins() bool {
+	switch t.getenv("TCELL_MARGINS") {
+	case "enable":
+		return true
+	case "disable":
+		return false
+	}
+	name := t.ti.Name
+	for _, vt := range []string{"vt420", "vt510", "vt520", "vt525"} {
+		if strings.HasPrefix(name, vt) {
+			return true
+		}
+	}
+	return false
+}
+
+// scroll moves the contents of the rectangle at x, y with the given size
+// up by n rows (or down, if n is negative).  Rows that are uncovered are
+// blanked.  If onScreen is true, then the terminal has scrolled its
+// display in the same way, so the record of what is displayed is moved
+// as well; otherwise the moved cells will be redrawn.
+func (cb *CellBuffer) scroll(x, y, w, h, n int, onScreen bool, style Style) {
+	if x < 0 {
+		w += x
+		x = 0
+	}
+	if y < 0 {
+		h += y
+		y = 0
+	}
+	if x+w > cb.w {
+		w = cb.w - x
+	}
+	if y+h > cb.h {
+		h = cb.h - y
+	}
+	if w <= 0 || h <= 0 || n == 0 {
+		return
+	}
+	move := func(row int) {
+		src := row + n
+		for col := x; col < x+w; col++ {
+			dc := &cb.cells[row*cb.w+col]
+			if src < y || src >= y+h {
+				dc.currMain = ' '
+				dc.currComb = nil
+				dc.currStyle = style
+				dc.width = 1
+				dc.lastMain = 0
+				continue
+			}
+			sc := &cb.cells[src*cb.w+col]
+			dc.currMain, dc.currComb, dc.currStyle, dc.width =
+				sc.currMain, sc.currComb, sc.currStyle, sc.width
+			if onScreen {
+				dc.lastMain, dc.lastComb, dc.lastStyle =
+					sc.lastMain, sc.lastComb, sc.lastStyle
+			} else {
+				dc.lastMain = 0
+			}
+		}
+	}
+	if n > 0 {
+		for row := y; row < y+h; row++ {
+			move(row)
+		}
+	} else {
+		for row := y + h - 1; row >= y; row-- {
+			move(row)
+		}
+	}
+	if x == 0 && w == cb.w {
+		// logical lines move with the rows
+		wraps := make([]bool, h)
+		for row := range wraps {
+			wraps[row] = row+n >= 0 && row+n < h && cb.Wrapped(y+row+n)
+		}
+		for row, wrapped := range wraps {
+			cb.SetWrapped(y+row, wrapped)
+		}
+	}
+}
+
+func (t *tScreen) Scroll(x, y, width, height, n int) {
+	t.Lock()
+	defer t.Unlock()
+	if t.fini {
+		return
+	}
+	t.history.save(&t.cells, x, y, width, height, n)
+	hw := t.running && t.hardScroll(x, y, width, height, n)
+	t.cells.scroll(x, y, width, height, n, hw, StyleDefault)
+}
+
+// hardScroll asks the terminal to scroll the region, and returns true
+// if it could.
+func (t *tScreen) hardScroll(x, y, w, h, n int) bool {
+	if x < 0 || y < 0 || w <= 0 || h <= 0 || x+w > t.w || y+h > t.h || n == 0 {
+		return false
+	}
+	if n >= h || -n >= h {
+		// everything is replaced anyway
+		return false
+	}
+	for row := y; row < y+h; row++ {
+		if row < len(t.lines) && t.lines[row] != LineNormal {
+			return false
+		}
+	}
+	margins := x != 0 || w != t.w
+	if margins && !t.hasLeftRightMargins() {
+		return false
+	}
+	if !isVTName(t.ti.Name) {
+		return false
+	}
+
+	// Uncovered rows are filled with the current background color.
+	t.sendStyle(t.style)
+	t.TPuts("\x1b[" + strconv.Itoa(y+1) + ";" + strconv.Itoa(y+h) + "r")
+	if margins {
+		t.TPuts(decLRMMOn)
+		t.TPuts("\x1b[" + strconv.Itoa(x+1) + ";" + strconv.Itoa(x+w) + "s")
+	}
+	seq := decIND
+	row := y + h - 1
+	if n < 0 {
+		seq = decRI
+		row = y
+		n = -n
+	}
+	t.TPuts(t.ti.TGoto(x, row))
+	t.TPuts(strings.Repeat(seq, n))
+	if margins {
+		t.TPuts(decSLRMOff)
+		t.TPuts(decLRMMOff)
+	}
+	t.TPuts(decSTBMOff)
+	t.cx, t.cy = -1, -1
+	return true
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestScroll(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(4, 4)
+
+	for y, row := range []string{"abcd", "efgh", "ijkl", "mnop"} {
+		for x, r := range row {
+			s.SetContent(x, y, r, nil, StyleDefault)
+		}
+	}
+	// scroll the middle two columns of the bottom three rows up
+	s.Scroll(1, 1, 2, 3, 1)
+	s.Show()
+	b, _, _ := s.GetContents()
+	got := ""
+	for _, c := range b {
+		got += string(c.Runes)
+	}
+	if got != "abcdejkhinolm  p" {
+		t.Errorf("wrong contents after scroll up: %q", got)
+	}
+
+	s.Scroll(0, 0, 4, 4, -2)
+	s.Show()
+	b, _, _ = s.GetContents()
+	got = ""
+	for _, c := range b {
+		got += string(c.Runes)
+	}
+	if got != "        abcdejkh" {
+		t.Errorf("wrong contents after scroll down: %q", got)
+	}
+}
+
+func TestScrollOnScreen(t *testing.T) {
+	var cb CellBuffer
+	cb.Resize(2, 3)
+	for y, r := range "abc" {
+		cb.SetContent(0, y, r, nil, StyleDefault)
+		cb.SetContent(1, y, r, nil, StyleDefault)
+		cb.SetDirty(0, y, false)
+		cb.SetDirty(1, y, false)
+	}
+	cb.scroll(0, 0, 2, 3, 1, true, StyleDefault)
+	if cb.Dirty(0, 0) || cb.Dirty(1, 1) {
+		t.Errorf("cells moved by the terminal should not be dirty")
+	}
+	if !cb.Dirty(0, 2) {
+		t.Errorf("uncovered cells should be dirty")
+	}
+	if r, _, _, _ := cb.GetContent(0, 1); r != 'c' {
+		t.Errorf("wrong content after scroll: %q", r)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// An application that runs without the alternate screen, such as one that
+// prints a log above a prompt, scrolls its output up with Scroll.  Rows
+// that scroll off the top of the screen go to the terminal's scrollback,
+// where the application can no longer see them; if asked to, the screen
+// keeps its own copy of them, so that they can be drawn again (say after
+// a resize) or exported.
+
+// scrollback holds the rows scrolled off the top of a screen.
+type scrollback struct {
+	max  int
+	rows [][]cell
+}
+
+// setMax sets the number of rows to keep, discarding the oldest.
+func (sb *scrollback) setMax(n int) {
+	if n < 0 {
+		n = 0
+	}
+	sb.max = n
+	sb.trim()
+}
+
+func (sb *scrollback) trim() {
+	if extra := len(sb.rows) - sb.max; extra > 0 {
+		sb.rows = append([][]cell{}, sb.rows[extra:]...)
+	}
+}
+
+// save records the rows that a scroll of the given region by n rows is
+// about to move off the top of the screen.  Only regions that span the
+// width of the screen from its top row push rows off it.
+func (sb *scrollback) save(cb *CellBuffer, x, y, w, h, n int) {
+	if sb.max == 0 || n <= 0 || x > 0 || y > 0 || x+w < cb.w || y+h <= 0 {
+		return
+	}
+	h += y
+	if h > cb.h {
+		h = cb.h
+	}
+	if n > h {
+		n = h
+	}
+	var rows [][]cell
+	for row := 0; row < n; row++ {
+		saved := make([]cell, cb.w)
+		for col := range saved {
+			c := &cb.cells[row*cb.w+col]
+			saved[col] = cell{
+				currMain:  c.currMain,
+				currComb:  c.currComb,
+				currStyle: c.currStyle,
+				width:     c.width,
+			}
+		}
+		rows = append(rows, saved)
+	}
+	sb.add(rows)
+}
+
+// add records rows, which are no longer on the screen.
+func (sb *scrollback) add(rows [][]cell) {
+	if sb.max == 0 {
+		return
+	}
+	sb.rows = append(sb.rows, rows...)
+	sb.trim()
+}
+
+// buffer returns a copy of the rows, oldest first, in a CellBuffer as
+// wide as the widest of them.
+func (sb *scrollback) buffer() *CellBuffer {
+	w := 0
+	for _, row := range sb.rows {
+		if len(row) > w {
+			w = len(row)
+		}
+	}
+	cb := &CellBuffer{}
+	cb.Resize(w, len(sb.rows))
+	cb.Fill(' ', StyleDefault)
+	for y, row := range sb.rows {
+		copy(cb.cells[y*w:], row)
+	}
+	return cb
+}
+
+func (t *tScreen) SetScrollback(lines int) {
+	t.Lock()
+	t.history.setMax(lines)
+	t.Unlock()
+}
+
+func (t *tScreen) Scrollback() *CellBuffer {
+	t.Lock()
+	defer t.Unlock()
+	return t.history.buffer()
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestScrollback(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(4, 3)
+	s.SetScrollback(3)
+	for i, line := range []string{"one", "two", "six"} {
+		for x, r := range line {
+			s.SetContent(x, i, r, nil, StyleDefault.Bold(i == 0))
+		}
+	}
+	s.Scroll(0, 0, 4, 3, 1)
+	s.Scroll(1, 0, 3, 3, 1) // not full width; nothing is kept
+	s.Scroll(0, 0, 4, 2, 5)
+	cb := s.Scrollback()
+	if w, h := cb.Size(); w != 4 || h != 3 {
+		t.Fatalf("wrong size: %dx%d", w, h)
+	}
+	var text []rune
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			r, _, _, _ := cb.GetContent(x, y)
+			text = append(text, r)
+		}
+	}
+	if string(text) != "one tix s   " {
+		t.Errorf("wrong scrollback: %q", string(text))
+	}
+	if _, _, st, _ := cb.GetContent(0, 0); st != StyleDefault.Bold(true) {
+		t.Errorf("wrong style: %v", st)
+	}
+	s.SetScrollback(1)
+	if _, h := s.Scrollback().Size(); h != 1 {
+		t.Errorf("scrollback not trimmed: %d", h)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strings"
+)
+
+// Selection is a range of selected cells.  Normally this runs from the
+// start to the end (inclusive) in reading order, the way text is selected:
+// the rest of the first row, any rows in between, and the beginning of the
+// last row.  If Block is true, it is instead the rectangle with the start
+// and end at opposite corners.  The start and end may be given either way
+// around.
+type Selection struct {
+	StartX int
+	StartY int
+	EndX   int
+	EndY   int
+	Block  bool
+}
+
+// normalize returns the selection with the start before the end.
+func (sel Selection) normalize() Selection {
+	if sel.Block {
+		if sel.StartX > sel.EndX {
+			sel.StartX, sel.EndX = sel.EndX, sel.StartX
+		}
+		if sel.StartY > sel.EndY {
+			sel.StartY, sel.EndY = sel.EndY, sel.StartY
+		}
+	} else if sel.StartY > sel.EndY || (sel.StartY == sel.EndY && sel.StartX > sel.EndX) {
+		sel.StartX, sel.EndX = sel.EndX, sel.StartX
+		sel.StartY, sel.EndY = sel.EndY, sel.StartY
+	}
+	return sel
+}
+
+// span returns the columns of row y that are selected, for a screen w
+// cells wide.  If none are, then a > b.
+func (sel Selection) span(y, w int) (a, b int) {
+	sel = sel.normalize()
+	if y < sel.StartY || y > sel.EndY {
+		return 0, -1
+	}
+	if sel.Block {
+		return sel.StartX, sel.EndX
+	}
+	a, b = 0, w-1
+	if y == sel.StartY {
+		a = sel.StartX
+	}
+	if y == sel.EndY {
+		b = sel.EndX
+	}
+	return a, b
+}
+
+// Contains returns true if the cell at x, y is selected, on a screen w
+// cells wide.
+func (sel Selection) Contains(x, y, w int) bool {
+	a, b := sel.span(y, w)
+	return x >= a && x <= b
+}
+
+// selection tracks the selection for a screen.
+type selection struct {
+	style  Style
+	ranges []Selection
+	shown  []Selection // as of the last draw
+	dirty  bool
+}
+
+// set replaces the selection.
+func (sl *selection) set(style Style, ranges []Selection) {
+	sl.style = style
+	sl.ranges = append([]Selection{}, ranges...)
+	sl.dirty = true
+}
+
+// prepare is called before drawing.  If the selection has changed, it marks
+// the cells of both the old and new selections dirty.
+func (sl *selection) prepare(cb *CellBuffer) {
+	if !sl.dirty {
+		return
+	}
+	w, h := cb.Size()
+	for _, list := range [][]Selection{sl.shown, sl.ranges} {
+		for _, sel := range list {
+			for y := 0; y < h; y++ {
+				a, b := sel.span(y, w)
+				if a < 0 {
+					a = 0
+				}
+				if b >= w {
+					b = w - 1
+				}
+				for x := a; x <= b; x++ {
+					cb.SetDirty(x, y, true)
+				}
+			}
+		}
+	}
+	sl.shown = sl.ranges
+	sl.dirty = false
+}
+
+// apply returns the style to display for the cell at x, y.
+func (sl *selection) apply(x, y, w int, style Style) Style {
+	for _, sel := range sl.shown {
+		if sel.Contains(x, y, w) {
+			return style.overlay(sl.style)
+		}
+	}
+	return style
+}
+
+// selectionText returns the text of the selected cells.  Rows are
+// separated by newlines, and trailing blanks on each row are removed.
+// A wide character is included if any of its cells are selected.
+func (cb *CellBuffer) selectionText(ranges []Selection) string {
+	var sb strings.Builder
+	for i, sel := range ranges {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sel = sel.normalize()
+		for y := sel.StartY; y <= sel.EndY; y++ {
+			if y < 0 || y >= cb.h {
+				continue
+			}
+			a, b := sel.span(y, cb.w)
+			var line []rune
+			for x := 0; x < cb.w && x <= b; {
+				mainc, combc, _, width := cb.GetContent(x, y)
+				if width < 1 {
+					width = 1
+				}
+				if x+width-1 >= a {
+					if mainc == 0 {
+						mainc = ' '
+					}
+					line = append(line, mainc)
+					line = append(line, combc...)
+				}
+				x += width
+			}
+			sb.WriteString(strings.TrimRight(string(line), " "))
+			if y < sel.EndY {
+				sb.WriteByte('\n')
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestSelection(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(6, 3)
+	for y, line := range []string{"hello", "wor", "a"} {
+		for x, r := range line {
+			s.SetContent(x, y, r, nil, StyleDefault)
+		}
+	}
+	s.SetContent(2, 2, '世', nil, StyleDefault)
+	sel := StyleDefault.Reverse(true)
+	s.SetSelection(sel, []Selection{{StartX: 1, StartY: 1, EndX: 3, EndY: 0}})
+	s.Show()
+	b, w, _ := s.GetContents()
+	for i, c := range b {
+		x, y := i%w, i/w
+		want := (y == 0 && x >= 3) || (y == 1 && x <= 1)
+		if (c.Style == sel) != want {
+			t.Errorf("cell %d,%d: wrong style %v", x, y, c.Style)
+		}
+	}
+	if r, _, st, _ := s.GetContent(3, 0); r != 'l' || st != StyleDefault {
+		t.Errorf("cell buffer modified")
+	}
+	if txt := s.SelectedText(); txt != "lo\nwo" {
+		t.Errorf("wrong text %q", txt)
+	}
+
+	// The second cell of a wide character selects it.
+	s.SetSelection(sel, []Selection{{StartX: 3, StartY: 2, EndX: 4, EndY: 2, Block: true}})
+	if txt := s.SelectedText(); txt != "世" {
+		t.Errorf("wrong text %q", txt)
+	}
+	s.SetSelection(sel, []Selection{{StartX: 0, StartY: 0, EndX: 1, EndY: 2, Block: true}})
+	if txt := s.SelectedText(); txt != "he\nwo\na" {
+		t.Errorf("wrong block text %q", txt)
+	}
+
+	// Selections reaching far beyond the screen are clipped to it.
+	s.SetSelection(sel, []Selection{{StartX: -1 << 30, StartY: 0, EndX: 1 << 30, EndY: 0, Block: true}})
+	s.Show()
+	if _, _, st, _ := s.GetContent(5, 0); st != StyleDefault {
+		t.Errorf("cell buffer modified")
+	}
+	b, _, _ = s.GetContents()
+	if b[0].Style != sel || b[5].Style != sel || b[6].Style == sel {
+		t.Errorf("wrong clipped selection")
+	}
+
+	s.SetSelection(sel, nil)
+	s.Show()
+	b, _, _ = s.GetContents()
+	for i, c := range b {
+		if c.Style != StyleDefault {
+			t.Errorf("cell %d still selected", i)
+		}
+	}
+}
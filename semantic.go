@@ -0,0 +1,94 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strconv"
+)
+
+// PromptMarker marks the prompts, input and output of an application that
+// works like a shell or REPL, using the OSC 133 sequences of FinalTerm
+// shell integration.  Terminals that understand these (iTerm2, kitty,
+// WezTerm, foot, Windows Terminal, VS Code and others) can then jump
+// between prompts, select the output of a command, and so forth.  Other
+// terminals ignore them.
+//
+// Terminals attach each mark to the position of the cursor when it is
+// received, so each method takes the position of the start of the zone.
+// The screen is drawn before the mark is sent, so the content should be
+// in place (with SetContent) first.  Marks are of most use when the
+// application does not use the alternate screen, as terminals generally
+// keep them only for the main screen and its scrollback.
+type PromptMarker struct {
+	s Screen
+}
+
+// NewPromptMarker creates a PromptMarker for a Screen.
+func NewPromptMarker(s Screen) *PromptMarker {
+	return &PromptMarker{s: s}
+}
+
+// markScreen is implemented by screens that can send a sequence with the
+// cursor at a given position.
+type markScreen interface {
+	emitAt(x, y int, seq string) error
+}
+
+func (m *PromptMarker) mark(x, y int, seq string) error {
+	seq = "\x1b]133;" + seq + "\x1b\\"
+	if ms, ok := m.s.(markScreen); ok {
+		return ms.emitAt(x, y, seq)
+	}
+	return m.s.EmitRaw(seq)
+}
+
+// emitAt draws any pending content, and then sends a sequence with the
+// cursor at x, y.
+func (t *tScreen) emitAt(x, y int, seq string) error {
+	t.Lock()
+	defer t.Unlock()
+	if t.fini {
+		return nil
+	}
+	t.resize()
+	t.draw()
+	t.TPuts(t.ti.TGoto(x, y))
+	t.writeString(seq)
+	t.cx, t.cy = x, y
+	return nil
+}
+
+// PromptStart marks the start of a prompt (OSC 133;A).
+func (m *PromptMarker) PromptStart(x, y int) error {
+	return m.mark(x, y, "A")
+}
+
+// InputStart marks the end of the prompt, and the start of the user's
+// input (OSC 133;B).
+func (m *PromptMarker) InputStart(x, y int) error {
+	return m.mark(x, y, "B")
+}
+
+// OutputStart marks the end of the input, and the start of the output of
+// the command (OSC 133;C).
+func (m *PromptMarker) OutputStart(x, y int) error {
+	return m.mark(x, y, "C")
+}
+
+// CommandEnd marks the end of the output of the command, with its exit
+// status, where zero indicates success (OSC 133;D).
+func (m *PromptMarker) CommandEnd(x, y, status int) error {
+	return m.mark(x, y, "D;"+strconv.Itoa(status))
+}
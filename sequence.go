@@ -0,0 +1,188 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bytes"
+)
+
+// SequenceKind identifies the type of a control sequence.
+type SequenceKind int
+
+const (
+	SequenceCSI SequenceKind = iota // ESC [ ... final
+	SequenceOSC                     // ESC ] ... BEL or ST
+	SequenceDCS                     // ESC P ... ST
+	SequenceAPC                     // ESC _ ... ST
+	SequencePM                      // ESC ^ ... ST
+	SequenceESC                     // ESC intermediates final
+)
+
+// String returns a short name for the kind, such as "CSI" or "OSC".
+func (k SequenceKind) String() string {
+	switch k {
+	case SequenceCSI:
+		return "CSI"
+	case SequenceOSC:
+		return "OSC"
+	case SequenceDCS:
+		return "DCS"
+	case SequenceAPC:
+		return "APC"
+	case SequencePM:
+		return "PM"
+	case SequenceESC:
+		return "ESC"
+	}
+	return "Unknown"
+}
+
+// UnknownSequenceHandler is called with control sequences received from
+// the terminal that tcell does not otherwise understand.  The payload
+// excludes the introducer and the string terminator; for CSI sequences
+// it includes the final byte.
+type UnknownSequenceHandler func(kind SequenceKind, payload string)
+
+// eventSequence carries an unknown sequence from the input parser to
+// the point where the handler can be called without holding the lock.
+// It is never delivered to the application as an event.
+type eventSequence struct {
+	EventTime
+	kind    SequenceKind
+	payload string
+}
+
+// stringKind returns the kind for control string introducers,
+// which are terminated by ST (or for OSC, by BEL).
+func stringKind(c byte) (SequenceKind, bool) {
+	switch c {
+	case ']':
+		return SequenceOSC, true
+	case 'P':
+		return SequenceDCS, true
+	case '_':
+		return SequenceAPC, true
+	case '^':
+		return SequencePM, true
+	}
+	return 0, false
+}
+
+// scanSequence looks for a single complete control sequence at the start of b.
+// It returns the kind, the payload, and the number of bytes consumed.  If the
+// data is a valid prefix of a sequence but is not yet complete, then n is zero
+// and ok is true.  If the data cannot be a sequence, ok is false.
+func scanSequence(b []byte) (kind SequenceKind, payload string, n int, ok bool) {
+	if len(b) == 0 || b[0] != '\x1b' {
+		return 0, "", 0, false
+	}
+	if len(b) == 1 {
+		return 0, "", 0, true
+	}
+	if b[1] == '[' {
+		for i := 2; i < len(b); i++ {
+			c := b[i]
+			switch {
+			case c >= 0x20 && c <= 0x3f:
+				// parameter and intermediate bytes
+				// (ECMA-48 orders these, but we are lenient)
+			case c >= 0x40 && c <= 0x7e:
+				return SequenceCSI, string(b[2 : i+1]), i + 1, true
+			default:
+				return 0, "", 0, false
+			}
+		}
+		return SequenceCSI, "", 0, true
+	}
+	if k, isStr := stringKind(b[1]); isStr {
+		for i := 2; i < len(b); i++ {
+			c := b[i]
+			if c == '\a' && k == SequenceOSC {
+				return k, string(b[2:i]), i + 1, true
+			}
+			if c != '\x1b' {
+				continue
+			}
+			if i+1 == len(b) {
+				break
+			}
+			if b[i+1] == '\\' {
+				return k, string(b[2:i]), i + 2, true
+			}
+			return 0, "", 0, false
+		}
+		return k, "", 0, true
+	}
+	for i := 1; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case c >= 0x20 && c <= 0x2f:
+		case c >= 0x30 && c <= 0x7e:
+			return SequenceESC, string(b[1 : i+1]), i + 1, true
+		default:
+			return 0, "", 0, false
+		}
+	}
+	return SequenceESC, "", 0, true
+}
+
+// validSequence returns true if the string consists of exactly one
+// complete control sequence, and nothing else.
+func validSequence(seq string) bool {
+	_, _, n, ok := scanSequence([]byte(seq))
+	return ok && n != 0 && n == len(seq)
+}
+
+// parseUnknownSequence consumes complete CSI, OSC, DCS, APC and PM sequences
+// that were not recognized by any of the other parsers, so that they are not
+// delivered to the application as a stream of bogus key presses.  Simple
+// escapes are left alone, as those are how the Alt modifier is reported.
+func (t *tScreen) parseUnknownSequence(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
+	b := buf.Bytes()
+	kind, payload, n, ok := scanSequence(b)
+	if !ok || kind == SequenceESC || len(b) < 2 {
+		return false, false
+	}
+	if n == 0 {
+		if len(b) > maxSequenceLen {
+			// Never going to end, or at least not soon enough.
+			// Throw it all away; if it was meant to answer a
+			// query, then that query has failed.
+			buf.Reset()
+			t.queries.failOldest(ErrQueryTooLong)
+			return true, true
+		}
+		return true, false
+	}
+	buf.Next(n)
+	if n > maxSequenceLen {
+		t.queries.failOldest(ErrQueryTooLong)
+		return true, true
+	}
+	if t.queries.deliver(kind, payload) {
+		return true, true
+	}
+	if kind == SequenceCSI {
+		// replies to queries that we know how to decode
+		if x, y, ok := parseWindowPosition(payload); ok {
+			*evs = append(*evs, NewEventWindowPosition(x, y))
+			return true, true
+		}
+	}
+	ev := &eventSequence{kind: kind, payload: payload}
+	ev.SetEventNow()
+	*evs = append(*evs, ev)
+	return true, true
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestValidSequence(t *testing.T) {
+	good := []string{
+		"\x1b[?1049h",
+		"\x1b]0;title\a",
+		"\x1b]8;;http://example.com\x1b\\",
+		"\x1bPq#0;2;0;0;0\x1b\\",
+		"\x1b_Gf=24\x1b\\",
+		"\x1b7",
+		"\x1b(B",
+	}
+	bad := []string{
+		"",
+		"hello",
+		"\x1b",
+		"\x1b[31",
+		"\x1b]0;title",
+		"\x1b[31mred",
+		"\x1b[31m\x1b[0m",
+		"\x1bPdata\a",
+		"\x1b]0;a\x1bb\a",
+	}
+	for _, s := range good {
+		if !validSequence(s) {
+			t.Errorf("%q should be valid", s)
+		}
+	}
+	for _, s := range bad {
+		if validSequence(s) {
+			t.Errorf("%q should not be valid", s)
+		}
+	}
+}
+
+func TestScanSequence(t *testing.T) {
+	kind, payload, n, ok := scanSequence([]byte("\x1b]11;rgb:0000/0000/0000\x1b\\x"))
+	if !ok || kind != SequenceOSC || payload != "11;rgb:0000/0000/0000" || n != 25 {
+		t.Errorf("bad OSC scan: %v %q %d %v", kind, payload, n, ok)
+	}
+	kind, payload, n, ok = scanSequence([]byte("\x1b[?62;22c"))
+	if !ok || kind != SequenceCSI || payload != "?62;22c" || n != 9 {
+		t.Errorf("bad CSI scan: %v %q %d %v", kind, payload, n, ok)
+	}
+	if _, _, n, ok = scanSequence([]byte("\x1bP1$r")); !ok || n != 0 {
+		t.Errorf("partial DCS should be incomplete")
+	}
+}
+
+func TestEmitRawSim(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	if e := s.EmitRaw("\x1b]2;title\a"); e != nil {
+		t.Errorf("unexpected error: %v", e)
+	}
+	if e := s.EmitRaw("plain"); e != ErrInvalidSequence {
+		t.Errorf("expected ErrInvalidSequence, got %v", e)
+	}
+}
+
+func TestParseWindowPosition(t *testing.T) {
+	if x, y, ok := parseWindowPosition("3;120;45t"); !ok || x != 120 || y != 45 {
+		t.Errorf("bad position: %d %d %v", x, y, ok)
+	}
+	for _, p := range []string{"3;1t", "4;1;2t", "3;a;2t", "3;1;2T"} {
+		if _, _, ok := parseWindowPosition(p); ok {
+			t.Errorf("%q should not parse", p)
+		}
+	}
+}
+
+func TestReGIS(t *testing.T) {
+	r := NewReGIS().Erase().Color(ColorRed).Move(10, 20).Line(30, 40).Circle(5).Text("it's")
+	want := "\x1bP0pS(E)W(I(H120L50S100))P[10,20]V[30,40]C[+5]T'it''s'\x1b\\"
+	if r.String() != want {
+		t.Errorf("wrong ReGIS: %q", r.String())
+	}
+	if !validSequence(r.String()) {
+		t.Errorf("ReGIS is not a valid sequence")
+	}
+	if h, _, _ := regisHLS(NewRGBColor(0, 0, 255)); h != 0 {
+		t.Errorf("blue should have ReGIS hue 0, got %d", h)
+	}
+}
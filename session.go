@@ -0,0 +1,261 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bytes"
+	"runtime"
+	"sync"
+
+	"github.com/gdamore/tcell/v2/terminfo"
+)
+
+// Session is a snapshot of everything that a screen's decisions were
+// based on: the terminal description, the environment, the user's quirks,
+// what the terminal said it was, and so forth.  Where a Report says what
+// was decided, a Session records why, so that a user's session can be
+// serialized as JSON, attached to a bug report, and replayed elsewhere
+// with ReplaySession to make the same decisions again.
+//
+// The only thing that tcell learns by probing the terminal is what it is
+// (with XTVERSION or DA2), so TerminalID is all that is kept of the
+// terminal's replies.  Replies to queries that the application makes
+// itself, with Query or EmitRaw, are not captured.
+type Session struct {
+	Platform     string             // GOOS/GOARCH
+	Driver       string             // "terminfo", or empty if it cannot be replayed
+	Terminfo     *terminfo.Terminfo `json:",omitempty"`
+	Env          map[string]string  `json:",omitempty"` // variables read, that were set
+	Quirks       map[string]string  `json:",omitempty"` // from the quirks file
+	TerminalID   TerminalID         // as the terminal identified itself
+	CharacterSet string             // the character set in use
+	Width        int                // width of the terminal in character cells
+	Height       int                // height of the terminal in character cells
+	NoAltScreen  bool               // true if drawing on the main screen
+	KeepMouse    bool               // true if mouse reporting is left enabled
+	NoEnv        bool               // true if the environment was ignored
+}
+
+// sessionEnv is the environment that a terminfo screen reads (see env.go),
+// including $TERM and the locale.
+var sessionEnv = []string{
+	"TERM",
+	"TCELL_FORCE_TERM",
+	"TCELL_TRUECOLOR",
+	"TCELL_ALTSCREEN",
+	"TCELL_MOUSE",
+	"TCELL_ACS",
+	"TCELL_C1",
+	"TCELL_SOFTBLINK",
+	"TCELL_NOTIFY",
+	"TCELL_PROGRESS",
+	"TCELL_MARGINS",
+	"TCELL_REP",
+	"TCELL_GLYPHS",
+	"TCELL_QUIRKS",
+	"TCELL_IDENTIFY",
+	"COLORTERM",
+	"LINES",
+	"COLUMNS",
+	"TERM_PROGRAM",
+	"WT_SESSION",
+	"ConEmuPID",
+	"LC_ALL",
+	"LC_CTYPE",
+	"LANG",
+}
+
+// sessionCapturer is implemented by screens that can be replayed.
+type sessionCapturer interface {
+	captureSession(sess *Session)
+}
+
+// CaptureSession captures a session for the given screen.  The screen
+// should already be initialized, and should have been running for long
+// enough for the terminal to have identified itself, if it is going to.
+func CaptureSession(s Screen) *Session {
+	sess := &Session{
+		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+		CharacterSet: s.CharacterSet(),
+		TerminalID:   s.TerminalID(),
+	}
+	sess.Width, sess.Height = s.Size()
+	if sc, ok := s.(sessionCapturer); ok {
+		sc.captureSession(sess)
+	}
+	return sess
+}
+
+func (t *tScreen) captureSession(sess *Session) {
+	t.Lock()
+	defer t.Unlock()
+
+	sess.Driver = "terminfo"
+	sess.Terminfo = t.ti
+	sess.NoAltScreen = t.noAltScreen
+	sess.KeepMouse = t.keepMouse
+	sess.NoEnv = t.noEnv
+	sess.Env = make(map[string]string)
+	for _, name := range sessionEnv {
+		if v := t.osGetenv(name); v != "" {
+			sess.Env[name] = v
+		}
+	}
+	if len(t.quirks) != 0 {
+		sess.Quirks = make(map[string]string)
+		for k, v := range t.quirks {
+			sess.Quirks[k] = v
+		}
+	}
+	if w, h, e := t.tty.WindowSize(); e == nil && w > 0 && h > 0 {
+		sess.Width, sess.Height = w, h
+	}
+}
+
+// replaySession applies what was captured in the session being replayed,
+// in place of what we would otherwise learn from the user's files and
+// from the terminal.
+func (t *tScreen) replaySession() {
+	t.quirks = t.session.Quirks
+	if t.termID = t.session.TerminalID; t.termID.Name != "" {
+		t.idQuirks = quirksFor(t.termID)
+	}
+	t.identifying = true // the terminal is not asked again
+}
+
+// ReplaySession returns a terminfo screen that makes the same decisions
+// as the one the session was captured from, without reading anything from
+// this host's environment or files.  In place of the terminal it uses a
+// ReplayTty, which collects what the screen sends, and can be used to send
+// the screen input as the user's terminal would have.  The terminal is
+// not asked to identify itself again.
+//
+// This is a real terminfo screen, rather than a SimulationScreen, so that
+// what it sends can be compared with what the user's terminal was sent.
+// To replay a session with a SimulationScreen instead, use the profile
+// from SimProfileFromSession.
+func ReplaySession(sess *Session) (Screen, *ReplayTty, error) {
+	if sess.Terminfo == nil {
+		return nil, nil, ErrInvalidSession
+	}
+	tty := &ReplayTty{w: sess.Width, h: sess.Height}
+	tty.cond = sync.NewCond(&tty.Mutex)
+	t, e := newTScreen(&screenOptions{
+		ti:        sess.Terminfo,
+		tty:       tty,
+		noAlt:     sess.NoAltScreen,
+		keepMouse: sess.KeepMouse,
+		noEnv:     sess.NoEnv,
+		session:   sess,
+	})
+	if e != nil {
+		return nil, nil, e
+	}
+	return t, tty, nil
+}
+
+// ReplayTty is a simulated terminal, used by ReplaySession.
+type ReplayTty struct {
+	w, h    int
+	out     bytes.Buffer
+	in      []byte
+	drained bool
+	resize  func()
+	cond    *sync.Cond
+
+	sync.Mutex
+}
+
+// Output returns what the screen has sent to the terminal, since the last
+// time that it was called.
+func (r *ReplayTty) Output() []byte {
+	r.Lock()
+	defer r.Unlock()
+	b := append([]byte{}, r.out.Bytes()...)
+	r.out.Reset()
+	return b
+}
+
+// Input sends the screen the given bytes, as though they had been typed
+// (or sent in reply to a query) at the terminal.
+func (r *ReplayTty) Input(b []byte) {
+	r.Lock()
+	r.in = append(r.in, b...)
+	r.cond.Broadcast()
+	r.Unlock()
+}
+
+// Resize changes the size of the terminal, and tells the screen so.
+func (r *ReplayTty) Resize(w, h int) {
+	r.Lock()
+	r.w, r.h = w, h
+	cb := r.resize
+	r.Unlock()
+	if cb != nil {
+		cb()
+	}
+}
+
+func (r *ReplayTty) Start() error {
+	r.Lock()
+	r.drained = false
+	r.Unlock()
+	return nil
+}
+
+func (r *ReplayTty) Stop() error {
+	return nil
+}
+
+func (r *ReplayTty) Drain() error {
+	r.Lock()
+	r.drained = true
+	r.cond.Broadcast()
+	r.Unlock()
+	return nil
+}
+
+func (r *ReplayTty) NotifyResize(cb func()) {
+	r.Lock()
+	r.resize = cb
+	r.Unlock()
+}
+
+func (r *ReplayTty) WindowSize() (int, int, error) {
+	r.Lock()
+	defer r.Unlock()
+	return r.w, r.h, nil
+}
+
+func (r *ReplayTty) Read(b []byte) (int, error) {
+	r.Lock()
+	defer r.Unlock()
+	for len(r.in) == 0 && !r.drained {
+		r.cond.Wait()
+	}
+	n := copy(b, r.in)
+	r.in = r.in[n:]
+	return n, nil
+}
+
+func (r *ReplayTty) Write(b []byte) (int, error) {
+	r.Lock()
+	defer r.Unlock()
+	return r.out.Write(b)
+}
+
+func (r *ReplayTty) Close() error {
+	return r.Drain()
+}
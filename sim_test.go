@@ -15,7 +15,9 @@
 package tcell
 
 import (
+	"sync"
 	"testing"
+	"time"
 )
 
 func mkTestScreen(t *testing.T, charset string) SimulationScreen {
@@ -29,6 +31,17 @@ func mkTestScreen(t *testing.T, charset string) SimulationScreen {
 	return s
 }
 
+// simRow returns the text of n cells of a row of a simulation screen,
+// starting at x, as last shown.
+func simRow(s SimulationScreen, x, y, n int) string {
+	cells, w, _ := s.GetContents()
+	var text []rune
+	for _, c := range cells[y*w+x : y*w+x+n] {
+		text = append(text, c.Runes[0])
+	}
+	return string(text)
+}
+
 func TestInitScreen(t *testing.T) {
 
 	s := mkTestScreen(t, "")
@@ -150,3 +163,77 @@ func TestBeep(t *testing.T) {
 		}
 	}
 }
+
+// TestConcurrentAccess is most useful when run with -race.
+func TestConcurrentAccess(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				s.SetContent(i%80, g, rune('A'+g), nil, StyleDefault)
+				s.GetContent(i%80, g)
+				if i%50 == 0 {
+					s.Clear()
+				}
+			}
+		}(g)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			s.Show()
+			s.Size()
+			if i%10 == 0 {
+				s.Sync()
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+func TestSimClock(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s.SetClock(clock)
+
+	s.InjectKey(KeyRune, 'a', ModNone)
+	clock.Advance(300 * time.Millisecond)
+	s.InjectMouse(1, 1, Button1, ModNone)
+	if ev := s.PollEvent(); !ev.When().Equal(start) {
+		t.Errorf("wrong key time %v", ev.When())
+	}
+	if ev := s.PollEvent(); ev.When().Sub(start) != 300*time.Millisecond {
+		t.Errorf("wrong mouse time %v", ev.When())
+	}
+
+	var fired []time.Duration
+	clock.AfterFunc(time.Second, func() {
+		fired = append(fired, clock.Now().Sub(start))
+		clock.AfterFunc(time.Second, func() {
+			fired = append(fired, clock.Now().Sub(start))
+		})
+	})
+	stop := clock.AfterFunc(2*time.Second, func() { t.Errorf("stopped timer fired") })
+	if !stop() {
+		t.Errorf("timer could not be stopped")
+	}
+	clock.Advance(time.Second)
+	if len(fired) != 1 || fired[0] != 1300*time.Millisecond {
+		t.Errorf("wrong timers fired: %v", fired)
+	}
+	clock.Advance(10 * time.Second)
+	if len(fired) != 2 || fired[1] != 2300*time.Millisecond {
+		t.Errorf("wrong timers fired: %v", fired)
+	}
+	if clock.Now().Sub(start) != 11300*time.Millisecond {
+		t.Errorf("wrong time after advance: %v", clock.Now())
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strings"
+)
+
+// SimImage is an image that was sent to a simulation screen with EmitRaw,
+// as a graphics sequence.  The simulation does not draw images, but records
+// them, so that tests can check that an image was drawn, and where,
+// without a terminal that can display it.
+type SimImage struct {
+	Kind string // "sixel", "regis", "kitty" or "iterm2"
+	X, Y int    // the cursor position when it was sent, or -1 if hidden
+	Data string // the sequence, as it was sent
+}
+
+// imageKind returns the kind of graphics sequence that seq is, or the
+// empty string if it is not one.
+func imageKind(seq string) string {
+	switch {
+	case strings.HasPrefix(seq, "\x1b_G"):
+		return "kitty"
+	case strings.HasPrefix(seq, "\x1b]1337;File="):
+		return "iterm2"
+	case strings.HasPrefix(seq, "\x1bP"):
+		// The parameters are followed by the final character, which
+		// says what the device control string is.
+		p := strings.TrimLeft(seq[2:], "0123456789;")
+		if p == "" {
+			return ""
+		}
+		switch p[0] {
+		case 'q':
+			return "sixel"
+		case 'p':
+			return "regis"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestSimImages(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	sixel := "\x1bP0;1q\"1;1;2;2#0~~-~~\x1b\\"
+	regis := NewReGIS().Move(0, 0).Line(10, 10).String()
+	kitty := "\x1b_Ga=T,f=100;iVBORw0KGgo=\x1b\\"
+	iterm := "\x1b]1337;File=inline=1:iVBORw0KGgo=\a"
+
+	s.ShowCursor(3, 2)
+	for _, seq := range []string{sixel, "\x1b]2;title\a", regis} {
+		if e := s.EmitRaw(seq); e != nil {
+			t.Fatalf("EmitRaw %q: %v", seq, e)
+		}
+	}
+	s.ShowCursor(7, 4)
+	_ = s.EmitRaw(kitty)
+	s.HideCursor()
+	_ = s.EmitRaw(iterm)
+	_ = s.EmitRaw("\x1bP1$r0m\x1b\\") // a reply, not an image
+
+	expect := []SimImage{
+		{Kind: "sixel", X: 3, Y: 2, Data: sixel},
+		{Kind: "regis", X: 3, Y: 2, Data: regis},
+		{Kind: "kitty", X: 7, Y: 4, Data: kitty},
+		{Kind: "iterm2", X: -1, Y: -1, Data: iterm},
+	}
+	images := s.GetImages()
+	if len(images) != len(expect) {
+		t.Fatalf("wrong images: %q", images)
+	}
+	for i := range expect {
+		if images[i] != expect[i] {
+			t.Errorf("image %d: %q, expected %q", i, images[i], expect[i])
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"github.com/gdamore/tcell/v2/terminfo"
+)
+
+// SimProfile describes the capabilities of a terminal, for a simulation
+// screen to mimic.  This lets applications test how they behave on less
+// capable terminals.  A simulation screen using a profile reports these
+// capabilities (with Colors, ColorModel, HasMouse and HasKey), draws colors
+// the way such a terminal would (mapping them to the nearest palette
+// color, or dropping them entirely), and discards injected events that
+// such a terminal could not send.
+type SimProfile struct {
+	// Name is the name of the terminal.
+	Name string
+
+	// Charset is the character set, such as "UTF-8" or "US-ASCII".
+	Charset string
+
+	// Colors is the size of the palette, which is 0 for a monochrome
+	// terminal.
+	Colors int
+
+	// TrueColor is true if 24-bit colors can be displayed.
+	TrueColor bool
+
+	// Mouse is true if the terminal can report mouse events.
+	Mouse bool
+
+	// Keys are the special keys that the terminal has.  KeyRune is
+	// always present, as are control keys.  If Keys is nil, all keys are
+	// present.
+	Keys []Key
+}
+
+// SimProfileWindowsConsole describes the legacy Windows console, which
+// has 16 colors, a mouse, and all of the usual keys.
+var SimProfileWindowsConsole = &SimProfile{
+	Name:    "windows-console",
+	Charset: "UTF-8",
+	Colors:  16,
+	Mouse:   true,
+}
+
+// NewSimProfile returns a profile for the named terminal, such as "vt100"
+// or "linux", based on its terminfo description.  The terminal is assumed
+// to use UTF-8; change the Charset to test others.
+// The name "windows-console" returns SimProfileWindowsConsole.
+func NewSimProfile(term string) (*SimProfile, error) {
+	if term == SimProfileWindowsConsole.Name {
+		p := *SimProfileWindowsConsole
+		return &p, nil
+	}
+	ti, err := LookupTerminfo(term)
+	if err != nil {
+		return nil, err
+	}
+	return SimProfileFromTerminfo(ti), nil
+}
+
+// SimProfileFromTerminfo returns a profile for the terminal described by
+// ti, which is assumed to use UTF-8.  The profile depends only on ti, and
+// not on the environment or the quirks file.
+func SimProfileFromTerminfo(ti *terminfo.Terminfo) *SimProfile {
+	p := &SimProfile{
+		Name:      ti.Name,
+		Charset:   "UTF-8",
+		Colors:    ti.Colors,
+		TrueColor: ti.SetFgBgRGB != "" || ti.SetFgRGB != "" || ti.SetBgRGB != "",
+		Mouse:     ti.Mouse != "",
+		Keys:      []Key{},
+	}
+	// The screen is only used to find the keys, which it must do without
+	// the user's quirks or environment.
+	if t, err := newTScreen(&screenOptions{ti: ti, noEnv: true}); err == nil {
+		p.Keys = t.simKeys()
+	}
+	return p
+}
+
+// SimProfileFromSession returns a profile for the terminal that the
+// session was captured from, as the screen found it once the session's
+// environment and quirks were applied.  A SimulationScreen using it can
+// then replay the session, after SetSize to the session's Width and
+// Height.  It returns ErrInvalidSession if the session was not captured
+// from a terminfo screen.
+func SimProfileFromSession(sess *Session) (*SimProfile, error) {
+	s, _, err := ReplaySession(sess)
+	if err != nil {
+		return nil, err
+	}
+	t := s.(*tScreen)
+	return &SimProfile{
+		Name:    t.ti.Name,
+		Charset: sess.CharacterSet,
+		Colors:  t.nColors(),
+		TrueColor: (t.ti.SetFgBgRGB != "" || t.ti.SetFgRGB != "" || t.ti.SetBgRGB != "") &&
+			t.getenv("TCELL_TRUECOLOR") != "disable",
+		Mouse: len(t.mouse) != 0,
+		Keys:  t.simKeys(),
+	}, nil
+}
+
+// simKeys returns the special keys that the terminal has.
+func (t *tScreen) simKeys() []Key {
+	keys := []Key{}
+	for k, ok := range t.keyexist {
+		if ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// NewSimulationScreenFromProfile returns a SimulationScreen that mimics
+// the terminal described by p.
+func NewSimulationScreenFromProfile(p *SimProfile) SimulationScreen {
+	s := NewSimulationScreen(p.Charset).(*simscreen)
+	s.profile = p
+	if p.Keys != nil {
+		s.keys = make(map[Key]bool)
+		for _, k := range p.Keys {
+			s.keys[k] = true
+		}
+	}
+	if !p.TrueColor {
+		s.palette = make([]Color, p.Colors)
+		for i := range s.palette {
+			s.palette[i] = PaletteColor(i)
+		}
+	}
+	return s
+}
+
+// hasKey returns true if the simulated terminal has the key.
+func (s *simscreen) hasKey(k Key) bool {
+	if s.keys == nil || k == KeyRune || (k >= KeyCtrlSpace && k <= KeyDEL) {
+		return true
+	}
+	return s.keys[k]
+}
+
+// profileColor returns the color that the simulated terminal displays
+// for c.
+func (s *simscreen) profileColor(c Color) Color {
+	if s.profile == nil || !c.Valid() {
+		return c
+	}
+	if s.profile.Colors == 0 && !s.profile.TrueColor {
+		return ColorDefault
+	}
+	if s.profile.TrueColor {
+		return c
+	}
+	if !c.IsRGB() && c&^ColorValid < Color(len(s.palette)) {
+		return c
+	}
+	return FindColor(c, s.palette)
+}
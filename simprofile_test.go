@@ -0,0 +1,95 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestSimProfile(t *testing.T) {
+	p, err := NewSimProfile("vt100")
+	if err != nil {
+		t.Fatalf("no profile: %v", err)
+	}
+	s := NewSimulationScreenFromProfile(p)
+	if err := s.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer s.Fini()
+	if s.Colors() != 0 || s.HasMouse() || s.HasKey(KeyF12) || !s.HasKey(KeyUp) || !s.HasKey(KeyEnter) {
+		t.Errorf("wrong capabilities for vt100")
+	}
+	s.SetContent(0, 0, 'a', nil, StyleDefault.Foreground(ColorRed).Bold(true))
+	s.Show()
+	b, _, _ := s.GetContents()
+	if b[0].Style != StyleDefault.Bold(true) {
+		t.Errorf("colors not dropped: %v", b[0].Style)
+	}
+	s.InjectKey(KeyF12, 0, ModNone)
+	s.InjectMouse(0, 0, Button1, ModNone)
+	s.InjectKey(KeyUp, 0, ModNone)
+	if ev, ok := s.PollEvent().(*EventKey); !ok || ev.Key() != KeyUp || s.HasPendingEvent() {
+		t.Errorf("events not filtered")
+	}
+
+	s = NewSimulationScreenFromProfile(SimProfileWindowsConsole)
+	if err := s.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer s.Fini()
+	if p, tc, _ := s.ColorModel(); p != 16 || tc || !s.HasMouse() || !s.HasKey(KeyF12) {
+		t.Errorf("wrong capabilities for windows console")
+	}
+	s.SetContent(0, 0, 'a', nil, StyleDefault.Foreground(NewRGBColor(250, 5, 5)).Background(Color100))
+	s.Show()
+	b, _, _ = s.GetContents()
+	if fg, bg, _ := b[0].Style.Decompose(); fg != ColorRed || bg.Hex() < 0 || bg&^ColorValid >= 16 {
+		t.Errorf("colors not fitted: %v %v", fg, bg)
+	}
+}
+
+func TestSimProfileFromSession(t *testing.T) {
+	if _, err := SimProfileFromSession(&Session{}); err != ErrInvalidSession {
+		t.Errorf("expected ErrInvalidSession, got %v", err)
+	}
+	ti, err := LookupTerminfo("xterm-256color")
+	if err != nil {
+		t.Fatalf("no terminfo: %v", err)
+	}
+	sess := &Session{
+		Driver:       "terminfo",
+		Terminfo:     ti,
+		Quirks:       map[string]string{"TCELL_TRUECOLOR": "enable"},
+		CharacterSet: "US-ASCII",
+		Width:        20,
+		Height:       5,
+	}
+	p, err := SimProfileFromSession(sess)
+	if err != nil {
+		t.Fatalf("no profile: %v", err)
+	}
+	if p.Name != "xterm-256color" || p.Charset != "US-ASCII" || p.Colors != 256 || !p.TrueColor || !p.Mouse {
+		t.Errorf("wrong profile: %+v", p)
+	}
+	s := NewSimulationScreenFromProfile(p)
+	if err := s.Init(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	defer s.Fini()
+	s.SetSize(sess.Width, sess.Height)
+	if w, h := s.Size(); w != 20 || h != 5 || !s.HasKey(KeyF12) || s.CharacterSet() != "US-ASCII" {
+		t.Errorf("session not replayed: %dx%d %s", w, h, s.CharacterSet())
+	}
+}
@@ -15,7 +15,11 @@
 package tcell
 
 import (
+	"image"
+	"os"
 	"sync"
+	"sync/atomic"
+	"time"
 	"unicode/utf8"
 
 	"golang.org/x/text/transform"
@@ -52,12 +56,39 @@ type SimulationScreen interface {
 	// GetContents returns screen contents as an array of
 	// cells, along with the physical width & height.   Note that the
 	// physical contents will be used until the next time SetSize()
-	// is called.
+	// is called.  The returned cells are updated in place by Show and
+	// Sync, so they should not be examined concurrently with those.
 	GetContents() (cells []SimCell, width int, height int)
 
 	// GetCursor returns the cursor details.
 	GetCursor() (x int, y int, visible bool)
 
+	// GetImages returns the images that have been sent with EmitRaw, as
+	// sixel, ReGIS, kitty or iTerm2 graphics sequences, in the order that
+	// they were sent.
+	GetImages() []SimImage
+
+	// ResizeWindow changes the size of the screen, as SetSize does, and
+	// posts an EventResize for the change, as though the user had resized
+	// the window.  (SetSize posts no event.)
+	ResizeWindow(width, height int)
+
+	// SetClock sets the clock used to time stamp the events that the
+	// simulation generates, including those injected with InjectKey,
+	// InjectKeyBytes and InjectMouse.  The default is SystemClock.
+	SetClock(c Clock)
+
+	// ToANSI, ToHTML and ToSVG export the contents of the screen, as
+	// drawn by the application, in the same way as the CellBuffer
+	// methods of the same names.
+	ToANSI() string
+	ToHTML() string
+	ToSVG() string
+
+	// ToImage draws the contents of the screen, as drawn by the
+	// application, in the same way as CellBuffer.ToImage.
+	ToImage(scale int) *image.RGBA
+
 	Screen
 }
 
@@ -89,7 +120,16 @@ type simscreen struct {
 	cursorx   int
 	cursory   int
 	cursorvis bool
+	scursor   softCursor
+	selection selection
+	highlight selection
+	rows      rowStyles
+	guides    guides
+	mag       magnifier
+	filter    RenderFilter
+	states    snapshots
 	mouse     bool
+	wheelKeys int
 	paste     bool
 	charset   string
 	encoder   transform.Transformer
@@ -97,6 +137,15 @@ type simscreen struct {
 	fillchar  rune
 	fillstyle Style
 	fallback  map[rune]string
+	lines     []LineMode
+	clock     atomic.Value
+	profile   *SimProfile
+	keys      map[Key]bool
+	palette   []Color
+	history   scrollback
+	changes   cellChanges
+	reflow    ResizePolicy
+	images    []SimImage
 
 	sync.Mutex
 }
@@ -151,7 +200,9 @@ func (s *simscreen) SetStyle(style Style) {
 }
 
 func (s *simscreen) Clear() {
-	s.Fill(' ', s.style)
+	s.Lock()
+	s.back.Fill(' ', s.style)
+	s.Unlock()
 }
 
 func (s *simscreen) Fill(r rune, style Style) {
@@ -160,6 +211,14 @@ func (s *simscreen) Fill(r rune, style Style) {
 	s.Unlock()
 }
 
+func (s *simscreen) FillFunc(x, y, width, height int, f FillFunc) {
+	w, h := s.Size()
+	b := newFillBatch(x, y, width, height, w, h, f)
+	s.Lock()
+	b.apply(&s.back)
+	s.Unlock()
+}
+
 func (s *simscreen) SetCell(x, y int, style Style, ch ...rune) {
 
 	if len(ch) > 0 {
@@ -176,6 +235,181 @@ func (s *simscreen) SetContent(x, y int, mainc rune, combc []rune, st Style) {
 	s.Unlock()
 }
 
+func (s *simscreen) SetTabStops(stops []int) {
+	s.Lock()
+	s.back.SetTabStops(stops)
+	s.Unlock()
+}
+
+func (s *simscreen) NextTabStop(x int) int {
+	s.Lock()
+	defer s.Unlock()
+	return s.back.NextTabStop(x)
+}
+
+func (s *simscreen) Scroll(x, y, width, height, n int) {
+	s.Lock()
+	s.history.save(&s.back, x, y, width, height, n)
+	s.back.scroll(x, y, width, height, n, false, StyleDefault)
+	s.Unlock()
+}
+
+func (s *simscreen) SetResizePolicy(policy ResizePolicy) {
+	s.Lock()
+	s.reflow = policy
+	s.Unlock()
+}
+
+func (s *simscreen) SetLineWrap(y int, wrapped bool) {
+	s.Lock()
+	s.back.SetWrapped(y, wrapped)
+	s.Unlock()
+}
+
+func (s *simscreen) SetRowStyle(y int, style Style) {
+	s.Lock()
+	s.rows.set(&s.back, y, style)
+	s.Unlock()
+}
+
+func (s *simscreen) GetRowStyle(y int) Style {
+	s.Lock()
+	defer s.Unlock()
+	return s.rows.get(y)
+}
+
+func (s *simscreen) SetGuides(list []Guide) {
+	s.Lock()
+	s.guides.set(list)
+	s.Unlock()
+}
+
+func (s *simscreen) ShowGuides(on bool) {
+	s.Lock()
+	s.guides.show(on)
+	s.Unlock()
+}
+
+// magnify draws the enlarged display, and puts it (with its line modes)
+// in place of the cells to be drawn, until unmagnify puts them back.  The
+// simulation has double size lines, which are used for a factor of two.
+func (s *simscreen) magnify() {
+	w, h := s.back.Size()
+	s.mag.compose(w, h, func(x, y int) (rune, []rune, Style, int) {
+		mainc, combc, style, width := s.back.GetContent(x, y)
+		mainc, combc, style = s.overlay(x, y, mainc, combc, style)
+		return mainc, combc, style, width
+	}, s.cursorx, s.cursory, true)
+	s.back, s.mag.buf = s.mag.buf, s.back
+	s.lines, s.mag.lines = s.mag.lines, s.lines
+}
+
+func (s *simscreen) unmagnify() {
+	s.back, s.mag.buf = s.mag.buf, s.back
+	s.lines, s.mag.lines = s.mag.lines, s.lines
+}
+
+func (s *simscreen) SetMagnifier(factor int) {
+	s.Lock()
+	if s.mag.set(factor) && !s.mag.on() {
+		s.back.Invalidate()
+	}
+	s.Unlock()
+}
+
+func (s *simscreen) SetRenderFilter(f RenderFilter) {
+	s.Lock()
+	s.filter = f
+	s.back.Invalidate()
+	s.mag.buf.Invalidate()
+	s.Unlock()
+}
+
+func (s *simscreen) OnCellChange(x, y, width, height int, f CellChangeHandler) func() {
+	s.Lock()
+	cw := s.changes.add(x, y, width, height, f)
+	s.Unlock()
+	return func() {
+		s.Lock()
+		s.changes.remove(cw)
+		s.Unlock()
+	}
+}
+
+func (s *simscreen) SetScrollback(lines int) {
+	s.Lock()
+	s.history.setMax(lines)
+	s.Unlock()
+}
+
+func (s *simscreen) Scrollback() *CellBuffer {
+	s.Lock()
+	defer s.Unlock()
+	return s.history.buffer()
+}
+
+func (s *simscreen) Query(string, SequenceMatcher, time.Duration) (string, error) {
+	return "", ErrNotSupported
+}
+
+func (s *simscreen) TerminalID() TerminalID {
+	return TerminalID{}
+}
+
+// SetStatusLine is ignored by the simulation, which has no status line.
+func (s *simscreen) SetStatusLine(string, Style) {}
+
+func (s *simscreen) ToImage(scale int) *image.RGBA {
+	s.Lock()
+	defer s.Unlock()
+	return s.back.ToImage(scale)
+}
+
+func (s *simscreen) ToANSI() string {
+	s.Lock()
+	defer s.Unlock()
+	return s.back.ToANSI()
+}
+
+func (s *simscreen) ToHTML() string {
+	s.Lock()
+	defer s.Unlock()
+	return s.back.ToHTML()
+}
+
+func (s *simscreen) ToSVG() string {
+	s.Lock()
+	defer s.Unlock()
+	return s.back.ToSVG()
+}
+
+// SetLineMode is supported by the simulation, which only draws the first
+// half of rows that are not LineNormal.
+func (s *simscreen) SetLineMode(y int, mode LineMode) {
+	s.Lock()
+	_, h := s.back.Size()
+	if y >= 0 && y < h {
+		for len(s.lines) <= y {
+			s.lines = append(s.lines, LineNormal)
+		}
+		s.lines[y] = mode
+		w, _ := s.back.Size()
+		for x := 0; x < w; x++ {
+			s.back.SetDirty(x, y, true)
+		}
+	}
+	s.Unlock()
+}
+
+func (s *simscreen) GetLineMode(y int) LineMode {
+	s.Lock()
+	defer s.Unlock()
+	if y < 0 || y >= len(s.lines) {
+		return LineNormal
+	}
+	return s.lines[y]
+}
+
 func (s *simscreen) GetContent(x, y int) (rune, []rune, Style, int) {
 	var mainc rune
 	var combc []rune
@@ -187,6 +421,16 @@ func (s *simscreen) GetContent(x, y int) (rune, []rune, Style, int) {
 	return mainc, combc, style, width
 }
 
+// overlay returns the content of the cell at x, y as it is displayed, with
+// the row style, guides, highlights, selection and soft cursor applied.
+func (s *simscreen) overlay(x, y int, mainc rune, combc []rune, style Style) (rune, []rune, Style) {
+	style = s.rows.apply(y, style, s.style)
+	style = s.guides.apply(x, y, style)
+	style = s.highlight.apply(x, y, s.physw, style)
+	style = s.selection.apply(x, y, s.physw, style)
+	return s.scursor.apply(x, y, mainc, combc, style)
+}
+
 func (s *simscreen) drawCell(x, y int) int {
 
 	mainc, combc, style, width := s.back.GetContent(x, y)
@@ -198,10 +442,15 @@ func (s *simscreen) drawCell(x, y int) int {
 	}
 	simc := &s.front[(y*s.physw)+x]
 
-	if style == StyleDefault {
-		style = s.style
+	if !s.mag.on() {
+		// When magnified, the cells have already been overlaid.
+		mainc, combc, style = s.overlay(x, y, mainc, combc, style)
+	}
+	style = style.Inherit(s.style)
+	if s.filter != nil {
+		style = s.filter(style)
 	}
-	simc.Style = style
+	simc.Style = style.Foreground(s.profileColor(style.fg)).Background(s.profileColor(style.bg))
 	simc.Runes = append([]rune{mainc}, combc...)
 
 	// now emit runes - taking care to not overrun width with a
@@ -237,6 +486,10 @@ func (s *simscreen) drawCell(x, y int) int {
 			} else if r >= ' ' && r <= '~' {
 				simc.Bytes = append(simc.Bytes, byte(r))
 
+			} else if subst, ok := bestFit(r); ok {
+				simc.Bytes = append(simc.Bytes,
+					[]byte(subst)...)
+
 			} else if simc.Bytes == nil {
 				simc.Bytes = append(simc.Bytes, '?')
 			}
@@ -262,7 +515,7 @@ func (s *simscreen) HideCursor() {
 func (s *simscreen) showCursor() {
 
 	x, y := s.cursorx, s.cursory
-	if x < 0 || y < 0 || x >= s.physw || y >= s.physh {
+	if x < 0 || y < 0 || x >= s.physw || y >= s.physh || s.scursor.on {
 		s.cursorvis = false
 	} else {
 		s.cursorvis = true
@@ -276,11 +529,61 @@ func (s *simscreen) hideCursor() {
 
 func (s *simscreen) SetCursorStyle(CursorStyle) {}
 
+func (s *simscreen) SetSoftCursor(style Style, r rune) {
+	s.Lock()
+	s.scursor.set(style, r)
+	s.Unlock()
+}
+
+func (s *simscreen) SetCursors(cursors []Cursor) {
+	s.Lock()
+	s.scursor.setExtra(cursors)
+	s.Unlock()
+}
+
+func (s *simscreen) SetSelection(style Style, ranges []Selection) {
+	s.Lock()
+	s.selection.set(style, ranges)
+	s.Unlock()
+}
+
+func (s *simscreen) SaveState() int {
+	s.Lock()
+	defer s.Unlock()
+	return s.states.save(&s.back)
+}
+
+func (s *simscreen) RestoreState(id int) error {
+	s.Lock()
+	defer s.Unlock()
+	return s.states.restore(&s.back, id)
+}
+
+func (s *simscreen) SetHighlights(style Style, ranges []Selection) {
+	s.Lock()
+	s.highlight.set(style, ranges)
+	s.Unlock()
+}
+
+func (s *simscreen) Find(pattern string, opts FindOptions) ([]Selection, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.back.Find(pattern, opts)
+}
+
+func (s *simscreen) SelectedText() string {
+	s.Lock()
+	defer s.Unlock()
+	return s.back.selectionText(s.selection.ranges)
+}
+
 func (s *simscreen) Show() {
 	s.Lock()
 	s.resize()
 	s.draw()
+	changes := s.changes.take()
 	s.Unlock()
+	reportChanges(changes)
 }
 
 func (s *simscreen) clearScreen() {
@@ -290,6 +593,7 @@ func (s *simscreen) clearScreen() {
 		s.front[i].Runes = []rune{s.fillchar}
 		s.front[i].Bytes = []byte{byte(s.fillchar)}
 	}
+	s.mag.buf.Invalidate()
 	s.clear = false
 }
 
@@ -299,30 +603,68 @@ func (s *simscreen) draw() {
 		s.clearScreen()
 	}
 
+	s.guides.prepare(&s.back)
+	s.highlight.prepare(&s.back)
+	s.selection.prepare(&s.back)
+	s.scursor.prepare(&s.back, s.cursorx, s.cursory)
+	s.changes.collect(&s.back)
+	magnified := s.mag.on()
+	if magnified {
+		s.magnify()
+	}
 	w, h := s.back.Size()
 	for y := 0; y < h; y++ {
+		w := w
+		if y < len(s.lines) && s.lines[y] != LineNormal {
+			w /= 2
+		}
 		for x := 0; x < w; x++ {
 			width := s.drawCell(x, y)
 			x += width - 1
 		}
 	}
+	if magnified {
+		s.unmagnify()
+		return
+	}
 	s.showCursor()
 }
 
 func (s *simscreen) EnableMouse(...MouseFlags) {
+	s.Lock()
 	s.mouse = true
+	s.Unlock()
+}
+
+// SetMouseShape is ignored by the simulation, which has no pointer.
+func (s *simscreen) SetMouseShape(MouseShape) {}
+
+func (s *simscreen) SetWheelKeys(n int) {
+	s.Lock()
+	s.wheelKeys = n
+	s.Unlock()
 }
 
 func (s *simscreen) DisableMouse() {
+	s.Lock()
 	s.mouse = false
+	s.Unlock()
 }
 
+func (s *simscreen) EnableKeypad() {}
+
+func (s *simscreen) DisableKeypad() {}
+
 func (s *simscreen) EnablePaste() {
+	s.Lock()
 	s.paste = true
+	s.Unlock()
 }
 
 func (s *simscreen) DisablePaste() {
+	s.Lock()
 	s.paste = false
+	s.Unlock()
 }
 
 func (s *simscreen) Size() (int, int) {
@@ -336,16 +678,28 @@ func (s *simscreen) resize() {
 	w, h := s.physw, s.physh
 	ow, oh := s.back.Size()
 	if w != ow || h != oh {
-		s.back.Resize(w, h)
-		ev := NewEventResize(w, h)
-		s.PostEvent(ev)
+		resizeCells(&s.back, &s.history, s.reflow, w, h)
+		s.post(newEventResize(w, h, ow, oh, resizeReason(w, h, ow, oh, [2]int{})))
 	}
 }
 
 func (s *simscreen) Colors() int {
+	if s.profile != nil {
+		if s.profile.TrueColor {
+			return 1 << 24
+		}
+		return s.profile.Colors
+	}
 	return 256
 }
 
+func (s *simscreen) ColorModel() (int, bool, bool) {
+	if s.profile != nil {
+		return s.profile.Colors, s.profile.TrueColor, s.profile.Colors > 0
+	}
+	return 256, false, true
+}
+
 func (s *simscreen) ChannelEvents(ch chan<- Event, quit <-chan struct{}) {
 	defer close(ch)
 	for {
@@ -392,14 +746,55 @@ func (s *simscreen) PostEvent(ev Event) error {
 	}
 }
 
+// post posts an event generated by the simulation, with the time from
+// its clock.  Events that the simulated terminal could not send are
+// discarded.
+func (s *simscreen) post(ev Event) {
+	now := s.getClock().Now()
+	switch ev := ev.(type) {
+	case *EventKey:
+		if !s.hasKey(ev.Key()) {
+			return
+		}
+		ev.t = now
+	case *EventMouse:
+		if s.profile != nil && !s.profile.Mouse {
+			return
+		}
+		ev.t = now
+	case *EventResize:
+		ev.t = now
+	}
+	_ = s.PostEvent(ev)
+}
+
+func (s *simscreen) SetClock(c Clock) {
+	s.clock.Store(&c)
+}
+
+func (s *simscreen) getClock() Clock {
+	if c, ok := s.clock.Load().(*Clock); ok {
+		return *c
+	}
+	return SystemClock
+}
+
 func (s *simscreen) InjectMouse(x, y int, buttons ButtonMask, mod ModMask) {
 	ev := NewEventMouse(x, y, buttons, mod)
-	s.PostEvent(ev)
+	s.Lock()
+	mouse, n := s.mouse, s.wheelKeys
+	s.Unlock()
+	if !mouse && n > 0 {
+		for _, kev := range wheelKeys(ev, n) {
+			s.post(kev)
+		}
+		return
+	}
+	s.post(ev)
 }
 
 func (s *simscreen) InjectKey(key Key, r rune, mod ModMask) {
-	ev := NewEventKey(key, r, mod)
-	s.PostEvent(ev)
+	s.post(NewEventKey(key, r, mod))
 }
 
 func (s *simscreen) InjectKeyBytes(b []byte) bool {
@@ -409,8 +804,7 @@ outer:
 	for len(b) > 0 {
 		if b[0] >= ' ' && b[0] <= 0x7F {
 			// printable ASCII easy to deal with -- no encodings
-			ev := NewEventKey(KeyRune, rune(b[0]), ModNone)
-			s.PostEvent(ev)
+			s.post(NewEventKey(KeyRune, rune(b[0]), ModNone))
 			b = b[1:]
 			continue
 		}
@@ -421,8 +815,7 @@ outer:
 			if Key(b[0]) >= KeyCtrlA && Key(b[0]) <= KeyCtrlZ {
 				mod = ModCtrl
 			}
-			ev := NewEventKey(Key(b[0]), 0, mod)
-			s.PostEvent(ev)
+			s.post(NewEventKey(Key(b[0]), 0, mod))
 			b = b[1:]
 			continue
 		}
@@ -435,8 +828,7 @@ outer:
 			if nout != 0 {
 				r, _ := utf8.DecodeRune(utfb[:nout])
 				if r != utf8.RuneError {
-					ev := NewEventKey(KeyRune, r, ModNone)
-					s.PostEvent(ev)
+					s.post(NewEventKey(KeyRune, r, ModNone))
 				}
 				b = b[nin:]
 				continue outer
@@ -456,7 +848,9 @@ func (s *simscreen) Sync() {
 	s.resize()
 	s.back.Invalidate()
 	s.draw()
+	changes := s.changes.take()
 	s.Unlock()
+	reportChanges(changes)
 }
 
 func (s *simscreen) CharacterSet() string {
@@ -464,6 +858,17 @@ func (s *simscreen) CharacterSet() string {
 }
 
 func (s *simscreen) SetSize(w, h int) {
+	s.setSize(w, h)
+}
+
+func (s *simscreen) ResizeWindow(w, h int) {
+	if ow, oh := s.setSize(w, h); w != ow || h != oh {
+		s.post(newEventResize(w, h, ow, oh, ResizeWindow))
+	}
+}
+
+// setSize changes the size of the screen, and returns the old size.
+func (s *simscreen) setSize(w, h int) (int, int) {
 	s.Lock()
 	newc := make([]SimCell, w*h)
 	for row := 0; row < h && row < s.physh; row++ {
@@ -472,10 +877,12 @@ func (s *simscreen) SetSize(w, h int) {
 		}
 	}
 	s.cursorx, s.cursory = -1, -1
+	ow, oh := s.physw, s.physh
 	s.physw, s.physh = w, h
 	s.front = newc
-	s.back.Resize(w, h)
+	resizeCells(&s.back, &s.history, s.reflow, w, h)
 	s.Unlock()
+	return ow, oh
 }
 
 func (s *simscreen) GetContents() ([]SimCell, int, int) {
@@ -498,6 +905,34 @@ func (s *simscreen) RegisterRuneFallback(r rune, subst string) {
 	s.Unlock()
 }
 
+// LoadSoftFont only checks that the font is valid, as the simulation
+// already has every glyph it needs.
+func (s *simscreen) LoadSoftFont(f *SoftFont) error {
+	if f == nil {
+		return nil
+	}
+	_, _, err := f.encode()
+	return err
+}
+
+// HasGlyphs reports only the box drawing set, as the simulation has
+// no font to speak of.
+func (s *simscreen) HasGlyphs(set GlyphSet) bool {
+	var have GlyphSet
+	if s.CanDisplay(RuneHLine, false) {
+		have |= GlyphsBoxDrawing
+	}
+	return have&set == set
+}
+
+func (s *simscreen) RegisterRuneFallbacks(fallbacks map[rune]string) {
+	s.Lock()
+	for r, subst := range fallbacks {
+		s.fallback[r] = subst
+	}
+	s.Unlock()
+}
+
 func (s *simscreen) UnregisterRuneFallback(r rune) {
 	s.Lock()
 	delete(s.fallback, r)
@@ -523,23 +958,86 @@ func (s *simscreen) CanDisplay(r rune, checkFallbacks bool) bool {
 	if _, ok := s.fallback[r]; ok {
 		return true
 	}
+	if _, ok := bestFit(r); ok {
+		return true
+	}
 	return false
 }
 
 func (s *simscreen) HasMouse() bool {
-	return false
+	return s.profile != nil && s.profile.Mouse
 }
 
 func (s *simscreen) Resize(int, int, int, int) {}
 
-func (s *simscreen) HasKey(Key) bool {
-	return true
+func (s *simscreen) HasKey(k Key) bool {
+	return s.hasKey(k)
 }
 
 func (s *simscreen) Beep() error {
 	return nil
 }
 
+func (s *simscreen) Notify(string, string) error {
+	return ErrNotSupported
+}
+
+func (s *simscreen) SetProgress(ProgressState, int) error {
+	return ErrNotSupported
+}
+
+// SetBellPolicy is ignored by the simulation, whose bell is silent.
+func (s *simscreen) SetBellPolicy(BellPolicy) {}
+
+func (s *simscreen) SetVisualBell(time.Duration, int, int, int, int) {}
+
+func (s *simscreen) HasWindowOps() bool {
+	return false
+}
+
+func (s *simscreen) IconifyWindow(bool) {}
+
+func (s *simscreen) MaximizeWindow(bool) {}
+
+func (s *simscreen) RaiseWindow() {}
+
+func (s *simscreen) SetWindowPosition(int, int) {}
+
+func (s *simscreen) QueryWindowPosition() {}
+
+func (s *simscreen) FontMetrics() (int, int, int) {
+	return 0, 0, 0
+}
+
+func (s *simscreen) DisableSignalHandling() {}
+
+func (s *simscreen) HandleSignal(os.Signal) {}
+
+func (s *simscreen) EmitRaw(seq string) error {
+	if !validSequence(seq) {
+		return ErrInvalidSequence
+	}
+	if kind := imageKind(seq); kind != "" {
+		s.Lock()
+		s.images = append(s.images, SimImage{
+			Kind: kind,
+			X:    s.cursorx,
+			Y:    s.cursory,
+			Data: seq,
+		})
+		s.Unlock()
+	}
+	return nil
+}
+
+func (s *simscreen) GetImages() []SimImage {
+	s.Lock()
+	defer s.Unlock()
+	return append([]SimImage{}, s.images...)
+}
+
+func (s *simscreen) OnUnknownSequence(UnknownSequenceHandler) {}
+
 func (s *simscreen) Suspend() error {
 	return nil
 }
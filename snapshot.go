@@ -0,0 +1,75 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// A snapshot is a copy of the contents of a cell buffer.  Taking one is
+// cheap: only the cell array is copied, as the combining characters of a
+// cell are never modified in place, and so can be shared.  Restoring one
+// only changes the cell contents, so the usual dirty tracking redraws
+// just the cells that differ.
+
+type snapshot struct {
+	id    int
+	w, h  int
+	cells []cell
+}
+
+// snapshots is a stack of saved cell buffer contents.
+type snapshots struct {
+	next  int
+	saved []snapshot
+}
+
+// save copies the contents of cb, and returns an id for them.
+func (ss *snapshots) save(cb *CellBuffer) int {
+	ss.next++
+	snap := snapshot{id: ss.next, w: cb.w, h: cb.h, cells: make([]cell, len(cb.cells))}
+	for i := range cb.cells {
+		c := &cb.cells[i]
+		snap.cells[i] = cell{
+			currMain:  c.currMain,
+			currComb:  c.currComb,
+			currStyle: c.currStyle,
+			width:     c.width,
+		}
+	}
+	ss.saved = append(ss.saved, snap)
+	return snap.id
+}
+
+// restore puts back the contents saved with id, and discards that
+// snapshot along with any saved after it.  If the buffer has been resized
+// since, only the part that is in both is restored.
+func (ss *snapshots) restore(cb *CellBuffer, id int) error {
+	for i := len(ss.saved) - 1; i >= 0; i-- {
+		snap := &ss.saved[i]
+		if snap.id != id {
+			continue
+		}
+		for y := 0; y < snap.h && y < cb.h; y++ {
+			for x := 0; x < snap.w && x < cb.w; x++ {
+				oc := &snap.cells[y*snap.w+x]
+				c := &cb.cells[y*cb.w+x]
+				c.currMain = oc.currMain
+				c.currComb = oc.currComb
+				c.currStyle = oc.currStyle
+				c.width = oc.width
+			}
+		}
+		ss.saved = ss.saved[:i]
+		return nil
+	}
+	return ErrNoSuchState
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestSaveState(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(4, 1)
+	for x, r := range "abcd" {
+		s.SetContent(x, 0, r, nil, StyleDefault)
+	}
+	s.Show()
+	id1 := s.SaveState()
+	s.SetContent(1, 0, 'X', nil, StyleDefault.Bold(true))
+	id2 := s.SaveState()
+	s.SetContent(2, 0, 'Y', nil, StyleDefault)
+	s.Show()
+
+	if err := s.RestoreState(id1); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	s.Show()
+	b, _, _ := s.GetContents()
+	got := ""
+	for _, c := range b {
+		got += string(c.Runes)
+	}
+	if got != "abcd" || b[1].Style != StyleDefault {
+		t.Errorf("wrong contents after restore: %q", got)
+	}
+	if err := s.RestoreState(id2); err != ErrNoSuchState {
+		t.Errorf("later state should be discarded: %v", err)
+	}
+	if err := s.RestoreState(id1); err != ErrNoSuchState {
+		t.Errorf("state restored twice: %v", err)
+	}
+}
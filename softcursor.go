@@ -0,0 +1,123 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// A software cursor is drawn as part of the screen content, by restyling
+// (and optionally replacing) the cell under it when it is displayed.  The
+// cell buffer itself is never modified, so applications need not redraw
+// anything when the cursor moves; the screen redraws the cell where the
+// cursor was, and the cell where it now is.  Besides the primary cursor,
+// which may be drawn either by the terminal or in software, applications
+// may have any number of secondary cursors, which are always drawn in
+// software.
+
+// Cursor is a secondary cursor, registered with Screen.SetCursors.  It
+// is drawn in software, in the same way as the software cursor described
+// by Screen.SetSoftCursor: Style is laid over the style of the cell at X,
+// Y, and Rune, if not zero, replaces its content.
+type Cursor struct {
+	X     int
+	Y     int
+	Style Style
+	Rune  rune
+}
+
+// softCursor tracks the software cursors for a screen.
+type softCursor struct {
+	on    bool
+	style Style
+	r     rune
+	extra []Cursor
+	drawn []Cursor // cursors drawn by the last draw, primary first
+}
+
+// set enables the software cursor, or disables it if style is StyleDefault
+// and r is zero.
+func (sc *softCursor) set(style Style, r rune) {
+	sc.on = style != StyleDefault || r != 0
+	sc.style = style
+	sc.r = r
+}
+
+// setExtra replaces the secondary cursors.
+func (sc *softCursor) setExtra(cursors []Cursor) {
+	sc.extra = append(sc.extra[:0], cursors...)
+}
+
+// prepare is called before drawing, with the primary cursor position.
+// It marks the cells where cursors were and where they will be drawn
+// dirty.
+func (sc *softCursor) prepare(cb *CellBuffer, x, y int) {
+	for _, c := range sc.drawn {
+		cb.SetDirty(c.X, c.Y, true)
+	}
+	sc.drawn = sc.drawn[:0]
+	w, h := cb.Size()
+	add := func(c Cursor) {
+		if c.X >= 0 && c.Y >= 0 && c.X < w && c.Y < h {
+			cb.SetDirty(c.X, c.Y, true)
+			sc.drawn = append(sc.drawn, c)
+		}
+	}
+	if sc.on {
+		add(Cursor{X: x, Y: y, Style: sc.style, Rune: sc.r})
+	}
+	for _, c := range sc.extra {
+		add(c)
+	}
+}
+
+// find returns the cursor drawn at x, y, if there is one.
+func (sc *softCursor) find(x, y int) (Cursor, bool) {
+	for _, c := range sc.drawn {
+		if c.X == x && c.Y == y {
+			return c, true
+		}
+	}
+	return Cursor{}, false
+}
+
+// at returns true if a cursor is drawn at x, y.
+func (sc *softCursor) at(x, y int) bool {
+	_, ok := sc.find(x, y)
+	return ok
+}
+
+// apply returns the content to display for the cell at x, y.
+func (sc *softCursor) apply(x, y int, mainc rune, combc []rune, style Style) (rune, []rune, Style) {
+	c, ok := sc.find(x, y)
+	if !ok {
+		return mainc, combc, style
+	}
+	if c.Rune != 0 {
+		mainc, combc = c.Rune, nil
+	}
+	return mainc, combc, style.overlay(c.Style)
+}
+
+// overlay returns s modified by o: the colors of o replace those of s,
+// unless they are ColorDefault, and the attributes of o are toggled.
+// Thus StyleDefault.Reverse(true) shows s in reverse video, or, if s is
+// already reversed, in normal video.
+func (s Style) overlay(o Style) Style {
+	if o.fg != ColorDefault {
+		s.fg = o.fg
+	}
+	if o.bg != ColorDefault {
+		s.bg = o.bg
+	}
+	s.attrs ^= o.attrs
+	return s
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestSoftCursor(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(3, 1)
+	s.SetContent(0, 0, 'a', nil, StyleDefault)
+	s.SetContent(1, 0, 'b', nil, StyleDefault.Reverse(true))
+	s.SetSoftCursor(StyleDefault.Reverse(true), 0)
+	s.ShowCursor(0, 0)
+	s.Show()
+	b, _, _ := s.GetContents()
+	if b[0].Style != StyleDefault.Reverse(true) || string(b[0].Runes) != "a" {
+		t.Errorf("cursor not drawn: %v %q", b[0].Style, string(b[0].Runes))
+	}
+	if _, _, vis := s.GetCursor(); vis {
+		t.Errorf("hardware cursor should be hidden")
+	}
+
+	s.ShowCursor(1, 0)
+	s.Show()
+	b, _, _ = s.GetContents()
+	if b[0].Style != StyleDefault || b[1].Style != StyleDefault {
+		t.Errorf("cursor not moved: %v %v", b[0].Style, b[1].Style)
+	}
+	if r, _, st, _ := s.GetContent(1, 0); r != 'b' || st != StyleDefault.Reverse(true) {
+		t.Errorf("cell buffer modified")
+	}
+
+	s.SetSoftCursor(StyleDefault.Foreground(ColorRed), '_')
+	s.Show()
+	b, _, _ = s.GetContents()
+	if string(b[1].Runes) != "_" || b[1].Style != StyleDefault.Reverse(true).Foreground(ColorRed) {
+		t.Errorf("cursor rune not drawn: %q %v", string(b[1].Runes), b[1].Style)
+	}
+
+	s.SetSoftCursor(StyleDefault, 0)
+	s.Show()
+	b, _, _ = s.GetContents()
+	if string(b[1].Runes) != "b" {
+		t.Errorf("cursor not removed")
+	}
+	if _, _, vis := s.GetCursor(); !vis {
+		t.Errorf("hardware cursor should be visible")
+	}
+}
+
+func TestCursors(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+	s.SetSize(4, 1)
+	for x, r := range "abcd" {
+		s.SetContent(x, 0, r, nil, StyleDefault)
+	}
+	s.ShowCursor(0, 0)
+	s.SetCursors([]Cursor{
+		{X: 2, Y: 0, Style: StyleDefault.Reverse(true)},
+		{X: 3, Y: 0, Style: StyleDefault.Background(ColorBlue), Rune: '|'},
+		{X: 9, Y: 0, Style: StyleDefault.Reverse(true)},
+	})
+	s.Show()
+	b, _, _ := s.GetContents()
+	if b[0].Style != StyleDefault || b[2].Style != StyleDefault.Reverse(true) ||
+		b[3].Style != StyleDefault.Background(ColorBlue) || string(b[3].Runes) != "|" {
+		t.Errorf("cursors not drawn")
+	}
+	if x, y, vis := s.GetCursor(); x != 0 || y != 0 || !vis {
+		t.Errorf("primary cursor should be the hardware cursor")
+	}
+
+	// The cursors survive a redraw of the whole screen.
+	s.Sync()
+	b, _, _ = s.GetContents()
+	if b[2].Style != StyleDefault.Reverse(true) {
+		t.Errorf("cursors lost on sync")
+	}
+
+	s.SetCursors(nil)
+	s.Show()
+	b, _, _ = s.GetContents()
+	if b[2].Style != StyleDefault || string(b[3].Runes) != "d" {
+		t.Errorf("cursors not removed")
+	}
+}
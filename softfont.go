@@ -0,0 +1,148 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SoftFont is a set of custom glyphs which can be downloaded to a DEC
+// VT220 or later terminal (using DECDLD), and then used to display
+// runes that the terminal could not otherwise draw.
+type SoftFont struct {
+	// Width and Height are the size of each glyph in pixels.  The
+	// VT220 uses 8x10 cells, and the VT320 and VT340 use 15x12.
+	Width  int
+	Height int
+
+	// Glyphs maps each rune to its bitmap, given as rows of pixels
+	// from top to bottom.  In each row a space or '.' is a clear pixel,
+	// and any other character is a set one.  At most 94 glyphs may be
+	// loaded at once.
+	Glyphs map[rune][]string
+}
+
+const (
+	softFontDscs  = " @"      // designator for the soft character set
+	softFontEnter = "\x1b( @" // designate the soft font as G0
+	softFontExit  = "\x1b(B"  // designate ASCII as G0 again
+	softFontMax   = 94        // number of characters in a 94-character set
+	softFontFirst = '!'       // first character in the set
+)
+
+// hasSoftFonts returns true if the terminal is known to support DECDLD.
+// Terminfo has no capability for it, so we rely on the name; the VT100
+// and VT102 lack it, but everything from the VT220 on has it.
+func hasSoftFonts(name string) bool {
+	for _, vt := range []string{"vt2", "vt3", "vt4", "vt5"} {
+		if strings.HasPrefix(name, vt) {
+			return true
+		}
+	}
+	return false
+}
+
+// encode returns the DECDLD sequence to load the font, and the string to
+// send for each rune.
+func (f *SoftFont) encode() (string, map[rune]string, error) {
+	if f.Width < 1 || f.Width > 15 || f.Height < 1 || f.Height > 16 ||
+		len(f.Glyphs) == 0 || len(f.Glyphs) > softFontMax {
+		return "", nil, ErrInvalidSoftFont
+	}
+
+	runes := make([]rune, 0, len(f.Glyphs))
+	for r := range f.Glyphs {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+	var sb strings.Builder
+	// Font 1, starting at the first character, erasing the whole font,
+	// with the given cell size, for 80 columns, full cell, 94 characters.
+	sb.WriteString("\x1bP1;1;0;")
+	sb.WriteString(strconv.Itoa(f.Width))
+	sb.WriteString(";0;2;")
+	sb.WriteString(strconv.Itoa(f.Height))
+	sb.WriteString(";0{")
+	sb.WriteString(softFontDscs)
+
+	seqs := make(map[rune]string, len(runes))
+	for i, r := range runes {
+		if i > 0 {
+			sb.WriteByte(';')
+		}
+		sb.WriteString(sixelGlyph(f.Glyphs[r], f.Width, f.Height))
+		seqs[r] = softFontEnter + string(softFontFirst+rune(i)) + softFontExit
+	}
+	sb.WriteString("\x1b\\")
+	return sb.String(), seqs, nil
+}
+
+// sixelGlyph encodes a bitmap as sixels: each character holds a column
+// of six pixels, least significant bit at the top, and bands of six rows
+// are separated by '/'.
+func sixelGlyph(rows []string, w, h int) string {
+	lit := func(x, y int) bool {
+		if y >= len(rows) || x >= len(rows[y]) {
+			return false
+		}
+		c := rows[y][x]
+		return c != ' ' && c != '.'
+	}
+	var sb strings.Builder
+	for band := 0; band*6 < h; band++ {
+		if band > 0 {
+			sb.WriteByte('/')
+		}
+		for x := 0; x < w; x++ {
+			bits := byte(0)
+			for i := 0; i < 6 && band*6+i < h; i++ {
+				if lit(x, band*6+i) {
+					bits |= 1 << uint(i)
+				}
+			}
+			sb.WriteByte('?' + bits)
+		}
+	}
+	return sb.String()
+}
+
+func (t *tScreen) LoadSoftFont(f *SoftFont) error {
+	if !hasSoftFonts(t.ti.Name) {
+		return ErrNotSupported
+	}
+	if f == nil {
+		t.Lock()
+		t.softFont = ""
+		t.soft = nil
+		t.Unlock()
+		return nil
+	}
+	load, seqs, err := f.encode()
+	if err != nil {
+		return err
+	}
+	t.Lock()
+	t.softFont = load
+	t.soft = seqs
+	if t.running {
+		t.TPuts(load)
+	}
+	t.cells.Invalidate()
+	t.Unlock()
+	return nil
+}
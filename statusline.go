@@ -0,0 +1,153 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strings"
+
+	runewidth "github.com/mattn/go-runewidth"
+)
+
+// Some terminals have a status line, separate from the main display.
+// Terminfo describes these with the tsl and fsl capabilities.  The DEC
+// VT320 and later have one too, although their terminfo entries rarely
+// say so; on these it is made host writable with DECSSDT, and selected
+// with DECSASD.  On other terminals the status line is emulated using the
+// bottom row of the screen, which is then not available to the
+// application (Size reports one less row).
+
+const (
+	decStatusHost  = "\x1b[2$~" // DECSSDT: host writable status line
+	decStatusNone  = "\x1b[0$~" // DECSSDT: no status line
+	decStatusEnter = "\x1b[1$}" // DECSASD: write to the status line
+	decStatusExit  = "\x1b[0$}" // DECSASD: write to the main display
+)
+
+// hasDECStatus returns true if the terminal is known to have a DEC host
+// writable status line.
+func hasDECStatus(name string) bool {
+	for _, vt := range []string{"vt3", "vt4", "vt5"} {
+		if strings.HasPrefix(name, vt) {
+			return true
+		}
+	}
+	return false
+}
+
+// trueStatus returns true if the terminal has a real status line.
+func (t *tScreen) trueStatus() bool {
+	return t.ti.ToStatus != "" || hasDECStatus(t.ti.Name)
+}
+
+func (t *tScreen) SetStatusLine(text string, style Style) {
+	t.Lock()
+	defer t.Unlock()
+	t.status = text
+	t.statusStyle = style
+	t.statusOn = text != ""
+	if !t.running {
+		return
+	}
+	if t.trueStatus() {
+		t.drawTrueStatus()
+		t.modes.status = t.statusOn
+		return
+	}
+	// Emulated; the size of the main region changes when the status
+	// line comes or goes.
+	t.resize()
+	t.statusDirty = true
+	t.draw()
+}
+
+// statusText truncates text to fit in w columns, returning its width.
+func statusText(text string, w int) (string, int) {
+	n := 0
+	for i, r := range text {
+		rw := runewidth.RuneWidth(r)
+		if n+rw > w {
+			return text[:i], n
+		}
+		n += rw
+	}
+	return text, n
+}
+
+// encodeStatus converts text to the terminal's character set.
+func (t *tScreen) encodeStatus(text string) string {
+	buf := make([]byte, 0, len(text))
+	for _, r := range text {
+		buf = t.encodeRune(r, buf)
+	}
+	return string(buf)
+}
+
+// drawTrueStatus writes the status line on terminals that have one.
+// The style is not used, as status lines generally have their own.
+func (t *tScreen) drawTrueStatus() {
+	ti := t.ti
+	text, _ := statusText(t.status, t.w)
+	if ti.ToStatus != "" {
+		if !t.statusOn {
+			t.TPuts(ti.DisStatus)
+			return
+		}
+		t.TPuts(ti.TParm(ti.ToStatus, 0))
+		t.writeString(t.encodeStatus(text))
+		t.TPuts(ti.FromStatus)
+		return
+	}
+	if !t.statusOn {
+		t.TPuts(decStatusNone)
+		return
+	}
+	t.TPuts(decStatusHost)
+	t.TPuts(decStatusEnter)
+	// Erase whatever was there before.
+	t.writeString(t.encodeStatus(text) + "\x1b[K")
+	t.TPuts(decStatusExit)
+}
+
+// statusRows returns the number of rows taken from the screen by an
+// emulated status line.
+func (t *tScreen) statusRows() int {
+	if t.statusOn && !t.trueStatus() {
+		return 1
+	}
+	return 0
+}
+
+// drawStatus draws an emulated status line below the main region, if
+// it has changed.
+func (t *tScreen) drawStatus() {
+	if !t.statusDirty || t.statusRows() == 0 {
+		return
+	}
+	t.statusDirty = false
+	style := t.statusStyle
+	if style == StyleDefault {
+		style = t.style
+	}
+	// Avoid the last column if writing there would scroll the screen.
+	w := t.w
+	if t.ti.AutoMargin {
+		w--
+	}
+	text, n := statusText(t.status, w)
+	t.TPuts(t.ti.TGoto(0, t.h))
+	t.sendStyle(style)
+	t.writeString(t.encodeStatus(text) + strings.Repeat(" ", w-n))
+	t.cx, t.cy = -1, -1
+}
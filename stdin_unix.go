@@ -36,6 +36,7 @@ type stdIoTty struct {
 	out   *os.File
 	saved *term.State
 	sig   chan os.Signal
+	nosig bool
 	cb    func()
 	stopQ chan struct{}
 	dev   string
@@ -103,7 +104,9 @@ func (tty *stdIoTty) Start() error {
 		}
 	}(tty.stopQ)
 
-	signal.Notify(tty.sig, syscall.SIGWINCH)
+	if !tty.nosig {
+		signal.Notify(tty.sig, syscall.SIGWINCH)
+	}
 	return nil
 }
 
@@ -152,6 +155,12 @@ func (tty *stdIoTty) WindowSize() (int, int, error) {
 	return w, h, nil
 }
 
+func (tty *stdIoTty) DisableSignals(disable bool) {
+	tty.l.Lock()
+	tty.nosig = disable
+	tty.l.Unlock()
+}
+
 func (tty *stdIoTty) NotifyResize(cb func()) {
 	tty.l.Lock()
 	tty.cb = cb
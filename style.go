@@ -58,6 +58,20 @@ func (s Style) Background(c Color) Style {
 	}
 }
 
+// Inherit returns s with any ColorInherit colors replaced by the
+// corresponding colors of base.  Screens do this when drawing, using
+// their default style as the base, so applications only need it when
+// combining styles of their own.
+func (s Style) Inherit(base Style) Style {
+	if s.fg == ColorInherit {
+		s.fg = base.fg
+	}
+	if s.bg == ColorInherit {
+		s.bg = base.bg
+	}
+	return s
+}
+
 // Decompose breaks a style up, returning the foreground, background,
 // and other attributes.  The URL if set is not included.
 func (s Style) Decompose() (fg Color, bg Color, attr AttrMask) {
@@ -15,6 +15,7 @@
 package tcell
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -39,3 +40,45 @@ func TestStyle(t *testing.T) {
 		t.Errorf("Bad custom style (%v, %v, %v)", fg, bg, attr)
 	}
 }
+
+func TestStyleText(t *testing.T) {
+	st := StyleDefault.Bold(true).Underline(true).
+		Foreground(NewHexColor(0xff8800)).Background(ColorBlack).
+		Url("https://example.com/a,b")
+	b, err := st.MarshalText()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if s := string(b); s != "bold,underline,fg=#ff8800,bg=black,url=https://example.com/a,b" {
+		t.Errorf("bad text %q", s)
+	}
+	var back Style
+	if err := back.UnmarshalText(b); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if back != st {
+		t.Errorf("did not round trip: %v", back)
+	}
+
+	if err := back.UnmarshalText([]byte(" Italic, fg = rgb(1,2,3) ")); err != nil {
+		t.Errorf("unmarshal: %v", err)
+	} else if back != StyleDefault.Italic(true).Foreground(NewRGBColor(1, 2, 3)) {
+		t.Errorf("wrong style %v", back)
+	}
+	if err := back.UnmarshalText(nil); err != nil || back != StyleDefault {
+		t.Errorf("empty text should be the default style")
+	}
+
+	for _, bad := range []string{
+		"boldly", "fg=nosuch", "bold,bold", "fg=red,fg=blue",
+		"bold,", "bold=yes", "url=", "underline=curly:#f00",
+	} {
+		back = StyleDefault.Dim(true)
+		if err := back.UnmarshalText([]byte(bad)); !errors.Is(err, ErrInvalidStyle) {
+			t.Errorf("%q: expected ErrInvalidStyle, got %v", bad, err)
+		}
+		if back != StyleDefault.Dim(true) {
+			t.Errorf("%q: style modified on error", bad)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// StyledRun is a run of text drawn in one style.
+type StyledRun struct {
+	Text  string
+	Style Style
+}
+
+// StyledText is text made of runs in different styles, such as the output
+// of a syntax highlighter.  It may have several lines, separated by
+// newlines.  Tabs are expanded to every eighth column of each line.
+// DrawStyledText draws it into a region of the screen.
+type StyledText []StyledRun
+
+// Append returns st with text added to it in style.  Text in the same
+// style as the last run is added to that run.
+func (st StyledText) Append(text string, style Style) StyledText {
+	if text == "" {
+		return st
+	}
+	if n := len(st); n > 0 && st[n-1].Style == style {
+		st[n-1].Text += text
+		return st
+	}
+	return append(st, StyledRun{Text: text, Style: style})
+}
+
+// String returns the text, without its styles.
+func (st StyledText) String() string {
+	var sb strings.Builder
+	for _, run := range st {
+		sb.WriteString(run.Text)
+	}
+	return sb.String()
+}
+
+// Lines splits the text at newlines, returning each line without its
+// newline.
+func (st StyledText) Lines() []StyledText {
+	lines := []StyledText{nil}
+	for _, run := range st {
+		parts := strings.Split(run.Text, "\n")
+		for i, part := range parts {
+			if i > 0 {
+				lines = append(lines, nil)
+			}
+			n := len(lines) - 1
+			lines[n] = lines[n].Append(part, run.Style)
+		}
+	}
+	return lines
+}
+
+// Size returns the width in cells of the widest line, and the number of
+// lines, which is the extent of the text for scrolling.
+func (st StyledText) Size() (int, int) {
+	w, h := 0, 1
+	st.layout(func(row, col int, _ rune, _ []rune, _ Style, width int) bool {
+		if col+width > w {
+			w = col + width
+		}
+		return true
+	})
+	for _, run := range st {
+		h += strings.Count(run.Text, "\n")
+	}
+	return w, h
+}
+
+// layout calls f for each cell of the text in turn, with its row and
+// column (counted from the start of the text), until it returns false.
+// Combining characters are given with the character they follow.
+func (st StyledText) layout(f func(row, col int, mainc rune, combc []rune, style Style, width int) bool) {
+	row, col := 0, 0
+	var mainc rune
+	var combc []rune
+	var style Style
+	width := 0 // of the cell pending, or zero if there is none
+	flush := func() bool {
+		if width == 0 {
+			return true
+		}
+		ok := f(row, col, mainc, combc, style, width)
+		col += width
+		width = 0
+		return ok
+	}
+	for _, run := range st {
+		for _, r := range run.Text {
+			w := runewidth.RuneWidth(r)
+			switch {
+			case r == '\n':
+				if !flush() {
+					return
+				}
+				row++
+				col = 0
+			case r == '\t':
+				if !flush() {
+					return
+				}
+				for stop := (col/defaultTabWidth + 1) * defaultTabWidth; col < stop; col++ {
+					if !f(row, col, ' ', nil, run.Style, 1) {
+						return
+					}
+				}
+			case w == 0:
+				if width > 0 {
+					combc = append(combc, r)
+				}
+			default:
+				if !flush() {
+					return
+				}
+				mainc, combc, style, width = r, nil, run.Style, w
+			}
+		}
+	}
+	flush()
+}
+
+// DrawStyledText draws text into the region at x, y with the given width
+// and height, scrolled left by hscroll columns, so that editors and pagers
+// can draw highlighted text without converting it cell by cell.  Lines
+// past the height of the region are not drawn, and the rest of each row
+// (and any rows past the end of the text) are filled with blanks in
+// fill, so that the region is entirely redrawn.  A wide character cut by
+// either edge of the region is drawn as blanks.
+func DrawStyledText(dst CellSetter, x, y, width, height int, text StyledText, hscroll int, fill Style) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	ends := make([]int, height) // where the text of each row ends
+	text.layout(func(row, col int, mainc rune, combc []rune, style Style, w int) bool {
+		if row >= height {
+			return false
+		}
+		col -= hscroll
+		if col+w <= 0 || col >= width {
+			return true
+		}
+		if col < 0 || col+w > width {
+			for c := col; c < col+w; c++ {
+				if c >= 0 && c < width {
+					dst.SetContent(x+c, y+row, ' ', nil, style)
+				}
+			}
+		} else {
+			dst.SetContent(x+col, y+row, mainc, combc, style)
+		}
+		ends[row] = col + w
+		if ends[row] > width {
+			ends[row] = width
+		}
+		return true
+	})
+	for row, end := range ends {
+		for c := end; c < width; c++ {
+			dst.SetContent(x+c, y+row, ' ', nil, fill)
+		}
+	}
+}
+
+// TokenStyles maps the names of types of tokens, such as those of a syntax
+// highlighter, to styles.  A type without a style of its own has that of
+// the longest name that begins its name, such as "Keyword" for
+// "KeywordType", as the types of the chroma package are named; the empty
+// name thus gives the style of any other tokens.
+type TokenStyles map[string]Style
+
+// Style returns the style of tokens of the named type.
+func (ts TokenStyles) Style(typ string) Style {
+	if style, ok := ts[typ]; ok {
+		return style
+	}
+	best, style := -1, StyleDefault
+	for name, s := range ts {
+		if len(name) > best && strings.HasPrefix(typ, name) {
+			best, style = len(name), s
+		}
+	}
+	return style
+}
+
+// StyledTextFromTokens builds StyledText from a stream of tokens, calling
+// next for each, with the name of its type and its text, until it returns
+// false.  The tokens of a chroma lexer, for example, can be used with:
+//
+//	it, _ := lexer.Tokenise(nil, source)
+//	text := tcell.StyledTextFromTokens(func() (string, string, bool) {
+//		tok := it()
+//		return tok.Type.String(), tok.Value, tok != chroma.EOF
+//	}, styles)
+func StyledTextFromTokens(next func() (typ, text string, ok bool), styles TokenStyles) StyledText {
+	var st StyledText
+	cache := map[string]Style{}
+	for {
+		typ, text, ok := next()
+		if !ok {
+			return st
+		}
+		style, found := cache[typ]
+		if !found {
+			style = styles.Style(typ)
+			cache[typ] = style
+		}
+		st = st.Append(text, style)
+	}
+}
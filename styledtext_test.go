@@ -0,0 +1,70 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestStyledText(t *testing.T) {
+	kw := StyleDefault.Bold(true)
+	str := StyleDefault.Foreground(ColorGreen)
+	styles := TokenStyles{"Keyword": kw, "LiteralString": str}
+	tokens := [][2]string{
+		{"KeywordDeclaration", "func"}, {"Text", " f() {\n"},
+		{"Text", "\t"}, {"Keyword", "return"}, {"Text", " "}, {"LiteralStringDouble", `"世界"`},
+		{"Text", "\n}"},
+	}
+	text := StyledTextFromTokens(func() (string, string, bool) {
+		if len(tokens) == 0 {
+			return "", "", false
+		}
+		tok := tokens[0]
+		tokens = tokens[1:]
+		return tok[0], tok[1], true
+	}, styles)
+	if len(text) != 6 || text[1].Text != " f() {\n\t" {
+		t.Errorf("wrong runs: %q", text)
+	}
+	if lines := text.Lines(); len(lines) != 3 || lines[1].String() != "\treturn \"世界\"" {
+		t.Errorf("wrong lines: %q", lines)
+	}
+	if w, h := text.Size(); w != 21 || h != 3 {
+		t.Errorf("wrong size: %d x %d", w, h)
+	}
+
+	cb := &CellBuffer{}
+	cb.Resize(8, 3)
+	cb.Fill('x', StyleDefault)
+	DrawStyledText(cb, 1, 0, 6, 2, text, 11, StyleDefault)
+	want := []string{"x      x", "xurn \" x", "xxxxxxxx"}
+	for y, line := range want {
+		var got []rune
+		for x := 0; x < 8; {
+			r, _, _, w := cb.GetContent(x, y)
+			got = append(got, r)
+			x += w
+		}
+		if string(got) != line {
+			t.Errorf("wrong row %d: %q", y, string(got))
+		}
+	}
+	if _, _, style, _ := cb.GetContent(1, 1); style != kw {
+		t.Errorf("wrong style: %v", style)
+	}
+	if _, _, style, _ := cb.GetContent(5, 1); style != str {
+		t.Errorf("wrong style of string: %v", style)
+	}
+}
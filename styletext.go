@@ -0,0 +1,156 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Styles are written as a comma separated list of attribute names and
+// key=value pairs, for example "bold,underline,fg=#ff8800,bg=black".
+// Colors use the notations accepted by ParseColor.  A URL, if present,
+// is always last, and extends to the end of the text, so that it may
+// itself contain commas.  The empty string is StyleDefault.
+
+var styleAttrNames = []struct {
+	name string
+	attr AttrMask
+}{
+	{"bold", AttrBold},
+	{"blink", AttrBlink},
+	{"reverse", AttrReverse},
+	{"underline", AttrUnderline},
+	{"dim", AttrDim},
+	{"italic", AttrItalic},
+	{"strikethrough", AttrStrikeThrough},
+}
+
+// MarshalText implements encoding.TextMarshaler.  The result can be
+// parsed by UnmarshalText to recover the same style.
+func (s Style) MarshalText() ([]byte, error) {
+	if s.attrs&AttrInvalid != 0 {
+		return nil, ErrInvalidStyle
+	}
+	var parts []string
+	for _, a := range styleAttrNames {
+		if s.attrs&a.attr != 0 {
+			parts = append(parts, a.name)
+		}
+	}
+	if s.fg != ColorDefault {
+		parts = append(parts, "fg="+s.fg.String())
+	}
+	if s.bg != ColorDefault {
+		parts = append(parts, "bg="+s.bg.String())
+	}
+	if s.url != "" {
+		parts = append(parts, "url="+s.url)
+	}
+	return []byte(strings.Join(parts, ",")), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.  Unknown attribute
+// names or keys, malformed colors, and settings given more than once are
+// all reported as errors wrapping ErrInvalidStyle, and leave s unchanged.
+func (s *Style) UnmarshalText(text []byte) error {
+	var st Style
+	seen := map[string]bool{}
+	rest := strings.TrimSpace(string(text))
+	for rest != "" {
+		var item string
+		item, rest = nextStyleItem(rest)
+		if item == "" {
+			return fmt.Errorf("%w: empty item", ErrInvalidStyle)
+		}
+
+		key, val := item, ""
+		if i := strings.IndexByte(item, '='); i >= 0 {
+			key, val = strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
+		}
+		key = strings.ToLower(key)
+		if seen[key] {
+			return fmt.Errorf("%w: %q given more than once", ErrInvalidStyle, key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "fg", "bg":
+			c, err := ParseColor(val)
+			if err != nil {
+				return fmt.Errorf("%w: %s: %v", ErrInvalidStyle, key, err)
+			}
+			if key == "fg" {
+				st.fg = c
+			} else {
+				st.bg = c
+			}
+		case "url":
+			if val == "" {
+				return fmt.Errorf("%w: empty url", ErrInvalidStyle)
+			}
+			st.url = val
+		default:
+			attr := styleAttr(key)
+			if attr == AttrNone {
+				return fmt.Errorf("%w: unknown attribute %q", ErrInvalidStyle, key)
+			}
+			if strings.IndexByte(item, '=') >= 0 {
+				return fmt.Errorf("%w: %q does not take a value", ErrInvalidStyle, key)
+			}
+			st.attrs |= attr
+		}
+	}
+	*s = st
+	return nil
+}
+
+// nextStyleItem splits the first item from a style string.  Commas inside
+// parentheses, as in "rgb(1,2,3)", do not end an item, and neither do
+// commas in a URL.
+func nextStyleItem(s string) (string, string) {
+	s = strings.TrimLeft(s, " ")
+	if strings.HasPrefix(s, "url=") {
+		return strings.TrimSpace(s), ""
+	}
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				rest := s[i+1:]
+				if strings.TrimSpace(rest) == "" {
+					// a trailing comma is an empty item
+					rest = " "
+				}
+				return strings.TrimSpace(s[:i]), rest
+			}
+		}
+	}
+	return strings.TrimSpace(s), ""
+}
+
+func styleAttr(name string) AttrMask {
+	for _, a := range styleAttrNames {
+		if a.name == name {
+			return a.attr
+		}
+	}
+	return AttrNone
+}
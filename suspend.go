@@ -0,0 +1,150 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// termModes records the modes that we have set in the terminal.  The
+// screen keeps the modes that are in effect, and every change, whether
+// from engaging, disengaging, or one of the setters, is made by setModes,
+// which sends just what differs.  So suspending puts back exactly what
+// was set (and nothing else), and resuming restores it.
+type termModes struct {
+	altScreen  bool          // alternate screen (smcup)
+	keypadXmit bool          // keypad transmit mode (smkx)
+	appKeypad  bool          // application keypad (DECKPAM)
+	c1         bool          // 8-bit controls (S8C1T)
+	mouse      MouseFlags    // mouse reporting
+	paste      bool          // bracketed paste
+	shape      MouseShape    // mouse pointer shape
+	progress   ProgressState // progress indicator
+	percent    int           // progress, as a percentage
+	status     bool          // host status line
+}
+
+// wantedModes returns the modes that the application has asked for.
+func (t *tScreen) wantedModes() termModes {
+	m := termModes{
+		altScreen:  !t.noAltScreen,
+		keypadXmit: true,
+		appKeypad:  t.keypad && t.ti.EnterKeypad != "",
+		c1:         t.c1,
+		mouse:      t.mouseModes(),
+		paste:      t.pasteEnabled,
+		shape:      t.mouseShape,
+		progress:   t.progState,
+		status:     t.statusOn && t.trueStatus(),
+	}
+	if m.progress != ProgressNone {
+		m.percent = t.progPercent
+	}
+	return m
+}
+
+// updateModes brings the terminal into line with the wanted modes, if
+// we are running.  Otherwise they will be set when we are next engaged.
+func (t *tScreen) updateModes() {
+	if t.running {
+		t.setModes(t.wantedModes())
+	}
+}
+
+// setModes changes the modes of the terminal from those in effect to m.
+// Modes are set in the order that engage has always used, and cleared in
+// the reverse order.
+func (t *tScreen) setModes(m termModes) {
+	old := t.modes
+	ti := t.ti
+	if m.c1 && !old.c1 {
+		t.TPuts(s8c1t)
+	}
+	if m.mouse != old.mouse {
+		t.enableMouse(m.mouse)
+	}
+	if m.paste != old.paste {
+		t.enablePasting(m.paste)
+	}
+	if m.shape != old.shape {
+		t.TPuts(mouseShapeSeq(m.shape))
+	}
+	if m.progress != old.progress || m.percent != old.percent {
+		// Cleared when disengaging, as the shell will not clear it.
+		t.writeString(progressSeq(m.progress, m.percent))
+	}
+	if m.altScreen != old.altScreen {
+		if m.altScreen {
+			t.TPuts(ti.EnterCA)
+		} else {
+			t.TPuts(ti.Clear)
+			t.TPuts(ti.ExitCA)
+		}
+	}
+	if old.appKeypad && !m.appKeypad {
+		t.enableKeypad(false)
+	}
+	if m.keypadXmit != old.keypadXmit {
+		if m.keypadXmit {
+			t.TPuts(ti.EnterKeypad)
+		} else {
+			t.TPuts(ti.ExitKeypad)
+		}
+	}
+	if m.appKeypad && !old.appKeypad {
+		t.enableKeypad(true)
+	}
+	if m.status != old.status {
+		on := t.statusOn
+		t.statusOn = m.status
+		t.drawTrueStatus()
+		t.statusOn = on
+	}
+	if old.c1 && !m.c1 {
+		t.TPuts(s7c1t)
+	}
+	t.modes = m
+}
+
+// Suspend and Resume nest; only the outermost pair disengages and then
+// engages the terminal.
+
+func (t *tScreen) Suspend() error {
+	return t.suspend(true)
+}
+
+func (t *tScreen) SuspendNoClear() error {
+	return t.suspend(false)
+}
+
+func (t *tScreen) suspend(clearScreen bool) error {
+	t.Lock()
+	t.suspends++
+	outer := t.suspends == 1
+	t.Unlock()
+	if outer {
+		t.disengage(clearScreen)
+	}
+	return nil
+}
+
+func (t *tScreen) Resume() error {
+	t.Lock()
+	if t.suspends > 0 {
+		t.suspends--
+		if t.suspends > 0 {
+			t.Unlock()
+			return nil
+		}
+	}
+	t.Unlock()
+	return t.engage()
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"sort"
+)
+
+// defaultTabWidth is the distance between tab stops, unless changed
+// with SetTabStops.
+const defaultTabWidth = 8
+
+// SetTabStops sets the columns used as tab stops.  Past the last column
+// given, further stops are placed at the same interval as the last two
+// (or every eight columns if only one is given).  Passing nil restores
+// the default of a stop every eight columns.
+func (cb *CellBuffer) SetTabStops(stops []int) {
+	if len(stops) == 0 {
+		cb.tabs = nil
+		return
+	}
+	cb.tabs = append([]int{}, stops...)
+	sort.Ints(cb.tabs)
+}
+
+// NextTabStop returns the column of the first tab stop after column x.
+func (cb *CellBuffer) NextTabStop(x int) int {
+	if x < 0 {
+		x = -1
+	}
+	tabs := cb.tabs
+	if len(tabs) == 0 {
+		return (x/defaultTabWidth + 1) * defaultTabWidth
+	}
+	for _, t := range tabs {
+		if t > x {
+			return t
+		}
+	}
+	last := tabs[len(tabs)-1]
+	step := defaultTabWidth
+	if len(tabs) > 1 && last > tabs[len(tabs)-2] {
+		step = last - tabs[len(tabs)-2]
+	}
+	return last + ((x-last)/step+1)*step
+}
+
+// setTab fills the cells from x up to the next tab stop with blanks.
+// Only the cells within the buffer are visited, however far off it x is.
+func (cb *CellBuffer) setTab(x, y int, style Style) {
+	if y < 0 || y >= cb.h || x >= cb.w {
+		return
+	}
+	if x < 0 {
+		x = 0
+	}
+	end := cb.NextTabStop(x)
+	if end > cb.w {
+		end = cb.w
+	}
+	for ; x < end; x++ {
+		cb.SetContent(x, y, ' ', nil, style)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"testing"
+)
+
+func TestTabStops(t *testing.T) {
+	s := mkTestScreen(t, "")
+	defer s.Fini()
+
+	if x := s.NextTabStop(0); x != 8 {
+		t.Errorf("default tab stop wrong: %d", x)
+	}
+	if x := s.NextTabStop(8); x != 16 {
+		t.Errorf("default tab stop wrong: %d", x)
+	}
+
+	s.SetTabStops([]int{4, 10})
+	for _, c := range []struct{ x, stop int }{{0, 4}, {4, 10}, {10, 16}, {17, 22}} {
+		if x := s.NextTabStop(c.x); x != c.stop {
+			t.Errorf("tab stop after %d: got %d, want %d", c.x, x, c.stop)
+		}
+	}
+
+	s.SetContent(0, 0, 'A', nil, StyleDefault)
+	s.SetContent(1, 0, 'B', nil, StyleDefault)
+	s.SetContent(2, 0, '\t', nil, StyleDefault)
+	s.SetContent(s.NextTabStop(2), 0, 'C', nil, StyleDefault)
+	s.Show()
+	b, _, _ := s.GetContents()
+	for x, want := range "AB  C" {
+		if got := string(b[x].Bytes); got != string(want) {
+			t.Errorf("cell %d: got %q, want %q", x, got, string(want))
+		}
+	}
+
+	s.SetTabStops(nil)
+	if x := s.NextTabStop(3); x != 8 {
+		t.Errorf("tab stops not reset: %d", x)
+	}
+}
+
+func TestTabFar(t *testing.T) {
+	var cb CellBuffer
+	cb.Resize(10, 2)
+	cb.SetContent(0, 0, 'x', nil, StyleDefault)
+	cb.SetContent(1, 0, 'x', nil, StyleDefault)
+	// Far off the buffer, tabs must not visit every column in between.
+	cb.SetContent(-1e9, 0, '\t', nil, StyleDefault)
+	cb.SetContent(-1e9, 5, '\t', nil, StyleDefault)
+	cb.SetContent(1e9, 1, '\t', nil, StyleDefault)
+	if mainc, _, _, _ := cb.GetContent(1, 0); mainc != ' ' {
+		t.Errorf("tab from the left not expanded: %q", mainc)
+	}
+}
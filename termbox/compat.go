@@ -24,10 +24,16 @@ import (
 
 var screen tcell.Screen
 var outMode OutputMode
+var inMode InputMode
+var pasteOn bool
+var lastButtons tcell.ButtonMask
 
 // Init initializes the screen for use.
 func Init() error {
 	outMode = OutputNormal
+	inMode = InputEsc
+	pasteOn = false
+	lastButtons = 0
 	if s, e := tcell.NewScreen(); e != nil {
 		return e
 	} else if e = s.Init(); e != nil {
@@ -65,8 +71,8 @@ func Size() (int, int) {
 }
 
 // Attribute affects the presentation of characters, such as color, boldness,
-// and so forth.
-type Attribute uint16
+// and so forth.  (This is wider than in termbox, to hold RGB colors.)
+type Attribute uint64
 
 // Colors first.  The order here is significant.
 const (
@@ -86,8 +92,35 @@ const (
 	AttrBold Attribute = 1 << (9 + iota)
 	AttrUnderline
 	AttrReverse
+	AttrBlink
+	AttrDim
+	AttrCursive
 )
 
+// RGB colors are kept above the attributes, with a flag to tell them
+// from the palette colors (which include black, as zero).
+const (
+	attrRGB  Attribute = 1 << 31
+	rgbShift           = 32
+)
+
+// RGBToAttribute returns the attribute for a 24-bit color, for use as a
+// foreground or background color.  Terminals that lack 24-bit color show
+// the nearest color that they have.
+func RGBToAttribute(r, g, b uint8) Attribute {
+	return attrRGB | (Attribute(r)<<16|Attribute(g)<<8|Attribute(b))<<rgbShift
+}
+
+// AttributeToRGB returns the red, green and blue of an attribute made by
+// RGBToAttribute, and zero otherwise.
+func AttributeToRGB(a Attribute) (uint8, uint8, uint8) {
+	if a&attrRGB == 0 {
+		return 0, 0, 0
+	}
+	v := a >> rgbShift
+	return uint8(v >> 16), uint8(v >> 8), uint8(v)
+}
+
 func fixColor(c tcell.Color) tcell.Color {
 	if c == tcell.ColorDefault {
 		return c
@@ -101,21 +134,27 @@ func fixColor(c tcell.Color) tcell.Color {
 		c = tcell.PaletteColor(int(c)%216 + 16)
 	case OutputGrayscale:
 		c %= tcell.PaletteColor(int(c)%24 + 232)
+	case OutputRGB:
+		c = tcell.PaletteColor(int(c) & 0xff)
 	default:
 		c = tcell.ColorDefault
 	}
 	return c
 }
 
+// mkColor returns the color of an attribute.
+func mkColor(a Attribute) tcell.Color {
+	if a&attrRGB != 0 {
+		r, g, b := AttributeToRGB(a)
+		return tcell.NewRGBColor(int32(r), int32(g), int32(b))
+	}
+	return fixColor(tcell.PaletteColor(int(a)&0x1ff - 1))
+}
+
 func mkStyle(fg, bg Attribute) tcell.Style {
 	st := tcell.StyleDefault
 
-	f := tcell.PaletteColor(int(fg)&0x1ff - 1)
-	b := tcell.PaletteColor(int(bg)&0x1ff - 1)
-
-	f = fixColor(f)
-	b = fixColor(b)
-	st = st.Foreground(f).Background(b)
+	st = st.Foreground(mkColor(fg)).Background(mkColor(bg))
 	if (fg|bg)&AttrBold != 0 {
 		st = st.Bold(true)
 	}
@@ -125,6 +164,15 @@ func mkStyle(fg, bg Attribute) tcell.Style {
 	if (fg|bg)&AttrReverse != 0 {
 		st = st.Reverse(true)
 	}
+	if (fg|bg)&AttrBlink != 0 {
+		st = st.Blink(true)
+	}
+	if (fg|bg)&AttrDim != 0 {
+		st = st.Dim(true)
+	}
+	if (fg|bg)&AttrCursive != 0 {
+		st = st.Italic(true)
+	}
 	return st
 }
 
@@ -139,21 +187,49 @@ func Clear(fg, bg Attribute) {
 	}
 }
 
-// InputMode is not used.
+// InputMode determines which input is reported.  The modes are flags,
+// with the same values as in termbox.
 type InputMode int
 
-// Unused input modes; here for compatibility.
+// Input modes.  InputEsc and InputAlt make no difference, as escape
+// sequences are always decoded, and Alt reported with ModAlt.
 const (
-	InputCurrent InputMode = iota
-	InputEsc
+	InputEsc InputMode = 1 << iota
 	InputAlt
-	InputMouse
+	InputMouse  // mouse buttons, and motion while a button is held
+	InputMotion // all motion of the mouse (an extension of termbox)
+
+	InputCurrent InputMode = 0
 )
 
-// SetInputMode does not do anything in this version.
+// SetInputMode sets the input mode, returning the new mode.  The mouse is
+// reported if InputMouse (or InputMotion) is set.
 func SetInputMode(mode InputMode) InputMode {
-	// We don't do anything else right now
-	return InputEsc
+	if mode == InputCurrent {
+		return inMode
+	}
+	switch {
+	case mode&InputMotion != 0:
+		screen.EnableMouse(tcell.MouseMotionEvents)
+	case mode&InputMouse != 0:
+		screen.EnableMouse(tcell.MouseButtonEvents | tcell.MouseDragEvents)
+	default:
+		screen.DisableMouse()
+	}
+	inMode = mode
+	return inMode
+}
+
+// SetPasteMode enables (or disables) bracketed paste.  While enabled,
+// pasted text is delivered between an EventPasteStart and EventPasteEnd,
+// so that it can be told from typing.  This is an extension of termbox.
+func SetPasteMode(on bool) {
+	if on {
+		screen.EnablePaste()
+	} else {
+		screen.DisablePaste()
+	}
+	pasteOn = on
 }
 
 // OutputMode represents an output mode, which determines how colors
@@ -167,6 +243,7 @@ const (
 	Output256
 	Output216
 	OutputGrayscale
+	OutputRGB // palette colors as Output256, and RGBToAttribute
 )
 
 // SetOutputMode is used to set the color palette used.
@@ -177,7 +254,7 @@ func SetOutputMode(mode OutputMode) OutputMode {
 	switch mode {
 	case OutputCurrent:
 		return outMode
-	case OutputNormal, Output256, Output216, OutputGrayscale:
+	case OutputNormal, Output256, Output216, OutputGrayscale, OutputRGB:
 		outMode = mode
 		return mode
 	default:
@@ -230,6 +307,8 @@ const (
 	EventInterrupt
 	EventError
 	EventRaw
+	EventPasteStart // extensions of termbox; see SetPasteMode
+	EventPasteEnd
 )
 
 // Keys codes.
@@ -316,9 +395,10 @@ const (
 	KeyCtrlLsqBracket = Key(tcell.KeyCtrlLeftSq)
 )
 
-// Modifiers.
+// Modifiers.  ModMotion is set on mouse events that report motion.
 const (
-	ModAlt = Modifier(tcell.ModAlt)
+	ModAlt    = Modifier(tcell.ModAlt)
+	ModMotion = Modifier(1 << 14)
 )
 
 func makeEvent(tev tcell.Event) Event {
@@ -346,11 +426,54 @@ func makeEvent(tev tcell.Event) Event {
 			Ch:   ch,
 			Mod:  Modifier(mod),
 		}
+	case *tcell.EventMouse:
+		return makeMouseEvent(tev)
+	case *tcell.EventPaste:
+		if !pasteOn {
+			return Event{Type: EventNone}
+		}
+		if tev.Start() {
+			return Event{Type: EventPasteStart}
+		}
+		return Event{Type: EventPasteEnd}
 	default:
 		return Event{Type: EventNone}
 	}
 }
 
+// makeMouseEvent converts a mouse event, with a key for the button as
+// termbox has it.  Events where the buttons have not changed are motion.
+func makeMouseEvent(tev *tcell.EventMouse) Event {
+	x, y := tev.Position()
+	btns := tev.Buttons()
+	ev := Event{Type: EventMouse, MouseX: x, MouseY: y}
+	if tev.Modifiers()&tcell.ModAlt != 0 {
+		ev.Mod = ModAlt
+	}
+	switch {
+	case btns&tcell.WheelUp != 0:
+		ev.Key = MouseWheelUp
+		return ev
+	case btns&tcell.WheelDown != 0:
+		ev.Key = MouseWheelDown
+		return ev
+	case btns&tcell.Button1 != 0:
+		ev.Key = MouseLeft
+	case btns&tcell.Button3 != 0:
+		ev.Key = MouseMiddle
+	case btns&tcell.Button2 != 0:
+		ev.Key = MouseRight
+	default:
+		ev.Key = MouseRelease
+	}
+	btns &= tcell.Button1 | tcell.Button2 | tcell.Button3
+	if btns == lastButtons && (btns != 0 || inMode&InputMotion != 0) {
+		ev.Mod |= ModMotion
+	}
+	lastButtons = btns
+	return ev
+}
+
 // ParseEvent is not supported.
 func ParseEvent(data []byte) Event {
 	// Not supported
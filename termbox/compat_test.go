@@ -0,0 +1,110 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package termbox
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// mkTestScreen sets up the package as Init does, but on a simulation
+// screen.
+func mkTestScreen(t *testing.T) tcell.SimulationScreen {
+	s := tcell.NewSimulationScreen("")
+	if e := s.Init(); e != nil {
+		t.Fatalf("failed to init: %v", e)
+	}
+	screen = s
+	outMode = OutputNormal
+	inMode = InputEsc
+	pasteOn = false
+	lastButtons = 0
+	return s
+}
+
+func TestRGBAttribute(t *testing.T) {
+	a := RGBToAttribute(0x12, 0x34, 0x56)
+	if r, g, b := AttributeToRGB(a); r != 0x12 || g != 0x34 || b != 0x56 {
+		t.Errorf("wrong color: %x %x %x", r, g, b)
+	}
+	if r, g, b := AttributeToRGB(ColorRed | AttrBold); r != 0 || g != 0 || b != 0 {
+		t.Errorf("palette color has RGB: %x %x %x", r, g, b)
+	}
+	// Black is zero, and must still be told from a palette color.
+	if c := mkColor(RGBToAttribute(0, 0, 0)); c != tcell.NewRGBColor(0, 0, 0) {
+		t.Errorf("wrong black: %v", c)
+	}
+}
+
+func TestSetCellRGB(t *testing.T) {
+	s := mkTestScreen(t)
+	defer Close()
+
+	if m := SetOutputMode(OutputRGB); m != OutputRGB {
+		t.Fatalf("wrong output mode: %v", m)
+	}
+	SetCell(0, 0, 'x', RGBToAttribute(0x12, 0x34, 0x56)|AttrBold|AttrCursive, ColorBlue)
+	Flush()
+	cells, _, _ := s.GetContents()
+	fg, bg, attr := cells[0].Style.Decompose()
+	if fg != tcell.NewRGBColor(0x12, 0x34, 0x56) || bg != tcell.ColorNavy {
+		t.Errorf("wrong colors: %v %v", fg, bg)
+	}
+	if attr != tcell.AttrBold|tcell.AttrItalic {
+		t.Errorf("wrong attributes: %v", attr)
+	}
+}
+
+func TestMouseMotion(t *testing.T) {
+	mkTestScreen(t)
+	defer Close()
+
+	SetInputMode(InputEsc | InputMouse)
+	expect := func(btn tcell.ButtonMask, key Key, motion bool) {
+		t.Helper()
+		ev := makeEvent(tcell.NewEventMouse(1, 2, btn, tcell.ModNone))
+		if ev.Type != EventMouse || ev.Key != key || ev.MouseX != 1 || ev.MouseY != 2 {
+			t.Errorf("wrong event: %+v", ev)
+		}
+		if (ev.Mod&ModMotion != 0) != motion {
+			t.Errorf("wrong motion for %+v", ev)
+		}
+	}
+	expect(tcell.Button1, MouseLeft, false)
+	expect(tcell.Button1, MouseLeft, true) // dragged
+	expect(tcell.ButtonNone, MouseRelease, false)
+	expect(tcell.ButtonNone, MouseRelease, false)
+
+	SetInputMode(InputEsc | InputMotion)
+	expect(tcell.ButtonNone, MouseRelease, true)
+	expect(tcell.Button2, MouseRight, false)
+}
+
+func TestPasteMode(t *testing.T) {
+	mkTestScreen(t)
+	defer Close()
+
+	if ev := makeEvent(tcell.NewEventPaste(true)); ev.Type != EventNone {
+		t.Errorf("paste reported when disabled: %+v", ev)
+	}
+	SetPasteMode(true)
+	if ev := makeEvent(tcell.NewEventPaste(true)); ev.Type != EventPasteStart {
+		t.Errorf("wrong paste start: %+v", ev)
+	}
+	if ev := makeEvent(tcell.NewEventPaste(false)); ev.Type != EventPasteEnd {
+		t.Errorf("wrong paste end: %+v", ev)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TerminalID identifies the terminal emulator, by the name and version
+// that it reports for itself.  Unlike $TERM, which is often set to that of
+// another terminal, and $TERM_PROGRAM, which is not passed on by ssh, this
+// comes from the terminal itself.
+type TerminalID struct {
+	Name    string // such as "XTerm", "kitty", "WezTerm" or "iTerm2"
+	Version string // such as "367" or "0.26.5", or empty if not given
+}
+
+// String returns the name and version, as "name version".
+func (id TerminalID) String() string {
+	if id.Version == "" {
+		return id.Name
+	}
+	return id.Name + " " + id.Version
+}
+
+// Before returns true if the terminal's version is known, and is earlier
+// than version.  Versions are compared by their numbers, so that "3.10"
+// is later than "3.9"; anything after the numbers (as in "3.3a") is
+// ignored.
+func (id TerminalID) Before(version string) bool {
+	if id.Version == "" {
+		return false
+	}
+	a, b := versionNumbers(id.Version), versionNumbers(version)
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			return x < y
+		}
+	}
+	return false
+}
+
+// versionNumbers returns the numbers at the start of a version, which are
+// separated by dots.
+func versionNumbers(v string) []int {
+	var nums []int
+	for _, part := range strings.Split(v, ".") {
+		end := 0
+		for end < len(part) && part[end] >= '0' && part[end] <= '9' {
+			end++
+		}
+		if end == 0 {
+			break
+		}
+		n, _ := strconv.Atoi(part[:end])
+		nums = append(nums, n)
+		if end < len(part) {
+			break
+		}
+	}
+	return nums
+}
+
+// parseXTVersion parses the payload of the reply to XTVERSION, which is a
+// DCS string such as ">|XTerm(367)", ">|kitty(0.26.5)" or ">|tmux 3.3a".
+func parseXTVersion(payload string) (TerminalID, bool) {
+	if !strings.HasPrefix(payload, ">|") {
+		return TerminalID{}, false
+	}
+	s := strings.TrimSpace(payload[2:])
+	if s == "" {
+		return TerminalID{}, false
+	}
+	if open := strings.IndexByte(s, '('); open > 0 && strings.HasSuffix(s, ")") {
+		return TerminalID{Name: strings.TrimSpace(s[:open]), Version: s[open+1 : len(s)-1]}, true
+	}
+	if sp := strings.IndexByte(s, ' '); sp > 0 {
+		return TerminalID{Name: s[:sp], Version: strings.TrimSpace(s[sp+1:])}, true
+	}
+	return TerminalID{Name: s}, true
+}
+
+// da2Terminals are the terminals recognized by the terminal type in their
+// reply to the secondary device attributes (DA2) query, with the way that
+// each gives its version in the firmware version.
+var da2Terminals = map[int]struct {
+	name    string
+	version func(int) string
+}{
+	41: {"XTerm", strconv.Itoa},   // the patch number
+	65: {"VTE", dottedVersion},    // 6800 for 0.68.0
+	77: {"mintty", dottedVersion}, // 30105 for 3.1.5
+	83: {"screen", dottedVersion}, // 40900 for 4.9.0
+	84: {"tmux", nil},
+}
+
+// dottedVersion returns the version major.minor.patch, given as major *
+// 10000 + minor * 100 + patch.
+func dottedVersion(n int) string {
+	return strconv.Itoa(n/10000) + "." + strconv.Itoa(n/100%100) + "." + strconv.Itoa(n%100)
+}
+
+// parseDA2 parses the payload of the reply to DA2, such as ">41;367;0c",
+// returning the terminal if it is one that can be recognized from it.
+func parseDA2(payload string) (TerminalID, bool) {
+	if !strings.HasPrefix(payload, ">") || !strings.HasSuffix(payload, "c") {
+		return TerminalID{}, false
+	}
+	params := strings.Split(payload[1:len(payload)-1], ";")
+	if len(params) < 2 {
+		return TerminalID{}, false
+	}
+	typ, e1 := strconv.Atoi(params[0])
+	fw, e2 := strconv.Atoi(params[1])
+	term, ok := da2Terminals[typ]
+	if e1 != nil || e2 != nil || !ok {
+		return TerminalID{}, false
+	}
+	id := TerminalID{Name: term.name}
+	if term.version != nil {
+		id.Version = term.version(fw)
+	}
+	return id, true
+}
+
+// terminalQuirk is an override, as for a quirks file (see quirks.go), for
+// the terminals of a name (ignoring case), or only for their versions
+// before a version, if one is given.
+type terminalQuirk struct {
+	name   string
+	before string
+	key    string
+	value  string
+}
+
+// terminalQuirks are applied to the terminal once it has identified
+// itself.  They take the place of recognizing the terminal by the
+// environment, which is not passed on over ssh, so they are overridden by
+// the environment and by the user's quirks file.
+var terminalQuirks = []terminalQuirk{
+	{name: "iTerm2", key: "TCELL_NOTIFY", value: "osc9"},
+	{name: "kitty", key: "TCELL_NOTIFY", value: "osc99"},
+	{name: "WezTerm", key: "TCELL_NOTIFY", value: "osc777"},
+	{name: "foot", key: "TCELL_NOTIFY", value: "osc777"},
+	{name: "ghostty", key: "TCELL_PROGRESS", value: "enable"},
+	{name: "XTerm", key: "TCELL_MARGINS", value: "enable"},
+	{name: "XTerm", key: "TCELL_REP", value: "enable"},
+}
+
+// quirksFor returns the overrides from terminalQuirks for the terminal.
+func quirksFor(id TerminalID) map[string]string {
+	quirks := make(map[string]string)
+	for _, q := range terminalQuirks {
+		if strings.EqualFold(q.name, id.Name) && (q.before == "" || id.Before(q.before)) {
+			quirks[q.key] = q.value
+		}
+	}
+	return quirks
+}
+
+// identifyTimeout is how long to wait for each reply when identifying the
+// terminal.  Terminals that do not understand a query just ignore it.
+const identifyTimeout = time.Second
+
+// identify asks the terminal what it is, with XTVERSION or else DA2, and
+// applies the quirks for it.  It is run in the background once the screen
+// is first engaged.
+func (t *tScreen) identify() {
+	reply, err := t.Query("\x1b[>0q", func(kind SequenceKind, payload string) bool {
+		return kind == SequenceDCS && strings.HasPrefix(payload, ">|")
+	}, identifyTimeout)
+	id, ok := parseXTVersion(reply)
+	if err != nil || !ok {
+		reply, err = t.Query("\x1b[>c", func(kind SequenceKind, payload string) bool {
+			return kind == SequenceCSI && strings.HasPrefix(payload, ">") && strings.HasSuffix(payload, "c")
+		}, identifyTimeout)
+		if id, ok = parseDA2(reply); err != nil || !ok {
+			return
+		}
+	}
+	t.Lock()
+	t.termID = id
+	t.idQuirks = quirksFor(id)
+	t.prepareNotify()
+	t.prepareProgress()
+	t.prepareRepeat()
+	t.Unlock()
+}
+
+func (t *tScreen) TerminalID() TerminalID {
+	t.Lock()
+	defer t.Unlock()
+	return t.termID
+}
@@ -219,6 +219,10 @@ func LoadTerminfo(name string) (*terminfo.Terminfo, string, error) {
 	t.SetCursor = tc.getstr("cup")
 	t.CursorBack1 = tc.getstr("cub1")
 	t.CursorUp1 = tc.getstr("cuu1")
+	t.ToStatus = tc.getstr("tsl")
+	t.FromStatus = tc.getstr("fsl")
+	t.DisStatus = tc.getstr("dsl")
+	t.RepeatChar = tc.getstr("rep")
 	t.KeyF1 = tc.getstr("kf1")
 	t.KeyF2 = tc.getstr("kf2")
 	t.KeyF3 = tc.getstr("kf3")
@@ -239,6 +239,10 @@ func getinfo(name string) (*terminfo.Terminfo, string, error) {
 	t.CursorBack1 = tc.getstr("cub1")
 	t.CursorUp1 = tc.getstr("cuu1")
 	t.InsertChar = tc.getstr("ich1")
+	t.ToStatus = tc.getstr("tsl")
+	t.FromStatus = tc.getstr("fsl")
+	t.DisStatus = tc.getstr("dsl")
+	t.RepeatChar = tc.getstr("rep")
 	t.AutoMargin = tc.getflag("am")
 	t.KeyF1 = tc.getstr("kf1")
 	t.KeyF2 = tc.getstr("kf2")
@@ -609,6 +613,10 @@ func dotGoInfo(w io.Writer, terms []*TData) {
 		dotGoAddFlag(w, "TrueColor", t.TrueColor)
 		dotGoAddFlag(w, "AutoMargin", t.AutoMargin)
 		dotGoAddStr(w, "InsertChar", t.InsertChar)
+		dotGoAddStr(w, "ToStatus", t.ToStatus)
+		dotGoAddStr(w, "FromStatus", t.FromStatus)
+		dotGoAddStr(w, "DisStatus", t.DisStatus)
+		dotGoAddStr(w, "RepeatChar", t.RepeatChar)
 		dotGoAddStr(w, "CursorDefault", t.CursorDefault)
 		dotGoAddStr(w, "CursorBlinkingBlock", t.CursorBlinkingBlock)
 		dotGoAddStr(w, "CursorSteadyBlock", t.CursorSteadyBlock)
@@ -160,6 +160,10 @@ type Terminfo struct {
 	KeyShfEnd    string // kEND
 	KeyShfInsert string // kIC
 	KeyShfDelete string // kDC
+	ToStatus     string // tsl
+	FromStatus   string // fsl
+	DisStatus    string // dsl
+	RepeatChar   string // rep
 
 	// These are non-standard extensions to terminfo.  This includes
 	// true color support, and some additional keys.  Its kind of bizarre
@@ -732,6 +736,12 @@ func AddTerminfo(t *Terminfo) {
 
 // LookupTerminfo attempts to find a definition for the named $TERM.
 func LookupTerminfo(name string) (*Terminfo, error) {
+	return LookupTerminfoEnv(name, os.Getenv)
+}
+
+// LookupTerminfoEnv is LookupTerminfo, but reads the environment variables
+// that amend the definition ($COLORTERM and $TCELL_TRUECOLOR) with getenv.
+func LookupTerminfoEnv(name string, getenv func(string) string) (*Terminfo, error) {
 	if name == "" {
 		// else on windows: index out of bounds
 		// on the name[0] reference below
@@ -740,7 +750,7 @@ func LookupTerminfo(name string) (*Terminfo, error) {
 
 	addtruecolor := false
 	add256color := false
-	switch os.Getenv("COLORTERM") {
+	switch getenv("COLORTERM") {
 	case "truecolor", "24bit", "24-bit":
 		addtruecolor = true
 	}
@@ -762,7 +772,7 @@ func LookupTerminfo(name string) (*Terminfo, error) {
 		}
 		base := name[:len(name)-len("-truecolor")]
 		for _, s := range suffixes {
-			if t, _ = LookupTerminfo(base + s); t != nil {
+			if t, _ = LookupTerminfoEnv(base+s, getenv); t != nil {
 				addtruecolor = true
 				break
 			}
@@ -777,7 +787,7 @@ func LookupTerminfo(name string) (*Terminfo, error) {
 		}
 		base := name[:len(name)-len("-256color")]
 		for _, s := range suffixes {
-			if t, _ = LookupTerminfo(base + s); t != nil {
+			if t, _ = LookupTerminfoEnv(base+s, getenv); t != nil {
 				add256color = true
 				break
 			}
@@ -788,7 +798,7 @@ func LookupTerminfo(name string) (*Terminfo, error) {
 		return nil, ErrTermNotFound
 	}
 
-	switch os.Getenv("TCELL_TRUECOLOR") {
+	switch getenv("TCELL_TRUECOLOR") {
 	case "":
 	case "disable":
 		addtruecolor = false
@@ -49,7 +49,12 @@ func NewTerminfoScreen() (Screen, error) {
 // LookupTerminfo attempts to find a definition for the named $TERM falling
 // back to attempting to parse the output from infocmp.
 func LookupTerminfo(name string) (ti *terminfo.Terminfo, e error) {
-	ti, e = terminfo.LookupTerminfo(name)
+	return lookupTerminfo(name, os.Getenv)
+}
+
+// lookupTerminfo is LookupTerminfo, reading the environment with getenv.
+func lookupTerminfo(name string, getenv func(string) string) (ti *terminfo.Terminfo, e error) {
+	ti, e = terminfo.LookupTerminfoEnv(name, getenv)
 	if e != nil {
 		ti, e = loadDynamicTerminfo(name)
 		if e != nil {
@@ -69,14 +74,42 @@ func LookupTerminfo(name string) (ti *terminfo.Terminfo, e error) {
 // If passed terminfo is nil, then TERM environment variable is queried for
 // terminal specification.
 func NewTerminfoScreenFromTtyTerminfo(tty Tty, ti *terminfo.Terminfo) (s Screen, e error) {
+	t, e := newTScreen(&screenOptions{tty: tty, ti: ti})
+	if e != nil {
+		return nil, e
+	}
+	return t, nil
+}
+
+// newTScreen creates a terminfo screen.  The options are applied before
+// anything else, so that the environment is not read if it is disabled.
+func newTScreen(o *screenOptions) (*tScreen, error) {
+	t := &tScreen{tty: o.tty}
+	t.noEnv = o.noEnv
+	t.noAltScreen = o.noAlt
+	t.keepMouse = o.keepMouse
+
+	ti := o.ti
 	if ti == nil {
-		ti, e = LookupTerminfo(os.Getenv("TERM"))
-		if e != nil {
-			return
+		var e error
+		if ti, e = lookupTerminfo(t.termName(o.term), t.getenv); e != nil {
+			return nil, e
 		}
 	}
-
-	t := &tScreen{ti: ti, tty: tty}
+	t.ti = ti
+	if t.session = o.session; t.session != nil {
+		t.replaySession()
+	} else {
+		t.loadQuirks()
+	}
+	switch t.getenv("TCELL_TRUECOLOR") {
+	case "", "disable":
+	default:
+		// A quirk may ask for what the environment did not.
+		ti = colorModeTerminfo(ti, ColorModeTrueColor)
+	}
+	ti = colorModeTerminfo(ti, o.colors)
+	t.ti = ti
 
 	t.keyexist = make(map[Key]bool)
 	t.keycodes = make(map[string]*tKeyCode)
@@ -85,11 +118,15 @@ func NewTerminfoScreenFromTtyTerminfo(tty Tty, ti *terminfo.Terminfo) (s Screen,
 	}
 	t.prepareKeys()
 	t.buildAcsMap()
+	t.prepareRepeat()
+	t.prepareSoftBlink()
+	t.lineModes = hasLineModes(ti.Name)
 	t.resizeQ = make(chan bool, 1)
 	t.fallback = make(map[rune]string)
 	for k, v := range RuneFallbacks {
 		t.fallback[k] = v
 	}
+	t.prepareEnv()
 
 	return t, nil
 }
@@ -136,6 +173,7 @@ type tScreen struct {
 	cursory      int
 	wasbtn       bool
 	acs          map[rune]string
+	acsFirst     bool
 	charset      string
 	encoder      transform.Transformer
 	decoder      transform.Transformer
@@ -144,13 +182,39 @@ type tScreen struct {
 	palette      []Color
 	truecolor    bool
 	escaped      bool
-	buttondn     bool
+	buttons      ButtonMask // held, as reported by SGR
 	finiOnce     sync.Once
 	enablePaste  string
 	disablePaste string
 	enterUrl     string
 	exitUrl      string
 	setWinSize   string
+	windowOps    bool
+	noSignals    bool
+	keypad       bool
+	c1           bool
+	lineModes    bool
+	lines        []LineMode
+	linesShown   []LineMode
+	softFont     string
+	soft         map[rune]string
+	status       string
+	statusStyle  Style
+	statusOn     bool
+	statusDirty  bool
+	repeat       bool
+	softBlink    bool
+	blinkOff     bool
+	blinker      *time.Timer
+	softCursor   softCursor
+	selection    selection
+	highlights   selection
+	rows         rowStyles
+	guides       guides
+	mag          magnifier
+	filter       RenderFilter
+	bell         visualBell
+	states       snapshots
 	cursorStyles map[CursorStyle]string
 	cursorStyle  CursorStyle
 	saved        *term.State
@@ -158,7 +222,31 @@ type tScreen struct {
 	running      bool
 	wg           sync.WaitGroup
 	mouseFlags   MouseFlags
+	wheelKeys    int
+	mouseShape   MouseShape
+	notify       notifyKind
+	notifyID     int
+	progress     bool
+	progState    ProgressState
+	progPercent  int
 	pasteEnabled bool
+	modes        termModes
+	noAltScreen  bool
+	keepMouse    bool
+	noMouse      bool
+	noEnv        bool
+	quirks       map[string]string
+	idQuirks     map[string]string // for the terminal, once identified
+	termID       TerminalID
+	session      *Session // being replayed, in place of the real terminal
+	identifying  bool
+	suspends     int
+	history      scrollback
+	reflow       ResizePolicy
+	wantSize     [2]int
+	changes      cellChanges
+	unknownSeq   UnknownSequenceHandler
+	queries      queryManager
 
 	sync.Mutex
 }
@@ -173,22 +261,27 @@ func (t *tScreen) Init() error {
 	t.keytimer = time.NewTimer(time.Millisecond * 50)
 	t.charset = "UTF-8"
 
-	t.charset = getCharset()
+	if t.session != nil {
+		t.charset = t.session.CharacterSet
+	} else {
+		t.charset = getCharset()
+	}
 	if enc := GetEncoding(t.charset); enc != nil {
 		t.encoder = enc.NewEncoder()
 		t.decoder = enc.NewDecoder()
 	} else {
 		return ErrNoCharset
 	}
+	t.prepareC1()
 	ti := t.ti
 
 	// environment overrides
 	w := ti.Columns
 	h := ti.Lines
-	if i, _ := strconv.Atoi(os.Getenv("LINES")); i != 0 {
+	if i, _ := strconv.Atoi(t.getenv("LINES")); i != 0 {
 		h = i
 	}
-	if i, _ := strconv.Atoi(os.Getenv("COLUMNS")); i != 0 {
+	if i, _ := strconv.Atoi(t.getenv("COLUMNS")); i != 0 {
 		w = i
 	}
 	if t.ti.SetFgBgRGB != "" || t.ti.SetFgRGB != "" || t.ti.SetBgRGB != "" {
@@ -196,7 +289,7 @@ func (t *tScreen) Init() error {
 	}
 	// A user who wants to have his themes honored can
 	// set this environment variable.
-	if os.Getenv("TCELL_TRUECOLOR") == "disable" {
+	if t.getenv("TCELL_TRUECOLOR") == "disable" {
 		t.truecolor = false
 	}
 	t.colors = make(map[Color]Color)
@@ -248,6 +341,9 @@ func (t *tScreen) prepareKeyModReplace(key Key, replace Key, mod ModMask, val st
 
 func (t *tScreen) prepareKeyModXTerm(key Key, val string) {
 
+	// The fourth modifier bit is Meta for xterm, but Super for kitty.
+	meta := xtermModBit8(t.ti)
+
 	if strings.HasPrefix(val, "\x1b[") && strings.HasSuffix(val, "~") {
 
 		// Drop the trailing ~
@@ -263,14 +359,14 @@ func (t *tScreen) prepareKeyModXTerm(key Key, val string) {
 		t.prepareKeyModReplace(key, key+36, ModCtrl|ModShift, val+";6~")
 		t.prepareKeyMod(key, ModAlt|ModCtrl, val+";7~")
 		t.prepareKeyMod(key, ModShift|ModAlt|ModCtrl, val+";8~")
-		t.prepareKeyMod(key, ModMeta, val+";9~")
-		t.prepareKeyMod(key, ModMeta|ModShift, val+";10~")
-		t.prepareKeyMod(key, ModMeta|ModAlt, val+";11~")
-		t.prepareKeyMod(key, ModMeta|ModAlt|ModShift, val+";12~")
-		t.prepareKeyMod(key, ModMeta|ModCtrl, val+";13~")
-		t.prepareKeyMod(key, ModMeta|ModCtrl|ModShift, val+";14~")
-		t.prepareKeyMod(key, ModMeta|ModCtrl|ModAlt, val+";15~")
-		t.prepareKeyMod(key, ModMeta|ModCtrl|ModAlt|ModShift, val+";16~")
+		t.prepareKeyMod(key, meta, val+";9~")
+		t.prepareKeyMod(key, meta|ModShift, val+";10~")
+		t.prepareKeyMod(key, meta|ModAlt, val+";11~")
+		t.prepareKeyMod(key, meta|ModAlt|ModShift, val+";12~")
+		t.prepareKeyMod(key, meta|ModCtrl, val+";13~")
+		t.prepareKeyMod(key, meta|ModCtrl|ModShift, val+";14~")
+		t.prepareKeyMod(key, meta|ModCtrl|ModAlt, val+";15~")
+		t.prepareKeyMod(key, meta|ModCtrl|ModAlt|ModShift, val+";16~")
 	} else if strings.HasPrefix(val, "\x1bO") && len(val) == 3 {
 		val = val[2:]
 		t.prepareKeyModReplace(key, key+12, ModShift, "\x1b[1;2"+val)
@@ -280,14 +376,14 @@ func (t *tScreen) prepareKeyModXTerm(key Key, val string) {
 		t.prepareKeyModReplace(key, key+60, ModAlt|ModShift, "\x1b[1;4"+val)
 		t.prepareKeyMod(key, ModAlt|ModCtrl, "\x1b[1;7"+val)
 		t.prepareKeyMod(key, ModShift|ModAlt|ModCtrl, "\x1b[1;8"+val)
-		t.prepareKeyMod(key, ModMeta, "\x1b[1;9"+val)
-		t.prepareKeyMod(key, ModMeta|ModShift, "\x1b[1;10"+val)
-		t.prepareKeyMod(key, ModMeta|ModAlt, "\x1b[1;11"+val)
-		t.prepareKeyMod(key, ModMeta|ModAlt|ModShift, "\x1b[1;12"+val)
-		t.prepareKeyMod(key, ModMeta|ModCtrl, "\x1b[1;13"+val)
-		t.prepareKeyMod(key, ModMeta|ModCtrl|ModShift, "\x1b[1;14"+val)
-		t.prepareKeyMod(key, ModMeta|ModCtrl|ModAlt, "\x1b[1;15"+val)
-		t.prepareKeyMod(key, ModMeta|ModCtrl|ModAlt|ModShift, "\x1b[1;16"+val)
+		t.prepareKeyMod(key, meta, "\x1b[1;9"+val)
+		t.prepareKeyMod(key, meta|ModShift, "\x1b[1;10"+val)
+		t.prepareKeyMod(key, meta|ModAlt, "\x1b[1;11"+val)
+		t.prepareKeyMod(key, meta|ModAlt|ModShift, "\x1b[1;12"+val)
+		t.prepareKeyMod(key, meta|ModCtrl, "\x1b[1;13"+val)
+		t.prepareKeyMod(key, meta|ModCtrl|ModShift, "\x1b[1;14"+val)
+		t.prepareKeyMod(key, meta|ModCtrl|ModAlt, "\x1b[1;15"+val)
+		t.prepareKeyMod(key, meta|ModCtrl|ModAlt|ModShift, "\x1b[1;16"+val)
 	}
 }
 
@@ -355,6 +451,9 @@ func (t *tScreen) prepareExtendedOSC() {
 	} else if t.ti.Mouse != "" {
 		t.setWinSize = "\x1b[8;%p1%p2%d;%dt"
 	}
+	// The other XTWINOPS are not described by terminfo at all, so
+	// assume that any terminal that can be resized can do them too.
+	t.windowOps = t.setWinSize != ""
 }
 
 func (t *tScreen) prepareCursorStyles() {
@@ -391,6 +490,7 @@ func (t *tScreen) prepareKey(key Key, val string) {
 
 func (t *tScreen) prepareKeys() {
 	ti := t.ti
+	t.prepareUserKeys()
 	t.prepareKey(KeyBackspace, ti.KeyBackspace)
 	t.prepareKey(KeyF1, ti.KeyF1)
 	t.prepareKey(KeyF2, ti.KeyF2)
@@ -526,6 +626,8 @@ func (t *tScreen) prepareKeys() {
 	t.prepareBracketedPaste()
 	t.prepareCursorStyles()
 	t.prepareExtendedOSC()
+	t.prepareNotify()
+	t.prepareProgress()
 
 outer:
 	// Add key mappings for control keys.
@@ -571,8 +673,12 @@ func (t *tScreen) SetStyle(style Style) {
 }
 
 func (t *tScreen) Clear() {
-	t.Fill(' ', t.style)
 	t.Lock()
+	defer t.Unlock()
+	if t.fini {
+		return
+	}
+	t.cells.Fill(' ', t.style)
 	t.clear = true
 	w, h := t.cells.Size()
 	// because we are going to clear (see t.clear) in the next cycle,
@@ -583,7 +689,6 @@ func (t *tScreen) Clear() {
 			t.cells.SetDirty(col, row, false)
 		}
 	}
-	t.Unlock()
 }
 
 func (t *tScreen) Fill(r rune, style Style) {
@@ -602,6 +707,18 @@ func (t *tScreen) SetContent(x, y int, mainc rune, combc []rune, style Style) {
 	t.Unlock()
 }
 
+func (t *tScreen) SetTabStops(stops []int) {
+	t.Lock()
+	t.cells.SetTabStops(stops)
+	t.Unlock()
+}
+
+func (t *tScreen) NextTabStop(x int) int {
+	t.Lock()
+	defer t.Unlock()
+	return t.cells.NextTabStop(x)
+}
+
 func (t *tScreen) GetContent(x, y int) (rune, []rune, Style, int) {
 	t.Lock()
 	mainc, combc, style, width := t.cells.GetContent(x, y)
@@ -625,6 +742,12 @@ func (t *tScreen) encodeRune(r rune, buf []byte) []byte {
 	ob = ob[:num]
 	dst := 0
 	var err error
+	if seq, ok := t.soft[r]; ok {
+		return append(buf, []byte(seq)...)
+	}
+	if acs, ok := t.acs[r]; ok && t.acsFirst {
+		return append(buf, []byte(acs)...)
+	}
 	if enc := t.encoder; enc != nil {
 		enc.Reset()
 		dst, _, err = enc.Transform(nb, ob, true)
@@ -636,6 +759,8 @@ func (t *tScreen) encodeRune(r rune, buf []byte) []byte {
 				buf = append(buf, []byte(acs)...)
 			} else if fb, ok := t.fallback[r]; ok {
 				buf = append(buf, []byte(fb)...)
+			} else if fb, ok := bestFit(r); ok {
+				buf = append(buf, []byte(fb)...)
 			} else {
 				buf = append(buf, '?')
 			}
@@ -711,6 +836,52 @@ func (t *tScreen) sendFgBg(fg Color, bg Color) {
 	}
 }
 
+// sendStyle sends the attributes and colors for style, if it is not
+// already the current style.
+func (t *tScreen) sendStyle(style Style) {
+	if style == t.curstyle {
+		return
+	}
+	ti := t.ti
+	fg, bg, attrs := style.Decompose()
+
+	t.TPuts(ti.AttrOff)
+
+	t.sendFgBg(fg, bg)
+	if attrs&AttrBold != 0 {
+		t.TPuts(ti.Bold)
+	}
+	if attrs&AttrUnderline != 0 {
+		t.TPuts(ti.Underline)
+	}
+	if attrs&AttrReverse != 0 {
+		t.TPuts(ti.Reverse)
+	}
+	if attrs&AttrBlink != 0 {
+		t.TPuts(ti.Blink)
+	}
+	if attrs&AttrDim != 0 {
+		t.TPuts(ti.Dim)
+	}
+	if attrs&AttrItalic != 0 {
+		t.TPuts(ti.Italic)
+	}
+	if attrs&AttrStrikeThrough != 0 {
+		t.TPuts(ti.StrikeThrough)
+	}
+
+	// URL string can be long, so don't send it unless we really need to
+	if t.enterUrl != "" && t.curstyle != style {
+		if style.url != "" {
+			t.TPuts(ti.TParm(t.enterUrl, style.url))
+		} else {
+			t.TPuts(t.exitUrl)
+		}
+	}
+
+	t.curstyle = style
+}
+
 func (t *tScreen) drawCell(x, y int) int {
 
 	ti := t.ti
@@ -743,48 +914,8 @@ func (t *tScreen) drawCell(x, y int) int {
 		t.cy = y
 	}
 
-	if style == StyleDefault {
-		style = t.style
-	}
-	if style != t.curstyle {
-		fg, bg, attrs := style.Decompose()
-
-		t.TPuts(ti.AttrOff)
-
-		t.sendFgBg(fg, bg)
-		if attrs&AttrBold != 0 {
-			t.TPuts(ti.Bold)
-		}
-		if attrs&AttrUnderline != 0 {
-			t.TPuts(ti.Underline)
-		}
-		if attrs&AttrReverse != 0 {
-			t.TPuts(ti.Reverse)
-		}
-		if attrs&AttrBlink != 0 {
-			t.TPuts(ti.Blink)
-		}
-		if attrs&AttrDim != 0 {
-			t.TPuts(ti.Dim)
-		}
-		if attrs&AttrItalic != 0 {
-			t.TPuts(ti.Italic)
-		}
-		if attrs&AttrStrikeThrough != 0 {
-			t.TPuts(ti.StrikeThrough)
-		}
-
-		// URL string can be long, so don't send it unless we really need to
-		if t.enterUrl != "" && t.curstyle != style {
-			if style.url != "" {
-				t.TPuts(ti.TParm(t.enterUrl, style.url))
-			} else {
-				t.TPuts(t.exitUrl)
-			}
-		}
-
-		t.curstyle = style
-	}
+	mainc, combc, style, blank := t.displayed(x, y, mainc, combc, style)
+	t.sendStyle(style)
 
 	// now emit runes - taking care to not overrun width with a
 	// wide character, and to ensure that we emit exactly one regular
@@ -804,17 +935,21 @@ func (t *tScreen) drawCell(x, y int) int {
 	}
 
 	str = string(buf)
-	if width > 1 && str == "?" {
-		// No FullWidth character support
-		str = "? "
+	if width > 1 && len(str) == 1 {
+		// No FullWidth character support, so a fallback (or "?")
+		// is padded to fill both cells.
+		str += " "
 		t.cx = -1
 	}
 
-	if x > t.w-width {
+	if x > t.lineWidth(y)-width {
 		// too wide to fit; emit a single space instead
 		width = 1
 		str = " "
 	}
+	if blank {
+		str = strings.Repeat(" ", width)
+	}
 	t.writeString(str)
 	t.cx += width
 	t.cells.SetDirty(x, y, false)
@@ -825,6 +960,33 @@ func (t *tScreen) drawCell(x, y int) int {
 	return width
 }
 
+// displayed returns what is displayed for the cell at x, y, which holds
+// the given content: the overlays, software blinking, the visual bell and
+// the render filter are all applied.  It returns true if the cell is to
+// be drawn blank, as blinking text that is hidden.
+func (t *tScreen) displayed(x, y int, mainc rune, combc []rune, style Style) (rune, []rune, Style, bool) {
+	if !t.mag.on() {
+		// When magnified, the cells have already been overlaid.
+		mainc, combc, style = t.overlay(x, y, mainc, combc, style)
+	}
+	style, blank := t.blinkStyle(style.Inherit(t.style))
+	style = t.bell.apply(x, y, style)
+	if t.filter != nil {
+		style = t.filter(style)
+	}
+	return mainc, combc, style, blank
+}
+
+// overlay returns the content of the cell at x, y as it is displayed, with
+// the row style, guides, highlights, selection and soft cursor applied.
+func (t *tScreen) overlay(x, y int, mainc rune, combc []rune, style Style) (rune, []rune, Style) {
+	style = t.rows.apply(y, style, t.style)
+	style = t.guides.apply(x, y, style)
+	style = t.highlights.apply(x, y, t.w, style)
+	style = t.selection.apply(x, y, t.w, style)
+	return t.softCursor.apply(x, y, mainc, combc, style)
+}
+
 func (t *tScreen) ShowCursor(x, y int) {
 	t.Lock()
 	t.cursorx = x
@@ -842,11 +1004,59 @@ func (t *tScreen) HideCursor() {
 	t.ShowCursor(-1, -1)
 }
 
+func (t *tScreen) SetSoftCursor(style Style, r rune) {
+	t.Lock()
+	t.softCursor.set(style, r)
+	t.Unlock()
+}
+
+func (t *tScreen) SetCursors(cursors []Cursor) {
+	t.Lock()
+	t.softCursor.setExtra(cursors)
+	t.Unlock()
+}
+
+func (t *tScreen) SetSelection(style Style, ranges []Selection) {
+	t.Lock()
+	t.selection.set(style, ranges)
+	t.Unlock()
+}
+
+func (t *tScreen) SaveState() int {
+	t.Lock()
+	defer t.Unlock()
+	return t.states.save(&t.cells)
+}
+
+func (t *tScreen) RestoreState(id int) error {
+	t.Lock()
+	defer t.Unlock()
+	return t.states.restore(&t.cells, id)
+}
+
+func (t *tScreen) SetHighlights(style Style, ranges []Selection) {
+	t.Lock()
+	t.highlights.set(style, ranges)
+	t.Unlock()
+}
+
+func (t *tScreen) Find(pattern string, opts FindOptions) ([]Selection, error) {
+	t.Lock()
+	defer t.Unlock()
+	return t.cells.Find(pattern, opts)
+}
+
+func (t *tScreen) SelectedText() string {
+	t.Lock()
+	defer t.Unlock()
+	return t.cells.selectionText(t.selection.ranges)
+}
+
 func (t *tScreen) showCursor() {
 
 	x, y := t.cursorx, t.cursory
 	w, h := t.cells.Size()
-	if x < 0 || y < 0 || x >= w || y >= h {
+	if x < 0 || y < 0 || x >= w || y >= h || t.softCursor.on {
 		t.hideCursor()
 		return
 	}
@@ -868,6 +1078,9 @@ func (t *tScreen) showCursor() {
 // with the intention that the entire buffer be sent to the terminal in one
 // write operation at some point later.
 func (t *tScreen) writeString(s string) {
+	if t.c1 {
+		s = toC1(s)
+	}
 	if t.buffering {
 		_, _ = io.WriteString(&t.buf, s)
 	} else {
@@ -876,6 +1089,9 @@ func (t *tScreen) writeString(s string) {
 }
 
 func (t *tScreen) TPuts(s string) {
+	if t.c1 {
+		s = toC1(s)
+	}
 	if t.buffering {
 		t.ti.TPuts(&t.buf, s)
 	} else {
@@ -889,7 +1105,9 @@ func (t *tScreen) Show() {
 		t.resize()
 		t.draw()
 	}
+	changes := t.changes.take()
 	t.Unlock()
+	reportChanges(changes)
 }
 
 func (t *tScreen) clearScreen() {
@@ -898,6 +1116,9 @@ func (t *tScreen) clearScreen() {
 	fg, bg, _ := t.style.Decompose()
 	t.sendFgBg(fg, bg)
 	t.TPuts(t.ti.Clear)
+	t.clearLineModes()
+	t.mag.buf.Invalidate()
+	t.statusDirty = true
 	t.clear = false
 }
 
@@ -933,11 +1154,27 @@ func (t *tScreen) draw() {
 		t.clearScreen()
 	}
 
+	t.guides.prepare(&t.cells)
+	t.highlights.prepare(&t.cells)
+	t.selection.prepare(&t.cells)
+	t.softCursor.prepare(&t.cells, t.cursorx, t.cursory)
+	t.changes.collect(&t.cells)
+	magnified := t.mag.on()
+	if magnified {
+		t.magnify()
+	}
+	t.growLines()
 	for y := 0; y < t.h; y++ {
-		for x := 0; x < t.w; x++ {
+		t.drawLineMode(y)
+		w := t.lineWidth(y)
+		for x := 0; x < w; x++ {
+			dirty := t.repeat && t.cells.Dirty(x, y)
 			width := t.drawCell(x, y)
+			if dirty && width == 1 {
+				x += t.repeatCell(x, y, w)
+			}
 			if width > 1 {
-				if x+1 < t.w {
+				if x+1 < w {
 					// this is necessary so that if we ever
 					// go back to drawing that cell, we
 					// actually will *draw* it.
@@ -947,11 +1184,60 @@ func (t *tScreen) draw() {
 			x += width - 1
 		}
 	}
+	t.drawStatus()
+	if magnified {
+		t.unmagnify()
+		t.hideCursor()
+	} else {
+		// restore the cursor
+		t.showCursor()
+	}
+
+	_, _ = t.buf.WriteTo(t.tty)
+}
+
+// drawOnly redraws the cells for which redraw returns true, for changes
+// that the screen makes by itself (such as blinking) between calls to
+// Show.  Cells that the application has changed since it last called
+// Show are left alone, as is everything else, so that a frame that the
+// application has not finished is never seen.  While the screen is
+// magnified, or is to be cleared, nothing is drawn until the next Show.
+// It returns the number of cells redrawn.
+func (t *tScreen) drawOnly(redraw func(x, y int) bool) int {
+	if t.clear || t.mag.on() {
+		return 0
+	}
+	t.cx = -1
+	t.cy = -1
+	t.curstyle = styleInvalid
 
-	// restore the cursor
+	t.buf.Reset()
+	t.buffering = true
+	t.hideCursor()
+	n := 0
+	for y := 0; y < t.h; y++ {
+		w := t.lineWidth(y)
+		for x := 0; x < w; x++ {
+			_, _, _, width := t.cells.GetContent(x, y)
+			if !t.cells.Dirty(x, y) && redraw(x, y) {
+				t.cells.SetDirty(x, y, true)
+				t.drawCell(x, y)
+				n++
+			}
+			if width > 1 {
+				x += width - 1
+			}
+		}
+	}
 	t.showCursor()
+	t.buffering = false
 
+	if n == 0 {
+		t.buf.Reset()
+		return 0
+	}
 	_, _ = t.buf.WriteTo(t.tty)
+	return n
 }
 
 func (t *tScreen) EnableMouse(flags ...MouseFlags) {
@@ -967,7 +1253,7 @@ func (t *tScreen) EnableMouse(flags ...MouseFlags) {
 
 	t.Lock()
 	t.mouseFlags = f
-	t.enableMouse(f)
+	t.updateModes()
 	t.Unlock()
 }
 
@@ -997,21 +1283,21 @@ func (t *tScreen) enableMouse(f MouseFlags) {
 func (t *tScreen) DisableMouse() {
 	t.Lock()
 	t.mouseFlags = 0
-	t.enableMouse(0)
+	t.updateModes()
 	t.Unlock()
 }
 
 func (t *tScreen) EnablePaste() {
 	t.Lock()
 	t.pasteEnabled = true
-	t.enablePasting(true)
+	t.updateModes()
 	t.Unlock()
 }
 
 func (t *tScreen) DisablePaste() {
 	t.Lock()
 	t.pasteEnabled = false
-	t.enablePasting(false)
+	t.updateModes()
 	t.Unlock()
 }
 
@@ -1036,15 +1322,17 @@ func (t *tScreen) Size() (int, int) {
 
 func (t *tScreen) resize() {
 	if w, h, e := t.tty.WindowSize(); e == nil {
+		h -= t.statusRows()
 		if w != t.w || h != t.h {
 			t.cx = -1
 			t.cy = -1
 
-			t.cells.Resize(w, h)
+			resizeCells(&t.cells, &t.history, t.reflow, w, h)
 			t.cells.Invalidate()
+			ev := newEventResize(w, h, t.w, t.h, resizeReason(w, h, t.w, t.h, t.wantSize))
 			t.h = h
 			t.w = w
-			ev := NewEventResize(w, h)
+			t.wantSize = [2]int{}
 			_ = t.PostEvent(ev)
 		}
 	}
@@ -1058,6 +1346,10 @@ func (t *tScreen) Colors() int {
 	return t.ti.Colors
 }
 
+func (t *tScreen) ColorModel() (int, bool, bool) {
+	return t.nColors(), t.truecolor, t.ti.ResetFgBg != ""
+}
+
 // nColors returns the size of the built-in palette.
 // This is distinct from Colors(), as it will generally
 // always be a small number. (<= 256)
@@ -1145,13 +1437,24 @@ var vtACSNames = map[byte]rune{
 	'~': RuneBullet,
 }
 
-// buildAcsMap builds a map of characters that we translate from Unicode to
-// alternate character encodings.  To do this, we use the standard VT100 ACS
-// maps.  This is only done if the terminal lacks support for Unicode; we
-// always prefer to emit Unicode glyphs when we are able.
+// buildAcsMap builds the map of runes that the terminal can draw using its
+// alternate character set.  These are normally only used when the character
+// set cannot represent the rune directly, so on UTF-8 terminals the Unicode
+// line drawing characters are used instead.  Setting TCELL_ACS to "always"
+// uses the alternate character set whenever possible, even with UTF-8, which
+// may help with fonts that lack the Unicode line drawing glyphs.  Setting it
+// to "never" disables the alternate character set entirely, which may help
+// with terminals that advertise it but do not implement it correctly; the
+// ASCII rune fallbacks are used instead.
 func (t *tScreen) buildAcsMap() {
 	acsstr := t.ti.AltChars
 	t.acs = make(map[rune]string)
+	switch t.getenv("TCELL_ACS") {
+	case "always":
+		t.acsFirst = true
+	case "never":
+		return
+	}
 	for len(acsstr) > 2 {
 		srcv := acsstr[0]
 		dstv := string(acsstr[1])
@@ -1234,6 +1537,10 @@ func (t *tScreen) buildMouseEvent(x, y, btn int) *EventMouse {
 		} else {
 			button = Button2
 		}
+	case 0x42:
+		button = WheelLeft
+	case 0x43:
+		button = WheelRight
 	}
 
 	if btn&0x4 != 0 {
@@ -1254,6 +1561,19 @@ func (t *tScreen) buildMouseEvent(x, y, btn int) *EventMouse {
 	return NewEventMouse(x, y, button, mod)
 }
 
+// sgrButton returns the button for the low bits of an SGR mouse report.
+func sgrButton(btn int) ButtonMask {
+	switch btn & 0x3 {
+	case 0:
+		return Button1
+	case 1:
+		return Button3
+	case 2:
+		return Button2
+	}
+	return ButtonNone
+}
+
 // parseSgrMouse attempts to locate an SGR mouse record at the start of the
 // buffer.  It returns true, true if it found one, and the associated bytes
 // be removed from the buffer.  It returns true, false if the buffer might
@@ -1342,32 +1662,47 @@ func (t *tScreen) parseSgrMouse(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
 
 			motion = (btn & 32) != 0
 			btn &^= 32
-			if b[i] == 'm' {
-				// mouse release, clear all buttons
-				btn |= 3
-				btn &^= 0x40
-				t.buttondn = false
-			} else if motion {
+			// SGR reports which button is released, so unlike
+			// the legacy encoding we can track several buttons
+			// held at once, and report chords.
+			var ev *EventMouse
+			switch {
+			case b[i] == 'm':
+				ev = t.buildMouseEvent(x, y, btn&^0x40|3)
+				if btn&0x40 == 0 {
+					t.buttons &^= sgrButton(btn)
+				}
+				ev.btn = t.buttons
+				t.wasbtn = t.buttons != 0
+			case motion:
 				/*
 				 * Some broken terminals appear to send
 				 * mouse button one motion events, instead of
 				 * encoding 35 (no buttons) into these events.
-				 * We resolve these by looking for a non-motion
-				 * event first.
+				 * We resolve these by reporting the buttons
+				 * that we saw pressed instead.  But motion
+				 * with no buttons means that we missed any
+				 * releases.
 				 */
-				if !t.buttondn {
-					btn |= 3
-					btn &^= 0x40
+				if btn&3 == 3 {
+					t.buttons = ButtonNone
 				}
-			} else {
-				t.buttondn = true
+				ev = t.buildMouseEvent(x, y, btn&^0x40|3)
+				ev.btn = t.buttons
+				t.wasbtn = t.buttons != 0
+			case btn&0x40 != 0:
+				ev = t.buildMouseEvent(x, y, btn)
+			default:
+				ev = t.buildMouseEvent(x, y, btn)
+				t.buttons |= ev.btn
+				ev.btn = t.buttons
 			}
 			// consume the event bytes
 			for i >= 0 {
 				_, _ = buf.ReadByte()
 				i--
 			}
-			*evs = append(*evs, t.buildMouseEvent(x, y, btn))
+			*evs = append(*evs, ev)
 			return true, true
 		}
 	}
@@ -1442,9 +1777,11 @@ func (t *tScreen) parseFunctionKey(buf *bytes.Buffer, evs *[]Event) (bool, bool)
 				r = rune(b[0])
 			}
 			mod := k.mod
+			raw := esc
 			if t.escaped {
 				mod |= ModAlt
 				t.escaped = false
+				raw = append([]byte{'\x1b'}, esc...)
 			}
 			switch k.key {
 			case keyPasteStart:
@@ -1452,7 +1789,9 @@ func (t *tScreen) parseFunctionKey(buf *bytes.Buffer, evs *[]Event) (bool, bool)
 			case keyPasteEnd:
 				*evs = append(*evs, NewEventPaste(false))
 			default:
-				*evs = append(*evs, NewEventKey(k.key, r, mod))
+				ev := NewEventKey(k.key, r, mod)
+				ev.raw = raw
+				*evs = append(*evs, ev)
 			}
 			for i := 0; i < len(esc); i++ {
 				_, _ = buf.ReadByte()
@@ -1471,11 +1810,15 @@ func (t *tScreen) parseRune(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
 	if b[0] >= ' ' && b[0] <= 0x7F {
 		// printable ASCII easy to deal with -- no encodings
 		mod := ModNone
+		raw := []byte{b[0]}
 		if t.escaped {
 			mod = ModAlt
 			t.escaped = false
+			raw = []byte{'\x1b', b[0]}
 		}
-		*evs = append(*evs, NewEventKey(KeyRune, rune(b[0]), mod))
+		ev := NewEventKey(KeyRune, rune(b[0]), mod)
+		ev.raw = raw
+		*evs = append(*evs, ev)
 		_, _ = buf.ReadByte()
 		return true, true
 	}
@@ -1496,11 +1839,15 @@ func (t *tScreen) parseRune(buf *bytes.Buffer, evs *[]Event) (bool, bool) {
 			r, _ := utf8.DecodeRune(utf[:nOut])
 			if r != utf8.RuneError {
 				mod := ModNone
+				raw := append([]byte{}, b[:nIn]...)
 				if t.escaped {
 					mod = ModAlt
 					t.escaped = false
+					raw = append([]byte{'\x1b'}, raw...)
 				}
-				*evs = append(*evs, NewEventKey(KeyRune, r, mod))
+				ev := NewEventKey(KeyRune, r, mod)
+				ev.raw = raw
+				*evs = append(*evs, ev)
 			}
 			for nIn > 0 {
 				_, _ = buf.ReadByte()
@@ -1517,6 +1864,15 @@ func (t *tScreen) scanInput(buf *bytes.Buffer, expire bool) {
 	evs := t.collectEventsFromInput(buf, expire)
 
 	for _, ev := range evs {
+		if seq, ok := ev.(*eventSequence); ok {
+			t.Lock()
+			cb := t.unknownSeq
+			t.Unlock()
+			if cb != nil {
+				cb(seq.kind, seq.payload)
+			}
+			continue
+		}
 		t.PostEventWait(ev)
 	}
 }
@@ -1546,23 +1902,47 @@ func (t *tScreen) collectEventsFromInput(buf *bytes.Buffer, expire bool) []Event
 			partials++
 		}
 
+		if part, comp := t.parseKeypadKey(buf, &res); comp {
+			continue
+		} else if part {
+			partials++
+		}
+
 		if part, comp := t.parseFunctionKey(buf, &res); comp {
 			continue
 		} else if part {
 			partials++
 		}
 
+		if part, comp := t.parseModifiedKey(buf, &res); comp {
+			continue
+		} else if part {
+			partials++
+		}
+
 		// Only parse mouse records if this term claims to have
 		// mouse support
 
 		if t.ti.Mouse != "" {
 			if part, comp := t.parseXtermMouse(buf, &res); comp {
+				t.translateWheel(&res)
 				continue
 			} else if part {
 				partials++
 			}
 
 			if part, comp := t.parseSgrMouse(buf, &res); comp {
+				t.translateWheel(&res)
+				continue
+			} else if part {
+				partials++
+			}
+		}
+
+		// Anything that still looks like a control sequence is
+		// something we do not understand.
+		if partials == 0 {
+			if part, comp := t.parseUnknownSequence(buf, &res); comp {
 				continue
 			} else if part {
 				partials++
@@ -1572,7 +1952,9 @@ func (t *tScreen) collectEventsFromInput(buf *bytes.Buffer, expire bool) []Event
 		if partials == 0 || expire {
 			if b[0] == '\x1b' {
 				if len(b) == 1 {
-					res = append(res, NewEventKey(KeyEsc, 0, ModNone))
+					ev := NewEventKey(KeyEsc, 0, ModNone)
+					ev.raw = []byte{'\x1b'}
+					res = append(res, ev)
 					t.escaped = false
 				} else {
 					t.escaped = true
@@ -1586,11 +1968,15 @@ func (t *tScreen) collectEventsFromInput(buf *bytes.Buffer, expire bool) []Event
 			// should only do this for control characters like ESC.
 			by, _ := buf.ReadByte()
 			mod := ModNone
+			raw := []byte{by}
 			if t.escaped {
 				t.escaped = false
 				mod = ModAlt
+				raw = []byte{'\x1b', by}
 			}
-			res = append(res, NewEventKey(KeyRune, rune(by), mod))
+			ev := NewEventKey(KeyRune, rune(by), mod)
+			ev.raw = raw
+			res = append(res, ev)
 			continue
 		}
 
@@ -1605,6 +1991,10 @@ func (t *tScreen) collectEventsFromInput(buf *bytes.Buffer, expire bool) []Event
 func (t *tScreen) mainLoop(stopQ chan struct{}) {
 	defer t.wg.Done()
 	buf := &bytes.Buffer{}
+	// Where resizes must settle (see resizeSettle), settle fires when the
+	// size is to be checked, and recheck when it is to be checked again.
+	var settle, recheck <-chan time.Time
+	var settled [2]int // the size when last checked
 	for {
 		select {
 		case <-stopQ:
@@ -1612,14 +2002,24 @@ func (t *tScreen) mainLoop(stopQ chan struct{}) {
 		case <-t.quit:
 			return
 		case <-t.resizeQ:
-			t.Lock()
-			t.cx = -1
-			t.cy = -1
-			t.resize()
-			t.cells.Invalidate()
-			t.draw()
-			t.Unlock()
+			if resizeSettle > 0 {
+				settle, settled = time.After(resizeSettle), [2]int{}
+				continue
+			}
+			t.resized()
 			continue
+		case <-settle:
+			if w, h, e := t.tty.WindowSize(); e == nil && [2]int{w, h} != settled {
+				settle, settled = time.After(resizeSettle), [2]int{w, h}
+				continue
+			}
+			settle, recheck = nil, time.After(resizeRecheck)
+			t.resized()
+		case <-recheck:
+			recheck = nil
+			if w, h, e := t.tty.WindowSize(); e == nil && [2]int{w, h} != settled {
+				t.resized()
+			}
 		case <-t.keytimer.C:
 			// If the timer fired, and the current time
 			// is after the expiration of the escape sequence,
@@ -1641,6 +2041,9 @@ func (t *tScreen) mainLoop(stopQ chan struct{}) {
 				t.keytimer.Reset(time.Millisecond * 50)
 			}
 		case chunk := <-t.keychan:
+			if t.c1 {
+				chunk = fromC1(chunk)
+			}
 			buf.Write(chunk)
 			t.keyexpire = time.Now().Add(time.Millisecond * 50)
 			t.scanInput(buf, false)
@@ -1657,6 +2060,18 @@ func (t *tScreen) mainLoop(stopQ chan struct{}) {
 	}
 }
 
+// resized resizes the screen to the size of the terminal, drawing it all
+// again.
+func (t *tScreen) resized() {
+	t.Lock()
+	t.cx = -1
+	t.cy = -1
+	t.resize()
+	t.cells.Invalidate()
+	t.draw()
+	t.Unlock()
+}
+
 func (t *tScreen) inputLoop(stopQ chan struct{}) {
 
 	defer t.wg.Done()
@@ -1695,7 +2110,9 @@ func (t *tScreen) Sync() {
 		t.cells.Invalidate()
 		t.draw()
 	}
+	changes := t.changes.take()
 	t.Unlock()
+	reportChanges(changes)
 }
 
 func (t *tScreen) CharacterSet() string {
@@ -1708,6 +2125,14 @@ func (t *tScreen) RegisterRuneFallback(orig rune, fallback string) {
 	t.Unlock()
 }
 
+func (t *tScreen) RegisterRuneFallbacks(fallbacks map[rune]string) {
+	t.Lock()
+	for r, fb := range fallbacks {
+		t.fallback[r] = fb
+	}
+	t.Unlock()
+}
+
 func (t *tScreen) UnregisterRuneFallback(orig rune) {
 	t.Lock()
 	delete(t.fallback, orig)
@@ -1732,12 +2157,18 @@ func (t *tScreen) CanDisplay(r rune, checkFallbacks bool) bool {
 	if _, ok := t.acs[r]; ok {
 		return true
 	}
+	if _, ok := t.soft[r]; ok {
+		return true
+	}
 	if !checkFallbacks {
 		return false
 	}
 	if _, ok := t.fallback[r]; ok {
 		return true
 	}
+	if _, ok := bestFit(r); ok {
+		return true
+	}
 	return false
 }
 
@@ -1753,8 +2184,14 @@ func (t *tScreen) HasKey(k Key) bool {
 }
 
 func (t *tScreen) SetSize(w, h int) {
+	t.Lock()
+	defer t.Unlock()
+	if t.fini {
+		return
+	}
 	if t.setWinSize != "" {
 		t.TPuts(t.ti.TParm(t.setWinSize, w, h))
+		t.wantSize = [2]int{w, h - t.statusRows()}
 	}
 	t.cells.Invalidate()
 	t.resize()
@@ -1762,18 +2199,48 @@ func (t *tScreen) SetSize(w, h int) {
 
 func (t *tScreen) Resize(int, int, int, int) {}
 
-func (t *tScreen) Suspend() error {
-	t.disengage(true)
-	return nil
+func (t *tScreen) HasWindowOps() bool {
+	return t.windowOps
 }
 
-func (t *tScreen) SuspendNoClear() error {
-	t.disengage(false)
-	return nil
+func (t *tScreen) windowOp(seq string) {
+	t.Lock()
+	if t.windowOps && !t.fini {
+		t.TPuts(seq)
+	}
+	t.Unlock()
+}
+
+func (t *tScreen) IconifyWindow(iconify bool) {
+	if iconify {
+		t.windowOp(xtIconify)
+	} else {
+		t.windowOp(xtDeiconify)
+	}
+}
+
+func (t *tScreen) MaximizeWindow(maximize bool) {
+	if maximize {
+		t.windowOp(xtMaximize)
+	} else {
+		t.windowOp(xtRestoreWindow)
+	}
+}
+
+func (t *tScreen) RaiseWindow() {
+	t.windowOp(xtRaiseWindow)
+}
+
+func (t *tScreen) SetWindowPosition(x, y int) {
+	t.windowOp(t.ti.TParm(xtMoveWindow, x, y))
+}
+
+func (t *tScreen) QueryWindowPosition() {
+	t.windowOp(xtQueryPosition)
 }
 
-func (t *tScreen) Resume() error {
-	return t.engage()
+func (t *tScreen) FontMetrics() (int, int, int) {
+	return 0, 0, 0
 }
 
 // engage is used to place the terminal in raw mode and establish screen size, etc.
@@ -1794,28 +2261,33 @@ func (t *tScreen) engage() error {
 	if t.running {
 		return errors.New("already engaged")
 	}
+	if st, ok := t.tty.(SignalDriver); ok {
+		st.DisableSignals(t.noSignals)
+	}
 	if err := t.tty.Start(); err != nil {
 		return err
 	}
 	t.running = true
 	if w, h, err := t.tty.WindowSize(); err == nil && w != 0 && h != 0 {
-		t.cells.Resize(w, h)
+		t.cells.Resize(w, h-t.statusRows())
 	}
 	stopQ := make(chan struct{})
 	t.stopQ = stopQ
-	t.enableMouse(t.mouseFlags)
-	t.enablePasting(t.pasteEnabled)
 
 	ti := t.ti
-	t.TPuts(ti.EnterCA)
-	t.TPuts(ti.EnterKeypad)
+	t.setModes(t.wantedModes())
 	t.TPuts(ti.HideCursor)
 	t.TPuts(ti.EnableAcs)
+	t.TPuts(t.softFont)
 	t.TPuts(ti.Clear)
 
 	t.wg.Add(2)
 	go t.inputLoop(stopQ)
 	go t.mainLoop(stopQ)
+	if !t.identifying && t.getenv("TCELL_IDENTIFY") != "disable" {
+		t.identifying = true
+		go t.identify()
+	}
 	return nil
 }
 
@@ -1831,6 +2303,8 @@ func (t *tScreen) disengage(clearScreen bool) {
 		return
 	}
 	t.running = false
+	t.stopBlink()
+	t.bell.stop()
 	stopQ := t.stopQ
 	close(stopQ)
 	_ = t.tty.Drain()
@@ -1845,27 +2319,65 @@ func (t *tScreen) disengage(clearScreen bool) {
 	t.cells.Resize(0, 0)
 	t.TPuts(ti.ShowCursor)
 	if t.cursorStyles != nil && t.cursorStyle != CursorStyleDefault {
-		t.TPuts(t.cursorStyles[t.cursorStyle])
+		t.TPuts(t.cursorStyles[CursorStyleDefault])
 	}
 	t.TPuts(ti.ResetFgBg)
 	t.TPuts(ti.AttrOff)
-	if clearScreen {
-		t.TPuts(ti.Clear)
-		t.TPuts(ti.ExitCA)
+	// Leave the alternate screen only if clearing; everything else is
+	// put back the way that we found it.
+	off := termModes{altScreen: t.modes.altScreen && !clearScreen}
+	if t.keepMouse {
+		off.mouse = t.modes.mouse
 	}
-	t.TPuts(ti.ExitKeypad)
-	t.enableMouse(0)
-	t.enablePasting(false)
+	t.setModes(off)
 
 	_ = t.tty.Stop()
 }
 
-// Beep emits a beep to the terminal.
-func (t *tScreen) Beep() error {
-	t.writeString(string(byte(7)))
+func (t *tScreen) DisableSignalHandling() {
+	t.Lock()
+	t.noSignals = true
+	t.Unlock()
+}
+
+func (t *tScreen) HandleSignal(sig os.Signal) {
+	if isResizeSignal(sig) {
+		select {
+		case t.resizeQ <- true:
+		default:
+		}
+	}
+}
+
+// EmitRaw sends a control sequence to the terminal, after drawing any
+// pending content.
+func (t *tScreen) EmitRaw(seq string) error {
+	if !validSequence(seq) {
+		return ErrInvalidSequence
+	}
+	t.Lock()
+	defer t.Unlock()
+	if t.fini {
+		return nil
+	}
+	t.resize()
+	t.draw()
+	t.writeString(seq)
+	// The sequence may have moved the cursor or changed attributes,
+	// so make no assumptions about either.
+	t.cx = -1
+	t.cy = -1
+	t.curstyle = styleInvalid
 	return nil
 }
 
+// OnUnknownSequence registers a handler for unrecognized input sequences.
+func (t *tScreen) OnUnknownSequence(cb UnknownSequenceHandler) {
+	t.Lock()
+	t.unknownSeq = cb
+	t.Unlock()
+}
+
 // finalize is used to at application shutdown, and restores the terminal
 // to it's initial state.  It should not be called more than once.
 func (t *tScreen) finalize() {
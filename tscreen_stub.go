@@ -16,6 +16,8 @@
 
 package tcell
 
+import "os"
+
 // NB: We might someday wish to move Windows to this model.   However,
 // that would probably mean sacrificing some of the richer key reporting
 // that we can obtain with the console API present on Windows.
@@ -23,3 +25,7 @@ package tcell
 func (t *tScreen) initialize() error {
 	return ErrNoScreen
 }
+
+func isResizeSignal(os.Signal) bool {
+	return false
+}
@@ -0,0 +1,917 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mkTermScreen returns a terminfo screen that is not attached to any
+// tty, which is enough to exercise the input decoder.
+func mkTermScreen(t *testing.T, term string) *tScreen {
+	ti, e := LookupTerminfo(term)
+	if e != nil {
+		t.Fatalf("Failed to find terminfo for %s: %v", term, e)
+	}
+	s, e := NewTerminfoScreenFromTtyTerminfo(nil, ti)
+	if e != nil {
+		t.Fatalf("Failed to create screen: %v", e)
+	}
+	return s.(*tScreen)
+}
+
+// outputTty is a Tty that just collects output.
+type outputTty struct {
+	bytes.Buffer
+	w, h int
+}
+
+func (o *outputTty) Start() error                  { return nil }
+func (o *outputTty) Stop() error                   { return nil }
+func (o *outputTty) Drain() error                  { return nil }
+func (o *outputTty) NotifyResize(func())           {}
+func (o *outputTty) WindowSize() (int, int, error) { return o.w, o.h, nil }
+func (o *outputTty) Close() error                  { return nil }
+
+// mkDrawScreen returns a terminfo screen of the given size, whose output
+// is collected, for checking what is drawn by draw().
+func mkDrawScreen(t *testing.T, term string, w, h int) (*tScreen, *outputTty) {
+	s := mkTermScreen(t, term)
+	tty := &outputTty{w: w, h: h}
+	s.tty = tty
+	s.charset = "UTF-8"
+	s.encoder = GetEncoding(s.charset).NewEncoder()
+	s.resize()
+	return s, tty
+}
+
+func TestKeyRaw(t *testing.T) {
+	s := mkTermScreen(t, "xterm")
+	cases := []struct {
+		in  string
+		key Key
+		mod ModMask
+	}{
+		{"\x1b[A", KeyUp, ModNone},
+		{"\x1b[1;5A", KeyUp, ModCtrl},
+		{"x", KeyRune, ModNone},
+		{"\x1bx", KeyRune, ModAlt},
+		{"\x01", KeyCtrlA, ModCtrl},
+	}
+	for _, c := range cases {
+		evs := s.collectEventsFromInput(bytes.NewBufferString(c.in), true)
+		if len(evs) != 1 {
+			t.Errorf("%q: expected 1 event, got %d", c.in, len(evs))
+			continue
+		}
+		ev, ok := evs[0].(*EventKey)
+		if !ok {
+			t.Errorf("%q: not a key event: %T", c.in, evs[0])
+			continue
+		}
+		if ev.Key() != c.key || ev.Modifiers() != c.mod {
+			t.Errorf("%q: wrong key %v", c.in, ev.Name())
+		}
+		if string(ev.Raw()) != c.in {
+			t.Errorf("%q: wrong raw bytes %q", c.in, ev.Raw())
+		}
+	}
+}
+
+func TestMouseChord(t *testing.T) {
+	s, _ := mkDrawScreen(t, "xterm", 80, 24)
+	cases := []struct {
+		in    string
+		btn   ButtonMask
+		mod   ModMask
+		chord bool
+	}{
+		{"\x1b[<0;5;5M", Button1, ModNone, false},
+		{"\x1b[<2;5;5M", Button1 | Button2, ModNone, true},
+		{"\x1b[<48;6;5M", Button1 | Button2, ModCtrl, true}, // motion
+		{"\x1b[<0;6;5m", Button2, ModNone, false},
+		{"\x1b[<2;6;5m", ButtonNone, ModNone, false},
+		{"\x1b[<69;6;5M", WheelDown, ModShift, false},
+		{"\x1b[<74;6;5M", WheelLeft, ModAlt, false},
+		{"\x1b[<0;5;5M", Button1, ModNone, false},
+		{"\x1b[<35;7;5M", ButtonNone, ModNone, false}, // missed release
+		{"\x1b[M`$$", WheelUp, ModNone, false},
+		{"\x1b[Mq$$", WheelDown, ModCtrl, false},
+	}
+	for _, c := range cases {
+		evs := s.collectEventsFromInput(bytes.NewBufferString(c.in), true)
+		if len(evs) != 1 {
+			t.Errorf("%q: expected 1 event, got %d", c.in, len(evs))
+			continue
+		}
+		ev, ok := evs[0].(*EventMouse)
+		if !ok {
+			t.Errorf("%q: not a mouse event: %T", c.in, evs[0])
+			continue
+		}
+		if ev.Buttons() != c.btn || ev.Modifiers() != c.mod || ev.Chord() != c.chord {
+			t.Errorf("%q: wrong event %x %x %v", c.in, ev.Buttons(), ev.Modifiers(), ev.Chord())
+		}
+	}
+}
+
+func TestWheelKeys(t *testing.T) {
+	s, _ := mkDrawScreen(t, "xterm", 80, 24)
+	s.SetWheelKeys(3)
+	if s.mouseModes() != MouseButtonEvents {
+		t.Errorf("button events not requested for the wheel")
+	}
+	evs := s.collectEventsFromInput(bytes.NewBufferString("\x1b[<65;5;5M\x1b[<0;5;5M\x1b[<0;5;5mx"), true)
+	if len(evs) != 4 {
+		t.Fatalf("expected 4 events, got %d", len(evs))
+	}
+	for _, ev := range evs[:3] {
+		if ev, ok := ev.(*EventKey); !ok || ev.Key() != KeyDown {
+			t.Errorf("wheel not translated: %v", ev)
+		}
+	}
+
+	// Once the application enables the mouse, the wheel is reported.
+	s.EnableMouse()
+	evs = s.collectEventsFromInput(bytes.NewBufferString("\x1b[<64;5;5M"), true)
+	if len(evs) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(evs))
+	}
+	if ev, ok := evs[0].(*EventMouse); !ok || ev.Buttons() != WheelUp {
+		t.Errorf("wheel not reported: %v", evs[0])
+	}
+}
+
+func TestUserKey(t *testing.T) {
+	k := RegisterKey("Macro1")
+	if k < KeyUser {
+		t.Errorf("registered key %d below KeyUser", k)
+	}
+	if k2 := RegisterKey("Macro1"); k2 != k {
+		t.Errorf("second registration returned a different key")
+	}
+	// This sequence would otherwise be F1.
+	RegisterKeySequence(k, ModShift, "\x1bOP")
+	defer func() {
+		userKeys.Lock()
+		delete(userKeys.seqs, "\x1bOP")
+		userKeys.Unlock()
+	}()
+
+	s := mkTermScreen(t, "xterm")
+	evs := s.collectEventsFromInput(bytes.NewBufferString("\x1bOP"), true)
+	if len(evs) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(evs))
+	}
+	ev := evs[0].(*EventKey)
+	if ev.Key() != k || ev.Modifiers() != ModShift || ev.Name() != "Shift+Macro1" {
+		t.Errorf("wrong key: %s", ev.Name())
+	}
+}
+
+func TestKeypad(t *testing.T) {
+	s := mkTermScreen(t, "xterm")
+
+	// Keypad keys are not reported unless asked for.
+	evs := s.collectEventsFromInput(bytes.NewBufferString("\x1bOw"), true)
+	for _, ev := range evs {
+		if ev.(*EventKey).Key() == KeyKP7 {
+			t.Errorf("keypad 7 reported when keypad is disabled")
+		}
+	}
+
+	s.EnableKeypad()
+	for seq, k := range map[string]Key{
+		"\x1bOw": KeyKP7,
+		"\x1bOp": KeyKP0,
+		"\x1bOM": KeyKPEnter,
+		"\x1bOk": KeyKPPlus,
+	} {
+		evs = s.collectEventsFromInput(bytes.NewBufferString(seq), true)
+		if len(evs) != 1 {
+			t.Errorf("%q: expected 1 event, got %d", seq, len(evs))
+			continue
+		}
+		if ev := evs[0].(*EventKey); ev.Key() != k {
+			t.Errorf("%q: got %s", seq, ev.Name())
+		}
+	}
+}
+
+func TestSuperHyper(t *testing.T) {
+	cases := []struct {
+		term string
+		in   string
+		key  Key
+		mod  ModMask
+	}{
+		{"xterm", "\x1b[1;9A", KeyUp, ModMeta},
+		{"xterm-kitty", "\x1b[1;9A", KeyUp, ModSuper},
+		{"xterm-kitty", "\x1b[1;17A", KeyUp, ModHyper},
+		{"xterm-kitty", "\x1b[1;21A", KeyUp, ModHyper | ModCtrl},
+		{"xterm-kitty", "\x1b[3;26~", KeyDelete, ModHyper | ModSuper | ModShift},
+	}
+	for _, c := range cases {
+		s := mkTermScreen(t, c.term)
+		evs := s.collectEventsFromInput(bytes.NewBufferString(c.in), true)
+		if len(evs) != 1 {
+			t.Errorf("%s %q: expected 1 event, got %d", c.term, c.in, len(evs))
+			continue
+		}
+		ev := evs[0].(*EventKey)
+		if ev.Key() != c.key || ev.Modifiers() != c.mod {
+			t.Errorf("%s %q: got %s", c.term, c.in, ev.Name())
+		}
+	}
+}
+
+func TestHasLineModes(t *testing.T) {
+	for name, ok := range map[string]bool{
+		"xterm-256color": true,
+		"vt220":          true,
+		"screen":         false,
+		"linux":          false,
+	} {
+		if hasLineModes(name) != ok {
+			t.Errorf("%s: expected line modes %v", name, ok)
+		}
+	}
+}
+
+func TestStatusLine(t *testing.T) {
+	s := mkTermScreen(t, "xterm")
+	s.SetStatusLine("ready", StyleDefault)
+	if s.trueStatus() || s.statusRows() != 1 {
+		t.Errorf("xterm status line should be emulated")
+	}
+	s.SetStatusLine("", StyleDefault)
+	if s.statusRows() != 0 {
+		t.Errorf("status line not removed")
+	}
+	if !hasDECStatus("vt320") || hasDECStatus("vt220") {
+		t.Errorf("wrong DEC status line detection")
+	}
+	if text, n := statusText("abcdef", 4); text != "abcd" || n != 4 {
+		t.Errorf("status not truncated: %q %d", text, n)
+	}
+}
+
+// identifyXTerm makes the screen behave as it does once XTerm has
+// identified itself.
+func identifyXTerm(s *tScreen) {
+	s.termID = TerminalID{Name: "XTerm", Version: "367"}
+	s.idQuirks = quirksFor(s.termID)
+	s.prepareRepeat()
+}
+
+func TestRepeat(t *testing.T) {
+	s, tty := mkDrawScreen(t, "xterm", 20, 2)
+	identifyXTerm(s)
+	for x := 0; x < 10; x++ {
+		s.SetContent(x, 0, '─', nil, StyleDefault)
+	}
+	s.SetContent(10, 0, 'x', nil, StyleDefault)
+	s.SetContent(11, 0, 'x', nil, StyleDefault)
+	s.draw()
+	out := tty.String()
+	if !strings.Contains(out, "─\x1b[9b") {
+		t.Errorf("rule not repeated: %q", out)
+	}
+	if strings.Contains(out, "x\x1b[") {
+		t.Errorf("short run should not be repeated: %q", out)
+	}
+
+	s, tty = mkDrawScreen(t, "vt100", 20, 2)
+	for x := 0; x < 10; x++ {
+		s.SetContent(x, 0, '=', nil, StyleDefault)
+	}
+	s.draw()
+	if strings.Contains(tty.String(), "b") {
+		t.Errorf("REP used without support: %q", tty.String())
+	}
+
+	// Terminals that only call themselves xterm may lack REP.
+	s, tty = mkDrawScreen(t, "xterm-256color", 20, 2)
+	for x := 0; x < 10; x++ {
+		s.SetContent(x, 0, '=', nil, StyleDefault)
+	}
+	s.draw()
+	if strings.Contains(tty.String(), "=\x1b[") {
+		t.Errorf("REP used before XTerm identified itself: %q", tty.String())
+	}
+}
+
+func TestRepeatOverlays(t *testing.T) {
+	s, tty := mkDrawScreen(t, "xterm", 40, 2)
+	identifyXTerm(s)
+	for x := 0; x < 30; x++ {
+		s.SetContent(x, 0, '-', nil, StyleDefault)
+	}
+	s.SetGuides([]Guide{{Pos: 5, Vertical: true, Style: StyleDefault.Bold(true)}})
+	s.SetSelection(StyleDefault.Reverse(true), []Selection{{StartX: 10, EndX: 13}})
+	s.SetHighlights(StyleDefault.Underline(true), []Selection{{StartX: 20, EndX: 23}})
+	s.draw()
+	out := tty.String()
+	// Each run is repeated only up to the next overlay, and the runs
+	// between them are too short to repeat.
+	if strings.Count(out, "-\x1b[5b") != 2 || strings.Count(out, "-") != 20 {
+		t.Errorf("repeated across overlays: %q", out)
+	}
+	for _, sgr := range []string{"\x1b[1m", "\x1b[7m", "\x1b[4m"} {
+		if !strings.Contains(out, sgr) {
+			t.Errorf("overlay %q not drawn: %q", sgr, out)
+		}
+	}
+}
+
+func TestMouseShape(t *testing.T) {
+	s, tty := mkDrawScreen(t, "xterm", 20, 2)
+	s.running = true
+	s.modes = s.wantedModes()
+	s.SetMouseShape(MouseShapeText)
+	s.SetMouseShape(MouseShapeText)
+	s.SetMouseShape("bad\x1bshape")
+	s.SetMouseShape(MouseShapeDefault)
+	if out := tty.String(); out != "\x1b]22;text\x1b\\\x1b]22;default\x1b\\" {
+		t.Errorf("wrong output: %q", out)
+	}
+
+	s, tty = mkDrawScreen(t, "vt100", 20, 2)
+	s.running = true
+	s.SetMouseShape(MouseShapePointer)
+	if tty.Len() != 0 {
+		t.Errorf("shape set without a mouse: %q", tty.String())
+	}
+}
+
+func TestVisualBell(t *testing.T) {
+	s, tty := mkDrawScreen(t, "xterm", 4, 2)
+	s.running = true
+	s.SetContent(0, 0, 'a', nil, StyleDefault)
+	s.SetContent(0, 1, 'b', nil, StyleDefault)
+	s.draw()
+	tty.Reset()
+
+	s.SetBellPolicy(BellNone)
+	_ = s.Beep()
+	if tty.Len() != 0 {
+		t.Errorf("bell not silenced: %q", tty.String())
+	}
+
+	s.SetBellPolicy(BellVisual)
+	s.SetVisualBell(time.Millisecond, 0, 1, 4, 1)
+	s.SetContent(1, 1, 'Z', nil, StyleDefault) // not yet shown
+	_ = s.Beep()
+	out := tty.String()
+	if strings.Contains(out, "\a") || !strings.Contains(out, "\x1b[7m") ||
+		!strings.Contains(out, "b") || strings.ContainsAny(out, "aZ") {
+		t.Errorf("wrong flash: %q", out)
+	}
+
+	for i := 0; i < 100; i++ {
+		s.Lock()
+		on := s.bell.on
+		s.Unlock()
+		if !on {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if s.bell.on || s.bell.apply(0, 1, StyleDefault) != StyleDefault {
+		t.Errorf("flash did not end")
+	}
+}
+
+func TestNotify(t *testing.T) {
+	cases := []struct {
+		term   string
+		notify string
+		want   string
+	}{
+		{"xterm", "", ""},
+		{"xterm", "osc9", "\x1b]9;Build: done, 0 errors\x1b\\"},
+		{"xterm", "osc777", "\x1b]777;notify;Build;done; 0 errors\x1b\\"},
+		{"xterm-kitty", "", "\x1b]99;i=1:d=0:p=title;Build\x1b\\\x1b]99;i=1:d=1:p=body;done; 0 errors\x1b\\"},
+		{"xterm-kitty", "disable", ""},
+	}
+	for _, c := range cases {
+		_ = os.Setenv("TCELL_NOTIFY", c.notify)
+		s, tty := mkDrawScreen(t, c.term, 20, 2)
+		s.running = true
+		err := s.Notify("Build", "done;\a0 errors")
+		if c.want == "" && err != ErrNotSupported {
+			t.Errorf("%s %s: expected not supported, got %v", c.term, c.notify, err)
+		}
+		if got := tty.String(); got != c.want {
+			t.Errorf("%s %s: got %q, want %q", c.term, c.notify, got, c.want)
+		}
+	}
+	_ = os.Unsetenv("TCELL_NOTIFY")
+}
+
+func TestProgress(t *testing.T) {
+	_ = os.Setenv("TCELL_PROGRESS", "disable")
+	s, tty := mkDrawScreen(t, "xterm", 20, 2)
+	s.running = true
+	if err := s.SetProgress(ProgressNormal, 50); err != ErrNotSupported || tty.Len() != 0 {
+		t.Errorf("progress reported when disabled: %v %q", err, tty.String())
+	}
+
+	_ = os.Setenv("TCELL_PROGRESS", "enable")
+	s, tty = mkDrawScreen(t, "xterm", 20, 2)
+	s.running = true
+	s.modes = s.wantedModes()
+	_ = s.SetProgress(ProgressNormal, 150)
+	_ = s.SetProgress(ProgressIndeterminate, 0)
+	if got := tty.String(); got != "\x1b]9;4;1;100\x1b\\\x1b]9;4;3;0\x1b\\" {
+		t.Errorf("wrong output: %q", got)
+	}
+	_ = os.Unsetenv("TCELL_PROGRESS")
+}
+
+func TestModesRestored(t *testing.T) {
+	s, tty := mkDrawScreen(t, "xterm", 20, 2)
+	s.running = true
+	s.setModes(s.wantedModes())
+	s.EnablePaste()
+	s.EnableMouse(MouseButtonEvents)
+	s.EnableKeypad()
+
+	// Leaving undoes all of it, once.
+	tty.Reset()
+	s.setModes(termModes{})
+	out := tty.String()
+	for _, want := range []string{"\x1b[?2004l", "\x1b[?1000l", "\x1b>", s.ti.ExitCA, s.ti.ExitKeypad} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in %q", want, out)
+		}
+	}
+	tty.Reset()
+	s.setModes(termModes{})
+	if tty.Len() != 0 {
+		t.Errorf("modes cleared twice: %q", tty.String())
+	}
+
+	// Changes while suspended are made when resuming.
+	s.running = false
+	s.DisablePaste()
+	if tty.Len() != 0 {
+		t.Errorf("output while suspended: %q", tty.String())
+	}
+	s.running = true
+	s.updateModes()
+	out = tty.String()
+	if strings.Contains(out, "\x1b[?2004h") || !strings.Contains(out, "\x1b[?1000h") ||
+		!strings.Contains(out, "\x1b=") || !strings.Contains(out, s.ti.EnterCA) {
+		t.Errorf("wrong modes restored: %q", out)
+	}
+}
+
+func TestPromptMarker(t *testing.T) {
+	s, tty := mkDrawScreen(t, "xterm", 20, 4)
+	m := NewPromptMarker(s)
+	_ = m.PromptStart(0, 1)
+	_ = m.InputStart(2, 1)
+	_ = m.OutputStart(0, 2)
+	_ = m.CommandEnd(0, 3, 1)
+	out := tty.String()
+	for _, want := range []string{
+		"\x1b[2;1H\x1b]133;A\x1b\\",
+		"\x1b[2;3H\x1b]133;B\x1b\\",
+		"\x1b[3;1H\x1b]133;C\x1b\\",
+		"\x1b[4;1H\x1b]133;D;1\x1b\\",
+	} {
+		i := strings.Index(out, want)
+		if i < 0 {
+			t.Fatalf("missing %q in %q", want, out)
+		}
+		out = out[i+len(want):]
+	}
+}
+
+func TestDeflateTty(t *testing.T) {
+	out := &outputTty{w: 80, h: 24}
+	tty, err := NewDeflateTty(out, flate.BestCompression)
+	if err != nil {
+		t.Fatalf("failed to create tty: %v", err)
+	}
+	frame := strings.Repeat("\x1b[1;1Hhello ", 100)
+	if _, err = tty.Write([]byte(frame)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if out.Len() >= len(frame)/4 {
+		t.Errorf("output not compressed: %d bytes", out.Len())
+	}
+	// The frame can be decoded without waiting for the stream to end.
+	b := make([]byte, len(frame))
+	if _, err = io.ReadFull(flate.NewReader(&out.Buffer), b); err != nil || string(b) != frame {
+		t.Errorf("frame not decoded: %v", err)
+	}
+}
+
+// waitPending waits for a query to be sent.
+func waitPending(s *tScreen) {
+	for {
+		s.queries.Lock()
+		n := len(s.queries.pending)
+		s.queries.Unlock()
+		if n != 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	s, tty := mkDrawScreen(t, "xterm", 80, 24)
+	s.running = true
+	isDA := func(kind SequenceKind, payload string) bool {
+		return kind == SequenceCSI && strings.HasPrefix(payload, "?") && strings.HasSuffix(payload, "c")
+	}
+
+	done := make(chan string)
+	go func() {
+		reply, err := s.Query("\x1b[c", isDA, time.Second)
+		if err != nil {
+			t.Errorf("query failed: %v", err)
+		}
+		done <- reply
+	}()
+	waitPending(s)
+	// Other input is still delivered, and the reply is not.
+	evs := s.collectEventsFromInput(bytes.NewBufferString("a\x1b[?62;22cb"), true)
+	if len(evs) != 2 {
+		t.Errorf("expected 2 events, got %d", len(evs))
+	}
+	if reply := <-done; reply != "?62;22c" {
+		t.Errorf("wrong reply: %q", reply)
+	}
+	if !strings.Contains(tty.String(), "\x1b[c") {
+		t.Errorf("query not sent")
+	}
+
+	if _, err := s.Query("\x1b[c", isDA, 10*time.Millisecond); err != ErrQueryTimeout {
+		t.Errorf("expected timeout, got %v", err)
+	}
+
+	go func() {
+		_, err := s.Query("\x1b]11;?\x07", func(SequenceKind, string) bool { return true }, time.Second)
+		done <- err.Error()
+	}()
+	waitPending(s)
+	long := "\x1b]11;" + strings.Repeat("x", maxSequenceLen+1)
+	s.collectEventsFromInput(bytes.NewBufferString(long), false)
+	if err := <-done; err != ErrQueryTooLong.Error() {
+		t.Errorf("expected reply too long, got %v", err)
+	}
+}
+
+func TestColorModel(t *testing.T) {
+	for _, c := range []struct {
+		term      string
+		palette   int
+		truecolor bool
+		defaults  bool
+	}{
+		{"xterm-256color", 256, false, true},
+		{"xterm-truecolor", 256, true, true},
+		{"vt100", 0, false, false},
+	} {
+		ti, err := LookupTerminfo(c.term)
+		if err != nil {
+			t.Errorf("%s: %v", c.term, err)
+			continue
+		}
+		s, _ := NewTerminfoScreenFromTtyTerminfo(nil, ti)
+		ts := s.(*tScreen)
+		ts.truecolor = ti.SetFgBgRGB != ""
+		p, tc, d := ts.ColorModel()
+		if p != c.palette || tc != c.truecolor || d != c.defaults {
+			t.Errorf("%s: got %d %v %v", c.term, p, tc, d)
+		}
+	}
+}
+
+func TestScreenOptions(t *testing.T) {
+	tty := &outputTty{w: 80, h: 24}
+	s, err := NewScreenWithOptions(WithTERM("xterm-truecolor"), WithTty(tty),
+		WithColorMode(ColorMode16), WithoutAltScreen(), WithoutMouseCleanup())
+	if err != nil {
+		t.Fatalf("failed to create screen: %v", err)
+	}
+	ts := s.(*tScreen)
+	if ts.tty != tty || ts.nColors() != 16 || ts.ti.SetFgBgRGB != "" {
+		t.Errorf("options not applied: %d %q", ts.nColors(), ts.ti.SetFgBgRGB)
+	}
+	if ts.wantedModes().altScreen || !ts.keepMouse {
+		t.Errorf("modes not applied")
+	}
+	if ti, _ := LookupTerminfo("xterm-truecolor"); ti.SetFgBgRGB == "" {
+		t.Errorf("terminal database was changed")
+	}
+
+	s, _ = NewScreenWithOptions(WithTERM("vt100"), WithTty(tty), WithColorMode(ColorModeTrueColor))
+	if ts = s.(*tScreen); ts.ti.SetFgBgRGB == "" {
+		t.Errorf("true color not added")
+	}
+}
+
+func TestEnvOverrides(t *testing.T) {
+	_ = os.Setenv("TCELL_FORCE_TERM", "vt100")
+	_ = os.Setenv("TCELL_ALTSCREEN", "disable")
+	_ = os.Setenv("TCELL_MOUSE", "disable")
+	defer func() {
+		_ = os.Unsetenv("TCELL_FORCE_TERM")
+		_ = os.Unsetenv("TCELL_ALTSCREEN")
+		_ = os.Unsetenv("TCELL_MOUSE")
+	}()
+	tty := &outputTty{w: 80, h: 24}
+	s, err := NewScreenWithOptions(WithTERM("xterm"), WithTty(tty))
+	if err != nil {
+		t.Fatalf("failed to create screen: %v", err)
+	}
+	ts := s.(*tScreen)
+	ts.mouseFlags = MouseButtonEvents
+	if ts.ti.Name != "vt100" || ts.wantedModes().altScreen || ts.mouseModes() != 0 {
+		t.Errorf("overrides not applied: %s", ts.ti.Name)
+	}
+
+	s, _ = NewScreenWithOptions(WithTERM("xterm"), WithTty(tty), WithoutEnv())
+	ts = s.(*tScreen)
+	ts.mouseFlags = MouseButtonEvents
+	if ts.ti.Name != "xterm" || !ts.wantedModes().altScreen || ts.mouseModes() == 0 {
+		t.Errorf("environment read: %s", ts.ti.Name)
+	}
+}
+
+func TestQuirks(t *testing.T) {
+	file := `
+glyphs = "powerline"  # everywhere
+[wezterm]
+notify = "osc777"
+["XTERM*"]
+truecolor = true
+acs = 'never'
+[other]
+c1 = false
+`
+	q := parseQuirks(strings.NewReader(file), []string{"xterm-256color", ""})
+	if len(q) != 3 || q["TCELL_GLYPHS"] != "powerline" ||
+		q["TCELL_TRUECOLOR"] != "enable" || q["TCELL_ACS"] != "never" {
+		t.Errorf("wrong quirks: %v", q)
+	}
+
+	dir, err := ioutil.TempDir("", "tcell")
+	if err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "quirks.toml")
+	if err = ioutil.WriteFile(name, []byte("[xterm]\naltscreen = false\n"), 0644); err != nil {
+		t.Fatalf("failed to write quirks: %v", err)
+	}
+	_ = os.Setenv("TCELL_QUIRKS", name)
+	defer os.Unsetenv("TCELL_QUIRKS")
+	s, _ := NewScreenWithOptions(WithTERM("xterm"), WithTty(&outputTty{w: 80, h: 24}))
+	if !s.(*tScreen).noAltScreen {
+		t.Errorf("quirk not applied")
+	}
+}
+
+func TestSoftBlink(t *testing.T) {
+	s, tty := mkDrawScreen(t, "xterm", 10, 1)
+	s.softBlink = true
+	defer s.stopBlink()
+	st := StyleDefault.Blink(true)
+	s.SetContent(0, 0, 'a', nil, st)
+	s.SetContent(1, 0, 'b', nil, st)
+	s.SetContent(2, 0, 'c', nil, StyleDefault)
+	s.draw()
+	out := tty.String()
+	if !strings.Contains(out, "abc") || strings.Contains(out, s.ti.Blink) {
+		t.Errorf("wrong output when shown: %q", out)
+	}
+	if s.blinker == nil {
+		t.Errorf("blink timer not started")
+	}
+
+	tty.Reset()
+	s.blinkOff = true
+	s.SetContent(5, 0, 'z', nil, StyleDefault) // not yet shown
+	if n := s.drawBlink(); n != 2 {
+		t.Errorf("wrong number of blinking cells: %d", n)
+	}
+	out = tty.String()
+	if strings.ContainsAny(out, "abcz") || !strings.Contains(out, "  ") {
+		t.Errorf("wrong output when hidden: %q", out)
+	}
+
+	s, tty = mkDrawScreen(t, "xterm", 10, 1)
+	s.softBlink = false
+	s.SetContent(0, 0, 'a', nil, st)
+	s.draw()
+	if !strings.Contains(tty.String(), s.ti.Blink) || s.blinker != nil {
+		t.Errorf("hardware blink not used: %q", tty.String())
+	}
+}
+
+func TestTerminalID(t *testing.T) {
+	for _, c := range []struct {
+		reply string
+		id    TerminalID
+		ok    bool
+	}{
+		{">|XTerm(367)", TerminalID{"XTerm", "367"}, true},
+		{">|kitty(0.26.5)", TerminalID{"kitty", "0.26.5"}, true},
+		{">|tmux 3.3a", TerminalID{"tmux", "3.3a"}, true},
+		{">|foot", TerminalID{"foot", ""}, true},
+		{"$r0m", TerminalID{}, false},
+	} {
+		if id, ok := parseXTVersion(c.reply); id != c.id || ok != c.ok {
+			t.Errorf("%q: got %v %v", c.reply, id, ok)
+		}
+	}
+	for _, c := range []struct {
+		reply string
+		id    TerminalID
+	}{
+		{">41;367;0c", TerminalID{"XTerm", "367"}},
+		{">65;6800;1c", TerminalID{"VTE", "0.68.0"}},
+		{">77;30105;0c", TerminalID{"mintty", "3.1.5"}},
+		{">0;10;1c", TerminalID{}},
+	} {
+		if id, _ := parseDA2(c.reply); id != c.id {
+			t.Errorf("%q: got %v", c.reply, id)
+		}
+	}
+
+	id := TerminalID{"iTerm2", "3.4.19"}
+	if !id.Before("3.5") || !id.Before("3.10") || id.Before("3.4") || id.Before("3.4.19") {
+		t.Errorf("wrong version comparison")
+	}
+	if (TerminalID{Name: "foot"}).Before("1") {
+		t.Errorf("unknown version should not be before")
+	}
+
+	s, tty := mkDrawScreen(t, "xterm", 80, 24)
+	s.running = true
+	done := make(chan struct{})
+	go func() {
+		s.identify()
+		close(done)
+	}()
+	waitPending(s)
+	s.collectEventsFromInput(bytes.NewBufferString("\x1bP>|kitty(0.26.5)\x1b\\"), true)
+	<-done
+	if id := s.TerminalID(); id != (TerminalID{"kitty", "0.26.5"}) {
+		t.Errorf("wrong id: %v", id)
+	}
+	if !strings.Contains(tty.String(), "\x1b[>0q") {
+		t.Errorf("XTVERSION not sent")
+	}
+	if s.notify != notifyOSC99 {
+		t.Errorf("quirk not applied")
+	}
+}
+
+func TestReplaySession(t *testing.T) {
+	ti, e := LookupTerminfo("xterm-256color")
+	if e != nil {
+		t.Fatalf("no terminfo: %v", e)
+	}
+	orig := &Session{
+		Driver:       "terminfo",
+		Terminfo:     ti,
+		Env:          map[string]string{"TERM": "xterm-256color", "LANG": "en_US.UTF-8"},
+		Quirks:       map[string]string{"TCELL_ALTSCREEN": "disable"},
+		TerminalID:   TerminalID{Name: "kitty", Version: "0.26.5"},
+		CharacterSet: "UTF-8",
+		Width:        20,
+		Height:       5,
+	}
+	b, e := json.Marshal(orig)
+	if e != nil {
+		t.Fatalf("cannot marshal: %v", e)
+	}
+	sess := &Session{}
+	if e = json.Unmarshal(b, sess); e != nil {
+		t.Fatalf("cannot unmarshal: %v", e)
+	}
+
+	s, tty, e := ReplaySession(sess)
+	if e != nil {
+		t.Fatalf("cannot replay: %v", e)
+	}
+	if e = s.Init(); e != nil {
+		t.Fatalf("cannot init: %v", e)
+	}
+	defer s.Fini()
+
+	if w, h := s.Size(); w != 20 || h != 5 {
+		t.Errorf("wrong size %dx%d", w, h)
+	}
+	if id := s.TerminalID(); id != orig.TerminalID {
+		t.Errorf("wrong terminal %v", id)
+	}
+	if ts := s.(*tScreen); ts.notify != notifyOSC99 {
+		t.Errorf("identified quirk not applied")
+	}
+	s.Show()
+	if out := tty.Output(); len(out) == 0 {
+		t.Errorf("nothing was sent")
+	} else if bytes.Contains(out, []byte("\x1b[?1049h")) {
+		t.Errorf("quirk not applied: alternate screen used")
+	}
+
+	tty.Input([]byte("\x1b[A"))
+	for {
+		ev := s.PollEvent()
+		if ev, ok := ev.(*EventKey); ok {
+			if ev.Key() != KeyUp {
+				t.Errorf("wrong key %v", ev.Name())
+			}
+			break
+		}
+	}
+
+	again := CaptureSession(s)
+	if again.Width != 20 || again.Height != 5 || again.TerminalID != orig.TerminalID ||
+		again.Env["LANG"] != "en_US.UTF-8" || again.Quirks["TCELL_ALTSCREEN"] != "disable" {
+		t.Errorf("session not captured again: %+v", again)
+	}
+	if _, _, e = ReplaySession(&Session{}); e != ErrInvalidSession {
+		t.Errorf("replayed empty session: %v", e)
+	}
+}
+
+func TestCellChangeMerged(t *testing.T) {
+	s, _ := mkDrawScreen(t, "xterm", 10, 2)
+	n := 0
+	s.OnCellChange(0, 0, 1, 1, func(x, y int) { n++ })
+	// Drawing without Show (as EmitRaw does) notes each cell only once.
+	for i := 0; i < 100; i++ {
+		s.SetContent(0, 0, rune('a'+i%2), nil, StyleDefault)
+		s.draw()
+	}
+	reportChanges(s.changes.take())
+	if n != 1 {
+		t.Errorf("wrong number of changes reported: %d", n)
+	}
+}
+
+func TestBestFitWide(t *testing.T) {
+	s, tty := mkDrawScreen(t, "vt100", 20, 2)
+	s.charset = "US-ASCII"
+	s.encoder = GetEncoding(s.charset).NewEncoder()
+	s.SetContent(0, 0, 'Ａ', nil, StyleDefault)
+	s.SetContent(2, 0, '…', nil, StyleDefault)
+	s.SetContent(3, 0, 'x', nil, StyleDefault)
+	s.draw()
+	// The fullwidth A is padded to fill its two cells.
+	if out := tty.String(); !strings.Contains(out, "A ") || !strings.Contains(out, "\x1b[1;3H.x") {
+		t.Errorf("best fit shifted the row: %q", out)
+	}
+}
+
+func TestScrollMargins(t *testing.T) {
+	s, tty := mkDrawScreen(t, "xterm-256color", 20, 5)
+	s.running = true
+	s.Scroll(2, 1, 5, 3, 1)
+	if out := tty.String(); strings.Contains(out, decLRMMOn) {
+		t.Errorf("margins used without support: %q", out)
+	}
+	tty.Reset()
+	s.Scroll(0, 1, 20, 3, 1)
+	if out := tty.String(); !strings.Contains(out, "\x1b[2;4r") {
+		t.Errorf("full width region not scrolled: %q", out)
+	}
+
+	// Once XTerm has identified itself, its margins are used.
+	identifyXTerm(s)
+	tty.Reset()
+	s.Scroll(2, 1, 5, 3, 1)
+	if out := tty.String(); !strings.Contains(out, decLRMMOn+"\x1b[3;7s") {
+		t.Errorf("margins not used: %q", out)
+	}
+}
@@ -16,6 +16,11 @@
 
 package tcell
 
+import (
+	"os"
+	"syscall"
+)
+
 // initialize is used at application startup, and sets up the initial values
 // including file descriptors used for terminals and saving the initial state
 // so that it can be restored when the application terminates.
@@ -29,3 +34,10 @@ func (t *tScreen) initialize() error {
 	}
 	return nil
 }
+
+// isResizeSignal returns true if the signal indicates that the terminal
+// size may have changed.  SIGCONT is included because the window may
+// have been resized while we were stopped.
+func isResizeSignal(sig os.Signal) bool {
+	return sig == syscall.SIGWINCH || sig == syscall.SIGCONT
+}
@@ -53,4 +53,4 @@ type Tty interface {
 	WindowSize() (width int, height int, err error)
 
 	io.ReadWriteCloser
-}
\ No newline at end of file
+}
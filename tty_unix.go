@@ -36,6 +36,7 @@ type devTty struct {
 	of    *os.File // the first open of /dev/tty
 	saved *term.State
 	sig   chan os.Signal
+	nosig bool
 	cb    func()
 	stopQ chan struct{}
 	dev   string
@@ -103,7 +104,9 @@ func (tty *devTty) Start() error {
 		}
 	}(tty.stopQ)
 
-	signal.Notify(tty.sig, syscall.SIGWINCH)
+	if !tty.nosig {
+		signal.Notify(tty.sig, syscall.SIGWINCH)
+	}
 	return nil
 }
 
@@ -155,6 +158,12 @@ func (tty *devTty) WindowSize() (int, int, error) {
 	return w, h, nil
 }
 
+func (tty *devTty) DisableSignals(disable bool) {
+	tty.l.Lock()
+	tty.nosig = disable
+	tty.l.Unlock()
+}
+
 func (tty *devTty) NotifyResize(cb func()) {
 	tty.l.Lock()
 	tty.cb = cb
@@ -0,0 +1,86 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"sync"
+)
+
+// KeyUser is the first Key value allocated by RegisterKey.  Keys from
+// this value up are reserved for application defined keys, and tcell
+// will never define keys of its own in this range.
+const KeyUser Key = 8192
+
+var userKeys struct {
+	sync.Mutex
+	next  Key
+	names map[string]Key
+	seqs  map[string]tKeyCode
+}
+
+// RegisterKey allocates a new Key for a key that tcell does not otherwise
+// know about, such as a macro key or a media key, and records its name in
+// KeyNames.  If a key has already been registered with the same name, then
+// that key is returned instead of allocating a new one.
+//
+// Registering a key by itself does not cause it to be reported; use
+// RegisterKeySequence to tell tcell what the terminal sends for it.
+// Keys should be registered during program initialization, before any
+// screens are created, as KeyNames is not protected against concurrent
+// access.
+func RegisterKey(name string) Key {
+	userKeys.Lock()
+	defer userKeys.Unlock()
+	if userKeys.names == nil {
+		userKeys.names = make(map[string]Key)
+		userKeys.next = KeyUser
+	}
+	if k, ok := userKeys.names[name]; ok {
+		return k
+	}
+	if userKeys.next >= keyPasteStart {
+		panic("tcell: too many registered keys")
+	}
+	k := userKeys.next
+	userKeys.next++
+	userKeys.names[name] = k
+	KeyNames[k] = name
+	return k
+}
+
+// RegisterKeySequence arranges for the given input sequence to be reported
+// as the key (normally one allocated by RegisterKey) with the modifiers.
+// Sequences registered this way take precedence over those that tcell
+// would otherwise recognize.  This only affects terminfo based screens
+// created after the call; the Windows console reports keys as virtual key
+// codes rather than sequences, and so is unaffected.
+func RegisterKeySequence(k Key, mod ModMask, seq string) {
+	userKeys.Lock()
+	defer userKeys.Unlock()
+	if userKeys.seqs == nil {
+		userKeys.seqs = make(map[string]tKeyCode)
+	}
+	userKeys.seqs[seq] = tKeyCode{key: k, mod: mod}
+}
+
+// prepareUserKeys adds the registered key sequences to the screen.
+// It must be called before any other keys are prepared.
+func (t *tScreen) prepareUserKeys() {
+	userKeys.Lock()
+	defer userKeys.Unlock()
+	for seq, kc := range userKeys.seqs {
+		t.prepareKeyMod(kc.key, kc.mod, seq)
+	}
+}
@@ -25,6 +25,7 @@ type Application struct {
 	widget Widget
 	screen tcell.Screen
 	style  tcell.Style
+	keys   *Shortcuts
 	err    error
 	wg     sync.WaitGroup
 }
@@ -96,6 +97,12 @@ func (app *Application) SetScreen(scr tcell.Screen) {
 	}
 }
 
+// SetShortcuts sets the key bindings for the application.  Key events
+// that the root Widget does not handle are offered to them.
+func (app *Application) SetShortcuts(keys *Shortcuts) {
+	app.keys = keys
+}
+
 // SetStyle sets the default style (background) to be used for Widgets
 // that have not specified any other style.
 func (app *Application) SetStyle(style tcell.Style) {
@@ -145,14 +152,18 @@ loop:
 		case *eventAppUpdate:
 			screen.Show()
 		case *eventAppRefresh:
+			Invalidate(widget)
 			screen.Sync()
 		case *eventAppFunc:
 			nev.fn()
 		case *tcell.EventResize:
 			screen.Sync()
 			widget.Resize()
+			Invalidate(widget)
 		default:
-			widget.HandleEvent(ev)
+			if !widget.HandleEvent(ev) && app.keys != nil {
+				app.keys.HandleEvent(ev)
+			}
 		}
 	}
 }
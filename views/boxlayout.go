@@ -20,6 +20,10 @@ import (
 
 // BoxLayout is a container Widget that lays out its child widgets in
 // either a horizontal row or a vertical column.
+//
+// Once drawn, a BoxLayout only redraws the children that have changed,
+// that is, those that have posted EventWidgetContent or handled an event
+// since, unless the layout itself changes.
 type BoxLayout struct {
 	view    View
 	orient  Orientation
@@ -28,6 +32,7 @@ type BoxLayout struct {
 	width   int
 	height  int
 	changed bool
+	drawn   bool // the view holds a complete drawing
 
 	WidgetWatchers
 }
@@ -38,6 +43,19 @@ type boxLayoutCell struct {
 	pad    int     // count of padding spaces (stretch)
 	frac   float64 // calculated residual spacing, used internally
 	view   *ViewPort
+	rect   [4]int // location when last laid out
+	dirty  bool   // must be redrawn
+	box    *BoxLayout
+}
+
+// HandleEvent receives the events of the cell's widget.  Each cell watches
+// its own widget, so that we know which one changed, even if the event
+// names a Widget embedded within it.
+func (c *boxLayoutCell) HandleEvent(ev tcell.Event) bool {
+	if _, ok := ev.(*EventWidgetContent); ok {
+		c.dirty = true
+	}
+	return c.box.HandleEvent(ev)
 }
 
 func (b *BoxLayout) hLayout() {
@@ -182,6 +200,21 @@ func (b *BoxLayout) layout() {
 		panic("Bad orientation")
 	}
 	b.changed = false
+
+	// If any cell moved, everything must be redrawn.
+	for _, c := range b.cells {
+		var rect [4]int
+		rect[0], rect[1], rect[2], rect[3] = c.view.GetPhysical()
+		if rect != c.rect {
+			c.rect = rect
+			b.drawn = false
+		}
+	}
+}
+
+// Invalidate causes the next Draw to redraw every child.
+func (b *BoxLayout) Invalidate() {
+	b.drawn = false
 }
 
 // Resize adjusts the layout when the underlying View changes size.
@@ -204,10 +237,19 @@ func (b *BoxLayout) Draw() {
 	if b.changed {
 		b.layout()
 	}
-	b.view.Fill(' ', b.style)
-	for i := range b.cells {
-		b.cells[i].widget.Draw()
+	if !b.drawn {
+		b.view.Fill(' ', b.style)
+	}
+	for _, c := range b.cells {
+		if !b.drawn {
+			Invalidate(c.widget)
+		} else if !c.dirty {
+			continue
+		}
+		c.dirty = false
+		c.widget.Draw()
 	}
+	b.drawn = true
 }
 
 // Size returns the preferred size in character cells (width, height).
@@ -218,6 +260,7 @@ func (b *BoxLayout) Size() (int, int) {
 // SetView sets the View object used for the text bar.
 func (b *BoxLayout) SetView(view View) {
 	b.changed = true
+	b.drawn = false
 	b.view = view
 	for _, c := range b.cells {
 		c.view.SetView(view)
@@ -238,6 +281,7 @@ func (b *BoxLayout) HandleEvent(ev tcell.Event) bool {
 	}
 	for _, c := range b.cells {
 		if c.widget.HandleEvent(ev) {
+			c.dirty = true
 			return true
 		}
 	}
@@ -250,11 +294,12 @@ func (b *BoxLayout) AddWidget(widget Widget, fill float64) {
 		widget: widget,
 		fill:   fill,
 		view:   NewViewPort(b.view, 0, 0, 0, 0),
+		box:    b,
 	}
 	widget.SetView(c.view)
 	b.cells = append(b.cells, c)
 	b.changed = true
-	widget.Watch(b)
+	widget.Watch(c)
 	b.layout()
 	b.PostEventWidgetContent(b)
 }
@@ -267,6 +312,7 @@ func (b *BoxLayout) InsertWidget(index int, widget Widget, fill float64) {
 		widget: widget,
 		fill:   fill,
 		view:   NewViewPort(b.view, 0, 0, 0, 0),
+		box:    b,
 	}
 	c.widget.SetView(c.view)
 	if index < 0 {
@@ -278,7 +324,7 @@ func (b *BoxLayout) InsertWidget(index int, widget Widget, fill float64) {
 	b.cells = append(b.cells, c)
 	copy(b.cells[index+1:], b.cells[index:])
 	b.cells[index] = c
-	widget.Watch(b)
+	widget.Watch(c)
 	b.layout()
 	b.PostEventWidgetContent(b)
 }
@@ -288,6 +334,7 @@ func (b *BoxLayout) RemoveWidget(widget Widget) {
 	changed := false
 	for i := 0; i < len(b.cells); i++ {
 		if b.cells[i].widget == widget {
+			widget.Unwatch(b.cells[i])
 			b.cells = append(b.cells[:i], b.cells[i+1:]...)
 			changed = true
 		}
@@ -296,7 +343,7 @@ func (b *BoxLayout) RemoveWidget(widget Widget) {
 		return
 	}
 	b.changed = true
-	widget.Unwatch(b)
+	b.drawn = false
 	b.layout()
 	b.PostEventWidgetContent(b)
 }
@@ -322,6 +369,7 @@ func (b *BoxLayout) SetOrientation(orient Orientation) {
 // SetStyle sets the style used.
 func (b *BoxLayout) SetStyle(style tcell.Style) {
 	b.style = style
+	b.drawn = false
 	b.PostEventWidgetContent(b)
 }
 
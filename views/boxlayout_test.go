@@ -0,0 +1,69 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"testing"
+)
+
+// countingText is a Text that counts how often it is drawn.
+type countingText struct {
+	draws int
+	Text
+}
+
+func (t *countingText) Draw() {
+	t.draws++
+	t.Text.Draw()
+}
+
+func TestBoxLayoutPartialDraw(t *testing.T) {
+	v := newTestView(10, 2)
+	b := NewBoxLayout(Vertical)
+	b.SetView(v)
+	one, two := &countingText{}, &countingText{}
+	one.SetText("one")
+	two.SetText("two")
+	b.AddWidget(one, 0)
+	b.AddWidget(two, 0)
+
+	b.Draw()
+	if one.draws != 1 || two.draws != 1 {
+		t.Fatalf("first draw incomplete: %d %d", one.draws, two.draws)
+	}
+	b.Draw()
+	if one.draws != 1 || two.draws != 1 {
+		t.Errorf("unchanged widgets redrawn: %d %d", one.draws, two.draws)
+	}
+
+	two.SetText("TWO")
+	b.Draw()
+	if one.draws != 1 || two.draws != 2 || v.row(1) != "TWO       " {
+		t.Errorf("wrong redraw: %d %d %q", one.draws, two.draws, v.row(1))
+	}
+
+	// A change of size moves things, so everything is redrawn.
+	one.SetText("one\nmore")
+	b.Draw()
+	if one.draws != 2 || two.draws != 3 {
+		t.Errorf("layout change not redrawn: %d %d", one.draws, two.draws)
+	}
+
+	b.Invalidate()
+	b.Draw()
+	if one.draws != 3 || two.draws != 4 {
+		t.Errorf("invalidate ignored: %d %d", one.draws, two.draws)
+	}
+}
@@ -277,6 +277,7 @@ func (a *CellView) Resize() {
 // SetCursor sets the the cursor position.
 func (a *CellView) SetCursor(x, y int) {
 	a.model.SetCursor(x, y)
+	a.PostEventWidgetContent(a)
 }
 
 // SetCursorX sets the the cursor column.
@@ -295,11 +296,13 @@ func (a *CellView) SetCursorY(y int) {
 // It does this by moving the ViewPort for the CellView.
 func (a *CellView) MakeVisible(x, y int) {
 	a.port.MakeVisible(x, y)
+	a.PostEventWidgetContent(a)
 }
 
 // SetStyle sets the the default fill style.
 func (a *CellView) SetStyle(s tcell.Style) {
 	a.style = s
+	a.PostEventWidgetContent(a)
 }
 
 // Init initializes a new CellView for use.
@@ -0,0 +1,55 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// dimView is a View that passes everything drawn on it through to another,
+// transforming its style with filter, if that is set.  The containers that
+// draw over their content, such as PopupMenu and Help, give their content
+// one, so that it can be dimmed beneath them.
+type dimView struct {
+	View
+	filter tcell.RenderFilter
+}
+
+// of makes d draw on view, and returns it, or nil if view is nil.
+func (d *dimView) of(view View) View {
+	d.View = view
+	if view == nil {
+		return nil
+	}
+	return d
+}
+
+func (d *dimView) SetContent(x, y int, ch rune, comb []rune, style tcell.Style) {
+	if d.filter != nil {
+		style = d.filter(style)
+	}
+	d.View.SetContent(x, y, ch, comb, style)
+}
+
+func (d *dimView) Fill(ch rune, style tcell.Style) {
+	if d.filter != nil {
+		style = d.filter(style)
+	}
+	d.View.Fill(ch, style)
+}
+
+func (d *dimView) Clear() {
+	d.Fill(' ', tcell.StyleDefault)
+}
@@ -0,0 +1,291 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// DragSource is implemented by Widgets that drags can start from.  See
+// EventDragStart for how a drag is started.
+type DragSource interface {
+	// DragEnd is called when a drag from the source ends, with the last
+	// EventDrag, and whether the data was dropped on a target.
+	DragEnd(ev *EventDrag, dropped bool)
+}
+
+// DropTarget is implemented by Widgets that data can be dropped on.  See
+// EventDrag for how a target accepts a drag.
+type DropTarget interface {
+	// DragEnter is called when a drag moves onto the target.
+	DragEnter(ev *EventDrag)
+
+	// DragLeave is called when a drag moves off the target, or is
+	// cancelled while over it.
+	DragLeave(ev *EventDrag)
+
+	// Drop is called when the data is dropped on the target, and returns
+	// true if it was taken.
+	Drop(ev *EventDrag) bool
+}
+
+// dragPoint is the part common to the drag events.
+type dragPoint struct {
+	x, y int
+	t    time.Time
+}
+
+// When returns the time of the event.
+func (ev *dragPoint) When() time.Time {
+	return ev.t
+}
+
+// Position returns the screen position of the mouse.
+func (ev *dragPoint) Position() (int, int) {
+	return ev.x, ev.y
+}
+
+// Local returns the position of the mouse relative to a View, and whether
+// it lies within that View.
+func (ev *dragPoint) Local(v View) (int, int, bool) {
+	if v == nil {
+		return 0, 0, false
+	}
+	dx, dy := viewOffset(v)
+	x, y := ev.x-dx, ev.y-dy
+	w, h := v.Size()
+	return x, y, x >= 0 && x < w && y >= 0 && y < h
+}
+
+// EventDragStart is passed down through HandleEvent when the mouse is
+// moved with the first button held.  Its position is that where the button
+// was pressed.  A DragSource at that position that wants to start a drag
+// calls Begin, and returns true.
+type EventDragStart struct {
+	source DragSource
+	data   interface{}
+	dragPoint
+}
+
+// Begin starts a drag of the given data from a source.
+func (ev *EventDragStart) Begin(source DragSource, data interface{}) {
+	ev.source = source
+	ev.data = data
+}
+
+// EventDrag is passed down through HandleEvent each time the mouse moves
+// during a drag.  A DropTarget at the mouse position that would take the
+// data calls Accept, and returns true.  The same event is passed to the
+// methods of DragSource and DropTarget.
+type EventDrag struct {
+	source DragSource
+	target DropTarget
+	data   interface{}
+	dragPoint
+}
+
+// Accept marks the target as the one that the data would be dropped on.
+func (ev *EventDrag) Accept(target DropTarget) {
+	ev.target = target
+}
+
+// Data returns the data being dragged.
+func (ev *EventDrag) Data() interface{} {
+	return ev.data
+}
+
+// Source returns the source of the drag.
+func (ev *EventDrag) Source() DragSource {
+	return ev.source
+}
+
+// DragDrop is a container Widget that turns the mouse events for its
+// content into drags.  When the mouse is moved with the first button held,
+// an EventDragStart is offered to the content.  If a source begins a drag,
+// then the mouse events are taken by the DragDrop, and an EventDrag is
+// offered to the content for each one, which finds the target under the
+// mouse.  The targets are told as the drag enters and leaves them, and
+// when the button is released, the data is dropped on the target, if any.
+// Escape cancels the drag.
+type DragDrop struct {
+	view    View
+	content Widget
+	buttons tcell.ButtonMask
+	px, py  int  // where the button was pressed
+	tried   bool // whether a drag was offered for this press
+	source  DragSource
+	target  DropTarget
+	data    interface{}
+
+	WidgetWatchers
+}
+
+// SetContent sets the Widget that drags happen within.
+func (d *DragDrop) SetContent(w Widget) {
+	if d.content != nil {
+		d.content.Unwatch(d)
+	}
+	d.content = w
+	if w != nil {
+		w.SetView(d.view)
+		w.Watch(d)
+	}
+	d.PostEventWidgetContent(d)
+}
+
+// Dragging returns true while a drag is in progress.
+func (d *DragDrop) Dragging() bool {
+	return d.source != nil
+}
+
+// Cancel ends any drag in progress, without dropping it.
+func (d *DragDrop) Cancel() {
+	if d.source != nil {
+		d.finish(d.event(d.px, d.py), false)
+	}
+}
+
+func (d *DragDrop) event(x, y int) *EventDrag {
+	ev := &EventDrag{source: d.source, data: d.data}
+	ev.x, ev.y, ev.t = x, y, time.Now()
+	return ev
+}
+
+// over finds the target at the position, telling the targets if it has
+// changed.
+func (d *DragDrop) over(x, y int) *EventDrag {
+	ev := d.event(x, y)
+	if d.content != nil {
+		d.content.HandleEvent(ev)
+	}
+	if ev.target != d.target {
+		if d.target != nil {
+			d.target.DragLeave(ev)
+		}
+		d.target = ev.target
+		if d.target != nil {
+			d.target.DragEnter(ev)
+		}
+	}
+	return ev
+}
+
+func (d *DragDrop) finish(ev *EventDrag, drop bool) {
+	dropped := false
+	if d.target != nil {
+		if drop {
+			dropped = d.target.Drop(ev)
+		} else {
+			d.target.DragLeave(ev)
+		}
+	}
+	source := d.source
+	d.source, d.target, d.data = nil, nil, nil
+	source.DragEnd(ev, dropped)
+}
+
+func (d *DragDrop) handleMouse(ev *tcell.EventMouse) bool {
+	buttons := ev.Buttons()
+	held := buttons&tcell.Button1 != 0
+	pressed := held && d.buttons&tcell.Button1 == 0
+	d.buttons = buttons
+	x, y := ev.Position()
+
+	if d.source != nil {
+		dev := d.over(x, y)
+		if !held {
+			d.finish(dev, true)
+		}
+		return true
+	}
+	if pressed {
+		d.px, d.py, d.tried = x, y, false
+	} else if held && !d.tried && (x != d.px || y != d.py) && d.content != nil {
+		d.tried = true
+		start := &EventDragStart{}
+		start.x, start.y, start.t = d.px, d.py, time.Now()
+		if d.content.HandleEvent(start) && start.source != nil {
+			d.source, d.data = start.source, start.data
+			d.over(x, y)
+			return true
+		}
+	}
+	return false
+}
+
+// Draw draws the content.
+func (d *DragDrop) Draw() {
+	if d.content != nil {
+		d.content.Draw()
+	}
+}
+
+// Resize is called when our View changes sizes.
+func (d *DragDrop) Resize() {
+	if d.content != nil {
+		d.content.Resize()
+	}
+	d.PostEventWidgetResize(d)
+}
+
+// Size returns the size of the content.
+func (d *DragDrop) Size() (int, int) {
+	if d.content != nil {
+		return d.content.Size()
+	}
+	return 0, 0
+}
+
+// SetView sets the View object used for the DragDrop and its content.
+func (d *DragDrop) SetView(view View) {
+	d.view = view
+	if d.content != nil {
+		d.content.SetView(view)
+	}
+}
+
+// HandleEvent handles mouse events to track drags, and passes other
+// events to the content.
+func (d *DragDrop) HandleEvent(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *EventWidgetContent:
+		d.PostEventWidgetContent(d)
+		return true
+	case *EventWidgetResize, *EventWidgetMove:
+		return true
+	case *tcell.EventMouse:
+		if d.handleMouse(ev) {
+			return true
+		}
+	case *tcell.EventKey:
+		if d.source != nil {
+			if ev.Key() == tcell.KeyEscape {
+				d.Cancel()
+			}
+			return true
+		}
+	}
+	if d.content != nil {
+		return d.content.HandleEvent(ev)
+	}
+	return false
+}
+
+// NewDragDrop creates a DragDrop, with no content.
+func NewDragDrop() *DragDrop {
+	return &DragDrop{}
+}
@@ -0,0 +1,158 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// dragList is a list of one item per row, whose items can be dragged
+// within it or to another dragList.
+type dragList struct {
+	view   View
+	items  []string
+	over   bool
+	ended  int
+	insert int
+	WidgetWatchers
+}
+
+func (l *dragList) DragEnd(ev *EventDrag, dropped bool) {
+	l.ended++
+	if dropped {
+		// The target has inserted it, so remove the original.
+		for i, s := range l.items {
+			if s == ev.Data() && i != l.insert {
+				l.items = append(l.items[:i], l.items[i+1:]...)
+				break
+			}
+		}
+	}
+	l.insert = -1
+}
+
+func (l *dragList) DragEnter(*EventDrag) { l.over = true }
+func (l *dragList) DragLeave(*EventDrag) { l.over = false }
+
+func (l *dragList) Drop(ev *EventDrag) bool {
+	l.over = false
+	_, y, _ := ev.Local(l.view)
+	if y > len(l.items) {
+		y = len(l.items)
+	}
+	if ev.Source() == DragSource(l) && y > 0 && l.items[y-1] == ev.Data() {
+		return false // dropped on itself
+	}
+	l.items = append(l.items[:y], append([]string{ev.Data().(string)}, l.items[y:]...)...)
+	l.insert = y
+	return true
+}
+
+func (l *dragList) HandleEvent(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *EventDragStart:
+		if _, y, ok := ev.Local(l.view); ok && y < len(l.items) {
+			ev.Begin(l, l.items[y])
+			return true
+		}
+	case *EventDrag:
+		if _, _, ok := ev.Local(l.view); ok {
+			ev.Accept(l)
+			return true
+		}
+	}
+	return false
+}
+
+func (l *dragList) Draw() {
+	for y, s := range l.items {
+		for x, r := range s {
+			l.view.SetContent(x, y, r, nil, tcell.StyleDefault)
+		}
+	}
+}
+
+func (l *dragList) Resize()           {}
+func (l *dragList) SetView(view View) { l.view = view }
+func (l *dragList) Size() (int, int)  { return 1, len(l.items) }
+
+func TestDragDrop(t *testing.T) {
+	v := newTestView(10, 5)
+	left := &dragList{items: []string{"a", "b", "c"}, insert: -1}
+	right := &dragList{items: []string{"x"}, insert: -1}
+	box := NewBoxLayout(Horizontal)
+	box.AddWidget(left, 1)
+	box.AddWidget(right, 1)
+	d := NewDragDrop()
+	d.SetView(v)
+	d.SetContent(box)
+	box.Resize()
+
+	mouse := func(x, y int, b tcell.ButtonMask) {
+		d.HandleEvent(tcell.NewEventMouse(x, y, b, 0))
+	}
+
+	// A click without moving does not start a drag.
+	mouse(0, 1, tcell.Button1)
+	mouse(0, 1, tcell.ButtonNone)
+	if d.Dragging() || left.ended != 0 {
+		t.Fatalf("drag started by a click")
+	}
+
+	// Drag "b" over the right list, and then drop it below "x".
+	mouse(0, 1, tcell.Button1)
+	mouse(1, 1, tcell.Button1)
+	if !d.Dragging() {
+		t.Fatalf("drag not started")
+	}
+	if !left.over || right.over {
+		t.Errorf("wrong target on start: %v %v", left.over, right.over)
+	}
+	mouse(6, 1, tcell.Button1)
+	if left.over || !right.over {
+		t.Errorf("wrong target after move: %v %v", left.over, right.over)
+	}
+	mouse(6, 1, tcell.ButtonNone)
+	if d.Dragging() {
+		t.Errorf("still dragging after drop")
+	}
+	if got := strings.Join(left.items, ""); got != "ac" {
+		t.Errorf("wrong left items: %q", got)
+	}
+	if got := strings.Join(right.items, ""); got != "xb" {
+		t.Errorf("wrong right items: %q", got)
+	}
+	if left.ended != 1 || right.over {
+		t.Errorf("drag not finished: %d %v", left.ended, right.over)
+	}
+
+	// Escape cancels a drag.
+	mouse(5, 0, tcell.Button1)
+	mouse(1, 0, tcell.Button1)
+	if !d.Dragging() || !left.over {
+		t.Fatalf("drag from right not started")
+	}
+	d.HandleEvent(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if d.Dragging() || left.over || right.ended != 1 {
+		t.Errorf("drag not cancelled")
+	}
+	mouse(1, 2, tcell.ButtonNone)
+	if got := strings.Join(left.items, "") + strings.Join(right.items, ""); got != "acxb" {
+		t.Errorf("items changed by cancelled drag: %q", got)
+	}
+}
@@ -0,0 +1,638 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// FormField is a field of a Form.  The concrete types are InputField,
+// Checkbox, SelectField, and Button.
+type FormField interface {
+	// FieldLabel returns the label shown for the field.
+	FieldLabel() string
+
+	// Error returns the error from the last validation, if any.
+	Error() error
+
+	validate()
+	width() int
+	draw(f *Form, x, y int, focused bool)
+	handleKey(f *Form, ev *tcell.EventKey) bool
+	click(f *Form, x int)
+}
+
+// InputField is a single line text entry field.
+type InputField struct {
+	Label     string
+	Value     string
+	Width     int                      // width of the entry area, in cells
+	Mask      rune                     // if not zero, shown in place of each rune
+	Validator func(value string) error // checks the value, if not nil
+
+	cursor int
+	err    error
+}
+
+// FieldLabel returns the label of the field.
+func (in *InputField) FieldLabel() string { return in.Label }
+
+// Error returns the error from the last validation, if any.
+func (in *InputField) Error() error { return in.err }
+
+func (in *InputField) validate() {
+	in.err = nil
+	if in.Validator != nil {
+		in.err = in.Validator(in.Value)
+	}
+}
+
+func (in *InputField) width() int {
+	return in.Width
+}
+
+func (in *InputField) draw(f *Form, x, y int, focused bool) {
+	style := f.style
+	if focused {
+		style = f.focus
+	}
+	runes := []rune(in.Value)
+	if in.cursor > len(runes) {
+		in.cursor = len(runes)
+	}
+	// Scroll so that the cursor is always visible.
+	start := 0
+	for runewidth.StringWidth(string(runes[start:in.cursor])) >= in.Width && start < in.cursor {
+		start++
+	}
+	col := 0
+	for i := start; i <= len(runes) && col < in.Width; i++ {
+		r := ' '
+		if i < len(runes) {
+			r = runes[i]
+			if in.Mask != 0 {
+				r = in.Mask
+			}
+		}
+		cs := style
+		if focused && i == in.cursor {
+			cs = cs.Reverse(true)
+		}
+		f.view.SetContent(x+col, y, r, nil, cs)
+		col += runewidth.RuneWidth(r)
+	}
+	for ; col < in.Width; col++ {
+		f.view.SetContent(x+col, y, ' ', nil, style)
+	}
+}
+
+func (in *InputField) handleKey(f *Form, ev *tcell.EventKey) bool {
+	runes := []rune(in.Value)
+	switch ev.Key() {
+	case tcell.KeyRune:
+		runes = append(runes[:in.cursor], append([]rune{ev.Rune()}, runes[in.cursor:]...)...)
+		in.cursor++
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if in.cursor == 0 {
+			return true
+		}
+		in.cursor--
+		runes = append(runes[:in.cursor], runes[in.cursor+1:]...)
+	case tcell.KeyDelete:
+		if in.cursor < len(runes) {
+			runes = append(runes[:in.cursor], runes[in.cursor+1:]...)
+		}
+	case tcell.KeyLeft:
+		if in.cursor > 0 {
+			in.cursor--
+		}
+	case tcell.KeyRight:
+		if in.cursor < len(runes) {
+			in.cursor++
+		}
+	case tcell.KeyHome, tcell.KeyCtrlA:
+		in.cursor = 0
+	case tcell.KeyEnd, tcell.KeyCtrlE:
+		in.cursor = len(runes)
+	default:
+		return false
+	}
+	in.Value = string(runes)
+	return true
+}
+
+func (in *InputField) click(f *Form, x int) {
+	in.cursor = x
+	if n := len([]rune(in.Value)); in.cursor > n {
+		in.cursor = n
+	}
+}
+
+// Checkbox is a field that is either checked or not.  Space toggles it.
+type Checkbox struct {
+	Label     string
+	Checked   bool
+	Validator func(checked bool) error // checks the value, if not nil
+
+	err error
+}
+
+// FieldLabel returns the label of the field.
+func (cb *Checkbox) FieldLabel() string { return cb.Label }
+
+// Error returns the error from the last validation, if any.
+func (cb *Checkbox) Error() error { return cb.err }
+
+func (cb *Checkbox) validate() {
+	cb.err = nil
+	if cb.Validator != nil {
+		cb.err = cb.Validator(cb.Checked)
+	}
+}
+
+func (cb *Checkbox) width() int {
+	return 3
+}
+
+func (cb *Checkbox) draw(f *Form, x, y int, focused bool) {
+	style := f.style
+	if focused {
+		style = f.focus
+	}
+	mark := ' '
+	if cb.Checked {
+		mark = 'x'
+	}
+	f.view.SetContent(x, y, '[', nil, style)
+	f.view.SetContent(x+1, y, mark, nil, style)
+	f.view.SetContent(x+2, y, ']', nil, style)
+}
+
+func (cb *Checkbox) handleKey(f *Form, ev *tcell.EventKey) bool {
+	if ev.Key() == tcell.KeyRune && ev.Rune() == ' ' {
+		cb.Checked = !cb.Checked
+		return true
+	}
+	return false
+}
+
+func (cb *Checkbox) click(f *Form, x int) {
+	cb.Checked = !cb.Checked
+}
+
+// SelectField is a field that offers a choice of options.  Left and Right
+// (or space) move between them.
+type SelectField struct {
+	Label     string
+	Options   []string
+	Selected  int
+	Validator func(selected int) error // checks the value, if not nil
+
+	err error
+}
+
+// FieldLabel returns the label of the field.
+func (sf *SelectField) FieldLabel() string { return sf.Label }
+
+// Error returns the error from the last validation, if any.
+func (sf *SelectField) Error() error { return sf.err }
+
+func (sf *SelectField) validate() {
+	sf.err = nil
+	if sf.Validator != nil {
+		sf.err = sf.Validator(sf.Selected)
+	}
+}
+
+func (sf *SelectField) width() int {
+	w := 0
+	for _, o := range sf.Options {
+		if ow := runewidth.StringWidth(o); ow > w {
+			w = ow
+		}
+	}
+	return w + 4
+}
+
+func (sf *SelectField) draw(f *Form, x, y int, focused bool) {
+	style := f.style
+	if focused {
+		style = f.focus
+	}
+	w := sf.width()
+	for col := 0; col < w; col++ {
+		f.view.SetContent(x+col, y, ' ', nil, style)
+	}
+	f.view.SetContent(x, y, '<', nil, style)
+	f.view.SetContent(x+w-1, y, '>', nil, style)
+	if sf.Selected >= 0 && sf.Selected < len(sf.Options) {
+		col := x + 2
+		for _, r := range sf.Options[sf.Selected] {
+			f.view.SetContent(col, y, r, nil, style)
+			col += runewidth.RuneWidth(r)
+		}
+	}
+}
+
+func (sf *SelectField) move(dir int) {
+	if n := len(sf.Options); n > 0 {
+		sf.Selected = (sf.Selected + dir + n) % n
+	}
+}
+
+func (sf *SelectField) handleKey(f *Form, ev *tcell.EventKey) bool {
+	switch {
+	case ev.Key() == tcell.KeyLeft:
+		sf.move(-1)
+	case ev.Key() == tcell.KeyRight, ev.Key() == tcell.KeyRune && ev.Rune() == ' ':
+		sf.move(1)
+	default:
+		return false
+	}
+	return true
+}
+
+func (sf *SelectField) click(f *Form, x int) {
+	if x == 0 {
+		sf.move(-1)
+	} else {
+		sf.move(1)
+	}
+}
+
+// Button is a push button.  Pressing it, with Enter, space, or a click,
+// fires EventFormButton.  Consecutive buttons share a row.
+type Button struct {
+	Label string
+}
+
+// FieldLabel returns the label of the button.
+func (b *Button) FieldLabel() string { return b.Label }
+
+// Error always returns nil, as buttons have no value.
+func (b *Button) Error() error { return nil }
+
+func (b *Button) validate() {}
+
+func (b *Button) width() int {
+	return runewidth.StringWidth(b.Label) + 4
+}
+
+func (b *Button) draw(f *Form, x, y int, focused bool) {
+	style := f.style
+	if focused {
+		style = f.focus
+	}
+	for _, r := range "[ " + b.Label + " ]" {
+		f.view.SetContent(x, y, r, nil, style)
+		x += runewidth.RuneWidth(r)
+	}
+}
+
+func (b *Button) handleKey(f *Form, ev *tcell.EventKey) bool {
+	if ev.Key() == tcell.KeyEnter || (ev.Key() == tcell.KeyRune && ev.Rune() == ' ') {
+		f.press(b)
+		return true
+	}
+	return false
+}
+
+func (b *Button) click(f *Form, x int) {
+	f.press(b)
+}
+
+// EventFormSubmit is fired when Enter is pressed in a field other than a
+// button, and all of the fields are valid.
+type EventFormSubmit struct {
+	widgetEvent
+}
+
+// EventFormCancel is fired when Escape is pressed.
+type EventFormCancel struct {
+	widgetEvent
+}
+
+// EventFormButton is fired when a button is pressed.
+type EventFormButton struct {
+	button *Button
+	widgetEvent
+}
+
+// Button returns the button that was pressed.
+func (ev *EventFormButton) Button() *Button {
+	return ev.button
+}
+
+// Form is a Widget that lays out labeled fields, one per row, with the
+// labels in a column to the left.  Tab and Down move to the next field,
+// and Backtab and Up to the previous one.  Each field's validator runs as
+// the field is left, and any error is shown beneath it, in the error
+// style.  Enter submits the form, once every field is valid, and Escape
+// cancels it.
+type Form struct {
+	view    View
+	fields  []FormField
+	spots   []formSpot
+	current int
+	style   tcell.Style
+	focus   tcell.Style
+	label   tcell.Style
+	errs    tcell.Style
+	buttons tcell.ButtonMask
+
+	WidgetWatchers
+}
+
+// formSpot records where a field was drawn, for mouse handling.
+type formSpot struct {
+	x, y, w int
+}
+
+// AddField adds a field to the end of the form.
+func (f *Form) AddField(field FormField) {
+	f.fields = append(f.fields, field)
+	f.PostEventWidgetContent(f)
+}
+
+// AddInput adds a text entry field.
+func (f *Form) AddInput(label, value string, width int) *InputField {
+	in := &InputField{Label: label, Value: value, Width: width}
+	in.cursor = len([]rune(value))
+	f.AddField(in)
+	return in
+}
+
+// AddCheckbox adds a checkbox.
+func (f *Form) AddCheckbox(label string, checked bool) *Checkbox {
+	cb := &Checkbox{Label: label, Checked: checked}
+	f.AddField(cb)
+	return cb
+}
+
+// AddSelect adds a field that chooses one of the options.
+func (f *Form) AddSelect(label string, options []string, selected int) *SelectField {
+	sf := &SelectField{Label: label, Options: options, Selected: selected}
+	f.AddField(sf)
+	return sf
+}
+
+// AddButton adds a button.
+func (f *Form) AddButton(label string) *Button {
+	b := &Button{Label: label}
+	f.AddField(b)
+	return b
+}
+
+// Fields returns the fields of the form.
+func (f *Form) Fields() []FormField {
+	return f.fields
+}
+
+// Focused returns the field with the focus, or nil if there are none.
+func (f *Form) Focused() FormField {
+	if f.current < len(f.fields) {
+		return f.fields[f.current]
+	}
+	return nil
+}
+
+// SetFocus moves the focus to the given field.
+func (f *Form) SetFocus(field FormField) {
+	for i, o := range f.fields {
+		if o == field {
+			f.current = i
+			f.PostEventWidgetContent(f)
+		}
+	}
+}
+
+// SetStyles sets the styles used for fields, the focused field, labels,
+// and error messages.
+func (f *Form) SetStyles(normal, focused, label, errors tcell.Style) {
+	f.style = normal
+	f.focus = focused
+	f.label = label
+	f.errs = errors
+	f.PostEventWidgetContent(f)
+}
+
+// Validate runs the validators of every field, and returns true if they
+// all pass.  Otherwise the focus moves to the first invalid field.
+func (f *Form) Validate() bool {
+	first := -1
+	for i, field := range f.fields {
+		field.validate()
+		if field.Error() != nil && first < 0 {
+			first = i
+		}
+	}
+	if first >= 0 {
+		f.current = first
+	}
+	f.PostEventWidgetContent(f)
+	return first < 0
+}
+
+func (f *Form) press(b *Button) {
+	ev := &EventFormButton{button: b}
+	ev.SetWidget(f)
+	ev.SetEventNow()
+	f.PostEvent(ev)
+}
+
+func (f *Form) moveFocus(dir int) {
+	if len(f.fields) == 0 {
+		return
+	}
+	f.fields[f.current].validate()
+	f.current = (f.current + dir + len(f.fields)) % len(f.fields)
+	f.PostEventWidgetContent(f)
+}
+
+func (f *Form) labelWidth() int {
+	w := 0
+	for _, field := range f.fields {
+		if _, ok := field.(*Button); ok {
+			continue
+		}
+		if lw := runewidth.StringWidth(field.FieldLabel()); lw > w {
+			w = lw
+		}
+	}
+	return w
+}
+
+// layout works out where each field goes.
+func (f *Form) layout() {
+	x := f.labelWidth() + 1
+	f.spots = f.spots[:0]
+	y, row := 0, false
+	for _, field := range f.fields {
+		_, button := field.(*Button)
+		if button && row {
+			// Share the row of the previous button.
+			f.spots = append(f.spots, formSpot{x: x, y: y - 1, w: field.width()})
+			x += field.width() + 1
+			continue
+		}
+		x = f.labelWidth() + 1
+		f.spots = append(f.spots, formSpot{x: x, y: y, w: field.width()})
+		x += field.width() + 1
+		row = button
+		y++
+		if field.Error() != nil {
+			y++
+		}
+	}
+}
+
+func (f *Form) drawText(x, y int, s string, style tcell.Style) {
+	for _, r := range s {
+		f.view.SetContent(x, y, r, nil, style)
+		x += runewidth.RuneWidth(r)
+	}
+}
+
+// Draw draws the Form.
+func (f *Form) Draw() {
+	if f.view == nil {
+		return
+	}
+	f.view.Fill(' ', f.style)
+	f.layout()
+	for i, field := range f.fields {
+		spot := f.spots[i]
+		if _, ok := field.(*Button); !ok {
+			f.drawText(0, spot.y, field.FieldLabel(), f.label)
+		}
+		field.draw(f, spot.x, spot.y, i == f.current)
+		if err := field.Error(); err != nil {
+			f.drawText(spot.x, spot.y+1, err.Error(), f.errs)
+		}
+	}
+}
+
+// Resize is called when our View changes sizes.
+func (f *Form) Resize() {
+	f.PostEventWidgetResize(f)
+}
+
+// Size returns the space needed for all of the fields.
+func (f *Form) Size() (int, int) {
+	f.layout()
+	w, h := 0, 0
+	for i, spot := range f.spots {
+		if spot.x+spot.w > w {
+			w = spot.x + spot.w
+		}
+		if spot.y+1 > h {
+			h = spot.y + 1
+		}
+		if err := f.fields[i].Error(); err != nil {
+			h = spot.y + 2
+		}
+	}
+	return w, h
+}
+
+// SetView sets the View object used for the Form.
+func (f *Form) SetView(view View) {
+	f.view = view
+}
+
+func (f *Form) handleKey(ev *tcell.EventKey) bool {
+	switch ev.Key() {
+	case tcell.KeyTab, tcell.KeyDown:
+		f.moveFocus(1)
+		return true
+	case tcell.KeyBacktab, tcell.KeyUp:
+		f.moveFocus(-1)
+		return true
+	case tcell.KeyEscape:
+		ev := &EventFormCancel{}
+		ev.SetWidget(f)
+		ev.SetEventNow()
+		f.PostEvent(ev)
+		return true
+	}
+	field := f.Focused()
+	if field == nil {
+		return false
+	}
+	if field.handleKey(f, ev) {
+		f.PostEventWidgetContent(f)
+		return true
+	}
+	if ev.Key() == tcell.KeyEnter {
+		if f.Validate() {
+			ev := &EventFormSubmit{}
+			ev.SetWidget(f)
+			ev.SetEventNow()
+			f.PostEvent(ev)
+		}
+		return true
+	}
+	return false
+}
+
+func (f *Form) handleMouse(ev *tcell.EventMouse) bool {
+	buttons := ev.Buttons()
+	pressed := buttons&tcell.Button1 != 0 && f.buttons&tcell.Button1 == 0
+	f.buttons = buttons
+	if !pressed {
+		return false
+	}
+	dx, dy := viewOffset(f.view)
+	x, y := ev.Position()
+	x -= dx
+	y -= dy
+	for i, spot := range f.spots {
+		if y == spot.y && x >= spot.x && x < spot.x+spot.w {
+			if i != f.current {
+				f.fields[f.current].validate()
+				f.current = i
+			}
+			f.fields[i].click(f, x-spot.x)
+			f.PostEventWidgetContent(f)
+			return true
+		}
+	}
+	return false
+}
+
+// HandleEvent handles keys and mouse clicks for the fields.
+func (f *Form) HandleEvent(ev tcell.Event) bool {
+	if f.view == nil {
+		return false
+	}
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+		return f.handleKey(ev)
+	case *tcell.EventMouse:
+		return f.handleMouse(ev)
+	}
+	return false
+}
+
+// NewForm creates an empty Form.
+func NewForm() *Form {
+	return &Form{
+		style: tcell.StyleDefault,
+		focus: tcell.StyleDefault.Reverse(true),
+		label: tcell.StyleDefault.Bold(true),
+		errs:  tcell.StyleDefault.Foreground(tcell.ColorRed),
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+type formWatcher struct {
+	submits int
+	cancels int
+	pressed []*Button
+}
+
+func (w *formWatcher) HandleEvent(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *EventFormSubmit:
+		w.submits++
+	case *EventFormCancel:
+		w.cancels++
+	case *EventFormButton:
+		w.pressed = append(w.pressed, ev.Button())
+	}
+	return false
+}
+
+func TestForm(t *testing.T) {
+	v := newTestView(24, 6)
+	f := NewForm()
+	f.SetView(v)
+	watcher := &formWatcher{}
+	f.Watch(watcher)
+
+	name := f.AddInput("Name", "", 8)
+	name.Validator = func(s string) error {
+		if s == "" {
+			return errors.New("required")
+		}
+		return nil
+	}
+	admin := f.AddCheckbox("Admin", false)
+	shell := f.AddSelect("Shell", []string{"sh", "bash", "zsh"}, 0)
+	ok := f.AddButton("OK")
+	f.AddButton("Cancel")
+
+	key := func(k tcell.Key, r rune) {
+		f.HandleEvent(tcell.NewEventKey(k, r, tcell.ModNone))
+	}
+
+	// Leaving an empty name shows the error beneath it.
+	key(tcell.KeyTab, 0)
+	f.Draw()
+	want := []string{
+		"Name                    ",
+		"      required          ",
+		"Admin [ ]               ",
+		"Shell < sh   >          ",
+		"      [ OK ] [ Cancel ] ",
+	}
+	for y, row := range want {
+		if got := v.row(y); got != row {
+			t.Errorf("row %d: got %q, want %q", y, got, row)
+		}
+	}
+
+	key(tcell.KeyRune, ' ')
+	key(tcell.KeyDown, 0)
+	key(tcell.KeyRight, 0)
+	key(tcell.KeyEnter, 0)
+	if !admin.Checked || shell.Selected != 1 {
+		t.Errorf("fields not changed: %v %d", admin.Checked, shell.Selected)
+	}
+	if watcher.submits != 0 || f.Focused() != name {
+		t.Errorf("invalid form submitted")
+	}
+
+	for _, r := range "joe" {
+		key(tcell.KeyRune, r)
+	}
+	key(tcell.KeyEnter, 0)
+	if watcher.submits != 1 || name.Value != "joe" || name.Error() != nil {
+		t.Errorf("form not submitted: %q %v", name.Value, name.Error())
+	}
+
+	// Click the OK button, then cancel with Escape.
+	f.Draw()
+	f.HandleEvent(tcell.NewEventMouse(8, 3, tcell.Button1, 0))
+	f.HandleEvent(tcell.NewEventMouse(8, 3, tcell.ButtonNone, 0))
+	key(tcell.KeyEscape, 0)
+	if len(watcher.pressed) != 1 || watcher.pressed[0] != ok || watcher.cancels != 1 {
+		t.Errorf("wrong button events: %v %d", watcher.pressed, watcher.cancels)
+	}
+}
@@ -0,0 +1,334 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// helpMaxWidth is the widest that the Help box is made, for readability.
+const helpMaxWidth = 80
+
+// Help is a container Widget that can show a help (or about) screen over
+// its content Widget, in a box in the middle of its View.  The text is
+// written in the subset of Markdown drawn by tcell.DrawMarkdown, and is
+// wrapped to fit the box.  While it is open it takes all key and mouse
+// events: the arrow keys, PgUp, PgDn, Space, Home and End and the mouse
+// wheel scroll it, and Escape, Enter, q or a click outside it close it.
+type Help struct {
+	view    View
+	content Widget
+	under   dimView // the view of the content
+	dim     tcell.RenderFilter
+	text    string
+	title   string
+	style   tcell.Style
+	styles  *tcell.MarkdownStyles
+	open    bool
+	top     int
+	model   *pagerModel // the text as drawn, or nil if it must be redrawn
+	width   int         // the width it was drawn at
+	buttons tcell.ButtonMask
+
+	WidgetWatchers
+}
+
+// SetContent sets the Widget shown beneath the help.
+func (h *Help) SetContent(w Widget) {
+	if h.content != nil {
+		h.content.Unwatch(h)
+	}
+	h.content = w
+	if w != nil {
+		w.SetView(h.under.of(h.view))
+		w.Watch(h)
+	}
+	h.PostEventWidgetContent(h)
+}
+
+// SetText sets the text shown, and scrolls back to its top.
+func (h *Help) SetText(text string) {
+	h.text = text
+	h.model = nil
+	h.top = 0
+	h.PostEventWidgetContent(h)
+}
+
+// SetTitle sets the title shown in the top of the box.
+func (h *Help) SetTitle(title string) {
+	h.title = title
+	h.PostEventWidgetContent(h)
+}
+
+// SetStyle sets the style of the box, and the styles used for the text.
+// If styles is nil, tcell.DefaultMarkdownStyles are used.
+func (h *Help) SetStyle(style tcell.Style, styles *tcell.MarkdownStyles) {
+	h.style = style
+	h.styles = styles
+	h.model = nil
+	h.PostEventWidgetContent(h)
+}
+
+// SetDim sets the style transform applied to the content while the help
+// is open, such as tcell.DimFilter or tcell.FadeFilter, so that the help
+// stands out from it.  If it is nil, as it is by default, the content is
+// drawn as it is.
+func (h *Help) SetDim(f tcell.RenderFilter) {
+	h.dim = f
+	h.PostEventWidgetContent(h)
+}
+
+// Open shows the help, scrolled to its top.
+func (h *Help) Open() {
+	h.open = true
+	h.top = 0
+	h.PostEventWidgetContent(h)
+}
+
+// Close hides the help.
+func (h *Help) Close() {
+	if h.open {
+		h.open = false
+		h.PostEventWidgetContent(h)
+	}
+}
+
+// IsOpen returns true if the help is shown.
+func (h *Help) IsOpen() bool {
+	return h.open
+}
+
+// layout returns the position and size of the box, drawing the text for
+// its width if need be.
+func (h *Help) layout() (x, y, w, ht int) {
+	vw, vh := h.view.Size()
+	w = vw - 4
+	if w > helpMaxWidth {
+		w = helpMaxWidth
+	}
+	if w < 6 {
+		w = vw
+	}
+	if h.model == nil || h.width != w-4 {
+		h.width = w - 4
+		h.model = &pagerModel{style: h.style}
+		h.model.height = tcell.DrawMarkdown(h.model, 0, 0, h.width, h.text, h.styles)
+	}
+	ht = h.model.height + 2
+	if ht > vh-2 {
+		ht = vh - 2
+	}
+	if ht < 3 {
+		ht = vh
+	}
+	return (vw - w) / 2, (vh - ht) / 2, w, ht
+}
+
+// scroll scrolls the text by n rows, keeping it within the box.
+func (h *Help) scroll(n int) {
+	_, _, _, ht := h.layout()
+	top := h.top + n
+	if top > h.model.height-(ht-2) {
+		top = h.model.height - (ht - 2)
+	}
+	if top < 0 {
+		top = 0
+	}
+	if top != h.top {
+		h.top = top
+		h.PostEventWidgetContent(h)
+	}
+}
+
+func (h *Help) handleKey(ev *tcell.EventKey) {
+	_, _, _, ht := h.layout()
+	page := ht - 3
+	if page < 1 {
+		page = 1
+	}
+	switch ev.Key() {
+	case tcell.KeyUp, tcell.KeyCtrlP:
+		h.scroll(-1)
+	case tcell.KeyDown, tcell.KeyCtrlN:
+		h.scroll(1)
+	case tcell.KeyPgUp:
+		h.scroll(-page)
+	case tcell.KeyPgDn:
+		h.scroll(page)
+	case tcell.KeyHome:
+		h.scroll(-h.model.height)
+	case tcell.KeyEnd:
+		h.scroll(h.model.height)
+	case tcell.KeyEscape, tcell.KeyEnter:
+		h.Close()
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case ' ':
+			h.scroll(page)
+		case 'q':
+			h.Close()
+		}
+	}
+}
+
+func (h *Help) handleMouse(ev *tcell.EventMouse) {
+	buttons := ev.Buttons()
+	pressed := buttons&tcell.Button1 != 0 && h.buttons&tcell.Button1 == 0
+	h.buttons = buttons
+
+	switch {
+	case buttons&tcell.WheelUp != 0:
+		h.scroll(-1)
+	case buttons&tcell.WheelDown != 0:
+		h.scroll(1)
+	case pressed:
+		dx, dy := viewOffset(h.view)
+		mx, my := ev.Position()
+		x, y, w, ht := h.layout()
+		if mx-dx < x || mx-dx >= x+w || my-dy < y || my-dy >= y+ht {
+			h.Close()
+		}
+	}
+}
+
+// drawBox draws the box, with its title and the text scrolled into it.
+func (h *Help) drawBox() {
+	v := h.view
+	x, y, w, ht := h.layout()
+	for row := 0; row < ht; row++ {
+		for col := 0; col < w; col++ {
+			v.SetContent(x+col, y+row, ' ', nil, h.style)
+		}
+	}
+	for col := 1; col < w-1; col++ {
+		v.SetContent(x+col, y, tcell.RuneHLine, nil, h.style)
+		v.SetContent(x+col, y+ht-1, tcell.RuneHLine, nil, h.style)
+	}
+	for row := 1; row < ht-1; row++ {
+		v.SetContent(x, y+row, tcell.RuneVLine, nil, h.style)
+		v.SetContent(x+w-1, y+row, tcell.RuneVLine, nil, h.style)
+	}
+	v.SetContent(x, y, tcell.RuneULCorner, nil, h.style)
+	v.SetContent(x+w-1, y, tcell.RuneURCorner, nil, h.style)
+	v.SetContent(x, y+ht-1, tcell.RuneLLCorner, nil, h.style)
+	v.SetContent(x+w-1, y+ht-1, tcell.RuneLRCorner, nil, h.style)
+
+	if h.title != "" {
+		col := x + 2
+		for _, r := range " " + h.title + " " {
+			rw := runewidth.RuneWidth(r)
+			if col+rw > x+w-2 {
+				break
+			}
+			v.SetContent(col, y, r, nil, h.style)
+			col += rw
+		}
+	}
+	if h.top > 0 {
+		v.SetContent(x+w-3, y, tcell.RuneUArrow, nil, h.style)
+	}
+	if h.top+ht-2 < h.model.height {
+		v.SetContent(x+w-3, y+ht-1, tcell.RuneDArrow, nil, h.style)
+	}
+
+	for row := 0; row < ht-2 && h.top+row < len(h.model.lines); row++ {
+		line := h.model.lines[h.top+row]
+		for col := 0; col < len(line) && col < h.width; {
+			c := line[col]
+			v.SetContent(x+2+col, y+1+row, c.mainc, c.combc, c.style)
+			if rw := runewidth.RuneWidth(c.mainc); rw > 1 {
+				col += rw
+			} else {
+				col++
+			}
+		}
+	}
+}
+
+// Draw draws the content, and then the help over it, if it is open.
+func (h *Help) Draw() {
+	if h.view == nil {
+		return
+	}
+	if h.content != nil {
+		// The help may have covered any part of the content.
+		h.under.filter = nil
+		if h.open {
+			h.under.filter = h.dim
+		}
+		Invalidate(h.content)
+		h.content.Draw()
+	}
+	if h.open {
+		h.drawBox()
+	}
+}
+
+// Resize is called when our View changes sizes.
+func (h *Help) Resize() {
+	if h.content != nil {
+		h.content.Resize()
+	}
+	h.PostEventWidgetResize(h)
+}
+
+// Size returns the size of the content.
+func (h *Help) Size() (int, int) {
+	if h.content != nil {
+		return h.content.Size()
+	}
+	return 0, 0
+}
+
+// SetView sets the View object used for the Help and its content.
+func (h *Help) SetView(view View) {
+	h.view = view
+	if h.content != nil {
+		h.content.SetView(h.under.of(view))
+	}
+}
+
+// HandleEvent handles key and mouse events while the help is open, and
+// otherwise passes events to the content.
+func (h *Help) HandleEvent(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *EventWidgetContent:
+		h.PostEventWidgetContent(h)
+		return true
+	case *EventWidgetResize, *EventWidgetMove:
+		return true
+	case *tcell.EventKey:
+		if h.open && h.view != nil {
+			h.handleKey(ev)
+			return true
+		}
+	case *tcell.EventMouse:
+		if h.open && h.view != nil {
+			h.handleMouse(ev)
+			return true
+		}
+		h.buttons = ev.Buttons()
+	}
+	if h.content != nil {
+		return h.content.HandleEvent(ev)
+	}
+	return false
+}
+
+// NewHelp creates a Help, with no content or text.
+func NewHelp() *Help {
+	return &Help{style: tcell.StyleDefault}
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestHelp(t *testing.T) {
+	v := newTestView(20, 7)
+	h := NewHelp()
+	h.SetView(v)
+	h.SetTitle("Help")
+	h.SetText("# Keys\n- **q** quits\n- `?` shows this\n- arrows move")
+	h.Open()
+	h.Draw()
+	var want []string
+	check := func() {
+		for y, row := range want {
+			for len([]rune(row)) < 20 {
+				row += " "
+			}
+			if got := v.row(y); got != row {
+				t.Errorf("row %d: got %q, want %q", y, got, row)
+			}
+		}
+	}
+	want = []string{
+		"",
+		"  ┌─ Help ───────┐",
+		"  │ Keys         │",
+		"  │ • q quits    │",
+		"  │ • ? shows    │",
+		"  └────────────↓─┘",
+		"",
+	}
+	check()
+
+	h.HandleEvent(tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone))
+	v.Clear()
+	h.Draw()
+	want = []string{
+		"",
+		"  ┌─ Help ─────↑─┐",
+		"  │   this       │",
+		"  │ • arrows     │",
+		"  │   move       │",
+		"  └──────────────┘",
+		"",
+	}
+	check()
+
+	h.HandleEvent(tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone))
+	if h.IsOpen() {
+		t.Errorf("help not closed")
+	}
+}
+
+type styleView struct {
+	*testView
+	styles map[[2]int]tcell.Style
+}
+
+func (v *styleView) SetContent(x, y int, ch rune, comb []rune, style tcell.Style) {
+	v.testView.SetContent(x, y, ch, comb, style)
+	v.styles[[2]int{x, y}] = style
+}
+
+func TestHelpDim(t *testing.T) {
+	v := &styleView{testView: newTestView(20, 7), styles: map[[2]int]tcell.Style{}}
+	text := NewText()
+	text.SetText("content")
+	text.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
+	h := NewHelp()
+	h.SetView(v)
+	h.SetContent(text)
+	h.SetText("help")
+	h.SetDim(tcell.DimFilter)
+
+	h.Draw()
+	if s := v.styles[[2]int{0, 0}]; s != tcell.StyleDefault.Foreground(tcell.ColorRed) {
+		t.Errorf("content dimmed while help closed: %v", s)
+	}
+	h.Open()
+	h.Draw()
+	if s := v.styles[[2]int{0, 0}]; s != tcell.StyleDefault.Foreground(tcell.ColorRed).Dim(true) {
+		t.Errorf("content not dimmed: %v", s)
+	}
+	h.Close()
+	h.Draw()
+	if s := v.styles[[2]int{0, 0}]; s != tcell.StyleDefault.Foreground(tcell.ColorRed) {
+		t.Errorf("content still dimmed: %v", s)
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// Notifier is a container Widget that shows transient messages, often
+// called toasts, stacked in a corner over its content Widget.  The content
+// is drawn first, and the messages over it, so nothing is lost beneath
+// them; once a message expires the content shows through again.
+//
+// Messages expire according to the Notifier's tcell.Clock.  Expired
+// messages are dropped when the Notifier is next drawn.  To have that
+// happen promptly, give the Notifier a way to run a function in the
+// event loop with SetPoster, such as Application.PostFunc.
+type Notifier struct {
+	view    View
+	content Widget
+	toasts  []*Toast
+	align   Alignment
+	width   int
+	clock   tcell.Clock
+	post    func(func())
+
+	WidgetWatchers
+}
+
+// Toast is a message shown by a Notifier.
+type Toast struct {
+	n     *Notifier
+	lines []string
+	style tcell.Style
+	until time.Time // zero if shown until dismissed
+	stop  func() bool
+}
+
+// Dismiss removes the message, whether or not it has expired.
+func (t *Toast) Dismiss() {
+	n := t.n
+	for i, o := range n.toasts {
+		if o == t {
+			if t.stop != nil {
+				t.stop()
+			}
+			n.toasts = append(n.toasts[:i], n.toasts[i+1:]...)
+			n.PostEventWidgetContent(n)
+			return
+		}
+	}
+}
+
+// SetContent sets the Widget shown beneath the messages.
+func (n *Notifier) SetContent(w Widget) {
+	if n.content != nil {
+		n.content.Unwatch(n)
+	}
+	n.content = w
+	if w != nil {
+		w.SetView(n.view)
+		w.Watch(n)
+	}
+	n.PostEventWidgetContent(n)
+}
+
+// SetAlignment sets the corner used for messages, as a combination of
+// HAlignLeft or HAlignRight, and VAlignTop or VAlignBottom.  The newest
+// message is nearest the corner.  The default is the bottom right.
+func (n *Notifier) SetAlignment(align Alignment) {
+	n.align = align
+	n.PostEventWidgetContent(n)
+}
+
+// SetWidth sets the widest a message may be, including its padding.
+// Longer lines are truncated.
+func (n *Notifier) SetWidth(width int) {
+	n.width = width
+	n.PostEventWidgetContent(n)
+}
+
+// SetClock sets the clock used to expire messages.  The default is
+// tcell.SystemClock.
+func (n *Notifier) SetClock(clock tcell.Clock) {
+	n.clock = clock
+}
+
+// SetPoster sets a function that runs a function in the event loop.
+// It is used to remove messages as soon as they expire.
+func (n *Notifier) SetPoster(post func(func())) {
+	n.post = post
+}
+
+// Notify shows a message, which may have several lines, in the given
+// style.  The message is removed after the duration, or if that is not
+// positive, when it is dismissed.
+func (n *Notifier) Notify(text string, style tcell.Style, d time.Duration) *Toast {
+	t := &Toast{n: n, lines: strings.Split(text, "\n"), style: style}
+	if d > 0 {
+		t.until = n.clock.Now().Add(d)
+		t.stop = n.clock.AfterFunc(d, func() {
+			if post := n.post; post != nil {
+				post(n.expire)
+			}
+		})
+	}
+	n.toasts = append(n.toasts, t)
+	n.PostEventWidgetContent(n)
+	return t
+}
+
+// Toasts returns the number of messages being shown.
+func (n *Notifier) Toasts() int {
+	return len(n.toasts)
+}
+
+func (n *Notifier) expire() {
+	now := n.clock.Now()
+	keep := n.toasts[:0]
+	for _, t := range n.toasts {
+		if t.until.IsZero() || now.Before(t.until) {
+			keep = append(keep, t)
+		}
+	}
+	if len(keep) != len(n.toasts) {
+		n.toasts = keep
+		n.PostEventWidgetContent(n)
+	}
+}
+
+// clipText truncates a line to fit the width, marking it with an ellipsis.
+func clipText(s string, width int) string {
+	if runewidth.StringWidth(s) <= width {
+		return s
+	}
+	return runewidth.Truncate(s, width, "…")
+}
+
+func (n *Notifier) drawToast(t *Toast, y, vw int) {
+	w := 0
+	for _, line := range t.lines {
+		if lw := runewidth.StringWidth(clipText(line, n.width-2)); lw > w {
+			w = lw
+		}
+	}
+	w += 2
+	x := 0
+	if n.align&HAlignLeft == 0 {
+		x = vw - w
+	}
+	for i, line := range t.lines {
+		for col := 0; col < w; col++ {
+			n.view.SetContent(x+col, y+i, ' ', nil, t.style)
+		}
+		col := x + 1
+		for _, r := range clipText(line, n.width-2) {
+			n.view.SetContent(col, y+i, r, nil, t.style)
+			col += runewidth.RuneWidth(r)
+		}
+	}
+}
+
+// Draw draws the content, and then the messages over it.
+func (n *Notifier) Draw() {
+	if n.view == nil {
+		return
+	}
+	n.expire()
+	if n.content != nil {
+		// The messages may have covered any part of the content.
+		Invalidate(n.content)
+		n.content.Draw()
+	}
+	vw, vh := n.view.Size()
+	top := n.align&VAlignTop != 0
+	y := 0
+	if !top {
+		y = vh
+	}
+	// Newest first, until there is no more room.
+	for i := len(n.toasts) - 1; i >= 0; i-- {
+		t := n.toasts[i]
+		h := len(t.lines)
+		if top {
+			if y+h > vh {
+				break
+			}
+			n.drawToast(t, y, vw)
+			y += h + 1
+		} else {
+			if y-h < 0 {
+				break
+			}
+			y -= h
+			n.drawToast(t, y, vw)
+			y--
+		}
+	}
+}
+
+// Resize is called when our View changes sizes.
+func (n *Notifier) Resize() {
+	if n.content != nil {
+		n.content.Resize()
+	}
+	n.PostEventWidgetResize(n)
+}
+
+// Size returns the size of the content.
+func (n *Notifier) Size() (int, int) {
+	if n.content != nil {
+		return n.content.Size()
+	}
+	return 0, 0
+}
+
+// SetView sets the View object used for the Notifier and its content.
+func (n *Notifier) SetView(view View) {
+	n.view = view
+	if n.content != nil {
+		n.content.SetView(view)
+	}
+}
+
+// HandleEvent passes events to the content, and reposts its content
+// changes as our own.
+func (n *Notifier) HandleEvent(ev tcell.Event) bool {
+	switch ev.(type) {
+	case *EventWidgetContent:
+		n.PostEventWidgetContent(n)
+		return true
+	case *EventWidgetResize, *EventWidgetMove:
+		return true
+	}
+	if n.content != nil {
+		return n.content.HandleEvent(ev)
+	}
+	return false
+}
+
+// NewNotifier creates a Notifier, with no content.
+func NewNotifier() *Notifier {
+	return &Notifier{
+		align: HAlignRight | VAlignBottom,
+		width: 40,
+		clock: tcell.SystemClock,
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestNotifier(t *testing.T) {
+	v := newTestView(12, 4)
+	clock := tcell.NewFakeClock(time.Unix(0, 0))
+	var posted []func()
+
+	n := NewNotifier()
+	n.SetClock(clock)
+	n.SetPoster(func(f func()) { posted = append(posted, f) })
+	n.SetView(v)
+	text := NewText()
+	text.SetText("aaaaaaaaaaaa\nbbbbbbbbbbbb\ncccccccccccc\ndddddddddddd")
+	n.SetContent(text)
+
+	n.Notify("saved", tcell.StyleDefault, time.Second)
+	sticky := n.Notify("error", tcell.StyleDefault, 0)
+	n.Draw()
+	want := []string{"aaaaaaaaaaaa", "bbbbb saved ", "cccccccccccc", "ddddd error "}
+	for y, row := range want {
+		if got := v.row(y); got != row {
+			t.Errorf("row %d: got %q, want %q", y, got, row)
+		}
+	}
+
+	clock.Advance(time.Second)
+	if len(posted) != 1 {
+		t.Fatalf("expiry not posted")
+	}
+	posted[0]()
+	if n.Toasts() != 1 {
+		t.Errorf("toast did not expire")
+	}
+	sticky.Dismiss()
+	n.Draw()
+	if v.row(1) != "bbbbbbbbbbbb" || v.row(3) != "dddddddddddd" {
+		t.Errorf("content not restored: %q %q", v.row(1), v.row(3))
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Observable holds a value, and tells its observers whenever the value
+// changes.  Widgets can be bound to an Observable, so that they update
+// themselves (and post EventWidgetContent) when the data they show changes,
+// rather than the application updating each of them by hand.
+//
+// Observers are called synchronously by Set, so Set should be called from
+// the event loop, for example using Application.PostFunc.
+type Observable struct {
+	value     interface{}
+	observers []observer
+	next      int
+	sync.Mutex
+}
+
+type observer struct {
+	id int
+	f  func(interface{})
+}
+
+// NewObservable creates an Observable holding the given value.
+func NewObservable(value interface{}) *Observable {
+	return &Observable{value: value}
+}
+
+// Get returns the current value.
+func (o *Observable) Get() interface{} {
+	o.Lock()
+	defer o.Unlock()
+	return o.value
+}
+
+// Set changes the value, and calls the observers.  Nothing happens if
+// the value is comparable, and equal to the current value.
+func (o *Observable) Set(value interface{}) {
+	o.Lock()
+	if value == nil && o.value == nil {
+		o.Unlock()
+		return
+	}
+	if t := reflect.TypeOf(value); t != nil && t.Comparable() &&
+		reflect.TypeOf(o.value) == t && o.value == value {
+		o.Unlock()
+		return
+	}
+	o.value = value
+	observers := append([]observer(nil), o.observers...)
+	o.Unlock()
+
+	for _, ob := range observers {
+		ob.f(value)
+	}
+}
+
+// Observe arranges for f to be called with the new value each time it
+// changes.  Observers are called in the order they were added.  The
+// returned function removes the observer.
+func (o *Observable) Observe(f func(value interface{})) (cancel func()) {
+	o.Lock()
+	defer o.Unlock()
+	id := o.next
+	o.next++
+	o.observers = append(o.observers, observer{id: id, f: f})
+	return func() {
+		o.Lock()
+		defer o.Unlock()
+		for i, ob := range o.observers {
+			if ob.id == id {
+				o.observers = append(o.observers[:i], o.observers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Bind is like Observe, but also calls f with the current value at once.
+func (o *Observable) Bind(f func(value interface{})) (cancel func()) {
+	cancel = o.Observe(f)
+	f(o.Get())
+	return cancel
+}
+
+// BindText binds a widget with a SetText method, such as Text, to an
+// Observable, showing the value as formatted by fmt.Sprint.
+func BindText(t interface{ SetText(string) }, o *Observable) (cancel func()) {
+	return o.Bind(func(value interface{}) {
+		t.SetText(fmt.Sprint(value))
+	})
+}
+
+// BindMarkup binds a SimpleStyledText to an Observable, whose value is
+// formatted by fmt.Sprint and used as markup.
+func BindMarkup(t *SimpleStyledText, o *Observable) (cancel func()) {
+	return o.Bind(func(value interface{}) {
+		t.SetMarkup(fmt.Sprint(value))
+	})
+}
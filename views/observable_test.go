@@ -0,0 +1,58 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+type contentWatcher struct {
+	count int
+}
+
+func (w *contentWatcher) HandleEvent(ev tcell.Event) bool {
+	if _, ok := ev.(*EventWidgetContent); ok {
+		w.count++
+	}
+	return false
+}
+
+func TestObservable(t *testing.T) {
+	o := NewObservable(1)
+	text := NewText()
+	watcher := &contentWatcher{}
+	text.Watch(watcher)
+
+	cancel := BindText(text, o)
+	if text.Text() != "1" || watcher.count != 1 {
+		t.Errorf("initial value not bound: %q", text.Text())
+	}
+	o.Set(2)
+	o.Set(2)
+	if text.Text() != "2" || watcher.count != 2 {
+		t.Errorf("change not seen once: %q %d", text.Text(), watcher.count)
+	}
+	o.Set([]int{3})
+	if text.Text() != "[3]" {
+		t.Errorf("slice not formatted: %q", text.Text())
+	}
+	cancel()
+	o.Set(4)
+	if text.Text() != "[3]" {
+		t.Errorf("cancelled binding still active")
+	}
+}
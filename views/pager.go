@@ -0,0 +1,279 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// Pager is a scrollable view of styled text, such as the colored output
+// of a command, in the manner of less(1).  Text containing ANSI escape
+// sequences is converted to styled cells, and may be searched.  Besides
+// the usual CellView keys, 'n' and 'N' move to the next and previous
+// match of the last search.
+type Pager struct {
+	model *pagerModel
+	once  sync.Once
+	CellView
+}
+
+type pagerCell struct {
+	mainc rune
+	combc []rune
+	style tcell.Style
+}
+
+type pagerModel struct {
+	lines  [][]pagerCell
+	width  int
+	height int
+	x      int
+	y      int
+	find   []rune
+	style  tcell.Style
+}
+
+// SetContent implements tcell.CellSetter, growing the model as needed.
+func (m *pagerModel) SetContent(x, y int, mainc rune, combc []rune, style tcell.Style) {
+	for len(m.lines) <= y {
+		m.lines = append(m.lines, nil)
+	}
+	for len(m.lines[y]) <= x {
+		m.lines[y] = append(m.lines[y], pagerCell{mainc: ' ', style: m.style})
+	}
+	m.lines[y][x] = pagerCell{mainc: mainc, combc: combc, style: style}
+}
+
+func (m *pagerModel) GetCell(x, y int) (rune, tcell.Style, []rune, int) {
+	if x < 0 || y < 0 || y >= len(m.lines) || x >= len(m.lines[y]) {
+		return 0, m.style, nil, 1
+	}
+	c := m.lines[y][x]
+	style := c.style
+	if m.matchAt(x, y) {
+		style = style.Reverse(true)
+	}
+	return c.mainc, style, c.combc, runewidth.RuneWidth(c.mainc)
+}
+
+// matchAt returns true if the cell is part of a match for the search.
+func (m *pagerModel) matchAt(x, y int) bool {
+	n := len(m.find)
+	if n == 0 {
+		return false
+	}
+	for start := x - n + 1; start <= x; start++ {
+		if m.matches(start, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns true if the search text starts at x, y.
+func (m *pagerModel) matches(x, y int) bool {
+	if x < 0 || x+len(m.find) > len(m.lines[y]) {
+		return false
+	}
+	for i, r := range m.find {
+		if m.lines[y][x+i].mainc != r {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *pagerModel) GetBounds() (int, int) {
+	return m.width, m.height
+}
+
+func (m *pagerModel) SetCursor(x, y int) {
+	m.x = x
+	m.y = y
+}
+
+func (m *pagerModel) MoveCursor(x, y int) {
+	m.x += x
+	m.y += y
+}
+
+func (m *pagerModel) GetCursor() (int, int, bool, bool) {
+	return m.x, m.y, false, false
+}
+
+// SetANSI sets the text to display.  Colors and attributes given by ANSI
+// escape sequences are preserved.
+func (p *Pager) SetANSI(text string) {
+	p.Init()
+	m := p.model
+	m.lines = nil
+	m.x, m.y = 0, 0
+
+	text = strings.TrimRight(text, "\n")
+	lines := strings.Split(text, "\n")
+	width := 0
+	for _, line := range lines {
+		if n := pagerWidth(line); n > width {
+			width = n
+		}
+	}
+	tcell.ImportANSI(m, 0, 0, width, len(lines), text)
+
+	// Lines with nothing drawn on them (even at the end) are kept.
+	for len(m.lines) < len(lines) {
+		m.lines = append(m.lines, nil)
+	}
+	m.height = len(m.lines)
+	m.width = 0
+	for _, line := range m.lines {
+		n := len(line)
+		if n > 0 {
+			n += runewidth.RuneWidth(line[n-1].mainc) - 1
+		}
+		if n > m.width {
+			m.width = n
+		}
+	}
+	p.CellView.SetModel(m)
+}
+
+// pagerTabWidth is the distance between tab stops, as ImportANSI uses.
+const pagerTabWidth = 8
+
+// pagerWidth returns a width that a line cannot be wider than when it is
+// displayed, so that nothing is wrapped.  It is measured as though the
+// escape sequences in it were displayed too.
+func pagerWidth(line string) int {
+	n := 0
+	for _, r := range line {
+		switch w := runewidth.RuneWidth(r); {
+		case r == '\t':
+			n = (n/pagerTabWidth + 1) * pagerTabWidth
+		case w > 1:
+			n += w
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// SetCommand runs the command, and displays its output (both standard
+// output and standard error).  This waits for the command to finish.
+// Many programs only use color when writing to a terminal, and so may
+// need to be told to use it anyway, for example with git's --color flag.
+// The output is displayed even if the command fails, in which case the
+// error is also returned.
+func (p *Pager) SetCommand(cmd *exec.Cmd) error {
+	out, err := cmd.CombinedOutput()
+	p.SetANSI(string(out))
+	return err
+}
+
+// Find searches for text, starting on the line after the one at the top
+// of the view (or at the top of the text, if the search has changed),
+// and scrolls to show the first match.  Every match is highlighted.
+// It returns false if there is no match.
+func (p *Pager) Find(text string) bool {
+	p.Init()
+	m := p.model
+	find := []rune(text)
+	from := m.y + 1
+	if string(find) != string(m.find) {
+		from = 0
+	}
+	m.find = find
+	return p.find(from, 1)
+}
+
+// FindNext moves to the next match of the last search, if there is one.
+func (p *Pager) FindNext() bool {
+	p.Init()
+	return p.find(p.model.y+1, 1)
+}
+
+// FindPrev moves to the previous match of the last search, if there is one.
+func (p *Pager) FindPrev() bool {
+	p.Init()
+	return p.find(p.model.y-1, -1)
+}
+
+func (p *Pager) find(from, dir int) bool {
+	m := p.model
+	if len(m.find) == 0 {
+		return false
+	}
+	for y := from; y >= 0 && y < len(m.lines); y += dir {
+		for x := range m.lines[y] {
+			if m.matches(x, y) {
+				m.x, m.y = x, y
+				p.showMatch(x, y)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// showMatch scrolls so that the match is on the top line.
+func (p *Pager) showMatch(x, y int) {
+	_, vh := p.port.Size()
+	p.port.MakeVisible(x, y+vh-1)
+	p.port.MakeVisible(x, y)
+}
+
+// HandleEvent handles the search keys, and then the CellView keys.
+func (p *Pager) HandleEvent(e tcell.Event) bool {
+	if ev, ok := e.(*tcell.EventKey); ok && ev.Key() == tcell.KeyRune {
+		switch ev.Rune() {
+		case 'n':
+			p.FindNext()
+			return true
+		case 'N':
+			p.FindPrev()
+			return true
+		}
+	}
+	return p.CellView.HandleEvent(e)
+}
+
+// SetStyle sets the style used for the background, where there is no text.
+func (p *Pager) SetStyle(style tcell.Style) {
+	p.Init()
+	p.model.style = style
+	p.CellView.SetStyle(style)
+}
+
+// Init initializes the Pager.
+func (p *Pager) Init() {
+	p.once.Do(func() {
+		m := &pagerModel{style: tcell.StyleDefault}
+		p.model = m
+		p.CellView.Init()
+		p.CellView.SetModel(m)
+	})
+}
+
+// NewPager creates an empty Pager.
+func NewPager() *Pager {
+	p := &Pager{}
+	p.Init()
+	return p
+}
@@ -0,0 +1,66 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestPager(t *testing.T) {
+	p := NewPager()
+	p.SetANSI("one\n\x1b[31mtwo\x1b[0m two\n\nthree two\n")
+
+	if w, h := p.model.GetBounds(); w != 9 || h != 4 {
+		t.Errorf("wrong bounds: %d x %d", w, h)
+	}
+	if _, st, _, _ := p.model.GetCell(0, 1); st != tcell.StyleDefault.Foreground(tcell.ColorMaroon) {
+		t.Errorf("color lost")
+	}
+
+	if !p.Find("two") || p.model.y != 1 || p.model.x != 0 {
+		t.Errorf("first match wrong: %d,%d", p.model.x, p.model.y)
+	}
+	if _, st, _, _ := p.model.GetCell(5, 1); st != tcell.StyleDefault.Reverse(true) {
+		t.Errorf("match not highlighted")
+	}
+	if !p.FindNext() || p.model.y != 3 || p.model.x != 6 {
+		t.Errorf("next match wrong: %d,%d", p.model.x, p.model.y)
+	}
+	if p.FindNext() {
+		t.Errorf("found match past the end")
+	}
+	if !p.FindPrev() || p.model.y != 1 {
+		t.Errorf("previous match wrong: %d,%d", p.model.x, p.model.y)
+	}
+}
+
+func TestPagerWide(t *testing.T) {
+	p := NewPager()
+	p.SetANSI("世界x\n\tb\nend\n\x1b[0m")
+
+	if w, h := p.model.GetBounds(); w != 9 || h != 4 {
+		t.Errorf("wrong bounds: %d x %d", w, h)
+	}
+	for _, c := range []struct {
+		x, y int
+		r    rune
+	}{{0, 0, '世'}, {2, 0, '界'}, {4, 0, 'x'}, {8, 1, 'b'}, {0, 2, 'e'}} {
+		if r, _, _, _ := p.model.GetCell(c.x, c.y); r != c.r {
+			t.Errorf("cell %d,%d: got %q, want %q", c.x, c.y, r, c.r)
+		}
+	}
+}
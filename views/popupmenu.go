@@ -0,0 +1,402 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// MenuItem is an entry in a popup menu.  An item with a Submenu opens it,
+// rather than being chosen.  A Separator item draws a line, and is never
+// selected.
+type MenuItem struct {
+	Label     string
+	Disabled  bool
+	Separator bool
+	Submenu   []*MenuItem
+	Data      interface{} // for use by the application
+}
+
+func (item *MenuItem) selectable() bool {
+	return !item.Separator && !item.Disabled
+}
+
+// EventMenuSelect is fired when an item of a popup menu is chosen.
+type EventMenuSelect struct {
+	item *MenuItem
+	widgetEvent
+}
+
+// Item returns the chosen item.
+func (ev *EventMenuSelect) Item() *MenuItem {
+	return ev.item
+}
+
+// PopupMenu is a container Widget that can show a menu, such as a context
+// menu, over its content Widget.  While the menu is open it takes all key
+// and mouse events: Up and Down move between items, Right or Enter opens a
+// submenu, Left or Escape closes one, and Enter or a click chooses an item,
+// which fires EventMenuSelect and closes the menu.  Clicking outside the
+// menu closes it.
+type PopupMenu struct {
+	view     View
+	content  Widget
+	under    dimView // the view of the content
+	dim      tcell.RenderFilter
+	levels   []*menuLevel
+	style    tcell.Style
+	selStyle tcell.Style
+	disStyle tcell.Style
+	buttons  tcell.ButtonMask
+
+	WidgetWatchers
+}
+
+type menuLevel struct {
+	items []*MenuItem
+	x, y  int
+	w, h  int
+	sel   int
+}
+
+func (l *menuLevel) contains(x, y int) bool {
+	return x >= l.x && x < l.x+l.w && y >= l.y && y < l.y+l.h
+}
+
+// step moves the selection to the next selectable item in the direction.
+func (l *menuLevel) step(dir int) {
+	for i, n := l.sel+dir, len(l.items); i >= 0 && i < n; i += dir {
+		if l.items[i].selectable() {
+			l.sel = i
+			return
+		}
+	}
+}
+
+// SetContent sets the Widget shown beneath the menu.
+func (m *PopupMenu) SetContent(w Widget) {
+	if m.content != nil {
+		m.content.Unwatch(m)
+	}
+	m.content = w
+	if w != nil {
+		w.SetView(m.under.of(m.view))
+		w.Watch(m)
+	}
+	m.PostEventWidgetContent(m)
+}
+
+// SetDim sets the style transform applied to the content while a menu is
+// open, such as tcell.DimFilter or tcell.FadeFilter, so that the menu
+// stands out from it.  If it is nil, as it is by default, the content is
+// drawn as it is.
+func (m *PopupMenu) SetDim(f tcell.RenderFilter) {
+	m.dim = f
+	m.PostEventWidgetContent(m)
+}
+
+// SetStyle sets the styles used for items, the selected item, and
+// disabled items.
+func (m *PopupMenu) SetStyle(normal, selected, disabled tcell.Style) {
+	m.style = normal
+	m.selStyle = selected
+	m.disStyle = disabled
+	m.PostEventWidgetContent(m)
+}
+
+// addLevel opens a menu with its top left corner near x, y, moving it
+// to fit in the View.  If it does not fit to the right of x, it is placed
+// so that it ends at left instead.
+func (m *PopupMenu) addLevel(items []*MenuItem, x, y, left int) {
+	l := &menuLevel{items: items, sel: -1, h: len(items) + 2}
+	for _, item := range items {
+		w := runewidth.StringWidth(item.Label) + 4
+		if len(item.Submenu) > 0 {
+			w += 2 // room for the arrow
+		}
+		if w > l.w {
+			l.w = w
+		}
+	}
+	if m.view != nil {
+		vw, vh := m.view.Size()
+		if x+l.w > vw {
+			x = left - l.w
+			if x+l.w > vw {
+				x = vw - l.w
+			}
+		}
+		if y+l.h > vh {
+			y = vh - l.h
+		}
+	}
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	l.x, l.y = x, y
+	l.step(1)
+	m.levels = append(m.levels, l)
+	m.PostEventWidgetContent(m)
+}
+
+// Open opens a menu at the given position in the View.
+func (m *PopupMenu) Open(items []*MenuItem, x, y int) {
+	m.levels = nil
+	m.addLevel(items, x, y, x)
+}
+
+// OpenAt opens a menu at the position of a mouse event.
+func (m *PopupMenu) OpenAt(items []*MenuItem, ev *tcell.EventMouse) {
+	dx, dy := viewOffset(m.view)
+	x, y := ev.Position()
+	m.Open(items, x-dx, y-dy)
+}
+
+// Close closes the menu, and any open submenus.
+func (m *PopupMenu) Close() {
+	if len(m.levels) > 0 {
+		m.levels = nil
+		m.PostEventWidgetContent(m)
+	}
+}
+
+// IsOpen returns true if the menu is open.
+func (m *PopupMenu) IsOpen() bool {
+	return len(m.levels) > 0
+}
+
+func (m *PopupMenu) top() *menuLevel {
+	return m.levels[len(m.levels)-1]
+}
+
+// activate opens the submenu of the selected item, or chooses it.
+func (m *PopupMenu) activate() {
+	l := m.top()
+	if l.sel < 0 {
+		return
+	}
+	item := l.items[l.sel]
+	if len(item.Submenu) > 0 {
+		m.addLevel(item.Submenu, l.x+l.w-1, l.y+1+l.sel, l.x+1)
+		return
+	}
+	m.Close()
+	ev := &EventMenuSelect{item: item}
+	ev.SetWidget(m)
+	ev.SetEventNow()
+	m.PostEvent(ev)
+}
+
+func (m *PopupMenu) back() {
+	m.levels = m.levels[:len(m.levels)-1]
+	m.PostEventWidgetContent(m)
+}
+
+func (m *PopupMenu) handleKey(ev *tcell.EventKey) {
+	l := m.top()
+	switch ev.Key() {
+	case tcell.KeyUp:
+		l.step(-1)
+	case tcell.KeyDown:
+		l.step(1)
+	case tcell.KeyHome:
+		l.sel = -1
+		l.step(1)
+	case tcell.KeyEnd:
+		l.sel = len(l.items)
+		l.step(-1)
+	case tcell.KeyRight:
+		if l.sel >= 0 && len(l.items[l.sel].Submenu) > 0 {
+			m.activate()
+		}
+	case tcell.KeyEnter:
+		m.activate()
+	case tcell.KeyLeft:
+		if len(m.levels) > 1 {
+			m.back()
+		}
+	case tcell.KeyEscape:
+		m.back()
+	}
+	m.PostEventWidgetContent(m)
+}
+
+func (m *PopupMenu) handleMouse(ev *tcell.EventMouse) {
+	buttons := ev.Buttons()
+	pressed := buttons&tcell.Button1 != 0 && m.buttons&tcell.Button1 == 0
+	m.buttons = buttons
+
+	dx, dy := viewOffset(m.view)
+	x, y := ev.Position()
+	x -= dx
+	y -= dy
+	for i := len(m.levels) - 1; i >= 0; i-- {
+		l := m.levels[i]
+		if !l.contains(x, y) {
+			continue
+		}
+		row := y - l.y - 1
+		if row < 0 || row >= len(l.items) || !l.items[row].selectable() {
+			return
+		}
+		if l.sel != row || i != len(m.levels)-1 {
+			l.sel = row
+			m.levels = m.levels[:i+1]
+			m.PostEventWidgetContent(m)
+		}
+		if pressed {
+			m.activate()
+		}
+		return
+	}
+	if pressed {
+		m.Close()
+	}
+}
+
+func (m *PopupMenu) drawLevel(l *menuLevel) {
+	v := m.view
+	for y := 0; y < l.h; y++ {
+		for x := 0; x < l.w; x++ {
+			v.SetContent(l.x+x, l.y+y, ' ', nil, m.style)
+		}
+	}
+	for x := 1; x < l.w-1; x++ {
+		v.SetContent(l.x+x, l.y, tcell.RuneHLine, nil, m.style)
+		v.SetContent(l.x+x, l.y+l.h-1, tcell.RuneHLine, nil, m.style)
+	}
+	for y := 1; y < l.h-1; y++ {
+		v.SetContent(l.x, l.y+y, tcell.RuneVLine, nil, m.style)
+		v.SetContent(l.x+l.w-1, l.y+y, tcell.RuneVLine, nil, m.style)
+	}
+	v.SetContent(l.x, l.y, tcell.RuneULCorner, nil, m.style)
+	v.SetContent(l.x+l.w-1, l.y, tcell.RuneURCorner, nil, m.style)
+	v.SetContent(l.x, l.y+l.h-1, tcell.RuneLLCorner, nil, m.style)
+	v.SetContent(l.x+l.w-1, l.y+l.h-1, tcell.RuneLRCorner, nil, m.style)
+
+	for i, item := range l.items {
+		y := l.y + 1 + i
+		if item.Separator {
+			v.SetContent(l.x, y, tcell.RuneLTee, nil, m.style)
+			for x := 1; x < l.w-1; x++ {
+				v.SetContent(l.x+x, y, tcell.RuneHLine, nil, m.style)
+			}
+			v.SetContent(l.x+l.w-1, y, tcell.RuneRTee, nil, m.style)
+			continue
+		}
+		style := m.style
+		if item.Disabled {
+			style = m.disStyle
+		} else if i == l.sel {
+			style = m.selStyle
+		}
+		for x := 1; x < l.w-1; x++ {
+			v.SetContent(l.x+x, y, ' ', nil, style)
+		}
+		x := l.x + 2
+		for _, r := range item.Label {
+			v.SetContent(x, y, r, nil, style)
+			x += runewidth.RuneWidth(r)
+		}
+		if len(item.Submenu) > 0 {
+			v.SetContent(l.x+l.w-3, y, '>', nil, style)
+		}
+	}
+}
+
+// Draw draws the content, and then any open menus over it.
+func (m *PopupMenu) Draw() {
+	if m.view == nil {
+		return
+	}
+	if m.content != nil {
+		// A menu may have covered any part of the content.
+		m.under.filter = nil
+		if len(m.levels) > 0 {
+			m.under.filter = m.dim
+		}
+		Invalidate(m.content)
+		m.content.Draw()
+	}
+	for _, l := range m.levels {
+		m.drawLevel(l)
+	}
+}
+
+// Resize is called when our View changes sizes.  Any open menu is
+// closed, as it may no longer fit.
+func (m *PopupMenu) Resize() {
+	m.levels = nil
+	if m.content != nil {
+		m.content.Resize()
+	}
+	m.PostEventWidgetResize(m)
+}
+
+// Size returns the size of the content.
+func (m *PopupMenu) Size() (int, int) {
+	if m.content != nil {
+		return m.content.Size()
+	}
+	return 0, 0
+}
+
+// SetView sets the View object used for the PopupMenu and its content.
+func (m *PopupMenu) SetView(view View) {
+	m.view = view
+	if m.content != nil {
+		m.content.SetView(m.under.of(view))
+	}
+}
+
+// HandleEvent handles key and mouse events while the menu is open, and
+// otherwise passes events to the content.
+func (m *PopupMenu) HandleEvent(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *EventWidgetContent:
+		m.PostEventWidgetContent(m)
+		return true
+	case *EventWidgetResize, *EventWidgetMove:
+		return true
+	case *tcell.EventKey:
+		if m.IsOpen() {
+			m.handleKey(ev)
+			return true
+		}
+	case *tcell.EventMouse:
+		if m.IsOpen() {
+			m.handleMouse(ev)
+			return true
+		}
+		m.buttons = ev.Buttons()
+	}
+	if m.content != nil {
+		return m.content.HandleEvent(ev)
+	}
+	return false
+}
+
+// NewPopupMenu creates a PopupMenu, with no content.
+func NewPopupMenu() *PopupMenu {
+	return &PopupMenu{
+		style:    tcell.StyleDefault,
+		selStyle: tcell.StyleDefault.Reverse(true),
+		disStyle: tcell.StyleDefault.Dim(true),
+	}
+}
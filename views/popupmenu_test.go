@@ -0,0 +1,103 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+type menuWatcher struct {
+	chosen []*MenuItem
+}
+
+func (w *menuWatcher) HandleEvent(ev tcell.Event) bool {
+	if ev, ok := ev.(*EventMenuSelect); ok {
+		w.chosen = append(w.chosen, ev.Item())
+	}
+	return false
+}
+
+func TestPopupMenu(t *testing.T) {
+	v := newTestView(20, 8)
+	m := NewPopupMenu()
+	m.SetView(v)
+	watcher := &menuWatcher{}
+	m.Watch(watcher)
+
+	cut := &MenuItem{Label: "Cut", Disabled: true}
+	copyItem := &MenuItem{Label: "Copy"}
+	upper := &MenuItem{Label: "Upper"}
+	items := []*MenuItem{
+		cut,
+		copyItem,
+		{Separator: true},
+		{Label: "Case", Submenu: []*MenuItem{upper, {Label: "Lower"}}},
+	}
+
+	// Opened near the right edge, the menu opens to the left instead.
+	m.OpenAt(items, tcell.NewEventMouse(18, 1, tcell.ButtonNone, 0))
+	m.Draw()
+	want := []string{
+		"",
+		"        ┌────────┐",
+		"        │ Cut    │",
+		"        │ Copy   │",
+		"        ├────────┤",
+		"        │ Case > │",
+		"        └────────┘",
+	}
+	for y, row := range want {
+		for len([]rune(row)) < 20 {
+			row += " "
+		}
+		if got := v.row(y); got != row {
+			t.Errorf("row %d: got %q, want %q", y, got, row)
+		}
+	}
+
+	key := func(k tcell.Key) {
+		m.HandleEvent(tcell.NewEventKey(k, 0, tcell.ModNone))
+	}
+	key(tcell.KeyDown) // skips the separator
+	key(tcell.KeyRight)
+	if len(m.levels) != 2 {
+		t.Fatalf("submenu did not open")
+	}
+	key(tcell.KeyEnter)
+	if m.IsOpen() || len(watcher.chosen) != 1 || watcher.chosen[0] != upper {
+		t.Errorf("wrong choice: %v", watcher.chosen)
+	}
+
+	// Clicking a disabled item does nothing, clicking Copy chooses it.
+	m.Open(items, 0, 0)
+	m.HandleEvent(tcell.NewEventMouse(2, 1, tcell.Button1, 0))
+	m.HandleEvent(tcell.NewEventMouse(2, 1, tcell.ButtonNone, 0))
+	if !m.IsOpen() {
+		t.Errorf("disabled item closed the menu")
+	}
+	m.HandleEvent(tcell.NewEventMouse(2, 2, tcell.Button1, 0))
+	m.HandleEvent(tcell.NewEventMouse(2, 2, tcell.ButtonNone, 0))
+	if m.IsOpen() || len(watcher.chosen) != 2 || watcher.chosen[1] != copyItem {
+		t.Errorf("click did not choose: %v", watcher.chosen)
+	}
+
+	m.Open(items, 0, 0)
+	m.HandleEvent(tcell.NewEventMouse(15, 7, tcell.Button1, 0))
+	if m.IsOpen() {
+		t.Errorf("click outside did not close")
+	}
+}
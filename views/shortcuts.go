@@ -0,0 +1,195 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Shortcut is a key binding registered with Shortcuts.
+type Shortcut struct {
+	Key    tcell.Key
+	Rune   rune // for KeyRune
+	Mod    tcell.ModMask
+	Help   string // a short description, for the help listing
+	Scope  Widget // the binding only applies when this has the focus, or nil
+	Action func()
+}
+
+// Name returns a printable name for the key, such as "Ctrl-S" or "?".
+func (sc *Shortcut) Name() string {
+	if sc.Key != tcell.KeyRune {
+		return tcell.NewEventKey(sc.Key, sc.Rune, sc.Mod).Name()
+	}
+	var m []string
+	if sc.Mod&tcell.ModAlt != 0 {
+		m = append(m, "Alt")
+	}
+	if sc.Mod&tcell.ModMeta != 0 {
+		m = append(m, "Meta")
+	}
+	if sc.Mod&tcell.ModCtrl != 0 {
+		m = append(m, "Ctrl")
+	}
+	return strings.Join(append(m, string(sc.Rune)), "+")
+}
+
+// isControl returns true for the keys that are delivered as control
+// characters, which may or may not be reported with ModCtrl.
+func isControl(k tcell.Key) bool {
+	return k < ' ' || k == tcell.KeyDEL
+}
+
+func (sc *Shortcut) matches(ev *tcell.EventKey) bool {
+	mod, want := ev.Modifiers(), sc.Mod
+	switch {
+	case ev.Key() == tcell.KeyRune:
+		if sc.Key != tcell.KeyRune || ev.Rune() != sc.Rune {
+			return false
+		}
+		// Shift has already been applied to the rune.
+		mod &^= tcell.ModShift
+		want &^= tcell.ModShift
+	case ev.Key() != sc.Key:
+		return false
+	case isControl(ev.Key()):
+		mod &^= tcell.ModCtrl
+		want &^= tcell.ModCtrl
+	}
+	return mod == want
+}
+
+// Shortcuts is a registry of key bindings, either global or scoped to a
+// Widget.  Scoped bindings apply only while their Widget has the focus,
+// and take precedence over global ones.  Besides dispatching keys, the
+// registry can list the bindings currently in effect, for display in a
+// help screen.
+type Shortcuts struct {
+	bindings []*Shortcut
+	focus    Widget
+}
+
+// Bind registers a binding, replacing any other for the same key and
+// scope.  A nil scope makes a global binding.
+func (s *Shortcuts) Bind(scope Widget, key tcell.Key, r rune, mod tcell.ModMask,
+	help string, action func()) *Shortcut {
+	sc := &Shortcut{Key: key, Rune: r, Mod: mod, Help: help, Scope: scope, Action: action}
+	s.Unbind(sc)
+	s.bindings = append(s.bindings, sc)
+	return sc
+}
+
+// Unbind removes any binding for the same key and scope as the given one.
+func (s *Shortcuts) Unbind(sc *Shortcut) {
+	keep := s.bindings[:0]
+	for _, o := range s.bindings {
+		if o.Scope != sc.Scope || o.Key != sc.Key || o.Rune != sc.Rune || o.Mod != sc.Mod {
+			keep = append(keep, o)
+		}
+	}
+	s.bindings = keep
+}
+
+// UnbindScope removes all of the bindings for a scope, for example when
+// its Widget is discarded.
+func (s *Shortcuts) UnbindScope(scope Widget) {
+	keep := s.bindings[:0]
+	for _, o := range s.bindings {
+		if o.Scope != scope {
+			keep = append(keep, o)
+		}
+	}
+	s.bindings = keep
+}
+
+// SetFocus sets the Widget with the focus, whose bindings are active.
+func (s *Shortcuts) SetFocus(w Widget) {
+	s.focus = w
+}
+
+// Focus returns the Widget with the focus.
+func (s *Shortcuts) Focus() Widget {
+	return s.focus
+}
+
+// Active returns the bindings that are in effect: those scoped to the
+// Widget with the focus, and then the global ones that they do not
+// override.  Within each group, bindings are in the order registered.
+func (s *Shortcuts) Active() []*Shortcut {
+	var scoped, global []*Shortcut
+	for _, sc := range s.bindings {
+		switch {
+		case sc.Scope == nil:
+			global = append(global, sc)
+		case sc.Scope == s.focus:
+			scoped = append(scoped, sc)
+		}
+	}
+	active := scoped
+outer:
+	for _, g := range global {
+		for _, sc := range scoped {
+			if sc.Key == g.Key && sc.Rune == g.Rune && sc.Mod == g.Mod {
+				continue outer
+			}
+		}
+		active = append(active, g)
+	}
+	return active
+}
+
+// ShortcutHelp is a line of a help listing, as returned by Help.
+type ShortcutHelp struct {
+	Key    string // the name of the key, such as "Ctrl-S"
+	Help   string
+	Global bool
+}
+
+// Help returns the active bindings that have help text, in the order
+// of Active, suitable for display in a help screen.
+func (s *Shortcuts) Help() []ShortcutHelp {
+	var help []ShortcutHelp
+	for _, sc := range s.Active() {
+		if sc.Help != "" {
+			help = append(help, ShortcutHelp{Key: sc.Name(), Help: sc.Help, Global: sc.Scope == nil})
+		}
+	}
+	return help
+}
+
+// HandleEvent runs the action of the active binding for a key event, if
+// there is one, and returns true if it did.
+func (s *Shortcuts) HandleEvent(ev tcell.Event) bool {
+	kev, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return false
+	}
+	for _, sc := range s.Active() {
+		if sc.matches(kev) {
+			if sc.Action != nil {
+				sc.Action()
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// NewShortcuts creates an empty registry.
+func NewShortcuts() *Shortcuts {
+	return &Shortcuts{}
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestShortcuts(t *testing.T) {
+	s := NewShortcuts()
+	editor, list := NewText(), NewText()
+	var ran []string
+	action := func(name string) func() {
+		return func() { ran = append(ran, name) }
+	}
+	s.Bind(nil, tcell.KeyCtrlS, 0, tcell.ModNone, "Save", action("save"))
+	s.Bind(nil, tcell.KeyRune, '?', tcell.ModNone, "Help", action("help"))
+	s.Bind(nil, tcell.KeyRune, 'd', tcell.ModNone, "", action("global d"))
+	s.Bind(list, tcell.KeyRune, 'd', tcell.ModNone, "Delete item", action("delete"))
+	s.Bind(editor, tcell.KeyRune, 'x', tcell.ModAlt, "Cut", action("cut"))
+
+	press := func(k tcell.Key, r rune, mod tcell.ModMask) bool {
+		return s.HandleEvent(tcell.NewEventKey(k, r, mod))
+	}
+	s.SetFocus(list)
+	press(tcell.KeyRune, 19, tcell.ModNone) // Ctrl-S as a control character
+	press(tcell.KeyRune, 'd', tcell.ModNone)
+	if press(tcell.KeyRune, 'x', tcell.ModAlt) {
+		t.Errorf("binding of another scope was used")
+	}
+	s.SetFocus(editor)
+	press(tcell.KeyRune, 'x', tcell.ModAlt)
+	press(tcell.KeyRune, 'd', tcell.ModNone)
+
+	want := []string{"save", "delete", "cut", "global d"}
+	if len(ran) != len(want) {
+		t.Fatalf("wrong actions: %v", ran)
+	}
+	for i := range want {
+		if ran[i] != want[i] {
+			t.Errorf("wrong actions: %v", ran)
+		}
+	}
+
+	help := s.Help()
+	if len(help) != 3 || help[0].Key != "Alt+x" || help[1].Key != "Ctrl-S" || !help[1].Global ||
+		help[2].Key != "?" {
+		t.Errorf("wrong help: %+v", help)
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// Split is a container Widget that divides its View between two Widgets,
+// side by side (Horizontal) or one above the other (Vertical), with a
+// divider between them.  The divider can be dragged with the mouse, or
+// moved one cell at a time with Alt and the arrow keys along the split,
+// when the panes themselves do not use those keys.  Splits may be nested
+// to build more complex layouts.
+type Split struct {
+	view     View
+	orient   Orientation
+	panes    [2]Widget
+	ports    [2]ViewPort
+	mins     [2]int
+	ratio    float64
+	pos      int // size of the first pane
+	style    tcell.Style
+	dragging bool
+	buttons  tcell.ButtonMask
+
+	WidgetWatchers
+}
+
+func (s *Split) extent() int {
+	if s.view == nil {
+		return 0
+	}
+	w, h := s.view.Size()
+	if s.orient == Horizontal {
+		return w - 1
+	}
+	return h - 1
+}
+
+// clamp limits a position for the divider to one that honors the
+// minimum sizes, favoring the first pane if they cannot both be met.
+func (s *Split) clamp(pos int) int {
+	avail := s.extent()
+	if pos > avail-s.mins[1] {
+		pos = avail - s.mins[1]
+	}
+	if pos < s.mins[0] {
+		pos = s.mins[0]
+	}
+	if pos > avail {
+		pos = avail
+	}
+	if pos < 0 {
+		pos = 0
+	}
+	return pos
+}
+
+func (s *Split) layout() {
+	if s.view == nil {
+		return
+	}
+	avail := s.extent()
+	s.pos = s.clamp(int(s.ratio*float64(avail) + 0.5))
+	w, h := s.view.Size()
+	if s.orient == Horizontal {
+		s.ports[0].Resize(0, 0, s.pos, h)
+		s.ports[1].Resize(s.pos+1, 0, avail-s.pos, h)
+	} else {
+		s.ports[0].Resize(0, 0, w, s.pos)
+		s.ports[1].Resize(0, s.pos+1, w, avail-s.pos)
+	}
+	for _, p := range s.panes {
+		if p != nil {
+			p.Resize()
+		}
+	}
+}
+
+// move places the divider at the given position, if it can be.
+func (s *Split) move(pos int) {
+	avail := s.extent()
+	if pos = s.clamp(pos); pos == s.pos || avail <= 0 {
+		return
+	}
+	s.ratio = float64(pos) / float64(avail)
+	s.layout()
+	s.PostEventWidgetContent(s)
+}
+
+// SetPane sets the Widget shown in the first (0) or second (1) pane.
+func (s *Split) SetPane(index int, widget Widget) {
+	if index < 0 || index > 1 {
+		return
+	}
+	if old := s.panes[index]; old != nil {
+		old.Unwatch(s)
+	}
+	s.panes[index] = widget
+	if widget != nil {
+		widget.SetView(&s.ports[index])
+		widget.Watch(s)
+	}
+	s.layout()
+	s.PostEventWidgetContent(s)
+}
+
+// Pane returns the Widget shown in the first (0) or second (1) pane.
+func (s *Split) Pane(index int) Widget {
+	if index < 0 || index > 1 {
+		return nil
+	}
+	return s.panes[index]
+}
+
+// SetOrientation sets the orientation as either Horizontal or Vertical.
+func (s *Split) SetOrientation(orient Orientation) {
+	if s.orient != orient {
+		s.orient = orient
+		s.layout()
+		s.PostEventWidgetContent(s)
+	}
+}
+
+// SetRatio sets the fraction of the space, from 0 to 1, given to the
+// first pane.  The ratio is kept as the Split is resized.
+func (s *Split) SetRatio(ratio float64) {
+	if ratio < 0 {
+		ratio = 0
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+	s.ratio = ratio
+	s.layout()
+	s.PostEventWidgetContent(s)
+}
+
+// SetPosition sets the size, in cells, of the first pane.
+func (s *Split) SetPosition(pos int) {
+	s.move(pos)
+}
+
+// Position returns the size, in cells, of the first pane.
+func (s *Split) Position() int {
+	return s.pos
+}
+
+// SetMinSizes sets the minimum sizes of the two panes.  The divider
+// cannot be moved so as to make either pane smaller than this.
+func (s *Split) SetMinSizes(first, second int) {
+	s.mins = [2]int{first, second}
+	s.layout()
+	s.PostEventWidgetContent(s)
+}
+
+// SetStyle sets the style used for the divider.
+func (s *Split) SetStyle(style tcell.Style) {
+	s.style = style
+	s.PostEventWidgetContent(s)
+}
+
+// Draw draws the Split.
+func (s *Split) Draw() {
+	if s.view == nil {
+		return
+	}
+	w, h := s.view.Size()
+	if s.orient == Horizontal {
+		for y := 0; y < h; y++ {
+			s.view.SetContent(s.pos, y, tcell.RuneVLine, nil, s.style)
+		}
+	} else {
+		for x := 0; x < w; x++ {
+			s.view.SetContent(x, s.pos, tcell.RuneHLine, nil, s.style)
+		}
+	}
+	for i, p := range s.panes {
+		s.ports[i].Clear()
+		if p != nil {
+			Invalidate(p)
+			p.Draw()
+		}
+	}
+}
+
+// Resize is called when our View changes sizes.
+func (s *Split) Resize() {
+	s.layout()
+	s.PostEventWidgetResize(s)
+}
+
+// Size returns the preferred size, which is enough for both panes and
+// the divider between them.
+func (s *Split) Size() (int, int) {
+	var sizes [2][2]int
+	for i, p := range s.panes {
+		if p != nil {
+			sizes[i][0], sizes[i][1] = p.Size()
+		}
+	}
+	if s.orient == Horizontal {
+		h := sizes[0][1]
+		if sizes[1][1] > h {
+			h = sizes[1][1]
+		}
+		return sizes[0][0] + 1 + sizes[1][0], h
+	}
+	w := sizes[0][0]
+	if sizes[1][0] > w {
+		w = sizes[1][0]
+	}
+	return w, sizes[0][1] + 1 + sizes[1][1]
+}
+
+// SetView sets the View object used for the Split.
+func (s *Split) SetView(view View) {
+	s.view = view
+	s.ports[0].SetView(view)
+	s.ports[1].SetView(view)
+	s.layout()
+}
+
+func (s *Split) handleMouse(ev *tcell.EventMouse) bool {
+	buttons := ev.Buttons()
+	pressed := buttons&tcell.Button1 != 0 && s.buttons&tcell.Button1 == 0
+	s.buttons = buttons
+
+	dx, dy := viewOffset(s.view)
+	x, y := ev.Position()
+	pos := x - dx
+	if s.orient == Vertical {
+		pos = y - dy
+	}
+	if s.dragging {
+		if buttons&tcell.Button1 == 0 {
+			s.dragging = false
+		} else {
+			s.move(pos)
+		}
+		return true
+	}
+	w, h := s.view.Size()
+	if pressed && pos == s.pos && x-dx >= 0 && x-dx < w && y-dy >= 0 && y-dy < h {
+		s.dragging = true
+		return true
+	}
+	return false
+}
+
+func (s *Split) handleKey(ev *tcell.EventKey) bool {
+	if ev.Modifiers() != tcell.ModAlt {
+		return false
+	}
+	switch {
+	case s.orient == Horizontal && ev.Key() == tcell.KeyLeft,
+		s.orient == Vertical && ev.Key() == tcell.KeyUp:
+		s.move(s.pos - 1)
+		return true
+	case s.orient == Horizontal && ev.Key() == tcell.KeyRight,
+		s.orient == Vertical && ev.Key() == tcell.KeyDown:
+		s.move(s.pos + 1)
+		return true
+	}
+	return false
+}
+
+// HandleEvent handles dragging of the divider, and otherwise offers the
+// event to the panes.  Keys that the panes do not handle may move the
+// divider.
+func (s *Split) HandleEvent(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *EventWidgetContent:
+		s.PostEventWidgetContent(s)
+		return true
+	case *EventWidgetResize, *EventWidgetMove:
+		return true
+	case *tcell.EventMouse:
+		if s.view != nil && s.handleMouse(ev) {
+			return true
+		}
+	}
+	for _, p := range s.panes {
+		if p != nil && p.HandleEvent(ev) {
+			return true
+		}
+	}
+	if ev, ok := ev.(*tcell.EventKey); ok {
+		return s.handleKey(ev)
+	}
+	return false
+}
+
+// NewSplit creates an empty Split, with the space divided evenly.
+func NewSplit(orient Orientation) *Split {
+	return &Split{orient: orient, ratio: 0.5}
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestSplit(t *testing.T) {
+	v := newTestView(11, 5)
+	s := NewSplit(Horizontal)
+	s.SetView(v)
+
+	inner := NewSplit(Vertical)
+	left, top, bottom := NewText(), NewText(), NewText()
+	left.SetText("L")
+	top.SetText("T")
+	bottom.SetText("B")
+	inner.SetPane(0, top)
+	inner.SetPane(1, bottom)
+	s.SetPane(0, left)
+	s.SetPane(1, inner)
+	s.Resize()
+	s.Draw()
+
+	want := []string{"L    │T    ", "     │     ", "     │─────", "     │B    "}
+	for y, row := range want {
+		if got := v.row(y); got != row {
+			t.Errorf("row %d: got %q, want %q", y, got, row)
+		}
+	}
+
+	// Drag the outer divider to the left, past the minimum size.
+	s.SetMinSizes(3, 0)
+	s.HandleEvent(tcell.NewEventMouse(5, 3, tcell.Button1, 0))
+	s.HandleEvent(tcell.NewEventMouse(1, 3, tcell.Button1, 0))
+	s.HandleEvent(tcell.NewEventMouse(1, 3, tcell.ButtonNone, 0))
+	if s.Position() != 3 {
+		t.Errorf("wrong position after drag: %d", s.Position())
+	}
+
+	// Drag the nested divider down; its offset comes from the ViewPort.
+	s.HandleEvent(tcell.NewEventMouse(6, 2, tcell.Button1, 0))
+	s.HandleEvent(tcell.NewEventMouse(6, 3, tcell.Button1, 0))
+	s.HandleEvent(tcell.NewEventMouse(6, 3, tcell.ButtonNone, 0))
+	if inner.Position() != 3 {
+		t.Errorf("wrong nested position after drag: %d", inner.Position())
+	}
+
+	s.HandleEvent(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModAlt))
+	if s.Position() != 4 {
+		t.Errorf("wrong position after key: %d", s.Position())
+	}
+}
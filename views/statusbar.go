@@ -0,0 +1,299 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// StatusBar is a single line Widget made of segments, each of which holds
+// SimpleStyledText markup and is aligned to the left, center, or right of
+// the bar, much like SimpleStyledTextBar.  When the bar is too narrow for
+// all of its segments, the segments with the lowest priority give way
+// first: a segment with a minimum width is truncated, with an ellipsis,
+// down to that width, and then it (or a segment without one) is elided.
+type StatusBar struct {
+	view     View
+	style    tcell.Style
+	segments []*StatusSegment
+	styles   map[rune]tcell.Style
+	sep      string
+
+	WidgetWatchers
+}
+
+// StatusSegment is a segment of a StatusBar, created with AddSegment.
+type StatusSegment struct {
+	bar      *StatusBar
+	text     *SimpleStyledText
+	align    Alignment
+	priority int
+	minWidth int
+	width    int // width after layout, zero if elided
+}
+
+// SetMarkup sets the text of the segment, using the markup of
+// SimpleStyledText.SetMarkup.
+func (seg *StatusSegment) SetMarkup(m string) {
+	seg.text.SetMarkup(m)
+	seg.bar.PostEventWidgetContent(seg.bar)
+}
+
+// Markup returns the text of the segment, including markup.
+func (seg *StatusSegment) Markup() string {
+	return seg.text.Markup()
+}
+
+// SetPriority sets the priority of the segment.  Segments with lower
+// priorities are truncated or elided first.
+func (seg *StatusSegment) SetPriority(priority int) {
+	seg.priority = priority
+	seg.bar.PostEventWidgetContent(seg.bar)
+}
+
+// SetMinWidth sets the narrowest width the segment may be truncated to.
+// Zero means that the segment is never truncated, but is either shown in
+// full or elided.
+func (seg *StatusSegment) SetMinWidth(width int) {
+	seg.minWidth = width
+	seg.bar.PostEventWidgetContent(seg.bar)
+}
+
+// Visible returns true if the segment was shown, at least in part, when
+// the bar was last drawn.
+func (seg *StatusSegment) Visible() bool {
+	return seg.width > 0
+}
+
+func (seg *StatusSegment) fullWidth() int {
+	w, _ := seg.text.Size()
+	return w
+}
+
+// AddSegment adds a segment to the bar.  The alignment is one of
+// HAlignLeft, HAlignCenter, or HAlignRight; segments with the same
+// alignment are drawn in the order they were added.
+func (s *StatusBar) AddSegment(align Alignment, priority, minWidth int) *StatusSegment {
+	seg := &StatusSegment{
+		bar:      s,
+		text:     NewSimpleStyledText(),
+		align:    align & (HAlignLeft | HAlignCenter | HAlignRight),
+		priority: priority,
+		minWidth: minWidth,
+	}
+	for r, style := range s.styles {
+		seg.text.RegisterStyle(r, style)
+	}
+	s.segments = append(s.segments, seg)
+	s.PostEventWidgetContent(s)
+	return seg
+}
+
+// RemoveSegment removes a segment from the bar.
+func (s *StatusBar) RemoveSegment(seg *StatusSegment) {
+	for i, sg := range s.segments {
+		if sg == seg {
+			s.segments = append(s.segments[:i], s.segments[i+1:]...)
+			s.PostEventWidgetContent(s)
+			return
+		}
+	}
+}
+
+// RegisterStyle registers a markup style for all segments, including
+// those added later.  See SimpleStyledText.RegisterStyle.
+func (s *StatusBar) RegisterStyle(r rune, style tcell.Style) {
+	if s.styles == nil {
+		s.styles = make(map[rune]tcell.Style)
+	}
+	s.styles[r] = style
+	for _, seg := range s.segments {
+		seg.text.RegisterStyle(r, style)
+	}
+}
+
+// SetStyle sets the style used for the bar where there is no text.
+func (s *StatusBar) SetStyle(style tcell.Style) {
+	s.style = style
+	s.PostEventWidgetContent(s)
+}
+
+// SetSeparator sets the string drawn between adjacent segments.  The
+// default is a single space.
+func (s *StatusBar) SetSeparator(sep string) {
+	s.sep = sep
+	s.PostEventWidgetContent(s)
+}
+
+// total returns the width needed by the visible segments.
+func (s *StatusBar) total() int {
+	n, count := 0, 0
+	for _, seg := range s.segments {
+		if seg.width > 0 {
+			n += seg.width
+			count++
+		}
+	}
+	if count > 1 {
+		n += (count - 1) * len([]rune(s.sep))
+	}
+	return n
+}
+
+// layout decides how wide each segment is, given the width of the bar.
+func (s *StatusBar) layout(width int) {
+	for _, seg := range s.segments {
+		seg.width = seg.fullWidth()
+	}
+	for {
+		excess := s.total() - width
+		if excess <= 0 {
+			return
+		}
+		var victim *StatusSegment
+		for _, seg := range s.segments {
+			if seg.width > 0 && (victim == nil || seg.priority <= victim.priority) {
+				victim = seg
+			}
+		}
+		if victim == nil {
+			return
+		}
+		if victim.minWidth > 0 && victim.width > victim.minWidth {
+			victim.width -= excess
+			if victim.width < victim.minWidth {
+				victim.width = victim.minWidth
+			}
+		} else {
+			victim.width = 0
+		}
+	}
+}
+
+// drawSegment draws the segment at x, truncating it if needed, and
+// returns the position after it.
+func (s *StatusBar) drawSegment(seg *StatusSegment, x int) int {
+	t := &seg.text.Text
+	end := x + seg.width
+	trunc := seg.width < seg.fullWidth()
+	for i, r := range t.text {
+		w := t.widths[i]
+		if trunc && x+w > end-1 {
+			s.view.SetContent(x, 0, '…', nil, t.styles[i])
+			x++
+			break
+		}
+		if w == 0 {
+			continue
+		}
+		s.view.SetContent(x, 0, r, nil, t.styles[i])
+		x += w
+	}
+	return end
+}
+
+func (s *StatusBar) drawGroup(segs []*StatusSegment, x int) {
+	for i, seg := range segs {
+		if i > 0 {
+			for _, r := range s.sep {
+				s.view.SetContent(x, 0, r, nil, s.style)
+				x++
+			}
+		}
+		x = s.drawSegment(seg, x)
+	}
+}
+
+func (s *StatusBar) groupWidth(segs []*StatusSegment) int {
+	n := 0
+	for i, seg := range segs {
+		if i > 0 {
+			n += len([]rune(s.sep))
+		}
+		n += seg.width
+	}
+	return n
+}
+
+// Draw draws the StatusBar.
+func (s *StatusBar) Draw() {
+	if s.view == nil {
+		return
+	}
+	w, _ := s.view.Size()
+	s.view.Fill(' ', s.style)
+	s.layout(w)
+
+	var left, center, right []*StatusSegment
+	for _, seg := range s.segments {
+		if seg.width == 0 {
+			continue
+		}
+		switch seg.align {
+		case HAlignCenter:
+			center = append(center, seg)
+		case HAlignRight:
+			right = append(right, seg)
+		default:
+			left = append(left, seg)
+		}
+	}
+	lw, cw, rw := s.groupWidth(left), s.groupWidth(center), s.groupWidth(right)
+	s.drawGroup(left, 0)
+	s.drawGroup(right, w-rw)
+
+	// Center the middle group, but keep it clear of the others.
+	x := (w - cw) / 2
+	if len(right) > 0 && x+cw > w-rw-len([]rune(s.sep)) {
+		x = w - rw - len([]rune(s.sep)) - cw
+	}
+	if len(left) > 0 && x < lw+len([]rune(s.sep)) {
+		x = lw + len([]rune(s.sep))
+	}
+	s.drawGroup(center, x)
+}
+
+// Size returns the width of all segments, and a height of one.
+func (s *StatusBar) Size() (int, int) {
+	n, count := 0, 0
+	for _, seg := range s.segments {
+		n += seg.fullWidth()
+		count++
+	}
+	if count > 1 {
+		n += (count - 1) * len([]rune(s.sep))
+	}
+	return n, 1
+}
+
+// SetView sets the View object used for the StatusBar.
+func (s *StatusBar) SetView(view View) {
+	s.view = view
+}
+
+// Resize is called when our View changes sizes.
+func (s *StatusBar) Resize() {
+	s.PostEventWidgetResize(s)
+}
+
+// HandleEvent implements a tcell.EventHandler, but does nothing.
+func (s *StatusBar) HandleEvent(tcell.Event) bool {
+	return false
+}
+
+// NewStatusBar creates an empty StatusBar.
+func NewStatusBar() *StatusBar {
+	return &StatusBar{sep: " "}
+}
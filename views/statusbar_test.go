@@ -0,0 +1,57 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"testing"
+)
+
+func TestStatusBar(t *testing.T) {
+	s := NewStatusBar()
+	file := s.AddSegment(HAlignLeft, 10, 6)
+	file.SetMarkup("%Bmain.go%N")
+	mode := s.AddSegment(HAlignLeft, 1, 0)
+	mode.SetMarkup("INSERT")
+	s.AddSegment(HAlignCenter, 2, 0).SetMarkup("utf-8")
+	s.AddSegment(HAlignRight, 5, 0).SetMarkup("12:34")
+
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{40, "main.go INSERT   utf-8             12:34"},
+		{28, "main.go INSERT utf-8   12:34"},
+		{20, "main.go utf-8  12:34"},
+		{13, "main.go 12:34"},
+		{12, "main.go"},
+		{6, "main.…"},
+		{5, ""},
+	}
+	for _, test := range tests {
+		v := newTestView(test.width, 1)
+		s.SetView(v)
+		s.Draw()
+		want := test.want
+		for len([]rune(want)) < test.width {
+			want += " "
+		}
+		if got := v.row(0); got != want {
+			t.Errorf("width %d: got %q, want %q", test.width, got, want)
+		}
+	}
+	if mode.Visible() || file.Visible() {
+		t.Errorf("wrong segments visible")
+	}
+}
@@ -0,0 +1,645 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// TabBar is a Widget that displays a single row of tab headers, one of
+// which is selected.  Each tab may be marked dirty, which is shown with
+// an asterisk after the title, and may carry a close button.  When the
+// headers do not fit, the row scrolls, with arrows at either end to show
+// that more tabs are present.
+//
+// Ctrl-PgUp and Ctrl-PgDn select the previous and next tab, and Alt-1
+// through Alt-9 select a tab directly.  Clicking a header selects it,
+// and clicking the arrows scrolls the row.
+type TabBar struct {
+	view    View
+	tabs    []*tabItem
+	current int
+	first   int  // first visible header
+	follow  bool // scroll to the current tab on the next draw
+	style   tcell.Style
+	active  tcell.Style
+	spots   []tabSpot
+	larrow  bool
+	rarrow  bool
+	buttons tcell.ButtonMask
+
+	WidgetWatchers
+}
+
+type tabItem struct {
+	title    string
+	dirty    bool
+	closable bool
+}
+
+// tabSpot records where a header was drawn, for mouse handling.
+type tabSpot struct {
+	index  int
+	x      int
+	width  int
+	closex int // -1 if there is no close button
+}
+
+// EventTabSelect is fired when a different tab is selected.
+type EventTabSelect struct {
+	index int
+	widgetEvent
+}
+
+// Index returns the index of the newly selected tab.
+func (ev *EventTabSelect) Index() int {
+	return ev.index
+}
+
+// EventTabClose is fired when the close button of a tab is clicked.
+// The tab is not removed; the application should do that with RemoveTab,
+// perhaps after asking the user whether to discard unsaved changes.
+type EventTabClose struct {
+	index int
+	widgetEvent
+}
+
+// Index returns the index of the tab to close.
+func (ev *EventTabClose) Index() int {
+	return ev.index
+}
+
+func (item *tabItem) label() string {
+	s := " " + item.title
+	if item.dirty {
+		s += "*"
+	}
+	if item.closable {
+		s += " x"
+	}
+	return s + " "
+}
+
+func (t *TabBar) valid(index int) bool {
+	return index >= 0 && index < len(t.tabs)
+}
+
+func (t *TabBar) postSelect() {
+	ev := &EventTabSelect{index: t.current}
+	ev.SetWidget(t)
+	ev.SetEventNow()
+	t.PostEvent(ev)
+}
+
+func (t *TabBar) postClose(index int) {
+	ev := &EventTabClose{index: index}
+	ev.SetWidget(t)
+	ev.SetEventNow()
+	t.PostEvent(ev)
+}
+
+// AddTab appends a tab with the given title, and returns its index.
+// The first tab added becomes the selected tab.
+func (t *TabBar) AddTab(title string) int {
+	t.tabs = append(t.tabs, &tabItem{title: title})
+	if len(t.tabs) == 1 {
+		t.current = 0
+		t.follow = true
+		t.postSelect()
+	}
+	t.PostEventWidgetContent(t)
+	return len(t.tabs) - 1
+}
+
+// RemoveTab removes the tab at the given index.  If it was selected, the
+// tab that takes its place (or the new last tab) becomes selected.
+func (t *TabBar) RemoveTab(index int) {
+	if !t.valid(index) {
+		return
+	}
+	t.tabs = append(t.tabs[:index], t.tabs[index+1:]...)
+	changed := index == t.current
+	if index < t.current || t.current >= len(t.tabs) {
+		t.current--
+		changed = true
+	}
+	if t.current < 0 {
+		t.current = 0
+	}
+	t.follow = true
+	if changed && len(t.tabs) > 0 {
+		t.postSelect()
+	}
+	t.PostEventWidgetContent(t)
+}
+
+// TabCount returns the number of tabs.
+func (t *TabBar) TabCount() int {
+	return len(t.tabs)
+}
+
+// Selected returns the index of the selected tab, or -1 if there
+// are no tabs.
+func (t *TabBar) Selected() int {
+	if len(t.tabs) == 0 {
+		return -1
+	}
+	return t.current
+}
+
+// Select selects the tab at the given index.
+func (t *TabBar) Select(index int) {
+	if !t.valid(index) || index == t.current {
+		return
+	}
+	t.current = index
+	t.follow = true
+	t.postSelect()
+	t.PostEventWidgetContent(t)
+}
+
+// SetTabTitle changes the title of a tab.
+func (t *TabBar) SetTabTitle(index int, title string) {
+	if t.valid(index) {
+		t.tabs[index].title = title
+		t.PostEventWidgetContent(t)
+	}
+}
+
+// TabTitle returns the title of a tab.
+func (t *TabBar) TabTitle(index int) string {
+	if t.valid(index) {
+		return t.tabs[index].title
+	}
+	return ""
+}
+
+// SetTabDirty marks a tab as having (or not having) unsaved changes.
+func (t *TabBar) SetTabDirty(index int, dirty bool) {
+	if t.valid(index) {
+		t.tabs[index].dirty = dirty
+		t.PostEventWidgetContent(t)
+	}
+}
+
+// TabDirty returns true if the tab is marked dirty.
+func (t *TabBar) TabDirty(index int) bool {
+	return t.valid(index) && t.tabs[index].dirty
+}
+
+// SetTabClosable controls whether a tab shows a close button.
+func (t *TabBar) SetTabClosable(index int, closable bool) {
+	if t.valid(index) {
+		t.tabs[index].closable = closable
+		t.PostEventWidgetContent(t)
+	}
+}
+
+// SetStyle sets the style used for the bar and the unselected tabs.
+func (t *TabBar) SetStyle(style tcell.Style) {
+	t.style = style
+	t.PostEventWidgetContent(t)
+}
+
+// SetActiveStyle sets the style used for the selected tab.
+func (t *TabBar) SetActiveStyle(style tcell.Style) {
+	t.active = style
+	t.PostEventWidgetContent(t)
+}
+
+// scroll adjusts the first visible header, so that the current tab is
+// visible if requested, and so that no space is wasted at the end.
+func (t *TabBar) scroll(width int) {
+	if t.Width() <= width {
+		t.first = 0
+		return
+	}
+	width -= 2 // room for the arrows
+	if t.first >= len(t.tabs) {
+		t.first = len(t.tabs) - 1
+	}
+	if t.first < 0 {
+		t.first = 0
+	}
+	span := func(from, to int) int {
+		n := 0
+		for i := from; i <= to; i++ {
+			n += runewidth.StringWidth(t.tabs[i].label())
+		}
+		return n
+	}
+	if t.follow {
+		if t.current < t.first {
+			t.first = t.current
+		}
+		for t.first < t.current && span(t.first, t.current) > width {
+			t.first++
+		}
+	}
+	for t.first > 0 && span(t.first-1, len(t.tabs)-1) <= width {
+		t.first--
+	}
+}
+
+// Draw draws the TabBar.
+func (t *TabBar) Draw() {
+	if t.view == nil {
+		return
+	}
+	w, _ := t.view.Size()
+	t.view.Fill(' ', t.style)
+	t.scroll(w)
+	t.follow = false
+	t.spots = t.spots[:0]
+
+	x, limit := 0, w
+	t.larrow = t.first > 0
+	t.rarrow = false
+	if t.larrow {
+		t.view.SetContent(0, 0, '<', nil, t.style)
+		x = 1
+	}
+	if t.first > 0 || t.Width() > w {
+		limit = w - 1
+	}
+	for i := t.first; i < len(t.tabs); i++ {
+		tab := t.tabs[i]
+		label := tab.label()
+		lw := runewidth.StringWidth(label)
+		if x+lw > limit {
+			t.rarrow = true
+			break
+		}
+		style := t.style
+		if i == t.current {
+			style = t.active
+		}
+		spot := tabSpot{index: i, x: x, width: lw, closex: -1}
+		if tab.closable {
+			spot.closex = x + lw - 2
+		}
+		t.spots = append(t.spots, spot)
+		for _, r := range label {
+			t.view.SetContent(x, 0, r, nil, style)
+			x += runewidth.RuneWidth(r)
+		}
+	}
+	if t.rarrow {
+		t.view.SetContent(w-1, 0, '>', nil, t.style)
+	}
+}
+
+// Width returns the total width of all tab headers.
+func (t *TabBar) Width() int {
+	n := 0
+	for _, tab := range t.tabs {
+		n += runewidth.StringWidth(tab.label())
+	}
+	return n
+}
+
+// Size returns the width of all tab headers, and a height of one.
+func (t *TabBar) Size() (int, int) {
+	return t.Width(), 1
+}
+
+// SetView sets the View object used for the tab bar.
+func (t *TabBar) SetView(view View) {
+	t.view = view
+}
+
+// Resize is called when our View changes sizes.
+func (t *TabBar) Resize() {
+	t.follow = true
+	t.PostEventWidgetResize(t)
+}
+
+// viewOffset returns the offset that translates screen coordinates into
+// the content coordinates of the view, by walking up through any
+// enclosing ViewPorts.
+func viewOffset(v View) (int, int) {
+	dx, dy := 0, 0
+	for {
+		if d, ok := v.(*dimView); ok {
+			v = d.View
+			continue
+		}
+		vp, ok := v.(*ViewPort)
+		if !ok {
+			return dx, dy
+		}
+		dx += vp.physx - vp.viewx
+		dy += vp.physy - vp.viewy
+		v = vp.v
+	}
+}
+
+func (t *TabBar) handleKey(ev *tcell.EventKey) bool {
+	if len(t.tabs) == 0 {
+		return false
+	}
+	switch {
+	case ev.Key() == tcell.KeyPgUp && ev.Modifiers() == tcell.ModCtrl:
+		t.Select((t.current + len(t.tabs) - 1) % len(t.tabs))
+		return true
+	case ev.Key() == tcell.KeyPgDn && ev.Modifiers() == tcell.ModCtrl:
+		t.Select((t.current + 1) % len(t.tabs))
+		return true
+	case ev.Key() == tcell.KeyRune && ev.Modifiers() == tcell.ModAlt &&
+		ev.Rune() >= '1' && ev.Rune() <= '9':
+		if index := int(ev.Rune() - '1'); index < len(t.tabs) {
+			t.Select(index)
+			return true
+		}
+	}
+	return false
+}
+
+func (t *TabBar) handleMouse(ev *tcell.EventMouse) bool {
+	if t.view == nil {
+		return false
+	}
+	buttons := ev.Buttons()
+	pressed := buttons&tcell.Button1 != 0 && t.buttons&tcell.Button1 == 0
+	t.buttons = buttons
+
+	dx, dy := viewOffset(t.view)
+	x, y := ev.Position()
+	x -= dx
+	y -= dy
+	vw, _ := t.view.Size()
+	if y != 0 || x < 0 || x >= vw {
+		return false
+	}
+	if !pressed {
+		return buttons&tcell.Button1 != 0
+	}
+	if t.larrow && x == 0 {
+		t.first--
+		t.PostEventWidgetContent(t)
+		return true
+	}
+	if t.rarrow && x == vw-1 {
+		t.first++
+		t.PostEventWidgetContent(t)
+		return true
+	}
+	for _, spot := range t.spots {
+		if x < spot.x || x >= spot.x+spot.width {
+			continue
+		}
+		if x == spot.closex {
+			t.postClose(spot.index)
+		} else {
+			t.Select(spot.index)
+		}
+		return true
+	}
+	return true
+}
+
+// HandleEvent handles the keys and mouse clicks that change tabs.
+func (t *TabBar) HandleEvent(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+		return t.handleKey(ev)
+	case *tcell.EventMouse:
+		return t.handleMouse(ev)
+	}
+	return false
+}
+
+// NewTabBar creates an empty TabBar.
+func NewTabBar() *TabBar {
+	return &TabBar{active: tcell.StyleDefault.Reverse(true)}
+}
+
+// TabPanel is a container Widget that shows a TabBar above the content
+// of the selected tab.  Only the selected tab's Widget is drawn, or
+// receives events.  The TabPanel reposts EventTabSelect and EventTabClose
+// events from its tab bar, as its own.
+type TabPanel struct {
+	view    View
+	bar     TabBar
+	barport ViewPort
+	body    ViewPort
+	widgets []Widget
+	inited  bool
+
+	WidgetWatchers
+}
+
+func (p *TabPanel) init() {
+	if !p.inited {
+		p.inited = true
+		p.bar.active = tcell.StyleDefault.Reverse(true)
+		p.bar.SetView(&p.barport)
+		p.bar.Watch(p)
+	}
+}
+
+func (p *TabPanel) current() Widget {
+	if index := p.bar.Selected(); index >= 0 {
+		return p.widgets[index]
+	}
+	return nil
+}
+
+func (p *TabPanel) layout() {
+	if p.view == nil {
+		return
+	}
+	w, h := p.view.Size()
+	p.barport.Resize(0, 0, w, 1)
+	p.body.Resize(0, 1, w, h-1)
+}
+
+// AddTab appends a tab showing the given Widget, and returns its index.
+func (p *TabPanel) AddTab(title string, widget Widget) int {
+	p.init()
+	p.widgets = append(p.widgets, widget)
+	widget.SetView(&p.body)
+	widget.Watch(p)
+	return p.bar.AddTab(title)
+}
+
+// RemoveTab removes the tab at the given index.
+func (p *TabPanel) RemoveTab(index int) {
+	p.init()
+	if !p.bar.valid(index) {
+		return
+	}
+	p.widgets[index].Unwatch(p)
+	p.widgets = append(p.widgets[:index], p.widgets[index+1:]...)
+	p.bar.RemoveTab(index)
+}
+
+// Widget returns the Widget shown by the tab at the given index.
+func (p *TabPanel) Widget(index int) Widget {
+	if p.bar.valid(index) {
+		return p.widgets[index]
+	}
+	return nil
+}
+
+// TabCount returns the number of tabs.
+func (p *TabPanel) TabCount() int {
+	return p.bar.TabCount()
+}
+
+// Selected returns the index of the selected tab, or -1 if there
+// are no tabs.
+func (p *TabPanel) Selected() int {
+	return p.bar.Selected()
+}
+
+// Select selects the tab at the given index.
+func (p *TabPanel) Select(index int) {
+	p.init()
+	p.bar.Select(index)
+}
+
+// SetTabTitle changes the title of a tab.
+func (p *TabPanel) SetTabTitle(index int, title string) {
+	p.init()
+	p.bar.SetTabTitle(index, title)
+}
+
+// SetTabDirty marks a tab as having (or not having) unsaved changes.
+func (p *TabPanel) SetTabDirty(index int, dirty bool) {
+	p.init()
+	p.bar.SetTabDirty(index, dirty)
+}
+
+// SetTabClosable controls whether a tab shows a close button.
+func (p *TabPanel) SetTabClosable(index int, closable bool) {
+	p.init()
+	p.bar.SetTabClosable(index, closable)
+}
+
+// SetStyle sets the style used for the tab bar and unselected tabs.
+func (p *TabPanel) SetStyle(style tcell.Style) {
+	p.init()
+	p.bar.SetStyle(style)
+}
+
+// SetActiveStyle sets the style used for the selected tab.
+func (p *TabPanel) SetActiveStyle(style tcell.Style) {
+	p.init()
+	p.bar.SetActiveStyle(style)
+}
+
+// Draw draws the TabPanel.
+func (p *TabPanel) Draw() {
+	p.init()
+	if p.view == nil {
+		return
+	}
+	p.bar.Draw()
+	p.body.Clear()
+	if w := p.current(); w != nil {
+		Invalidate(w)
+		w.Draw()
+	}
+}
+
+// Resize is called when our View changes sizes.
+func (p *TabPanel) Resize() {
+	p.init()
+	p.layout()
+	p.bar.Resize()
+	if w := p.current(); w != nil {
+		w.Resize()
+	}
+	p.PostEventWidgetResize(p)
+}
+
+// Size returns the preferred size, which is enough for the tab headers
+// and the largest of the tab Widgets.
+func (p *TabPanel) Size() (int, int) {
+	w, h := p.bar.Size()
+	for _, widget := range p.widgets {
+		ww, wh := widget.Size()
+		if ww > w {
+			w = ww
+		}
+		if wh+1 > h {
+			h = wh + 1
+		}
+	}
+	return w, h
+}
+
+// SetView sets the View object used for the TabPanel.
+func (p *TabPanel) SetView(view View) {
+	p.init()
+	p.view = view
+	p.barport.SetView(view)
+	p.body.SetView(view)
+	p.layout()
+}
+
+// HandleEvent handles events from the tab bar and the tab Widgets, and
+// otherwise offers the event to the tab bar and then to the selected
+// tab's Widget.
+func (p *TabPanel) HandleEvent(ev tcell.Event) bool {
+	p.init()
+	switch ev := ev.(type) {
+	case *EventTabSelect:
+		if ev.Widget() == &p.bar {
+			if w := p.current(); w != nil {
+				w.Resize()
+			}
+			nev := &EventTabSelect{index: ev.index}
+			nev.SetWidget(p)
+			nev.SetEventNow()
+			p.PostEvent(nev)
+		}
+		return true
+	case *EventTabClose:
+		if ev.Widget() == &p.bar {
+			nev := &EventTabClose{index: ev.index}
+			nev.SetWidget(p)
+			nev.SetEventNow()
+			p.PostEvent(nev)
+		}
+		return true
+	case *EventWidgetContent:
+		p.PostEventWidgetContent(p)
+		return true
+	case *EventWidgetResize, *EventWidgetMove:
+		return true
+	case *tcell.EventKey:
+		if p.bar.handleKey(ev) {
+			return true
+		}
+	case *tcell.EventMouse:
+		if p.bar.handleMouse(ev) {
+			return true
+		}
+	}
+	if w := p.current(); w != nil {
+		return w.HandleEvent(ev)
+	}
+	return false
+}
+
+// NewTabPanel creates an empty TabPanel.
+func NewTabPanel() *TabPanel {
+	p := &TabPanel{}
+	p.init()
+	return p
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// testView is a View backed by a simple array of runes.
+type testView struct {
+	width  int
+	height int
+	cells  []rune
+}
+
+func newTestView(w, h int) *testView {
+	v := &testView{width: w, height: h, cells: make([]rune, w*h)}
+	v.Clear()
+	return v
+}
+
+func (v *testView) SetContent(x, y int, ch rune, _ []rune, _ tcell.Style) {
+	if x >= 0 && y >= 0 && x < v.width && y < v.height {
+		v.cells[y*v.width+x] = ch
+	}
+}
+
+func (v *testView) Size() (int, int)      { return v.width, v.height }
+func (v *testView) Resize(_, _, _, _ int) {}
+func (v *testView) Clear()                { v.Fill(' ', tcell.StyleDefault) }
+func (v *testView) Fill(ch rune, _ tcell.Style) {
+	for i := range v.cells {
+		v.cells[i] = ch
+	}
+}
+
+func (v *testView) row(y int) string {
+	return string(v.cells[y*v.width : (y+1)*v.width])
+}
+
+type tabWatcher struct {
+	selected []int
+	closed   []int
+}
+
+func (w *tabWatcher) HandleEvent(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *EventTabSelect:
+		w.selected = append(w.selected, ev.Index())
+	case *EventTabClose:
+		w.closed = append(w.closed, ev.Index())
+	}
+	return false
+}
+
+func TestTabPanel(t *testing.T) {
+	v := newTestView(20, 3)
+	p := NewTabPanel()
+	p.SetView(v)
+	watcher := &tabWatcher{}
+	p.Watch(watcher)
+
+	for _, title := range []string{"one", "two", "three"} {
+		text := NewText()
+		text.SetText(title + " body")
+		p.AddTab(title, text)
+	}
+	p.SetTabClosable(1, true)
+	p.SetTabDirty(2, true)
+	p.Resize()
+	p.Draw()
+
+	if row := v.row(0); row != " one  two x  three* " {
+		t.Errorf("wrong header: %q", row)
+	}
+	if row := v.row(1); row != "one body            " {
+		t.Errorf("wrong body: %q", row)
+	}
+
+	p.HandleEvent(tcell.NewEventKey(tcell.KeyPgDn, 0, tcell.ModCtrl))
+	p.Draw()
+	if p.Selected() != 1 || v.row(1) != "two body            " {
+		t.Errorf("Ctrl-PgDn failed: %d %q", p.Selected(), v.row(1))
+	}
+
+	// Click the header of the third tab, then the close button of the second.
+	p.HandleEvent(tcell.NewEventMouse(15, 0, tcell.Button1, 0))
+	p.HandleEvent(tcell.NewEventMouse(15, 0, tcell.ButtonNone, 0))
+	p.HandleEvent(tcell.NewEventMouse(10, 0, tcell.Button1, 0))
+	p.HandleEvent(tcell.NewEventMouse(10, 0, tcell.ButtonNone, 0))
+	if p.Selected() != 2 {
+		t.Errorf("click did not select: %d", p.Selected())
+	}
+	if len(watcher.closed) != 1 || watcher.closed[0] != 1 {
+		t.Errorf("wrong close events: %v", watcher.closed)
+	}
+	if len(watcher.selected) != 3 || watcher.selected[2] != 2 {
+		t.Errorf("wrong select events: %v", watcher.selected)
+	}
+
+	// Overflow: the selected tab is scrolled into view.
+	p.AddTab("four", NewText())
+	p.Select(3)
+	p.Draw()
+	if row := v.row(0); row != "< three*  four      " {
+		t.Errorf("wrong scrolled header: %q", row)
+	}
+
+	p.RemoveTab(3)
+	if p.Selected() != 2 || p.TabCount() != 3 {
+		t.Errorf("wrong selection after remove: %d", p.Selected())
+	}
+}
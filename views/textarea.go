@@ -119,6 +119,7 @@ func (ta *TextArea) SetStyle(style tcell.Style) {
 func (ta *TextArea) EnableCursor(on bool) {
 	ta.Init()
 	ta.model.cursor = on
+	ta.PostEventWidgetContent(ta)
 }
 
 // HideCursor hides or shows the cursor in the TextArea.
@@ -127,6 +128,7 @@ func (ta *TextArea) EnableCursor(on bool) {
 func (ta *TextArea) HideCursor(on bool) {
 	ta.Init()
 	ta.model.hide = on
+	ta.PostEventWidgetContent(ta)
 }
 
 // SetContent is used to set the textual content, passed as a
@@ -76,6 +76,7 @@ func (t *TextBar) SetRight(s string, style tcell.Style) {
 func (t *TextBar) SetStyle(style tcell.Style) {
 	t.initialize()
 	t.style = style
+	t.PostEventWidgetContent(t)
 }
 
 func (t *TextBar) initialize() {
@@ -180,6 +181,7 @@ func (t *TextBar) HandleEvent(ev tcell.Event) bool {
 	switch ev.(type) {
 	case *EventWidgetContent:
 		t.changed = true
+		t.PostEventWidgetContent(t)
 		return true
 	}
 	return false
@@ -0,0 +1,429 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// TreeNode is a single node of a Tree.  A node whose children are
+// expensive to find, such as a directory, can be marked Lazy, in which
+// case the Tree's loader is called to supply the children the first time
+// the node is expanded.
+type TreeNode struct {
+	Text     string
+	Style    tcell.Style // style of the text, StyleDefault for the tree's
+	Icon     rune        // overrides the tree's icon, if not zero
+	Data     interface{} // for use by the application
+	Lazy     bool        // children are loaded when first expanded
+	Children []*TreeNode
+
+	parent   *TreeNode
+	expanded bool
+	loaded   bool
+}
+
+// NewTreeNode creates a TreeNode with the given text.
+func NewTreeNode(text string) *TreeNode {
+	return &TreeNode{Text: text}
+}
+
+// AddChild appends a child to the node, and returns the child.
+func (n *TreeNode) AddChild(child *TreeNode) *TreeNode {
+	child.parent = n
+	n.Children = append(n.Children, child)
+	return child
+}
+
+// Parent returns the parent of the node, or nil for the root.
+func (n *TreeNode) Parent() *TreeNode {
+	return n.parent
+}
+
+// Expanded returns true if the node's children are being shown.
+func (n *TreeNode) Expanded() bool {
+	return n.expanded
+}
+
+func (n *TreeNode) expandable() bool {
+	return len(n.Children) > 0 || (n.Lazy && !n.loaded)
+}
+
+// TreeGuides are the strings drawn to the left of each node to show the
+// structure of the tree.  Branch leads to a node with later siblings, Last
+// to the last of its siblings, Line continues past a node whose ancestor
+// has later siblings, and Blank is used otherwise.  All four should have
+// the same width.
+type TreeGuides struct {
+	Branch string
+	Last   string
+	Line   string
+	Blank  string
+}
+
+var (
+	// TreeGuidesLines draws the tree using box drawing characters.
+	TreeGuidesLines = TreeGuides{Branch: "├─", Last: "└─", Line: "│ ", Blank: "  "}
+
+	// TreeGuidesASCII draws the tree using only ASCII characters.
+	TreeGuidesASCII = TreeGuides{Branch: "|-", Last: "`-", Line: "| ", Blank: "  "}
+
+	// TreeGuidesIndent shows the tree structure by indentation alone.
+	TreeGuidesIndent = TreeGuides{Branch: "  ", Last: "  ", Line: "  ", Blank: "  "}
+)
+
+// TreeIcons are the runes drawn just before the text of each node.
+// A zero rune draws nothing.
+type TreeIcons struct {
+	Expanded  rune
+	Collapsed rune
+	Leaf      rune
+}
+
+// EventTreeActivate is fired when Enter is pressed on a node.
+type EventTreeActivate struct {
+	node *TreeNode
+	widgetEvent
+}
+
+// Node returns the node that was activated.
+func (ev *EventTreeActivate) Node() *TreeNode {
+	return ev.node
+}
+
+// Tree is a scrollable, expandable view of a hierarchy of TreeNodes, such
+// as a file system or the fields of an object.  Up and Down move through
+// the visible nodes, Right or '+' expands the selected node (or moves to
+// its first child), Left or '-' collapses it (or moves to its parent),
+// space toggles it, and Enter fires EventTreeActivate.
+type Tree struct {
+	model *treeModel
+	once  sync.Once
+	CellView
+}
+
+type treeRow struct {
+	node  *TreeNode
+	runes []rune // one per column, with zero after a wide rune
+	text  int    // column where the node's text starts
+}
+
+func treeColumns(s string) []rune {
+	var runes []rune
+	for _, r := range s {
+		runes = append(runes, r)
+		if runewidth.RuneWidth(r) == 2 {
+			runes = append(runes, 0)
+		}
+	}
+	return runes
+}
+
+type treeModel struct {
+	root   *TreeNode
+	rows   []treeRow
+	width  int
+	y      int
+	style  tcell.Style
+	guides TreeGuides
+	icons  TreeIcons
+	loader func(*TreeNode) []*TreeNode
+}
+
+func (m *treeModel) GetCell(x, y int) (rune, tcell.Style, []rune, int) {
+	if x < 0 || y < 0 || y >= len(m.rows) || x >= m.width {
+		return 0, m.style, nil, 1
+	}
+	row := m.rows[y]
+	ch, style, wid := ' ', m.style, 1
+	if x < len(row.runes) {
+		ch = row.runes[x]
+		if runewidth.RuneWidth(ch) == 2 {
+			wid = 2
+		}
+		if x >= row.text && row.node.Style != tcell.StyleDefault {
+			style = row.node.Style
+		}
+	}
+	if y == m.y {
+		style = style.Reverse(true)
+	}
+	return ch, style, nil, wid
+}
+
+func (m *treeModel) GetBounds() (int, int) {
+	return m.width, len(m.rows)
+}
+
+func (m *treeModel) limitCursor() {
+	if m.y > len(m.rows)-1 {
+		m.y = len(m.rows) - 1
+	}
+	if m.y < 0 {
+		m.y = 0
+	}
+}
+
+func (m *treeModel) SetCursor(_, y int) {
+	m.y = y
+	m.limitCursor()
+}
+
+func (m *treeModel) MoveCursor(_, y int) {
+	m.y += y
+	m.limitCursor()
+}
+
+func (m *treeModel) GetCursor() (int, int, bool, bool) {
+	return 0, m.y, true, false
+}
+
+// addRows adds the rows for a node and its visible descendants.  The
+// guide is drawn before the node itself, and indent before its children.
+func (m *treeModel) addRows(n *TreeNode, prefix, guide, indent string) {
+	s := prefix + guide
+	icon := n.Icon
+	if icon == 0 {
+		switch {
+		case !n.expandable():
+			icon = m.icons.Leaf
+		case n.expanded:
+			icon = m.icons.Expanded
+		default:
+			icon = m.icons.Collapsed
+		}
+	}
+	if icon != 0 {
+		s += string(icon) + " "
+	}
+	row := treeRow{node: n, runes: treeColumns(s)}
+	row.text = len(row.runes)
+	row.runes = append(row.runes, treeColumns(n.Text)...)
+	m.rows = append(m.rows, row)
+	if len(row.runes) > m.width {
+		m.width = len(row.runes)
+	}
+	if !n.expanded {
+		return
+	}
+	prefix += indent
+	for i, c := range n.Children {
+		if i == len(n.Children)-1 {
+			m.addRows(c, prefix, m.guides.Last, m.guides.Blank)
+		} else {
+			m.addRows(c, prefix, m.guides.Branch, m.guides.Line)
+		}
+	}
+}
+
+func (m *treeModel) layout() {
+	var sel *TreeNode
+	if m.y < len(m.rows) {
+		sel = m.rows[m.y].node
+	}
+	m.rows = m.rows[:0]
+	m.width = 0
+	if m.root != nil {
+		m.addRows(m.root, "", "", "")
+	}
+	for i := range m.rows {
+		if m.rows[i].node == sel {
+			m.y = i
+		}
+	}
+	m.limitCursor()
+}
+
+// SetRoot sets the root node of the tree.  The root is always shown,
+// and its first level of children are shown expanded.
+func (t *Tree) SetRoot(root *TreeNode) {
+	t.Init()
+	t.model.root = root
+	t.model.y = 0
+	t.model.rows = nil
+	if root != nil {
+		t.expand(root)
+	}
+	t.Refresh()
+}
+
+// Root returns the root node of the tree.
+func (t *Tree) Root() *TreeNode {
+	t.Init()
+	return t.model.root
+}
+
+// SetLoader sets the function used to load the children of Lazy nodes.
+// It is called once, the first time such a node is expanded.
+func (t *Tree) SetLoader(loader func(node *TreeNode) []*TreeNode) {
+	t.Init()
+	t.model.loader = loader
+}
+
+// SetGuides sets the guide strings used to show the tree's structure.
+func (t *Tree) SetGuides(guides TreeGuides) {
+	t.Init()
+	t.model.guides = guides
+	t.Refresh()
+}
+
+// SetIcons sets the icons drawn before each node's text.
+func (t *Tree) SetIcons(icons TreeIcons) {
+	t.Init()
+	t.model.icons = icons
+	t.Refresh()
+}
+
+// Refresh recomputes the displayed rows.  It must be called after the
+// application changes the nodes of the tree directly.
+func (t *Tree) Refresh() {
+	t.Init()
+	t.model.layout()
+	t.CellView.SetModel(t.model)
+	t.MakeCursorVisible()
+	t.PostEventWidgetContent(t)
+}
+
+func (t *Tree) expand(n *TreeNode) {
+	if n.Lazy && !n.loaded {
+		n.loaded = true
+		if t.model.loader != nil {
+			for _, c := range t.model.loader(n) {
+				n.AddChild(c)
+			}
+		}
+	}
+	n.expanded = len(n.Children) > 0
+}
+
+// Expand shows the children of the node, loading them if necessary.
+func (t *Tree) Expand(n *TreeNode) {
+	t.Init()
+	t.expand(n)
+	t.Refresh()
+}
+
+// Collapse hides the children of the node.
+func (t *Tree) Collapse(n *TreeNode) {
+	t.Init()
+	n.expanded = false
+	t.Refresh()
+}
+
+// Selected returns the selected node, or nil if the tree is empty.
+func (t *Tree) Selected() *TreeNode {
+	t.Init()
+	m := t.model
+	if m.y < len(m.rows) {
+		return m.rows[m.y].node
+	}
+	return nil
+}
+
+// Select selects the given node, expanding its ancestors as needed.
+func (t *Tree) Select(n *TreeNode) {
+	t.Init()
+	for p := n.parent; p != nil; p = p.parent {
+		p.expanded = true
+	}
+	t.model.layout()
+	for i := range t.model.rows {
+		if t.model.rows[i].node == n {
+			t.model.y = i
+		}
+	}
+	t.Refresh()
+}
+
+// HandleEvent handles the keys that expand, collapse and activate nodes,
+// and otherwise those of a CellView.
+func (t *Tree) HandleEvent(e tcell.Event) bool {
+	ev, ok := e.(*tcell.EventKey)
+	n := t.Selected()
+	if !ok || n == nil {
+		return t.CellView.HandleEvent(e)
+	}
+	key := ev.Key()
+	if key == tcell.KeyRune {
+		switch ev.Rune() {
+		case '+':
+			key = tcell.KeyRight
+		case '-':
+			key = tcell.KeyLeft
+		case ' ':
+			if n.expanded {
+				t.Collapse(n)
+			} else {
+				t.Expand(n)
+			}
+			return true
+		}
+	}
+	switch key {
+	case tcell.KeyRight:
+		if n.expanded {
+			t.Select(n.Children[0])
+		} else {
+			t.Expand(n)
+		}
+		return true
+	case tcell.KeyLeft:
+		if n.expanded {
+			t.Collapse(n)
+		} else if n.parent != nil {
+			t.Select(n.parent)
+		}
+		return true
+	case tcell.KeyEnter:
+		ev := &EventTreeActivate{node: n}
+		ev.SetWidget(t)
+		ev.SetEventNow()
+		t.PostEvent(ev)
+		return true
+	}
+	return t.CellView.HandleEvent(e)
+}
+
+// SetStyle sets the style used for the guides, and for any nodes that
+// do not have their own.
+func (t *Tree) SetStyle(style tcell.Style) {
+	t.Init()
+	t.model.style = style
+	t.CellView.SetStyle(style)
+}
+
+// Init initializes the Tree.
+func (t *Tree) Init() {
+	t.once.Do(func() {
+		m := &treeModel{
+			style:  tcell.StyleDefault,
+			guides: TreeGuidesLines,
+			icons:  TreeIcons{Expanded: '-', Collapsed: '+'},
+		}
+		t.model = m
+		t.CellView.Init()
+		t.CellView.SetModel(m)
+	})
+}
+
+// NewTree creates an empty Tree.
+func NewTree() *Tree {
+	t := &Tree{}
+	t.Init()
+	return t
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package views
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func treeLines(tr *Tree) []string {
+	var lines []string
+	for _, row := range tr.model.rows {
+		lines = append(lines, strings.Replace(string(row.runes), "\x00", "", -1))
+	}
+	return lines
+}
+
+func TestTree(t *testing.T) {
+	root := NewTreeNode("/")
+	etc := root.AddChild(&TreeNode{Text: "etc", Lazy: true})
+	root.AddChild(NewTreeNode("tmp"))
+
+	loads := 0
+	tr := NewTree()
+	tr.SetGuides(TreeGuidesASCII)
+	tr.SetLoader(func(n *TreeNode) []*TreeNode {
+		loads++
+		return []*TreeNode{NewTreeNode(n.Text + "/hosts"), NewTreeNode(n.Text + "/passwd")}
+	})
+	tr.SetRoot(root)
+
+	want := []string{"- /", "|-+ etc", "`-tmp"}
+	if got := treeLines(tr); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("wrong rows: %q", got)
+	}
+
+	key := func(k tcell.Key, r rune) {
+		tr.HandleEvent(tcell.NewEventKey(k, r, tcell.ModNone))
+	}
+	key(tcell.KeyDown, 0)
+	key(tcell.KeyRight, 0)
+	key(tcell.KeyRight, 0)
+	if tr.Selected() == nil || tr.Selected().Text != "etc/hosts" {
+		t.Errorf("wrong selection: %v", tr.Selected())
+	}
+	want = []string{"- /", "|-- etc", "| |-etc/hosts", "| `-etc/passwd", "`-tmp"}
+	if got := treeLines(tr); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("wrong expanded rows: %q", got)
+	}
+
+	key(tcell.KeyLeft, 0)
+	key(tcell.KeyLeft, 0)
+	key(tcell.KeyRune, '+')
+	if tr.Selected() != etc || !etc.Expanded() || loads != 1 {
+		t.Errorf("lazy children loaded %d times", loads)
+	}
+}
@@ -22,6 +22,12 @@ import (
 type Widget interface {
 	// Draw is called to inform the widget to draw itself.  A containing
 	// Widget will generally call this during the application draw loop.
+	// Containers such as BoxLayout redraw a child only when it has
+	// handled an event, or has posted an EventWidgetContent (as with
+	// WidgetWatchers.PostEventWidgetContent) to its watchers.  So a
+	// Widget whose content changes in any other way, such as from a
+	// timer or another goroutine, must post EventWidgetContent, or it
+	// will not be redrawn until its container next redraws everything.
 	Draw()
 
 	// Resize is called in response to a resize of the View.  Unlike with
@@ -62,6 +68,23 @@ type Widget interface {
 	Unwatch(handler tcell.EventHandler)
 }
 
+// Invalidator is implemented by Widgets that, to save time, redraw only
+// the parts of themselves that have changed since they were last drawn.
+// Invalidate tells such a Widget that its View has been cleared or drawn
+// over, so that its next Draw must redraw everything.  Containers that
+// clear their children's Views, or draw over them, should invalidate the
+// children before drawing them.
+type Invalidator interface {
+	Invalidate()
+}
+
+// Invalidate calls the Invalidate method of the Widget, if it has one.
+func Invalidate(w Widget) {
+	if i, ok := w.(Invalidator); ok {
+		i.Invalidate()
+	}
+}
+
 // EventWidget is an event delivered by a specific widget.
 type EventWidget interface {
 	Widget() Widget
@@ -0,0 +1,76 @@
+// Copyright 2022 The Tcell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+// Many terminal emulators, when the alternate screen is in use and mouse
+// reporting is off, send the cursor keys for the mouse wheel, so that
+// pagers and the like can be scrolled with it.  Not all do, and some only
+// do when configured to, so we offer the same ourselves: with SetWheelKeys,
+// while the application has not enabled the mouse, we ask for button
+// events, and turn the wheel into Up and Down keys, discarding the rest.
+
+// wheelKeys returns the key events that stand in for a mouse event, which
+// is nil unless the event is for the wheel.
+func wheelKeys(ev *EventMouse, n int) []Event {
+	var k Key
+	switch {
+	case ev.Buttons()&WheelUp != 0:
+		k = KeyUp
+	case ev.Buttons()&WheelDown != 0:
+		k = KeyDown
+	default:
+		return nil
+	}
+	evs := make([]Event, 0, n)
+	for i := 0; i < n; i++ {
+		evs = append(evs, NewEventKey(k, 0, ev.Modifiers()))
+	}
+	return evs
+}
+
+func (t *tScreen) SetWheelKeys(n int) {
+	t.Lock()
+	defer t.Unlock()
+	if n < 0 {
+		n = 0
+	}
+	t.wheelKeys = n
+	t.updateModes()
+}
+
+// mouseModes returns the mouse reporting that we want from the terminal.
+func (t *tScreen) mouseModes() MouseFlags {
+	if t.noMouse {
+		return 0
+	}
+	if t.mouseFlags == 0 && t.wheelKeys > 0 {
+		return MouseButtonEvents
+	}
+	return t.mouseFlags
+}
+
+// translateWheel replaces the mouse event just added to evs with keys,
+// if the wheel is being translated.
+func (t *tScreen) translateWheel(evs *[]Event) {
+	if t.mouseFlags != 0 || t.wheelKeys == 0 {
+		return
+	}
+	last := len(*evs) - 1
+	ev, ok := (*evs)[last].(*EventMouse)
+	if !ok {
+		return
+	}
+	*evs = append((*evs)[:last], wheelKeys(ev, t.wheelKeys)...)
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 The TCell Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use file except in compliance with the License.
+// You may obtain a copy of the license at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcell
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventWindowPosition is sent in response to QueryWindowPosition, and
+// reports the position of the terminal window on the desktop.
+type EventWindowPosition struct {
+	t time.Time
+	x int
+	y int
+}
+
+// NewEventWindowPosition creates an EventWindowPosition with the given
+// position, which is given in pixels relative to the top left of the desktop.
+func NewEventWindowPosition(x, y int) *EventWindowPosition {
+	return &EventWindowPosition{t: time.Now(), x: x, y: y}
+}
+
+// When returns the time when the Event was created.
+func (ev *EventWindowPosition) When() time.Time {
+	return ev.t
+}
+
+// Position returns the window position as x, y in pixels.
+func (ev *EventWindowPosition) Position() (int, int) {
+	return ev.x, ev.y
+}
+
+// XTWINOPS sequences, as implemented by xterm and many emulators that
+// follow it.  Note that xterm itself disables some of these by default
+// (see the allowWindowOps resource), in which case they are just ignored.
+const (
+	xtDeiconify     = "\x1b[1t"
+	xtIconify       = "\x1b[2t"
+	xtMoveWindow    = "\x1b[3;%p1%d;%p2%dt"
+	xtRaiseWindow   = "\x1b[5t"
+	xtRestoreWindow = "\x1b[9;0t"
+	xtMaximize      = "\x1b[9;1t"
+	xtQueryPosition = "\x1b[13t"
+)
+
+// parseWindowPosition decodes the reply to xtQueryPosition, which
+// is the CSI payload "3;x;yt".
+func parseWindowPosition(payload string) (int, int, bool) {
+	if !strings.HasPrefix(payload, "3;") || !strings.HasSuffix(payload, "t") {
+		return 0, 0, false
+	}
+	parts := strings.Split(payload[2:len(payload)-1], ";")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	x, e1 := strconv.Atoi(parts[0])
+	y, e2 := strconv.Atoi(parts[1])
+	if e1 != nil || e2 != nil {
+		return 0, 0, false
+	}
+	return x, y, true
+}